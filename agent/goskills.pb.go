@@ -0,0 +1,408 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: goskills.proto
+
+package agent
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecuteEvent_Type int32
+
+const (
+	ExecuteEvent_TYPE_UNSPECIFIED ExecuteEvent_Type = 0
+	// The LLM called a tool; content holds the JSON arguments.
+	ExecuteEvent_TYPE_TOOL_CALL ExecuteEvent_Type = 1
+	// A tool returned; content holds its output.
+	ExecuteEvent_TYPE_TOOL_RESULT ExecuteEvent_Type = 2
+	// The final answer; content holds the answer. It is always the last event.
+	ExecuteEvent_TYPE_RESULT ExecuteEvent_Type = 3
+)
+
+// Enum value maps for ExecuteEvent_Type.
+var (
+	ExecuteEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_TOOL_CALL",
+		2: "TYPE_TOOL_RESULT",
+		3: "TYPE_RESULT",
+	}
+	ExecuteEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_TOOL_CALL":   1,
+		"TYPE_TOOL_RESULT": 2,
+		"TYPE_RESULT":      3,
+	}
+)
+
+func (x ExecuteEvent_Type) Enum() *ExecuteEvent_Type {
+	p := new(ExecuteEvent_Type)
+	*p = x
+	return p
+}
+
+func (x ExecuteEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExecuteEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_goskills_proto_enumTypes[0].Descriptor()
+}
+
+func (ExecuteEvent_Type) Type() protoreflect.EnumType {
+	return &file_goskills_proto_enumTypes[0]
+}
+
+func (x ExecuteEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExecuteEvent_Type.Descriptor instead.
+func (ExecuteEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_goskills_proto_rawDescGZIP(), []int{3, 0}
+}
+
+// Message is a message of the conversation history.
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_goskills_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_goskills_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_goskills_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Message) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ExecuteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The user prompt.
+	Prompt string `protobuf:"bytes,1,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// Earlier messages of the conversation, oldest first. Empty starts a new
+	// conversation.
+	History       []*Message `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteRequest) Reset() {
+	*x = ExecuteRequest{}
+	mi := &file_goskills_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteRequest) ProtoMessage() {}
+
+func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goskills_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return file_goskills_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExecuteRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetHistory() []*Message {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type ExecuteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The final answer of the agent.
+	Result string `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// The skill that produced the answer.
+	Skill         string `protobuf:"bytes,2,opt,name=skill,proto3" json:"skill,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteResponse) Reset() {
+	*x = ExecuteResponse{}
+	mi := &file_goskills_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteResponse) ProtoMessage() {}
+
+func (x *ExecuteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goskills_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteResponse) Descriptor() ([]byte, []int) {
+	return file_goskills_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExecuteResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ExecuteResponse) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+type ExecuteEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Type    ExecuteEvent_Type      `protobuf:"varint,1,opt,name=type,proto3,enum=goskills.v1.ExecuteEvent_Type" json:"type,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Name of the tool for tool events.
+	ToolName string `protobuf:"bytes,3,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"`
+	// Links a tool result to its tool call.
+	ToolCallId string `protobuf:"bytes,4,opt,name=tool_call_id,json=toolCallId,proto3" json:"tool_call_id,omitempty"`
+	// The skill that produced the answer, set on the result event.
+	Skill         string `protobuf:"bytes,5,opt,name=skill,proto3" json:"skill,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteEvent) Reset() {
+	*x = ExecuteEvent{}
+	mi := &file_goskills_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteEvent) ProtoMessage() {}
+
+func (x *ExecuteEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_goskills_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteEvent.ProtoReflect.Descriptor instead.
+func (*ExecuteEvent) Descriptor() ([]byte, []int) {
+	return file_goskills_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExecuteEvent) GetType() ExecuteEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return ExecuteEvent_TYPE_UNSPECIFIED
+}
+
+func (x *ExecuteEvent) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ExecuteEvent) GetToolName() string {
+	if x != nil {
+		return x.ToolName
+	}
+	return ""
+}
+
+func (x *ExecuteEvent) GetToolCallId() string {
+	if x != nil {
+		return x.ToolCallId
+	}
+	return ""
+}
+
+func (x *ExecuteEvent) GetSkill() string {
+	if x != nil {
+		return x.Skill
+	}
+	return ""
+}
+
+var File_goskills_proto protoreflect.FileDescriptor
+
+const file_goskills_proto_rawDesc = "" +
+	"\n" +
+	"\x0egoskills.proto\x12\vgoskills.v1\"7\n" +
+	"\aMessage\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"X\n" +
+	"\x0eExecuteRequest\x12\x16\n" +
+	"\x06prompt\x18\x01 \x01(\tR\x06prompt\x12.\n" +
+	"\ahistory\x18\x02 \x03(\v2\x14.goskills.v1.MessageR\ahistory\"?\n" +
+	"\x0fExecuteResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\tR\x06result\x12\x14\n" +
+	"\x05skill\x18\x02 \x01(\tR\x05skill\"\x8a\x02\n" +
+	"\fExecuteEvent\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.goskills.v1.ExecuteEvent.TypeR\x04type\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1b\n" +
+	"\ttool_name\x18\x03 \x01(\tR\btoolName\x12 \n" +
+	"\ftool_call_id\x18\x04 \x01(\tR\n" +
+	"toolCallId\x12\x14\n" +
+	"\x05skill\x18\x05 \x01(\tR\x05skill\"W\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eTYPE_TOOL_CALL\x10\x01\x12\x14\n" +
+	"\x10TYPE_TOOL_RESULT\x10\x02\x12\x0f\n" +
+	"\vTYPE_RESULT\x10\x032\xa2\x01\n" +
+	"\x0fGoSkillsService\x12D\n" +
+	"\aExecute\x12\x1b.goskills.v1.ExecuteRequest\x1a\x1c.goskills.v1.ExecuteResponse\x12I\n" +
+	"\rExecuteStream\x12\x1b.goskills.v1.ExecuteRequest\x1a\x19.goskills.v1.ExecuteEvent0\x01B%Z#github.com/smallnest/goskills/agentb\x06proto3"
+
+var (
+	file_goskills_proto_rawDescOnce sync.Once
+	file_goskills_proto_rawDescData []byte
+)
+
+func file_goskills_proto_rawDescGZIP() []byte {
+	file_goskills_proto_rawDescOnce.Do(func() {
+		file_goskills_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goskills_proto_rawDesc), len(file_goskills_proto_rawDesc)))
+	})
+	return file_goskills_proto_rawDescData
+}
+
+var file_goskills_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_goskills_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_goskills_proto_goTypes = []any{
+	(ExecuteEvent_Type)(0),  // 0: goskills.v1.ExecuteEvent.Type
+	(*Message)(nil),         // 1: goskills.v1.Message
+	(*ExecuteRequest)(nil),  // 2: goskills.v1.ExecuteRequest
+	(*ExecuteResponse)(nil), // 3: goskills.v1.ExecuteResponse
+	(*ExecuteEvent)(nil),    // 4: goskills.v1.ExecuteEvent
+}
+var file_goskills_proto_depIdxs = []int32{
+	1, // 0: goskills.v1.ExecuteRequest.history:type_name -> goskills.v1.Message
+	0, // 1: goskills.v1.ExecuteEvent.type:type_name -> goskills.v1.ExecuteEvent.Type
+	2, // 2: goskills.v1.GoSkillsService.Execute:input_type -> goskills.v1.ExecuteRequest
+	2, // 3: goskills.v1.GoSkillsService.ExecuteStream:input_type -> goskills.v1.ExecuteRequest
+	3, // 4: goskills.v1.GoSkillsService.Execute:output_type -> goskills.v1.ExecuteResponse
+	4, // 5: goskills.v1.GoSkillsService.ExecuteStream:output_type -> goskills.v1.ExecuteEvent
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_goskills_proto_init() }
+func file_goskills_proto_init() {
+	if File_goskills_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goskills_proto_rawDesc), len(file_goskills_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_goskills_proto_goTypes,
+		DependencyIndexes: file_goskills_proto_depIdxs,
+		EnumInfos:         file_goskills_proto_enumTypes,
+		MessageInfos:      file_goskills_proto_msgTypes,
+	}.Build()
+	File_goskills_proto = out.File
+	file_goskills_proto_goTypes = nil
+	file_goskills_proto_depIdxs = nil
+}
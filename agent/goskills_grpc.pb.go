@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: goskills.proto
+
+package agent
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GoSkillsService_Execute_FullMethodName       = "/goskills.v1.GoSkillsService/Execute"
+	GoSkillsService_ExecuteStream_FullMethodName = "/goskills.v1.GoSkillsService/ExecuteStream"
+)
+
+// GoSkillsServiceClient is the client API for GoSkillsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GoSkillsService runs prompts through a goskills agent.
+type GoSkillsServiceClient interface {
+	// Execute runs a prompt and returns the final answer.
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error)
+	// ExecuteStream runs a prompt and streams its tool calls and tool results as
+	// they happen, followed by the final answer.
+	ExecuteStream(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteEvent], error)
+}
+
+type goSkillsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGoSkillsServiceClient(cc grpc.ClientConnInterface) GoSkillsServiceClient {
+	return &goSkillsServiceClient{cc}
+}
+
+func (c *goSkillsServiceClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (*ExecuteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteResponse)
+	err := c.cc.Invoke(ctx, GoSkillsService_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goSkillsServiceClient) ExecuteStream(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GoSkillsService_ServiceDesc.Streams[0], GoSkillsService_ExecuteStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecuteRequest, ExecuteEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoSkillsService_ExecuteStreamClient = grpc.ServerStreamingClient[ExecuteEvent]
+
+// GoSkillsServiceServer is the server API for GoSkillsService service.
+// All implementations must embed UnimplementedGoSkillsServiceServer
+// for forward compatibility.
+//
+// GoSkillsService runs prompts through a goskills agent.
+type GoSkillsServiceServer interface {
+	// Execute runs a prompt and returns the final answer.
+	Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error)
+	// ExecuteStream runs a prompt and streams its tool calls and tool results as
+	// they happen, followed by the final answer.
+	ExecuteStream(*ExecuteRequest, grpc.ServerStreamingServer[ExecuteEvent]) error
+	mustEmbedUnimplementedGoSkillsServiceServer()
+}
+
+// UnimplementedGoSkillsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGoSkillsServiceServer struct{}
+
+func (UnimplementedGoSkillsServiceServer) Execute(context.Context, *ExecuteRequest) (*ExecuteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedGoSkillsServiceServer) ExecuteStream(*ExecuteRequest, grpc.ServerStreamingServer[ExecuteEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteStream not implemented")
+}
+func (UnimplementedGoSkillsServiceServer) mustEmbedUnimplementedGoSkillsServiceServer() {}
+func (UnimplementedGoSkillsServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeGoSkillsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoSkillsServiceServer will
+// result in compilation errors.
+type UnsafeGoSkillsServiceServer interface {
+	mustEmbedUnimplementedGoSkillsServiceServer()
+}
+
+func RegisterGoSkillsServiceServer(s grpc.ServiceRegistrar, srv GoSkillsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedGoSkillsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GoSkillsService_ServiceDesc, srv)
+}
+
+func _GoSkillsService_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoSkillsServiceServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoSkillsService_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoSkillsServiceServer).Execute(ctx, req.(*ExecuteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoSkillsService_ExecuteStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoSkillsServiceServer).ExecuteStream(m, &grpc.GenericServerStream[ExecuteRequest, ExecuteEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GoSkillsService_ExecuteStreamServer = grpc.ServerStreamingServer[ExecuteEvent]
+
+// GoSkillsService_ServiceDesc is the grpc.ServiceDesc for GoSkillsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GoSkillsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goskills.v1.GoSkillsService",
+	HandlerType: (*GoSkillsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _GoSkillsService_Execute_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteStream",
+			Handler:       _GoSkillsService_ExecuteStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "goskills.proto",
+}
@@ -0,0 +1,125 @@
+// Package agent serves a goskills agent over gRPC, for Go services that embed
+// goskills rather than call the OpenAI-compatible HTTP API of goskills serve.
+//
+// The service is defined in goskills.proto; the generated stubs are goskills.pb.go
+// and goskills_grpc.pb.go.
+package agent
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative goskills.proto
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Runner runs a single prompt with the given conversation history.
+// *goskills.Agent implements it.
+type Runner interface {
+	RunWithMessages(ctx context.Context, userPrompt string, messages []openai.ChatCompletionMessage) (string, []openai.ChatCompletionMessage, error)
+	ActiveSkill() string
+}
+
+// Server implements GoSkillsService on top of a Runner. Requests are handled one
+// at a time because an agent runs a single conversation at once.
+type Server struct {
+	UnimplementedGoSkillsServiceServer
+
+	mu     sync.Mutex
+	runner Runner
+}
+
+// NewServer creates a Server backed by runner.
+func NewServer(runner Runner) *Server {
+	return &Server{runner: runner}
+}
+
+// Register registers s with a gRPC server.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	RegisterGoSkillsServiceServer(registrar, s)
+}
+
+// Execute runs the prompt and returns the final answer.
+func (s *Server) Execute(ctx context.Context, req *ExecuteRequest) (*ExecuteResponse, error) {
+	result, skill, err := s.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecuteResponse{Result: result, Skill: skill}, nil
+}
+
+// ExecuteStream runs the prompt and sends an event for every tool call and tool
+// result as it happens, followed by the final answer. Runners other than
+// *goskills.Agent must report tool events through goskills.WithToolEvents.
+func (s *Server) ExecuteStream(req *ExecuteRequest, stream grpc.ServerStreamingServer[ExecuteEvent]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var mu sync.Mutex // Serializes Send, which is not safe for concurrent use
+	var sendErr error
+	done := false // Set once the run returns; cancelled parallel skills may still report events
+	ctx = goskills.WithToolEvents(ctx, func(ev goskills.ToolEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done || sendErr != nil {
+			return
+		}
+		eventType := ExecuteEvent_TYPE_TOOL_CALL
+		if ev.Type == goskills.ToolEventResult {
+			eventType = ExecuteEvent_TYPE_TOOL_RESULT
+		}
+		sendErr = stream.Send(&ExecuteEvent{
+			Type:       eventType,
+			Content:    ev.Content,
+			ToolName:   ev.ToolName,
+			ToolCallId: ev.ToolCallID,
+		})
+		if sendErr != nil {
+			// The client is gone; stop the agent
+			cancel()
+		}
+	})
+
+	result, skill, err := s.run(ctx, req)
+	mu.Lock()
+	defer mu.Unlock()
+	done = true
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return err
+	}
+	return stream.Send(&ExecuteEvent{Type: ExecuteEvent_TYPE_RESULT, Content: result, Skill: skill})
+}
+
+// run executes req on the runner and returns the answer and the skill that produced
+// it. Errors are gRPC status errors.
+func (s *Server) run(ctx context.Context, req *ExecuteRequest) (string, string, error) {
+	prompt := strings.TrimSpace(req.GetPrompt())
+	if prompt == "" {
+		return "", "", status.Error(codes.InvalidArgument, "prompt is required")
+	}
+	var history []openai.ChatCompletionMessage
+	for _, msg := range req.GetHistory() {
+		history = append(history, openai.ChatCompletionMessage{Role: msg.GetRole(), Content: msg.GetContent()})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, _, err := s.runner.RunWithMessages(ctx, prompt, history)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return "", "", status.FromContextError(err).Err()
+		}
+		return "", "", status.Error(codes.Internal, err.Error())
+	}
+	return result, s.runner.ActiveSkill(), nil
+}
@@ -0,0 +1,217 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newUpstreamLLM starts a fake OpenAI API that returns responses in order and
+// records the requests it receives.
+func newUpstreamLLM(t *testing.T, responses ...openai.ChatCompletionResponse) (string, *[]openai.ChatCompletionRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []openai.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if len(responses) == 0 {
+			http.Error(w, `{"error": {"message": "no more responses"}}`, http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(responses[0])
+		responses = responses[1:]
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &requests
+}
+
+func answer(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+	}}}
+}
+
+func toolCall(id, name, arguments string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{
+			Role: openai.ChatMessageRoleAssistant,
+			ToolCalls: []openai.ToolCall{{
+				ID:       id,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: name, Arguments: arguments},
+			}},
+		},
+		FinishReason: openai.FinishReasonToolCalls,
+	}}}
+}
+
+// newTestClient serves a real agent, backed by the upstream LLM, over an in-memory
+// gRPC connection and returns a client for it. The agent runs the "greeter" skill,
+// whose directory holds name.txt.
+func newTestClient(t *testing.T, upstream string) GoSkillsServiceClient {
+	t.Helper()
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "greeter")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: greeter\ndescription: Greets people\n---\nGreet the user by the name in name.txt."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "name.txt"), []byte("Ada"), 0644))
+
+	ag, err := goskills.NewAgent(goskills.RunnerConfig{
+		APIKey:           "test-key",
+		APIBase:          upstream,
+		Model:            "test-model",
+		SkillsDir:        skillsDir,
+		SkillName:        "greeter",
+		AutoApproveTools: true,
+	}, nil)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewServer(ag).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewGoSkillsServiceClient(conn)
+}
+
+func TestServer_Execute(t *testing.T) {
+	upstream, requests := newUpstreamLLM(t, answer("Hello, Ada!"))
+	client := newTestClient(t, upstream)
+
+	resp, err := client.Execute(context.Background(), &ExecuteRequest{
+		Prompt:  "greet me",
+		History: []*Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "Hi there."}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Ada!", resp.GetResult())
+	assert.Equal(t, "greeter", resp.GetSkill())
+
+	// The history starts the conversation sent to the LLM
+	require.Len(t, *requests, 1)
+	messages := (*requests)[0].Messages
+	require.Len(t, messages, 4)
+	assert.Equal(t, "hi", messages[0].Content)
+	assert.Equal(t, "Hi there.", messages[1].Content)
+	assert.Equal(t, "greet me", messages[3].Content)
+}
+
+func TestServer_ExecuteStream(t *testing.T) {
+	upstream, _ := newUpstreamLLM(t,
+		toolCall("call-1", "read_file", `{"filePath": "name.txt"}`),
+		answer("Hello, Ada!"),
+	)
+	client := newTestClient(t, upstream)
+
+	stream, err := client.ExecuteStream(context.Background(), &ExecuteRequest{Prompt: "greet me"})
+	require.NoError(t, err)
+	var events []*ExecuteEvent
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, ev)
+	}
+
+	require.Len(t, events, 3)
+	assert.Equal(t, ExecuteEvent_TYPE_TOOL_CALL, events[0].GetType())
+	assert.Equal(t, "read_file", events[0].GetToolName())
+	assert.Equal(t, `{"filePath": "name.txt"}`, events[0].GetContent())
+	assert.Equal(t, ExecuteEvent_TYPE_TOOL_RESULT, events[1].GetType())
+	assert.Equal(t, "read_file", events[1].GetToolName())
+	assert.Equal(t, "call-1", events[1].GetToolCallId())
+	assert.Equal(t, "Ada", events[1].GetContent())
+	assert.Equal(t, ExecuteEvent_TYPE_RESULT, events[2].GetType())
+	assert.Equal(t, "Hello, Ada!", events[2].GetContent())
+	assert.Equal(t, "greeter", events[2].GetSkill())
+}
+
+func TestServer_ExecuteStreamLive(t *testing.T) {
+	// The upstream LLM holds back the final answer until the test has seen the tool events
+	release := make(chan struct{})
+	var once sync.Once
+	releaseAnswer := func() { once.Do(func() { close(release) }) }
+	var mu sync.Mutex
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			json.NewEncoder(w).Encode(toolCall("call-1", "read_file", `{"filePath": "name.txt"}`))
+			return
+		}
+		<-release
+		json.NewEncoder(w).Encode(answer("Hello, Ada!"))
+	}))
+	t.Cleanup(upstream.Close)
+	t.Cleanup(releaseAnswer)
+	client := newTestClient(t, upstream.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := client.ExecuteStream(ctx, &ExecuteRequest{Prompt: "greet me"})
+	require.NoError(t, err)
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ExecuteEvent_TYPE_TOOL_CALL, ev.GetType())
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ExecuteEvent_TYPE_TOOL_RESULT, ev.GetType())
+	assert.Equal(t, "Ada", ev.GetContent())
+
+	releaseAnswer()
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ExecuteEvent_TYPE_RESULT, ev.GetType())
+	assert.Equal(t, "Hello, Ada!", ev.GetContent())
+}
+
+func TestServer_Errors(t *testing.T) {
+	upstream, _ := newUpstreamLLM(t)
+	client := newTestClient(t, upstream)
+
+	_, err := client.Execute(context.Background(), &ExecuteRequest{Prompt: "  "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The upstream LLM has no responses left and fails
+	_, err = client.Execute(context.Background(), &ExecuteRequest{Prompt: "greet me"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	stream, err := client.ExecuteStream(context.Background(), &ExecuteRequest{Prompt: "greet me"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
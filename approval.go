@@ -0,0 +1,218 @@
+package goskills
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Approval modes for RunnerConfig.ApprovalMode.
+const (
+	ApprovalModeCLI  = "cli"  // Ask on the terminal
+	ApprovalModeGUI  = "gui"  // Ask with a zenity dialog
+	ApprovalModeAuto = "auto" // Approve every tool call
+)
+
+// ToolApprovalHandler decides whether a tool call may run. Returning an error denies the call.
+type ToolApprovalHandler interface {
+	RequestApproval(toolName, args string) (bool, error)
+}
+
+// CLIApprovalHandler asks for approval on a terminal and accepts "y" or "yes".
+// It is safe for concurrent use; concurrent requests are asked one at a time.
+type CLIApprovalHandler struct {
+	In  io.Reader // Defaults to os.Stdin
+	Out io.Writer // Defaults to os.Stdout
+
+	mu     sync.Mutex
+	reader *bufio.Reader
+}
+
+// RequestApproval prints the tool call and reads the answer from one line of input.
+func (h *CLIApprovalHandler) RequestApproval(toolName, args string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := h.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	fmt.Fprintf(out, "⚠️  Allow tool %s with args %s? [y/N]: ", toolName, args)
+	line, err := h.input().ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return false, fmt.Errorf("failed to read approval: %w", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
+
+// input returns the buffered reader of In. Callers must hold h.mu or otherwise
+// ensure that no approval is being requested.
+func (h *CLIApprovalHandler) input() *bufio.Reader {
+	if h.reader == nil {
+		in := h.In
+		if in == nil {
+			in = os.Stdin
+		}
+		h.reader = bufio.NewReader(in)
+	}
+	return h.reader
+}
+
+// ZenityApprovalHandler asks for approval with a `zenity --question` dialog, which
+// works on Linux desktops and on macOS with zenity installed.
+type ZenityApprovalHandler struct {
+	Command string // Defaults to "zenity"
+}
+
+// RequestApproval shows the dialog; "Yes" approves and "No" or closing the dialog denies.
+func (h *ZenityApprovalHandler) RequestApproval(toolName, args string) (bool, error) {
+	command := h.Command
+	if command == "" {
+		command = "zenity"
+	}
+	text := fmt.Sprintf("Allow goskills to run the tool %s?\n\nArguments: %s", toolName, args)
+	cmd := exec.Command(command, "--question", "--title=goskills: tool approval", "--no-markup", "--text="+text)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to run %s: %w", command, err)
+}
+
+// ApprovalRequest is sent by WebSocketApprovalHandler for every tool call.
+type ApprovalRequest struct {
+	Type      string `json:"type"` // Always "approval_request"
+	ID        string `json:"id"`
+	Tool      string `json:"tool"`
+	Arguments string `json:"arguments"`
+}
+
+// ApprovalResponse is the reply WebSocketApprovalHandler waits for.
+type ApprovalResponse struct {
+	Type     string `json:"type"` // Must be "approval_response"
+	ID       string `json:"id"`   // ID of the request being answered
+	Approved bool   `json:"approved"`
+}
+
+// WebSocketApprovalHandler asks a web client for approval over a WebSocket connection.
+// Once the first approval is requested, the handler reads every message from Conn until
+// the connection is closed.
+type WebSocketApprovalHandler struct {
+	Conn    *websocket.Conn
+	Timeout time.Duration // Deny when no answer arrives in time; 0 waits indefinitely
+
+	mu        sync.Mutex
+	nextID    int
+	readOnce  sync.Once
+	responses chan ApprovalResponse
+	readErr   error // Why reading failed; set before responses is closed
+}
+
+// RequestApproval sends the request and blocks until the client answers.
+func (h *WebSocketApprovalHandler) RequestApproval(toolName, args string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A read deadline would break the connection for good, so a timeout only stops
+	// waiting; a late answer is skipped by the next request
+	h.readOnce.Do(func() {
+		h.responses = make(chan ApprovalResponse)
+		go h.readResponses()
+	})
+
+	h.nextID++
+	id := strconv.Itoa(h.nextID)
+	if err := h.Conn.WriteJSON(ApprovalRequest{Type: "approval_request", ID: id, Tool: toolName, Arguments: args}); err != nil {
+		return false, fmt.Errorf("failed to send approval request: %w", err)
+	}
+
+	var timeout <-chan time.Time
+	if h.Timeout > 0 {
+		timer := time.NewTimer(h.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		select {
+		case resp, ok := <-h.responses:
+			if !ok {
+				return false, fmt.Errorf("failed to read approval response: %w", h.readErr)
+			}
+			if resp.ID == id {
+				return resp.Approved, nil
+			}
+		case <-timeout:
+			return false, nil
+		}
+	}
+}
+
+// readResponses passes the approval responses read from Conn to RequestApproval until
+// reading fails. Other messages are skipped.
+func (h *WebSocketApprovalHandler) readResponses() {
+	defer close(h.responses)
+	for {
+		_, data, err := h.Conn.ReadMessage()
+		if err != nil {
+			h.readErr = err
+			return
+		}
+		var resp ApprovalResponse
+		if err := json.Unmarshal(data, &resp); err == nil && resp.Type == "approval_response" {
+			h.responses <- resp
+		}
+	}
+}
+
+// approvalHandler returns the handler that approves tool calls, or nil when every call
+// is approved automatically. An empty ApprovalMode follows AutoApproveTools.
+func (a *Agent) approvalHandler() ToolApprovalHandler {
+	if a.cfg.ApprovalHandler != nil {
+		return a.cfg.ApprovalHandler
+	}
+	switch a.cfg.ApprovalMode {
+	case ApprovalModeAuto:
+		return nil
+	case ApprovalModeGUI:
+		return &ZenityApprovalHandler{}
+	case ApprovalModeCLI:
+	default:
+		if a.cfg.AutoApproveTools {
+			return nil
+		}
+	}
+	return a.terminalApproval()
+}
+
+// terminalApproval returns the agent's own CLIApprovalHandler, creating it on first use.
+func (a *Agent) terminalApproval() *CLIApprovalHandler {
+	if a.cliApproval == nil {
+		a.cliApproval = &CLIApprovalHandler{}
+	}
+	return a.cliApproval
+}
+
+// stdinReader returns the reader RunLoop reads prompts from. It is the one the
+// terminal approval prompt reads from, so neither buffers input meant for the other.
+func (a *Agent) stdinReader() *bufio.Reader {
+	if h, ok := a.cfg.ApprovalHandler.(*CLIApprovalHandler); ok && h.In == nil {
+		return h.input()
+	}
+	return a.terminalApproval().input()
+}
@@ -0,0 +1,272 @@
+package goskills
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ ToolApprovalHandler = (*CLIApprovalHandler)(nil)
+	_ ToolApprovalHandler = (*ZenityApprovalHandler)(nil)
+	_ ToolApprovalHandler = (*WebSocketApprovalHandler)(nil)
+)
+
+// fakeApprovalHandler answers with a fixed decision and records the tool calls it saw.
+type fakeApprovalHandler struct {
+	approved bool
+	err      error
+	calls    []string
+}
+
+func (h *fakeApprovalHandler) RequestApproval(toolName, args string) (bool, error) {
+	h.calls = append(h.calls, toolName+" "+args)
+	return h.approved, h.err
+}
+
+func TestCLIApprovalHandler(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, err = w.WriteString("y\nno\nYES\n")
+	require.NoError(t, err)
+	w.Close()
+
+	var out bytes.Buffer
+	h := &CLIApprovalHandler{In: r, Out: &out}
+
+	for _, want := range []bool{true, false, true} {
+		approved, err := h.RequestApproval("run_shell_code", `{"code":"ls"}`)
+		require.NoError(t, err)
+		assert.Equal(t, want, approved)
+	}
+	assert.Contains(t, out.String(), `Allow tool run_shell_code with args {"code":"ls"}? [y/N]`)
+
+	// The pipe is closed and drained
+	approved, err := h.RequestApproval("run_shell_code", "{}")
+	assert.Error(t, err)
+	assert.False(t, approved)
+}
+
+func TestCLIApprovalHandler_NoTrailingNewline(t *testing.T) {
+	h := &CLIApprovalHandler{In: strings.NewReader("y"), Out: &bytes.Buffer{}}
+	approved, err := h.RequestApproval("read_file", "{}")
+	require.NoError(t, err)
+	assert.True(t, approved)
+}
+
+func TestCLIApprovalHandler_Concurrent(t *testing.T) {
+	var out bytes.Buffer
+	h := &CLIApprovalHandler{In: strings.NewReader("y\nn\ny\nn\n"), Out: &out}
+
+	var wg sync.WaitGroup
+	var approvals atomic.Int32
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			approved, err := h.RequestApproval("read_file", "{}")
+			assert.NoError(t, err)
+			if approved {
+				approvals.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every request got its own line and its own complete prompt
+	assert.Equal(t, int32(2), approvals.Load())
+	assert.Equal(t, 4, strings.Count(out.String(), "Allow tool read_file with args {}? [y/N]: "))
+}
+
+func TestAgentFork_SharesCLIApproval(t *testing.T) {
+	a := &Agent{}
+	first, second := a.fork(), a.fork()
+	assert.Same(t, a.approvalHandler(), first.approvalHandler())
+	assert.Same(t, first.approvalHandler(), second.approvalHandler())
+}
+
+func TestAgent_StdinReaderSharedWithApproval(t *testing.T) {
+	a := &Agent{}
+	assert.Same(t, a.terminalApproval().input(), a.stdinReader())
+
+	custom := &CLIApprovalHandler{}
+	a = &Agent{cfg: RunnerConfig{ApprovalHandler: custom}}
+	assert.Same(t, custom.input(), a.stdinReader())
+
+	// A handler reading elsewhere leaves stdin to the agent's own reader
+	a = &Agent{cfg: RunnerConfig{ApprovalHandler: &CLIApprovalHandler{In: strings.NewReader("y\n")}}}
+	assert.Same(t, a.terminalApproval().input(), a.stdinReader())
+}
+
+func TestZenityApprovalHandler(t *testing.T) {
+	dir := t.TempDir()
+	script := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755))
+		return path
+	}
+
+	approved, err := (&ZenityApprovalHandler{Command: script("yes", "exit 0")}).RequestApproval("read_file", "{}")
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	approved, err = (&ZenityApprovalHandler{Command: script("no", "exit 1")}).RequestApproval("read_file", "{}")
+	require.NoError(t, err)
+	assert.False(t, approved)
+
+	approved, err = (&ZenityApprovalHandler{Command: script("broken", "exit 5")}).RequestApproval("read_file", "{}")
+	assert.Error(t, err)
+	assert.False(t, approved)
+
+	_, err = (&ZenityApprovalHandler{Command: filepath.Join(dir, "missing")}).RequestApproval("read_file", "{}")
+	assert.Error(t, err)
+}
+
+func TestWebSocketApprovalHandler(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req ApprovalRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			// Unrelated messages and answers to other requests are skipped
+			conn.WriteJSON(map[string]string{"type": "status"})
+			conn.WriteJSON(ApprovalResponse{Type: "approval_response", ID: "other", Approved: true})
+			conn.WriteJSON(ApprovalResponse{Type: "approval_response", ID: req.ID, Approved: req.Tool == "read_file"})
+		}
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	h := &WebSocketApprovalHandler{Conn: conn}
+	approved, err := h.RequestApproval("read_file", `{"file_path":"a.txt"}`)
+	require.NoError(t, err)
+	assert.True(t, approved)
+
+	approved, err = h.RequestApproval("run_shell_code", `{"code":"rm -rf /"}`)
+	require.NoError(t, err)
+	assert.False(t, approved)
+}
+
+func TestWebSocketApprovalHandler_Timeout(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// The first request is only answered, too late, once the second arrives
+		var first, second ApprovalRequest
+		if conn.ReadJSON(&first) != nil || conn.ReadJSON(&second) != nil {
+			return
+		}
+		conn.WriteJSON(ApprovalResponse{Type: "approval_response", ID: first.ID, Approved: true})
+		conn.WriteJSON(ApprovalResponse{Type: "approval_response", ID: second.ID, Approved: true})
+		conn.ReadJSON(&first)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	h := &WebSocketApprovalHandler{Conn: conn, Timeout: 100 * time.Millisecond}
+	approved, err := h.RequestApproval("run_shell_code", `{"code":"ls"}`)
+	require.NoError(t, err)
+	assert.False(t, approved, "no answer in time denies the call")
+
+	// The connection still works after a timeout
+	h.Timeout = 5 * time.Second
+	approved, err = h.RequestApproval("read_file", `{"file_path":"a.txt"}`)
+	require.NoError(t, err)
+	assert.True(t, approved)
+}
+
+func TestAgentApprovalHandler(t *testing.T) {
+	custom := &fakeApprovalHandler{}
+	tests := []struct {
+		cfg  RunnerConfig
+		want any
+	}{
+		{RunnerConfig{AutoApproveTools: true}, nil},
+		{RunnerConfig{}, &CLIApprovalHandler{}},
+		{RunnerConfig{ApprovalMode: ApprovalModeAuto}, nil},
+		{RunnerConfig{ApprovalMode: ApprovalModeCLI, AutoApproveTools: true}, &CLIApprovalHandler{}},
+		{RunnerConfig{ApprovalMode: ApprovalModeGUI}, &ZenityApprovalHandler{}},
+		{RunnerConfig{ApprovalMode: ApprovalModeAuto, ApprovalHandler: custom}, custom},
+	}
+	for _, tt := range tests {
+		agent := &Agent{cfg: tt.cfg}
+		got := agent.approvalHandler()
+		if tt.want == nil {
+			assert.Nil(t, got, "%+v", tt.cfg)
+		} else {
+			assert.IsType(t, tt.want, got, "%+v", tt.cfg)
+		}
+	}
+
+	_, err := NewAgent(RunnerConfig{APIKey: "test-key", ApprovalMode: "email"}, nil)
+	assert.Error(t, err)
+}
+
+func TestContinueSkillWithTools_Approval(t *testing.T) {
+	final := openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "done"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		handler *fakeApprovalHandler
+		want    string
+	}{
+		{"approved", &fakeApprovalHandler{approved: true}, "test"},
+		{"denied", &fakeApprovalHandler{}, "Error: User denied tool execution."},
+		{"error", &fakeApprovalHandler{err: errors.New("dialog crashed")}, "Error: Tool approval failed."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := NewMockOpenAIClient(append(toolCallResponses(1), final), nil)
+			agent := &Agent{
+				client: mockClient,
+				cfg:    RunnerConfig{Model: "test-model", ApprovalHandler: tt.handler},
+			}
+
+			result, err := agent.continueSkillWithTools(context.Background(), "test prompt", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+			require.NoError(t, err)
+			assert.Equal(t, "done", result)
+			assert.Equal(t, []string{`run_shell_code {"code": "echo test", "args": {}}`}, tt.handler.calls)
+
+			toolMessage := mockClient.requests[1].Messages[len(mockClient.requests[1].Messages)-1]
+			assert.Equal(t, openai.ChatMessageRoleTool, toolMessage.Role)
+			assert.Contains(t, toolMessage.Content, tt.want)
+		})
+	}
+}
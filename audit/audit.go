@@ -0,0 +1,172 @@
+// Package audit records tool calls made by the agent to an append-only JSONL file.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// RedactedValue replaces any argument value considered secret.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactPatterns match argument names and values that commonly carry secrets.
+var DefaultRedactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(api[_-]?key|token|secret|password|passwd|authorization|credential)`),
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{16,}`),
+}
+
+// Record kinds.
+const (
+	KindToolCall   = "tool_call"
+	KindCompletion = "completion"
+)
+
+// Record is a single entry in the audit log: either a tool call or an LLM completion.
+type Record struct {
+	Kind             string         `json:"kind,omitempty"` // KindToolCall when empty
+	Timestamp        time.Time      `json:"timestamp"`
+	SessionID        string         `json:"session_id"`
+	Skill            string         `json:"skill"`
+	Tool             string         `json:"tool,omitempty"`
+	Arguments        map[string]any `json:"arguments,omitempty"`
+	OutputLength     int            `json:"output_length"`
+	DurationMs       int64          `json:"duration_ms"`
+	Error            string         `json:"error,omitempty"`
+	PromptTokens     int            `json:"prompt_tokens,omitempty"`
+	CompletionTokens int            `json:"completion_tokens,omitempty"`
+}
+
+// IsToolCall reports whether the record describes a tool call.
+func (r Record) IsToolCall() bool {
+	return r.Kind == "" || r.Kind == KindToolCall
+}
+
+// Logger appends audit records to a JSONL file.
+type Logger struct {
+	mu       sync.Mutex
+	path     string
+	patterns []*regexp.Regexp
+}
+
+// NewLogger creates a Logger writing to path. Argument values are redacted with patterns;
+// when patterns is nil, DefaultRedactPatterns is used.
+func NewLogger(path string, patterns []*regexp.Regexp) (*Logger, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit log path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	if patterns == nil {
+		patterns = DefaultRedactPatterns
+	}
+	return &Logger{path: path, patterns: patterns}, nil
+}
+
+// Log redacts the record's arguments and appends it to the audit file as one JSON line.
+// The file is synced before Log returns.
+func (l *Logger) Log(rec Record) error {
+	rec.Arguments = RedactArguments(rec.Arguments, l.patterns)
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to flush audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadRecords reads every record from the audit log at path.
+func ReadRecords(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log line %d: %w", lineNo, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return records, nil
+}
+
+// Redact returns value with secrets removed. If key matches any pattern the whole value
+// is replaced; otherwise every substring of value matching a pattern is replaced.
+func Redact(key, value string, patterns []*regexp.Regexp) string {
+	for _, p := range patterns {
+		if p.MatchString(key) {
+			return RedactedValue
+		}
+	}
+	for _, p := range patterns {
+		value = p.ReplaceAllString(value, RedactedValue)
+	}
+	return value
+}
+
+// RedactArguments returns a copy of args with Redact applied to every string value,
+// descending into nested maps and slices.
+func RedactArguments(args map[string]any, patterns []*regexp.Regexp) map[string]any {
+	if args == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(args))
+	for k, v := range args {
+		redacted[k] = redactValue(k, v, patterns)
+	}
+	return redacted
+}
+
+func redactValue(key string, value any, patterns []*regexp.Regexp) any {
+	switch v := value.(type) {
+	case string:
+		return Redact(key, v, patterns)
+	case map[string]any:
+		return RedactArguments(v, patterns)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = redactValue(key, item, patterns)
+		}
+		return items
+	default:
+		return value
+	}
+}
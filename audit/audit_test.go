@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`(?i)api[_-]?key`),
+		regexp.MustCompile(`sk-[a-z0-9]+`),
+	}
+
+	assert.Equal(t, RedactedValue, Redact("API_KEY", "abc", patterns))
+	assert.Equal(t, "export KEY=[REDACTED]", Redact("code", "export KEY=sk-abc123", patterns))
+	assert.Equal(t, "plain text", Redact("code", "plain text", patterns))
+	assert.Equal(t, "sk-abc123", Redact("code", "sk-abc123", nil))
+}
+
+func TestRedactArguments(t *testing.T) {
+	args := map[string]any{
+		"query":    "weather",
+		"password": "hunter2",
+		"nested":   map[string]any{"token": "xyz", "count": float64(3)},
+		"args":     []any{"--api-key", "sk-abcdefghijklmnopqrstuvwxyz"},
+	}
+
+	redacted := RedactArguments(args, DefaultRedactPatterns)
+
+	assert.Equal(t, "weather", redacted["query"])
+	assert.Equal(t, RedactedValue, redacted["password"])
+	assert.Equal(t, map[string]any{"token": RedactedValue, "count": float64(3)}, redacted["nested"])
+	assert.Equal(t, []any{"--[REDACTED]", RedactedValue}, redacted["args"])
+	// The input must not be modified
+	assert.Equal(t, "hunter2", args["password"])
+}
+
+func TestLogger_Log(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	logger, err := NewLogger(path, nil)
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, logger.Log(Record{
+		Timestamp:    ts,
+		SessionID:    "session-1",
+		Skill:        "pdf",
+		Tool:         "run_shell_code",
+		Arguments:    map[string]any{"code": "echo hi", "api_key": "secret"},
+		OutputLength: 3,
+		DurationMs:   12,
+	}))
+	require.NoError(t, logger.Log(Record{
+		Timestamp: ts,
+		SessionID: "session-1",
+		Skill:     "pdf",
+		Tool:      "read_file",
+		Error:     "file not found",
+	}))
+
+	// A second logger on the same file appends rather than truncating
+	other, err := NewLogger(path, nil)
+	require.NoError(t, err)
+	require.NoError(t, other.Log(Record{Timestamp: ts, SessionID: "session-2", Tool: "web_fetch"}))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+
+	first := lines[0]
+	assert.Equal(t, "2025-01-02T03:04:05Z", first["timestamp"])
+	assert.Equal(t, "session-1", first["session_id"])
+	assert.Equal(t, "pdf", first["skill"])
+	assert.Equal(t, "run_shell_code", first["tool"])
+	assert.Equal(t, map[string]any{"code": "echo hi", "api_key": RedactedValue}, first["arguments"])
+	assert.Equal(t, float64(3), first["output_length"])
+	assert.Equal(t, float64(12), first["duration_ms"])
+	assert.NotContains(t, first, "error")
+
+	assert.Equal(t, "file not found", lines[1]["error"])
+	assert.Equal(t, "session-2", lines[2]["session_id"])
+}
+
+func TestNewLogger_EmptyPath(t *testing.T) {
+	_, err := NewLogger("", nil)
+	assert.Error(t, err)
+}
+
+func TestReadRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(path, nil)
+	require.NoError(t, err)
+	require.NoError(t, logger.Log(Record{Kind: KindToolCall, SessionID: "s1", Tool: "read_file"}))
+	require.NoError(t, logger.Log(Record{Kind: KindCompletion, SessionID: "s1", PromptTokens: 10, CompletionTokens: 5}))
+
+	records, err := ReadRecords(path)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.True(t, records[0].IsToolCall())
+	assert.False(t, records[1].IsToolCall())
+	assert.Equal(t, 10, records[1].PromptTokens)
+
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0600))
+	_, err = ReadRecords(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/log"
+)
+
+// Checkpoint is the state of a run saved after a tool-call iteration, from which an
+// interrupted run can be resumed.
+type Checkpoint struct {
+	Messages      []openai.ChatCompletionMessage `json:"messages"`
+	SelectedSkill string                         `json:"selected-skill"`
+	LastPrompt    string                         `json:"last-prompt"`
+}
+
+// CheckpointPath returns the file the agent writes its checkpoints to, or "" when
+// RunnerConfig.CheckpointDir is empty.
+func (a *Agent) CheckpointPath() string {
+	if a.cfg.CheckpointDir == "" {
+		return ""
+	}
+	return filepath.Join(a.cfg.CheckpointDir, a.sessionID+".json")
+}
+
+// SaveCheckpointToFile writes the agent's conversation, active skill and current prompt
+// to path, creating its directory if needed. The file is replaced atomically.
+func (a *Agent) SaveCheckpointToFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	data, err := json.MarshalIndent(Checkpoint{
+		Messages:      a.messages,
+		SelectedSkill: a.activeSkill,
+		LastPrompt:    a.lastPrompt,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// saveCheckpoint writes a checkpoint to CheckpointPath when checkpoints are enabled.
+// Failures are only logged so they never interrupt the run.
+func (a *Agent) saveCheckpoint() {
+	path := a.CheckpointPath()
+	if path == "" {
+		return
+	}
+	if err := a.SaveCheckpointToFile(path); err != nil {
+		log.Warn("%v", err)
+	} else if a.cfg.Verbose >= 2 {
+		log.Debug("checkpoint written to %s", path)
+	}
+}
+
+// RestoreFromCheckpoint replaces the agent's conversation, active skill and current
+// prompt with those saved at path; call Resume to continue the run. A trailing tool
+// call round whose results are incomplete is dropped, so the run continues after the
+// last completed tool call.
+func (a *Agent) RestoreFromCheckpoint(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if cp.SelectedSkill == "" {
+		return fmt.Errorf("checkpoint %s has no selected skill", path)
+	}
+	a.messages = dropIncompleteToolRound(cp.Messages)
+	a.activeSkill = cp.SelectedSkill
+	a.lastPrompt = cp.LastPrompt
+	return nil
+}
+
+// dropIncompleteToolRound removes the last assistant message calling tools, and the
+// results following it, when one of its tool calls has no result.
+func dropIncompleteToolRound(messages []openai.ChatCompletionMessage) []openai.ChatCompletionMessage {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role != openai.ChatMessageRoleAssistant {
+			continue
+		}
+		results := make(map[string]bool)
+		for _, m := range messages[i+1:] {
+			if m.Role == openai.ChatMessageRoleTool {
+				results[m.ToolCallID] = true
+			}
+		}
+		for _, tc := range msg.ToolCalls {
+			if !results[tc.ID] {
+				return messages[:i]
+			}
+		}
+		break
+	}
+	return messages
+}
+
+// Resume continues the run restored by RestoreFromCheckpoint, sending the restored
+// conversation to the LLM and executing the tools it calls as Run does. If the run had
+// already finished, its final answer is returned without calling the LLM.
+func (a *Agent) Resume(ctx context.Context) (string, error) {
+	if a.activeSkill == "" {
+		return "", errors.New("no checkpoint has been restored")
+	}
+	if n := len(a.messages); n > 0 && a.messages[n-1].Role == openai.ChatMessageRoleAssistant {
+		return a.messages[n-1].Content, nil
+	}
+
+	skills, err := a.discoverSkills(a.cfg.SkillsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover skills: %w", err)
+	}
+	var skill *SkillPackage
+	if s, ok := skills[a.activeSkill]; ok {
+		skill = &s
+	} else if a.cfg.AllSkillsMode && a.activeSkill == allSkillsName {
+		limit := a.cfg.AllSkillsTokenLimit
+		if limit <= 0 {
+			limit = DefaultAllSkillsTokenLimit
+		}
+		skill, _ = combineSkills(a.cfg.SkillsDir, skills, limit)
+	} else {
+		return "", &skerrors.SkillNotFoundError{Name: a.activeSkill, Available: getAvailableSkillNames(skills)}
+	}
+	if a.cfg.Verbose >= 1 {
+		log.Info("resuming skill %s from checkpoint (%d messages)", skill.Meta.Name, len(a.messages))
+	}
+	return a.runToolLoop(ctx, a.lastPrompt, skill, "")
+}
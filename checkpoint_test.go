@@ -0,0 +1,142 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCheckpointAgent returns an agent running the notes skill that checkpoints into dir.
+func newCheckpointAgent(t *testing.T, client *MockOpenAIClient, skillsDir, dir, session string) *Agent {
+	t.Helper()
+	return &Agent{
+		client:    client,
+		sessionID: session,
+		cfg: RunnerConfig{
+			Model:         "test",
+			SkillsDir:     skillsDir,
+			SkillName:     "notes",
+			CheckpointDir: dir,
+		},
+	}
+}
+
+func TestAgent_ResumeMatchesUninterruptedRun(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "notes", "", "Take notes with the shell.")
+	checkpointDir := t.TempDir()
+	responses := []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "run_shell_code", `{"code":"echo one"}`),
+		toolCallResponse("call-2", "run_shell_code", `{"code":"echo two"}`),
+		textResponse("one, two"),
+	}
+
+	full := NewMockOpenAIClient(responses, nil)
+	uninterrupted := newCheckpointAgent(t, full, skillsDir, checkpointDir, "full")
+	want, err := uninterrupted.Run(context.Background(), "count")
+	require.NoError(t, err)
+	require.Equal(t, "one, two", want)
+
+	// The run fails when the LLM is called after the first tool call
+	failing := NewMockOpenAIClient(responses[:1], nil)
+	interrupted := newCheckpointAgent(t, failing, skillsDir, checkpointDir, "interrupted")
+	_, err = interrupted.Run(context.Background(), "count")
+	var apiErr *skerrors.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	path := interrupted.CheckpointPath()
+	assert.Equal(t, filepath.Join(checkpointDir, "interrupted.json"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var cp Checkpoint
+	require.NoError(t, json.Unmarshal(data, &cp))
+	assert.Equal(t, "notes", cp.SelectedSkill)
+	assert.Equal(t, "count", cp.LastPrompt)
+	require.Len(t, cp.Messages, 4, "system, user, tool call and its result")
+	assert.Equal(t, "call-1", cp.Messages[3].ToolCallID)
+
+	rest := NewMockOpenAIClient(responses[1:], nil)
+	resumed := newCheckpointAgent(t, rest, skillsDir, checkpointDir, "resumed")
+	require.NoError(t, resumed.RestoreFromCheckpoint(path))
+	got, err := resumed.Resume(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, uninterrupted.messages, resumed.messages)
+	assert.Equal(t, "notes", resumed.ActiveSkill())
+	require.Len(t, rest.requests, 2)
+	assert.Equal(t, full.requests[1:], rest.requests, "the LLM sees the same conversation")
+}
+
+func TestAgent_SaveAndRestoreCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "cp.json")
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: "skill"},
+		{Role: openai.ChatMessageRoleUser, Content: "question"},
+		{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{
+			{ID: "1", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "read_file", Arguments: "{}"}},
+		}},
+		{Role: openai.ChatMessageRoleTool, ToolCallID: "1", Content: "text"},
+	}
+	a := &Agent{messages: messages, activeSkill: "notes", lastPrompt: "question"}
+	require.NoError(t, a.SaveCheckpointToFile(path))
+
+	restored := &Agent{}
+	require.NoError(t, restored.RestoreFromCheckpoint(path))
+	assert.Equal(t, messages, restored.messages)
+	assert.Equal(t, "notes", restored.activeSkill)
+	assert.Equal(t, "question", restored.lastPrompt)
+
+	// A tool call round without all its results is dropped
+	incomplete := append(append([]openai.ChatCompletionMessage(nil), messages...),
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{
+			{ID: "2", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "read_file", Arguments: "{}"}},
+			{ID: "3", Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: "read_file", Arguments: "{}"}},
+		}},
+		openai.ChatCompletionMessage{Role: openai.ChatMessageRoleTool, ToolCallID: "2", Content: "text"},
+	)
+	a.messages = incomplete
+	require.NoError(t, a.SaveCheckpointToFile(path))
+	require.NoError(t, restored.RestoreFromCheckpoint(path))
+	assert.Equal(t, messages, restored.messages)
+}
+
+func TestAgent_ResumeFinishedRun(t *testing.T) {
+	client := NewMockOpenAIClient(nil, nil)
+	a := &Agent{client: client, activeSkill: "notes", messages: []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: "question"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "answer"},
+	}}
+	result, err := a.Resume(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "answer", result)
+	assert.Empty(t, client.requests)
+}
+
+func TestAgent_ResumeErrors(t *testing.T) {
+	_, err := (&Agent{}).Resume(context.Background())
+	assert.EqualError(t, err, "no checkpoint has been restored")
+
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "notes", "", "Body")
+	a := &Agent{cfg: RunnerConfig{SkillsDir: skillsDir}, activeSkill: "removed", messages: []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: "question"},
+	}}
+	_, err = a.Resume(context.Background())
+	var notFound *skerrors.SkillNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	err = a.RestoreFromCheckpoint(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read checkpoint")
+
+	path := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"messages": []}`), 0644))
+	assert.ErrorContains(t, a.RestoreFromCheckpoint(path), "has no selected skill")
+}
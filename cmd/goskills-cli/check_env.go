@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smallnest/goskills"
+	"github.com/smallnest/goskills/tool"
+	"github.com/spf13/cobra"
+)
+
+var checkEnvRequiredOnly bool
+
+// versionTimeout bounds how long a binary may take to print its version.
+const versionTimeout = 5 * time.Second
+
+// envTool is an external program goskills or its skills may run.
+type envTool struct {
+	Name     string
+	Required bool                   // Needed to run skills at all, not only some tools
+	Lookup   func() (string, error) // Returns the path of the installed binary
+}
+
+// lookPathAny returns a lookup that finds the first of names in PATH.
+func lookPathAny(names ...string) func() (string, error) {
+	return func() (string, error) {
+		for _, name := range names {
+			if path, err := exec.LookPath(name); err == nil {
+				return path, nil
+			}
+		}
+		return "", fmt.Errorf("%s not found in PATH", strings.Join(names, " or "))
+	}
+}
+
+// baseEnvTools are the programs the goskills tools use. Python and bash run skill
+// scripts; the others are only used by some tools.
+func baseEnvTools() []envTool {
+	return []envTool{
+		{Name: "python", Required: true, Lookup: lookPathAny("python3", "python")},
+		{Name: "bash", Required: true, Lookup: lookPathAny("bash")},
+		{Name: "node", Lookup: lookPathAny("node")},
+		{Name: "git", Lookup: lookPathAny("git")},
+		{Name: "chromium", Lookup: tool.FindChrome},
+	}
+}
+
+// scriptInterpreters maps script extensions to the tool that runs them.
+var scriptInterpreters = map[string]string{
+	".py":   "python",
+	".sh":   "bash",
+	".bash": "bash",
+	".js":   "node",
+	".mjs":  "node",
+}
+
+// shellBuiltins are command words that need no binary.
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "printf": true, "test": true, "[": true, "true": true, "false": true,
+	"export": true, "set": true, "read": true, "exit": true, "exec": true, "source": true, ".": true,
+	"eval": true, "if": true, "for": true, "while": true, "case": true, "command": true,
+}
+
+// envCheck is the result of checking a single tool.
+type envCheck struct {
+	Name     string
+	Found    bool
+	Path     string
+	Version  string
+	Required bool
+	Skills   []string // Skills that need the tool
+}
+
+var checkEnvCmd = &cobra.Command{
+	Use:   "check-env [skills_directory]",
+	Short: "Checks that the external tools skills depend on are installed.",
+	Long: `The check-env command looks for the programs goskills and its skills run:
+python3 (or python), bash, node, git, Chrome/Chromium for the browser tools, and
+every program named by the command of an inline tool in the skills of the given
+directory (default ~/.goskills/skills). For each it prints whether it was found,
+its version (from --version) and the skills that need it.
+
+Python, bash and every program a skill needs are required; the command exits
+with a non-zero status if any of them is missing. Use --required-only to skip
+the optional tools.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillsDir := defaultSkillsDir
+		if len(args) == 1 {
+			skillsDir = args[0]
+		}
+		skillsDir, err := expandHome(skillsDir)
+		if err != nil {
+			return err
+		}
+
+		var packages []*goskills.SkillPackage
+		if _, err := os.Stat(skillsDir); err == nil {
+			if packages, err = goskills.ParseSkillPackages(skillsDir); err != nil {
+				return fmt.Errorf("could not parse skills in directory '%s': %w", skillsDir, err)
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		checks := checkEnv(cmd.Context(), baseEnvTools(), packages, checkEnvRequiredOnly)
+		printEnvChecks(cmd.OutOrStdout(), checks)
+
+		var missing []string
+		for _, c := range checks {
+			if c.Required && !c.Found {
+				missing = append(missing, c.Name)
+			}
+		}
+		if len(missing) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("missing required tools: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	},
+}
+
+// checkEnv checks the base tools and the programs the skills need. A tool any skill
+// needs is required. With requiredOnly, optional tools are left out.
+func checkEnv(ctx context.Context, tools []envTool, packages []*goskills.SkillPackage, requiredOnly bool) []envCheck {
+	needs := skillRequirements(packages)
+	for _, t := range tools {
+		delete(needs.extra, t.Name)
+	}
+	var extra []string
+	for name := range needs.extra {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		tools = append(tools, envTool{Name: name, Lookup: lookPathAny(name)})
+	}
+
+	var checks []envCheck
+	for _, t := range tools {
+		skills := needs.bySkill[t.Name]
+		c := envCheck{Name: t.Name, Required: t.Required || len(skills) > 0, Skills: skills}
+		if requiredOnly && !c.Required {
+			continue
+		}
+		if path, err := t.Lookup(); err == nil {
+			c.Found = true
+			c.Path = path
+			c.Version = binaryVersion(ctx, path)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// skillNeeds records which skills need which tools.
+type skillNeeds struct {
+	bySkill map[string][]string // Tool name to the sorted names of the skills needing it
+	extra   map[string]bool     // Programs named by inline tool commands
+}
+
+// skillRequirements collects the interpreters of the skills' scripts and the
+// programs their inline tools run.
+func skillRequirements(packages []*goskills.SkillPackage) skillNeeds {
+	needs := skillNeeds{bySkill: make(map[string][]string), extra: make(map[string]bool)}
+	add := func(toolName, skill string) {
+		if !slices.Contains(needs.bySkill[toolName], skill) {
+			needs.bySkill[toolName] = append(needs.bySkill[toolName], skill)
+		}
+	}
+	for _, pkg := range packages {
+		if pkg == nil {
+			continue
+		}
+		for _, script := range pkg.Resources.Scripts {
+			if name, ok := scriptInterpreters[strings.ToLower(filepath.Ext(script))]; ok {
+				add(name, pkg.Meta.Name)
+			}
+		}
+		for _, inline := range pkg.Meta.Tools {
+			if program := commandProgram(inline.Command); program != "" {
+				add(program, pkg.Meta.Name)
+				needs.extra[program] = true
+			}
+		}
+	}
+	for _, skills := range needs.bySkill {
+		sort.Strings(skills)
+	}
+	return needs
+}
+
+// commandProgram returns the program a shell command runs: its first word after
+// any VAR=value assignments. Shell builtins, template actions and paths, such as
+// scripts shipped with the skill, yield "".
+func commandProgram(command string) string {
+	for _, word := range strings.Fields(command) {
+		if strings.Contains(word, "=") && !strings.HasPrefix(word, "=") {
+			continue
+		}
+		if shellBuiltins[word] || strings.Contains(word, "{{") || strings.ContainsRune(word, '/') {
+			return ""
+		}
+		return word
+	}
+	return ""
+}
+
+// binaryVersion returns the first line printed by path --version, or "" if it fails.
+func binaryVersion(ctx context.Context, path string) string {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// printEnvChecks prints one line per tool, followed by the skills that need it.
+func printEnvChecks(w io.Writer, checks []envCheck) {
+	fmt.Fprintln(w, "--- Environment Check ---")
+	for _, c := range checks {
+		status := "found"
+		detail := c.Path
+		if c.Version != "" {
+			detail = fmt.Sprintf("%s (%s)", c.Version, c.Path)
+		}
+		if !c.Found {
+			status = "missing"
+			detail = "required"
+			if !c.Required {
+				detail = "optional"
+			}
+		}
+		fmt.Fprintf(w, "  %-8s %-12s %s\n", status, c.Name, detail)
+		if len(c.Skills) > 0 {
+			fmt.Fprintf(w, "           %-12s required by: %s\n", "", strings.Join(c.Skills, ", "))
+		}
+	}
+}
+
+func init() {
+	checkEnvCmd.Flags().BoolVar(&checkEnvRequiredOnly, "required-only", false, "Only check required tools, skipping node, git and Chromium unless a skill needs them")
+	rootCmd.AddCommand(checkEnvCmd)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFakeBinary writes an executable named name into binDir that prints version
+// when called with --version.
+func installFakeBinary(t *testing.T, binDir, name, version string) {
+	t.Helper()
+	script := "#!/bin/sh\nprintf '%s\\n' '" + version + "'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755))
+}
+
+// writeEnvSkills creates a skills directory with a skill that has a Python script
+// and an inline tool running jq.
+func writeEnvSkills(t *testing.T) string {
+	t.Helper()
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "reporter")
+	require.NoError(t, os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(`---
+name: reporter
+description: Builds reports.
+tools:
+  - name: extract
+    description: Extracts a field.
+    command: LC_ALL=C jq .field data.json
+---
+# Reporter
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "scripts", "report.py"), []byte("print('report')\n"), 0644))
+	return skillsDir
+}
+
+func runCheckEnv(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	defer func() { checkEnvRequiredOnly = false }()
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(append([]string{"check-env"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestCheckEnvCmd_AllRequiredFound(t *testing.T) {
+	binDir := t.TempDir()
+	installFakeBinary(t, binDir, "python3", "Python 3.12.0")
+	installFakeBinary(t, binDir, "bash", "GNU bash, version 5.2.21")
+	installFakeBinary(t, binDir, "jq", "jq-1.7")
+	t.Setenv("PATH", binDir)
+
+	output, err := runCheckEnv(t, writeEnvSkills(t))
+	require.NoError(t, err)
+	assert.Contains(t, output, "found    python       Python 3.12.0 ("+filepath.Join(binDir, "python3")+")")
+	assert.Contains(t, output, "found    bash         GNU bash, version 5.2.21")
+	assert.Contains(t, output, "found    jq           jq-1.7")
+	assert.Contains(t, output, "missing  node         optional")
+	assert.Contains(t, output, "missing  git          optional")
+	assert.Contains(t, output, "missing  chromium     optional")
+	assert.Equal(t, 2, bytes.Count([]byte(output), []byte("required by: reporter")), "python and jq are needed by the skill")
+}
+
+func TestCheckEnvCmd_MissingRequired(t *testing.T) {
+	binDir := t.TempDir()
+	installFakeBinary(t, binDir, "python", "Python 2.7.18")
+	t.Setenv("PATH", binDir)
+
+	output, err := runCheckEnv(t, writeEnvSkills(t))
+	assert.EqualError(t, err, "missing required tools: bash, jq")
+	assert.Contains(t, output, "found    python       Python 2.7.18")
+	assert.Contains(t, output, "missing  bash         required")
+	assert.Contains(t, output, "missing  jq           required")
+}
+
+func TestCheckEnvCmd_RequiredOnly(t *testing.T) {
+	binDir := t.TempDir()
+	installFakeBinary(t, binDir, "python3", "Python 3.12.0")
+	installFakeBinary(t, binDir, "bash", "GNU bash, version 5.2.21")
+	t.Setenv("PATH", binDir)
+
+	// Without skills only python and bash are required
+	output, err := runCheckEnv(t, "--required-only", filepath.Join(t.TempDir(), "no-skills"))
+	require.NoError(t, err)
+	assert.Contains(t, output, "python")
+	assert.Contains(t, output, "bash")
+	assert.NotContains(t, output, "node")
+	assert.NotContains(t, output, "git")
+	assert.NotContains(t, output, "chromium")
+}
+
+func TestCommandProgram(t *testing.T) {
+	testCases := map[string]string{
+		"jq .field data.json":                 "jq",
+		"LC_ALL=C FOO=bar sort -u":            "sort",
+		"echo hi":                             "",
+		"./scripts/run.sh {{.GOSKILLS_ARGS}}": "",
+		"{{.GOSKILLS_ARGS.cmd}} --help":       "",
+		"/usr/bin/env python3 x.py":           "",
+		"":                                    "",
+	}
+	for command, want := range testCases {
+		assert.Equal(t, want, commandProgram(command), command)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generates a shell completion script.",
+	Long: `The completion command prints a completion script for goskills-cli to stdout.
+
+To load completions in the current shell:
+
+  bash:       source <(goskills-cli completion bash)
+  zsh:        source <(goskills-cli completion zsh)
+  fish:       goskills-cli completion fish | source
+  powershell: goskills-cli completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		return fmt.Errorf("unsupported shell '%s'", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
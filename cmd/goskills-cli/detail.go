@@ -41,6 +41,9 @@ var detailCmd = &cobra.Command{
 		if skillPackage.Meta.License != "" {
 			fmt.Printf("License: %s\n", skillPackage.Meta.License)
 		}
+		if len(skillPackage.Meta.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(skillPackage.Meta.Tags, ", "))
+		}
 
 		fmt.Println("\n--- SKILL.md Body ---")
 		fmt.Println(skillPackage.Body) // Directly print the raw markdown body
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var diffOutput string
+
+// fieldChange describes a metadata field whose value differs between two skill versions.
+type fieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// skillDiff is the machine-readable summary emitted by `diff --output json`.
+type skillDiff struct {
+	Old          string        `json:"old"`
+	New          string        `json:"new"`
+	Fields       []fieldChange `json:"fields"`
+	BodyChanged  bool          `json:"body_changed"`
+	AddedLines   []string      `json:"added_lines"`
+	RemovedLines []string      `json:"removed_lines"`
+
+	bodyDiffs []diffmatchpatch.Diff
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old_skill_directory> <new_skill_directory>",
+	Short: "Compares two versions of a skill package.",
+	Long: `The diff command parses two skill packages and reports which metadata
+fields changed and a line-based diff of the SKILL.md body.
+Use --output json to emit a machine-readable change summary.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffOutput != "text" && diffOutput != "json" {
+			return fmt.Errorf("unsupported output format '%s' (expected text or json)", diffOutput)
+		}
+
+		oldPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+		newPackage, err := parseSkillDir(args[1])
+		if err != nil {
+			return err
+		}
+
+		result := diffSkillPackages(oldPackage, newPackage)
+		if diffOutput == "json" {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+
+		printSkillDiff(cmd.OutOrStdout(), result)
+		return nil
+	},
+}
+
+// parseSkillDir resolves dir to an absolute path and parses the skill package in it.
+func parseSkillDir(dir string) (*goskills.SkillPackage, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for %s: %w", dir, err)
+	}
+
+	skillPackage, err := goskills.ParseSkillPackage(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse skill package %s: %w", dir, err)
+	}
+	return skillPackage, nil
+}
+
+// diffSkillPackages compares the metadata and body of two skill packages.
+func diffSkillPackages(oldPackage, newPackage *goskills.SkillPackage) *skillDiff {
+	result := &skillDiff{
+		Old:          oldPackage.Path,
+		New:          newPackage.Path,
+		Fields:       []fieldChange{},
+		AddedLines:   []string{},
+		RemovedLines: []string{},
+	}
+
+	oldMeta, newMeta := oldPackage.Meta, newPackage.Meta
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", oldMeta.Name, newMeta.Name},
+		{"description", oldMeta.Description, newMeta.Description},
+		{"allowed-tools", strings.Join(oldMeta.AllowedTools, ", "), strings.Join(newMeta.AllowedTools, ", ")},
+		{"model", oldMeta.Model, newMeta.Model},
+		{"author", oldMeta.Author, newMeta.Author},
+		{"version", oldMeta.Version, newMeta.Version},
+		{"license", oldMeta.License, newMeta.License},
+		{"tags", strings.Join(oldMeta.Tags, ", "), strings.Join(newMeta.Tags, ", ")},
+	}
+	for _, f := range fields {
+		if f.old != f.new {
+			result.Fields = append(result.Fields, fieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+
+	if oldPackage.Body == newPackage.Body {
+		return result
+	}
+	result.BodyChanged = true
+
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldPackage.Body, newPackage.Body)
+	result.bodyDiffs = dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+	for _, d := range result.bodyDiffs {
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			result.AddedLines = append(result.AddedLines, splitDiffLines(d.Text)...)
+		case diffmatchpatch.DiffDelete:
+			result.RemovedLines = append(result.RemovedLines, splitDiffLines(d.Text)...)
+		}
+	}
+
+	return result
+}
+
+// printSkillDiff writes a human-readable rendering of result to w.
+func printSkillDiff(w io.Writer, result *skillDiff) {
+	fmt.Fprintf(w, "--- %s\n", result.Old)
+	fmt.Fprintf(w, "+++ %s\n", result.New)
+
+	fmt.Fprintln(w, "\n--- Metadata ---")
+	if len(result.Fields) == 0 {
+		fmt.Fprintln(w, "No metadata changes.")
+	}
+	for _, f := range result.Fields {
+		fmt.Fprintf(w, "%s:\n", f.Field)
+		fmt.Fprintf(w, "  - %s\n", f.Old)
+		fmt.Fprintf(w, "  + %s\n", f.New)
+	}
+
+	fmt.Fprintln(w, "\n--- SKILL.md Body ---")
+	if !result.BodyChanged {
+		fmt.Fprintln(w, "No body changes.")
+		return
+	}
+	for _, d := range result.bodyDiffs {
+		prefix := " "
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		}
+		for _, line := range splitDiffLines(d.Text) {
+			fmt.Fprintf(w, "%s %s\n", prefix, line)
+		}
+	}
+}
+
+// splitDiffLines splits a line-mode diff chunk into its individual lines.
+func splitDiffLines(text string) []string {
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "text", "Output format: text or json")
+	rootCmd.AddCommand(diffCmd)
+}
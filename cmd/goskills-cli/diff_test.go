@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runDiff(t *testing.T, args ...string) string {
+	t.Helper()
+	defer func() { diffOutput = "text" }()
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(append([]string{"diff"}, args...))
+	require.NoError(t, rootCmd.Execute())
+	return buf.String()
+}
+
+func TestDiffCmd_Text(t *testing.T) {
+	output := runDiff(t, "testdata/diff/old", "testdata/diff/new")
+
+	assert.Contains(t, output, "description:\n  - Greets the user.\n  + Greets the user by name.")
+	assert.Contains(t, output, "allowed-tools:\n  - read_file\n  + read_file, write_file")
+	assert.Contains(t, output, "version:\n  - 1.0.0\n  + 1.1.0")
+	assert.NotContains(t, output, "name:\n")
+	assert.Contains(t, output, "- Say hello to the user.\n")
+	assert.Contains(t, output, "+ Say hello to the user by name.\n")
+	assert.Contains(t, output, "  Keep it short.\n")
+}
+
+func TestDiffCmd_JSON(t *testing.T) {
+	output := runDiff(t, "testdata/diff/old", "testdata/diff/new", "--output", "json")
+
+	var result skillDiff
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+	fields := map[string]fieldChange{}
+	for _, f := range result.Fields {
+		fields[f.Field] = f
+	}
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "1.0.0", fields["version"].Old)
+	assert.Equal(t, "1.1.0", fields["version"].New)
+	assert.Contains(t, fields, "description")
+	assert.Contains(t, fields, "allowed-tools")
+
+	assert.True(t, result.BodyChanged)
+	assert.Equal(t, []string{"Say hello to the user by name."}, result.AddedLines)
+	assert.Equal(t, []string{"Say hello to the user."}, result.RemovedLines)
+}
+
+func TestDiffCmd_Identical(t *testing.T) {
+	output := runDiff(t, "testdata/diff/old", "testdata/diff/old")
+
+	assert.Contains(t, output, "No metadata changes.")
+	assert.Contains(t, output, "No body changes.")
+}
+
+func TestDiffCmd_InvalidOutput(t *testing.T) {
+	defer func() { diffOutput = "text" }()
+
+	rootCmd.SetOut(new(bytes.Buffer))
+	rootCmd.SetErr(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{"diff", "testdata/diff/old", "testdata/diff/new", "--output", "yaml"})
+	assert.Error(t, rootCmd.Execute())
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+)
+
+const defaultEmbeddingModel = openai.SmallEmbedding3
+
+// newEmbedder creates the embeddings client from OPENAI_API_KEY and OPENAI_API_BASE.
+// Tests replace it with a fake.
+var newEmbedder = func() (goskills.EmbeddingClient, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+	config := openai.DefaultConfig(apiKey)
+	if base := os.Getenv("OPENAI_API_BASE"); base != "" {
+		config.BaseURL = base
+	}
+	return openai.NewClientWithConfig(config), nil
+}
+
+// skillEmbedding is the embedding of one skill's name and description.
+type skillEmbedding struct {
+	skillIndexEntry
+	Embedding []float32 `json:"embedding"`
+}
+
+// embeddingStore is the content of ~/.goskills/embeddings.json.
+type embeddingStore struct {
+	Model  string           `json:"model"`
+	Skills []skillEmbedding `json:"skills"`
+}
+
+// skillMatch is a skill ranked by similarity to a query.
+type skillMatch struct {
+	skillIndexEntry
+	Score float64 `json:"score"`
+}
+
+// embeddingText is the text embedded for a skill.
+func embeddingText(entry skillIndexEntry) string {
+	return entry.Name + ": " + entry.Description
+}
+
+// embedTexts returns one embedding per input, in input order.
+func embedTexts(ctx context.Context, client goskills.EmbeddingClient, model string, inputs []string) ([][]float32, error) {
+	resp, err := client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: inputs,
+		Model: openai.EmbeddingModel(model),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	if len(resp.Data) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(resp.Data))
+	}
+
+	vectors := make([][]float32, len(inputs))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(inputs) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// buildEmbeddingStore embeds every skill in entries.
+func buildEmbeddingStore(ctx context.Context, client goskills.EmbeddingClient, model string, entries []skillIndexEntry) (*embeddingStore, error) {
+	store := &embeddingStore{Model: model, Skills: []skillEmbedding{}}
+	if len(entries) == 0 {
+		return store, nil
+	}
+
+	inputs := make([]string, len(entries))
+	for i, entry := range entries {
+		inputs[i] = embeddingText(entry)
+	}
+	vectors, err := embedTexts(ctx, client, model, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		store.Skills = append(store.Skills, skillEmbedding{skillIndexEntry: entry, Embedding: vectors[i]})
+	}
+	return store, nil
+}
+
+// rankSkills returns the skills whose similarity to query is at least threshold,
+// most similar first, limited to top results when top > 0.
+func rankSkills(store *embeddingStore, query []float32, top int, threshold float64) []skillMatch {
+	matches := []skillMatch{}
+	for _, skill := range store.Skills {
+		score := goskills.CosineSimilarity(query, skill.Embedding)
+		if score >= threshold {
+			matches = append(matches, skillMatch{skillIndexEntry: skill.skillIndexEntry, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if top > 0 && len(matches) > top {
+		matches = matches[:top]
+	}
+	return matches
+}
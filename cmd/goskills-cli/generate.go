@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	generateDescription string
+	generateName        string
+	generateOutputDir   string
+	generateModel       string
+)
+
+// newChatClient creates the chat client from OPENAI_API_KEY and OPENAI_API_BASE.
+// Tests replace it with a fake.
+var newChatClient = func() (goskills.OpenAIChatClient, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+	config := openai.DefaultConfig(apiKey)
+	if base := os.Getenv("OPENAI_API_BASE"); base != "" {
+		config.BaseURL = base
+	}
+	return openai.NewClientWithConfig(config), nil
+}
+
+// skillNamePattern is the form of a skill name: lowercase words joined by hyphens.
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+const generateSystemPrompt = `You write Claude skills. A skill is a directory containing:
+
+- SKILL.md: YAML frontmatter with "name" (lowercase words joined by hyphens) and
+  "description" (one or two sentences saying what the skill does and when to use it),
+  optionally "allowed-tools", followed by a Markdown body with step-by-step instructions
+  for the assistant that uses the skill.
+- scripts/: optional helper scripts (Python .py or shell .sh) the instructions refer to.
+  Each script becomes a tool named run_<path with non-alphanumerics replaced by _>,
+  e.g. scripts/convert.py is run with run_scripts_convert_py.
+
+The assistant can also use these built-in tools: run_shell_code, run_python_code,
+read_file, write_file, web_fetch, wikipedia_search and tavily_search.
+
+Reply with a single JSON object and nothing else:
+{
+  "name": "skill-name",
+  "description": "What the skill does and when to use it.",
+  "allowed_tools": ["optional list of built-in tool names"],
+  "body": "# Title\n\nMarkdown instructions...",
+  "scripts": [{"path": "scripts/helper.py", "content": "..."}]
+}`
+
+// generatedScript is a script file in the LLM's reply.
+type generatedScript struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// generatedSkill is the JSON reply the LLM is asked for.
+type generatedSkill struct {
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	AllowedTools []string          `json:"allowed_tools"`
+	Body         string            `json:"body"`
+	Scripts      []generatedScript `json:"scripts"`
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generates a new skill from a description using an LLM.",
+	Long: `The generate command asks an OpenAI-compatible model to write a complete skill
+(SKILL.md and any helper scripts) from a description, writes it to
+<output-dir>/<name> and validates the result by parsing it.
+
+Requires OPENAI_API_KEY; OPENAI_API_BASE selects a compatible endpoint.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.TrimSpace(generateDescription) == "" {
+			return errors.New("--description is required")
+		}
+		if generateName != "" && !skillNamePattern.MatchString(generateName) {
+			return fmt.Errorf("invalid --name '%s': use lowercase words joined by hyphens", generateName)
+		}
+		client, err := newChatClient()
+		if err != nil {
+			return err
+		}
+
+		skillPackage, scripts, err := generateSkill(cmd.Context(), client, generateModel, generateDescription, generateName)
+		if err != nil {
+			return err
+		}
+		skillDir, err := writeGeneratedSkill(generateOutputDir, skillPackage, scripts)
+		if err != nil {
+			return err
+		}
+
+		parsed, err := goskills.ParseSkillPackage(skillDir)
+		if err != nil {
+			return fmt.Errorf("generated skill in %s is invalid: %w", skillDir, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Generated skill '%s' in %s (%d scripts)\n", parsed.Meta.Name, skillDir, len(parsed.Resources.Scripts))
+		return nil
+	},
+}
+
+// generateSkill asks the LLM for a skill matching description. A non-empty name replaces
+// the name the LLM chose. It returns the skill and the contents of its scripts by path.
+func generateSkill(ctx context.Context, client goskills.OpenAIChatClient, model, description, name string) (*goskills.SkillPackage, map[string]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	prompt := "Write a skill for the following description:\n\n" + description
+	if name != "" {
+		prompt += "\n\nName the skill " + name + "."
+	}
+	resp, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: generateSystemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate skill: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, nil, errors.New("LLM returned no choices")
+	}
+
+	var generated generatedSkill
+	if err := json.Unmarshal([]byte(stripCodeFence(resp.Choices[0].Message.Content)), &generated); err != nil {
+		return nil, nil, fmt.Errorf("LLM reply is not a valid skill JSON object: %w", err)
+	}
+	if name != "" {
+		generated.Name = name
+	}
+	if !skillNamePattern.MatchString(generated.Name) {
+		return nil, nil, fmt.Errorf("LLM chose an invalid skill name '%s'", generated.Name)
+	}
+	if generated.Description == "" || strings.TrimSpace(generated.Body) == "" {
+		return nil, nil, errors.New("LLM reply is missing the description or body")
+	}
+
+	skillPackage := &goskills.SkillPackage{
+		Meta: goskills.SkillMeta{
+			Name:         generated.Name,
+			Description:  generated.Description,
+			AllowedTools: generated.AllowedTools,
+		},
+		Body: strings.TrimSpace(generated.Body),
+	}
+	scripts := make(map[string]string)
+	for _, script := range generated.Scripts {
+		path := filepath.ToSlash(filepath.Clean(script.Path))
+		if !strings.HasPrefix(path, "scripts/") || filepath.IsAbs(script.Path) {
+			return nil, nil, fmt.Errorf("generated script '%s' must be inside scripts/", script.Path)
+		}
+		scripts[path] = script.Content
+		skillPackage.Resources.Scripts = append(skillPackage.Resources.Scripts, path)
+	}
+	return skillPackage, scripts, nil
+}
+
+// writeGeneratedSkill writes SKILL.md and the scripts to outputDir/<name> and returns
+// that directory. It refuses to overwrite an existing directory.
+func writeGeneratedSkill(outputDir string, skillPackage *goskills.SkillPackage, scripts map[string]string) (string, error) {
+	skillDir := filepath.Join(outputDir, skillPackage.Meta.Name)
+	if _, err := os.Stat(skillDir); err == nil {
+		return "", fmt.Errorf("%s already exists", skillDir)
+	}
+
+	frontmatter, err := yaml.Marshal(skillPackage.Meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", skillDir, err)
+	}
+	content := "---\n" + string(frontmatter) + "---\n\n" + skillPackage.Body + "\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write SKILL.md: %w", err)
+	}
+
+	for _, path := range skillPackage.Resources.Scripts {
+		scriptPath := filepath.Join(skillDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(scriptPath, []byte(scripts[path]), 0755); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return skillDir, nil
+}
+
+// stripCodeFence removes a Markdown code fence some models wrap JSON replies in.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	return strings.TrimSpace(strings.TrimSuffix(s, "```"))
+}
+
+func init() {
+	generateCmd.Flags().StringVar(&generateDescription, "description", "", "What the skill should do (required)")
+	generateCmd.Flags().StringVar(&generateName, "name", "", "Name of the skill (default: chosen by the LLM)")
+	generateCmd.Flags().StringVar(&generateOutputDir, "output-dir", ".", "Directory to create the skill directory in")
+	generateCmd.Flags().StringVar(&generateModel, "model", "gpt-4o", "OpenAI-compatible model name")
+	rootCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChatClient answers every request with reply and records the requests.
+type fakeChatClient struct {
+	reply    string
+	requests []openai.ChatCompletionRequest
+}
+
+func (f *fakeChatClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	f.requests = append(f.requests, req)
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: f.reply}}},
+	}, nil
+}
+
+func useFakeChatClient(t *testing.T, reply string) *fakeChatClient {
+	t.Helper()
+	fake := &fakeChatClient{reply: reply}
+	old := newChatClient
+	newChatClient = func() (goskills.OpenAIChatClient, error) { return fake, nil }
+	t.Cleanup(func() {
+		newChatClient = old
+		generateDescription = ""
+		generateName = ""
+		generateOutputDir = "."
+		generateModel = "gpt-4o"
+	})
+	return fake
+}
+
+const generatedSkillReply = "```json\n" + `{
+  "name": "csv-summarizer",
+  "description": "Summarizes CSV files. Use when the user asks for statistics about a CSV file.",
+  "allowed_tools": ["read_file", "run_python_code"],
+  "body": "# CSV Summarizer\n\nRun run_scripts_summarize_py with the path of the CSV file and report the result.",
+  "scripts": [{"path": "scripts/summarize.py", "content": "import sys\nprint(open(sys.argv[1]).read().count('\\n'))\n"}]
+}` + "\n```"
+
+func TestGenerateCmd(t *testing.T) {
+	fake := useFakeChatClient(t, generatedSkillReply)
+	outputDir := t.TempDir()
+
+	output, err := runCLI(t, "generate", "--description", "summarize CSV files", "--output-dir", outputDir, "--model", "test-model")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Generated skill 'csv-summarizer'")
+
+	require.Len(t, fake.requests, 1)
+	assert.Equal(t, "test-model", fake.requests[0].Model)
+	assert.Contains(t, fake.requests[0].Messages[1].Content, "summarize CSV files")
+
+	skillPackage, err := goskills.ParseSkillPackage(filepath.Join(outputDir, "csv-summarizer"))
+	require.NoError(t, err)
+	assert.Equal(t, "Summarizes CSV files. Use when the user asks for statistics about a CSV file.", skillPackage.Meta.Description)
+	assert.Equal(t, []string{"read_file", "run_python_code"}, skillPackage.Meta.AllowedTools)
+	assert.Contains(t, skillPackage.Body, "# CSV Summarizer")
+	assert.Equal(t, []string{"scripts/summarize.py"}, skillPackage.Resources.Scripts)
+
+	info, err := os.Stat(filepath.Join(outputDir, "csv-summarizer", "scripts", "summarize.py"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100, "scripts are executable")
+
+	// An existing skill is not overwritten
+	_, err = runCLI(t, "generate", "--description", "summarize CSV files", "--output-dir", outputDir)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestGenerateCmd_Name(t *testing.T) {
+	fake := useFakeChatClient(t, generatedSkillReply)
+	outputDir := t.TempDir()
+
+	_, err := runCLI(t, "generate", "--description", "summarize CSV files", "--name", "csv-stats", "--output-dir", outputDir)
+	require.NoError(t, err)
+	assert.Contains(t, fake.requests[0].Messages[1].Content, "Name the skill csv-stats.")
+
+	skillPackage, err := goskills.ParseSkillPackage(filepath.Join(outputDir, "csv-stats"))
+	require.NoError(t, err)
+	assert.Equal(t, "csv-stats", skillPackage.Meta.Name)
+
+	_, err = runCLI(t, "generate", "--description", "summarize CSV files", "--name", "CSV Stats")
+	assert.ErrorContains(t, err, "invalid --name")
+}
+
+func TestGenerateSkill_InvalidReplies(t *testing.T) {
+	for name, reply := range map[string]string{
+		"not json":       "Here is your skill!",
+		"bad name":       `{"name": "My Skill", "description": "d", "body": "b"}`,
+		"missing body":   `{"name": "my-skill", "description": "d"}`,
+		"escaping paths": `{"name": "my-skill", "description": "d", "body": "b", "scripts": [{"path": "../evil.sh", "content": "rm -rf /"}]}`,
+	} {
+		_, _, err := generateSkill(context.Background(), &fakeChatClient{reply: reply}, "test-model", "anything", "")
+		assert.Error(t, err, name)
+	}
+}
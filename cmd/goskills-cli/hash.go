@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var hashCmd = &cobra.Command{
+	Use:   "hash <skill_directory>",
+	Short: "Prints the integrity hash of a skill package.",
+	Long: `The hash command prints a SHA-256 hash computed over a skill's frontmatter,
+body and every script, reference and asset file. Publish it alongside a skill so
+users can check their copy with the verify command.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		hash, err := skillPackage.Hash()
+		if err != nil {
+			return fmt.Errorf("failed to hash skill: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), hash)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hashCmd)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func copySkillFixture(t *testing.T, src string) string {
+	t.Helper()
+	dst := t.TempDir()
+	data, err := os.ReadFile(filepath.Join(src, "SKILL.md"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "SKILL.md"), data, 0644))
+	return dst
+}
+
+func TestHashAndVerifyCmd(t *testing.T) {
+	skillDir := copySkillFixture(t, "testdata/diff/old")
+
+	output, err := runCLI(t, "hash", skillDir)
+	require.NoError(t, err)
+	hash := strings.TrimSpace(output)
+	assert.Len(t, hash, 64)
+
+	output, err = runCLI(t, "verify", skillDir, strings.ToUpper(hash))
+	require.NoError(t, err)
+	assert.Contains(t, output, "OK: skill 'greeter' matches "+hash)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "scripts", "extra.sh"), []byte("echo hi\n"), 0755))
+
+	_, err = runCLI(t, "verify", skillDir, hash)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hash mismatch")
+}
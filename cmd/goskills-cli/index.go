@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultSkillsDir      = "~/.goskills/skills"
+	defaultIndexPath      = "~/.goskills/index.json"
+	defaultEmbeddingsPath = "~/.goskills/embeddings.json"
+)
+
+var (
+	indexWithEmbeddings bool
+	indexEmbeddingModel string
+)
+
+// skillIndexEntry describes one skill in the index.
+type skillIndexEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Path        string   `json:"path"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// skillIndex is the list of installed skills saved to ~/.goskills/index.json.
+type skillIndex struct {
+	SkillsDir string            `json:"skills_dir"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Skills    []skillIndexEntry `json:"skills"`
+}
+
+var indexCmd = &cobra.Command{
+	Use:   "index [skills_directory]",
+	Short: "Builds an index of installed skills.",
+	Long: `The index command scans a skills directory (default ~/.goskills/skills) and saves
+the name, description and path of every skill to ~/.goskills/index.json.
+
+With --with-embeddings it also computes an embedding of each skill's name and
+description using the OpenAI embeddings API (OPENAI_API_KEY, OPENAI_API_BASE) and
+saves them to ~/.goskills/embeddings.json for use by 'search <query>'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillsDir := defaultSkillsDir
+		if len(args) == 1 {
+			skillsDir = args[0]
+		}
+		skillsDir, err := expandHome(skillsDir)
+		if err != nil {
+			return err
+		}
+
+		index, err := buildSkillIndex(skillsDir)
+		if err != nil {
+			return err
+		}
+		indexPath, err := expandHome(defaultIndexPath)
+		if err != nil {
+			return err
+		}
+		if err := writeJSONFile(indexPath, index); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Indexed %d skills from %s into %s\n", len(index.Skills), skillsDir, indexPath)
+
+		if !indexWithEmbeddings {
+			return nil
+		}
+
+		client, err := newEmbedder()
+		if err != nil {
+			return err
+		}
+		store, err := buildEmbeddingStore(context.Background(), client, indexEmbeddingModel, index.Skills)
+		if err != nil {
+			return err
+		}
+		embeddingsPath, err := expandHome(defaultEmbeddingsPath)
+		if err != nil {
+			return err
+		}
+		if err := writeJSONFile(embeddingsPath, store); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved embeddings for %d skills to %s\n", len(store.Skills), embeddingsPath)
+		return nil
+	},
+}
+
+// buildSkillIndex parses every skill under skillsDir, sorted by name.
+func buildSkillIndex(skillsDir string) (*skillIndex, error) {
+	packages, err := goskills.ParseSkillPackages(skillsDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse skills in directory '%s': %w", skillsDir, err)
+	}
+
+	index := &skillIndex{SkillsDir: skillsDir, UpdatedAt: time.Now().UTC(), Skills: []skillIndexEntry{}}
+	for _, pkg := range packages {
+		if pkg == nil {
+			continue
+		}
+		index.Skills = append(index.Skills, skillIndexEntry{
+			Name:        pkg.Meta.Name,
+			Description: pkg.Meta.Description,
+			Path:        pkg.Path,
+			Tags:        pkg.Meta.Tags,
+		})
+	}
+	sort.Slice(index.Skills, func(i, j int) bool { return index.Skills[i].Name < index.Skills[j].Name })
+	return index, nil
+}
+
+// loadSkillIndex reads the index written by the index command.
+func loadSkillIndex(path string) (*skillIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index skillIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse skill index %s: %w", path, err)
+	}
+	return &index, nil
+}
+
+// expandHome replaces a leading "~" in path with the user's home directory.
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// writeJSONFile writes v to path as indented JSON, creating parent directories.
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	indexCmd.Flags().BoolVar(&indexWithEmbeddings, "with-embeddings", false, "Also compute skill embeddings for semantic search")
+	indexCmd.Flags().StringVar(&indexEmbeddingModel, "embedding-model", string(defaultEmbeddingModel), "OpenAI embedding model to use")
+	rootCmd.AddCommand(indexCmd)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIndexSkills creates a skills directory holding the given name -> description skills.
+func writeIndexSkills(t *testing.T, skills map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, description := range skills {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		content := "---\nname: " + name + "\ndescription: " + description + "\n---\n# " + name + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+	}
+	return root
+}
+
+func TestIndexCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	skillsDir := writeIndexSkills(t, map[string]string{
+		"xlsx": "Create and edit spreadsheets.",
+		"pdf":  "Extract text from PDF documents.",
+	})
+
+	output, err := runCLI(t, "index", skillsDir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Indexed 2 skills")
+
+	index, err := loadSkillIndex(filepath.Join(home, ".goskills", "index.json"))
+	require.NoError(t, err)
+	assert.Equal(t, skillsDir, index.SkillsDir)
+	require.Len(t, index.Skills, 2)
+	assert.Equal(t, "pdf", index.Skills[0].Name)
+	assert.Equal(t, "Extract text from PDF documents.", index.Skills[0].Description)
+	assert.Equal(t, filepath.Join(skillsDir, "pdf"), index.Skills[0].Path)
+	assert.Equal(t, "xlsx", index.Skills[1].Name)
+
+	_, err = os.Stat(filepath.Join(home, ".goskills", "embeddings.json"))
+	assert.True(t, os.IsNotExist(err), "embeddings should only be built with --with-embeddings")
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var lintFormat string
+
+// lintFinding is a single issue reported by an external linter.
+type lintFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Level   string `json:"level"` // "error", "warning", "info" or "style"
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Linter  string `json:"linter"`
+}
+
+// lintReport is the result of linting a skill's scripts.
+type lintReport struct {
+	Skill    string        `json:"skill"`
+	Findings []lintFinding `json:"findings"`
+	Warnings []string      `json:"warnings"`
+}
+
+// errorCount returns the number of error-level findings.
+func (r *lintReport) errorCount() int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Level == "error" {
+			n++
+		}
+	}
+	return n
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <skill_directory>",
+	Short: "Checks a skill's scripts for common issues.",
+	Long: `The lint command runs shellcheck on the skill's .sh scripts and ruff
+(or pyflakes) on its .py scripts, when those tools are installed, and prints
+the issues they report. Missing linters produce a warning instead of a failure.
+The command exits with a non-zero status if any error-level issue is found.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lintFormat != "text" && lintFormat != "json" {
+			return fmt.Errorf("unsupported format '%s' (expected text or json)", lintFormat)
+		}
+
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		report := lintSkill(skillPackage)
+		if lintFormat == "json" {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			printLintReport(cmd.OutOrStdout(), report)
+		}
+
+		if n := report.errorCount(); n > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("lint found %d error(s)", n)
+		}
+		return nil
+	},
+}
+
+// lintSkill runs the available linters over the skill's shell and Python scripts.
+func lintSkill(skillPackage *goskills.SkillPackage) *lintReport {
+	report := &lintReport{Skill: skillPackage.Meta.Name, Findings: []lintFinding{}, Warnings: []string{}}
+
+	var shellScripts, pythonScripts []string
+	for _, script := range skillPackage.Resources.Scripts {
+		path := filepath.Join(skillPackage.Path, script)
+		switch filepath.Ext(script) {
+		case ".sh", ".bash":
+			shellScripts = append(shellScripts, path)
+		case ".py":
+			pythonScripts = append(pythonScripts, path)
+		}
+	}
+
+	if len(shellScripts) > 0 {
+		if findings, err := runShellcheck(shellScripts); err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		} else {
+			report.Findings = append(report.Findings, findings...)
+		}
+	}
+	if len(pythonScripts) > 0 {
+		if findings, err := runPythonLinter(pythonScripts); err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		} else {
+			report.Findings = append(report.Findings, findings...)
+		}
+	}
+
+	for i := range report.Findings {
+		if rel, err := filepath.Rel(skillPackage.Path, report.Findings[i].File); err == nil && !strings.HasPrefix(rel, "..") {
+			report.Findings[i].File = rel
+		}
+	}
+	return report
+}
+
+// runLinter runs name with args and returns its stdout. Linters exit non-zero when
+// they find issues, so only failures to start the process are reported as errors.
+func runLinter(name string, args ...string) ([]byte, error) {
+	exe, err := exec.LookPath(name)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("failed to run %s: %w", name, err)
+		}
+		if stdout.Len() == 0 && stderr.Len() > 0 {
+			return nil, fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stderr.String()))
+		}
+	}
+	return stdout.Bytes(), nil
+}
+
+// runShellcheck lints shell scripts with shellcheck's JSON output.
+func runShellcheck(paths []string) ([]lintFinding, error) {
+	if _, err := exec.LookPath("shellcheck"); err != nil {
+		return nil, fmt.Errorf("shellcheck not found in PATH; skipped %d shell script(s)", len(paths))
+	}
+	out, err := runLinter("shellcheck", append([]string{"--format=json"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		File    string `json:"file"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+		Level   string `json:"level"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(out, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse shellcheck output: %w", err)
+	}
+
+	findings := make([]lintFinding, 0, len(results))
+	for _, r := range results {
+		findings = append(findings, lintFinding{
+			File:    r.File,
+			Line:    r.Line,
+			Column:  r.Column,
+			Level:   r.Level,
+			Code:    fmt.Sprintf("SC%d", r.Code),
+			Message: r.Message,
+			Linter:  "shellcheck",
+		})
+	}
+	return findings, nil
+}
+
+// runPythonLinter lints Python scripts with ruff, falling back to pyflakes.
+func runPythonLinter(paths []string) ([]lintFinding, error) {
+	if _, err := exec.LookPath("ruff"); err == nil {
+		return runRuff(paths)
+	}
+	if _, err := exec.LookPath("pyflakes"); err == nil {
+		return runPyflakes(paths)
+	}
+	return nil, fmt.Errorf("neither ruff nor pyflakes found in PATH; skipped %d Python script(s)", len(paths))
+}
+
+func runRuff(paths []string) ([]lintFinding, error) {
+	out, err := runLinter("ruff", append([]string{"check", "--output-format=json", "--no-cache"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		Filename string `json:"filename"`
+		Location struct {
+			Row    int `json:"row"`
+			Column int `json:"column"`
+		} `json:"location"`
+	}
+	if err := json.Unmarshal(out, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse ruff output: %w", err)
+	}
+
+	findings := make([]lintFinding, 0, len(results))
+	for _, r := range results {
+		findings = append(findings, lintFinding{
+			File:    r.Filename,
+			Line:    r.Location.Row,
+			Column:  r.Location.Column,
+			Level:   "error",
+			Code:    r.Code,
+			Message: r.Message,
+			Linter:  "ruff",
+		})
+	}
+	return findings, nil
+}
+
+// runPyflakes parses pyflakes output lines of the form "file:line[:col]: message".
+func runPyflakes(paths []string) ([]lintFinding, error) {
+	out, err := runLinter("pyflakes", paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []lintFinding
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 4)
+		if len(parts) < 3 {
+			continue
+		}
+		line, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		finding := lintFinding{File: parts[0], Line: line, Level: "error", Linter: "pyflakes"}
+		if col, err := strconv.Atoi(parts[2]); err == nil && len(parts) == 4 {
+			finding.Column = col
+			finding.Message = strings.TrimSpace(parts[3])
+		} else {
+			finding.Message = strings.TrimSpace(strings.Join(parts[2:], ":"))
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+func printLintReport(w io.Writer, report *lintReport) {
+	fmt.Fprintf(w, "--- Lint: %s ---\n", report.Skill)
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+	for _, f := range report.Findings {
+		location := fmt.Sprintf("%s:%d", f.File, f.Line)
+		if f.Column > 0 {
+			location += fmt.Sprintf(":%d", f.Column)
+		}
+		code := ""
+		if f.Code != "" {
+			code = " " + f.Code
+		}
+		fmt.Fprintf(w, "%s: %s [%s%s] %s\n", location, f.Level, f.Linter, code, f.Message)
+	}
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "No issues found.")
+	} else {
+		fmt.Fprintf(w, "\n%d issue(s), %d error(s)\n", len(report.Findings), report.errorCount())
+	}
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "Output format: text or json")
+	rootCmd.AddCommand(lintCmd)
+}
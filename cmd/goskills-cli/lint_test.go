@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLintSkill creates a skill with a shell script and a Python script that
+// contain well-known issues.
+func writeLintSkill(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"),
+		[]byte("---\nname: linty\ndescription: A skill with lint issues.\n---\n\n# Linty\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "bad.sh"),
+		[]byte("#!/bin/sh\necho $1\ncd $DIR\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "bad.py"),
+		[]byte("import os\nprint(undefined_name)\n"), 0644))
+	return dir
+}
+
+// installFakeLinter writes an executable named name into binDir that prints output.
+// It only uses shell builtins so tests can run with PATH limited to binDir.
+func installFakeLinter(t *testing.T, binDir, name, output string) {
+	t.Helper()
+	outputPath := filepath.Join(binDir, name+".out")
+	require.NoError(t, os.WriteFile(outputPath, []byte(output+"\n"), 0644))
+	script := "#!/bin/sh\nwhile IFS= read -r line; do printf '%s\\n' \"$line\"; done < '" + outputPath + "'\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755))
+}
+
+func runLint(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	defer func() { lintFormat = "text" }()
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs(append([]string{"lint"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestLintCmd_ReportsFindings(t *testing.T) {
+	skillDir := writeLintSkill(t)
+	shPath := filepath.Join(skillDir, "scripts", "bad.sh")
+	pyPath := filepath.Join(skillDir, "scripts", "bad.py")
+
+	binDir := t.TempDir()
+	installFakeLinter(t, binDir, "shellcheck", `[
+  {"file": "`+shPath+`", "line": 2, "column": 6, "level": "info", "code": 2086, "message": "Double quote to prevent globbing and word splitting."},
+  {"file": "`+shPath+`", "line": 3, "column": 1, "level": "warning", "code": 2164, "message": "Use 'cd ... || exit' in case cd fails."}
+]`)
+	installFakeLinter(t, binDir, "ruff", `[
+  {"code": "F401", "message": "`+"`os`"+` imported but unused", "filename": "`+pyPath+`", "location": {"row": 1, "column": 8}},
+  {"code": "F821", "message": "Undefined name `+"`undefined_name`"+`", "filename": "`+pyPath+`", "location": {"row": 2, "column": 7}}
+]`)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	output, err := runLint(t, skillDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 error(s)")
+	assert.Contains(t, output, "scripts/bad.sh:2:6: info [shellcheck SC2086] Double quote to prevent globbing and word splitting.")
+	assert.Contains(t, output, "scripts/bad.sh:3:1: warning [shellcheck SC2164]")
+	assert.Contains(t, output, "scripts/bad.py:2:7: error [ruff F821] Undefined name `undefined_name`")
+	assert.Contains(t, output, "4 issue(s), 2 error(s)")
+}
+
+func TestLintCmd_JSON(t *testing.T) {
+	skillDir := writeLintSkill(t)
+	shPath := filepath.Join(skillDir, "scripts", "bad.sh")
+
+	binDir := t.TempDir()
+	installFakeLinter(t, binDir, "shellcheck", `[{"file": "`+shPath+`", "line": 3, "column": 1, "level": "warning", "code": 2164, "message": "Use 'cd ... || exit' in case cd fails."}]`)
+	t.Setenv("PATH", binDir)
+
+	output, err := runLint(t, skillDir, "--format", "json")
+	require.NoError(t, err)
+
+	var report lintReport
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.Equal(t, "linty", report.Skill)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, lintFinding{
+		File:    filepath.Join("scripts", "bad.sh"),
+		Line:    3,
+		Column:  1,
+		Level:   "warning",
+		Code:    "SC2164",
+		Message: "Use 'cd ... || exit' in case cd fails.",
+		Linter:  "shellcheck",
+	}, report.Findings[0])
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "neither ruff nor pyflakes")
+}
+
+func TestLintCmd_MissingLinters(t *testing.T) {
+	skillDir := writeLintSkill(t)
+	t.Setenv("PATH", t.TempDir())
+
+	output, err := runLint(t, skillDir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "warning: shellcheck not found in PATH")
+	assert.Contains(t, output, "warning: neither ruff nor pyflakes found in PATH")
+	assert.Contains(t, output, "No issues found.")
+}
+
+func TestParsePyflakesOutput(t *testing.T) {
+	binDir := t.TempDir()
+	installFakeLinter(t, binDir, "pyflakes", "scripts/bad.py:1:1: 'os' imported but unused\nscripts/bad.py:2: undefined name 'undefined_name'")
+	t.Setenv("PATH", binDir)
+
+	findings, err := runPythonLinter([]string{"scripts/bad.py"})
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, 1, findings[0].Column)
+	assert.Equal(t, "'os' imported but unused", findings[0].Message)
+	assert.Equal(t, 2, findings[1].Line)
+	assert.Equal(t, "undefined name 'undefined_name'", findings[1].Message)
+	assert.Equal(t, "pyflakes", findings[1].Linter)
+}
@@ -7,11 +7,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var listTags []string
+
 var listCmd = &cobra.Command{
 	Use:   "list [path]",
 	Short: "Lists all valid skills in a given directory.",
 	Long: `The list command scans a directory for subdirectories that are valid 
-Claude skill packages and prints a summary of each one found.`,
+Claude skill packages and prints a summary of each one found.
+Use --tag to only list skills carrying at least one of the given tags.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		skillsRoot := args[0]
@@ -21,13 +24,21 @@ Claude skill packages and prints a summary of each one found.`,
 			return fmt.Errorf("could not parse skills in directory '%s': %w", skillsRoot, err)
 		}
 
+		filter := goskills.DiscoveryFilter{Tags: listTags}
+		var matched []*goskills.SkillPackage
+		for _, skillPackage := range packages {
+			if filter.Matches(skillPackage.Meta) {
+				matched = append(matched, skillPackage)
+			}
+		}
+
 		fmt.Printf("--- Skills found in %s ---\n", skillsRoot)
-		if len(packages) == 0 {
+		if len(matched) == 0 {
 			fmt.Println("No valid skills found.")
 			return nil
 		}
 
-		for _, skillPackage := range packages {
+		for _, skillPackage := range matched {
 			fmt.Printf("- %-20s: %s\n", skillPackage.Meta.Name, skillPackage.Meta.Description)
 		}
 
@@ -36,5 +47,6 @@ Claude skill packages and prints a summary of each one found.`,
 }
 
 func init() {
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "Only list skills with at least one of these tags (repeatable or comma-separated)")
 	rootCmd.AddCommand(listCmd)
 }
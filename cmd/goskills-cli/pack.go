@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var (
+	packTag    string
+	packPush   bool
+	packDryRun bool
+)
+
+// packBaseImage is the image skills are packed on top of.
+const packBaseImage = "python:3-slim"
+
+// packResourceDirs are the skill package directories copied into the image.
+var packResourceDirs = []string{"scripts", "references", "assets", "templates"}
+
+// dockerCommand builds the docker invocations used by pack; replaceable in tests.
+var dockerCommand = func(args ...string) *exec.Cmd {
+	return exec.Command("docker", args...)
+}
+
+var packCmd = &cobra.Command{
+	Use:   "pack <skill_directory>",
+	Short: "Builds an OCI image of a skill with Docker.",
+	Long: `The pack command generates a Dockerfile for a skill package and builds it
+with the Docker CLI. The image is based on python:3-slim, contains SKILL.md (or skill.md) and the
+skill's resource directories under /skill, installs requirements.txt with pip when
+present, and is labelled with the skill's name, version and description.
+
+Use --dry-run to print the Dockerfile without building, and --push to push the
+image to its registry after building.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		dockerfile := generateDockerfile(skillPackage)
+		out := cmd.OutOrStdout()
+		if packDryRun {
+			fmt.Fprint(out, dockerfile)
+			return nil
+		}
+
+		tag := packTag
+		if tag == "" {
+			tag = defaultImageTag(skillPackage)
+		}
+
+		build := dockerCommand("build", "--tag", tag, "--file", "-", skillPackage.Path)
+		build.Stdin = strings.NewReader(dockerfile)
+		build.Stdout = out
+		build.Stderr = cmd.ErrOrStderr()
+		if err := build.Run(); err != nil {
+			return fmt.Errorf("docker build failed: %w", err)
+		}
+		fmt.Fprintf(out, "Built image %s\n", tag)
+
+		if packPush {
+			push := dockerCommand("push", tag)
+			push.Stdout = out
+			push.Stderr = cmd.ErrOrStderr()
+			if err := push.Run(); err != nil {
+				return fmt.Errorf("docker push failed: %w", err)
+			}
+			fmt.Fprintf(out, "Pushed image %s\n", tag)
+		}
+		return nil
+	},
+}
+
+// generateDockerfile returns a Dockerfile that packages the skill on top of packBaseImage.
+// It copies the skill's SKILL.md, or skill.md for skills in the OpenAI format. Only resource directories that exist are copied, and pip runs only when the skill
+// ships a requirements.txt at its root or in scripts/.
+func generateDockerfile(skill *goskills.SkillPackage) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "FROM %s\n\n", packBaseImage)
+	fmt.Fprintf(&sb, "LABEL goskills.skill.name=%s \\\n", strconv.Quote(skill.Meta.Name))
+	fmt.Fprintf(&sb, "      goskills.skill.version=%s \\\n", strconv.Quote(skill.Meta.Version))
+	fmt.Fprintf(&sb, "      goskills.skill.description=%s\n\n", strconv.Quote(skill.Meta.Description))
+	sb.WriteString("WORKDIR /skill\n")
+	fmt.Fprintf(&sb, "COPY %s ./\n", skillFileName(skill.Path))
+	for _, dir := range packResourceDirs {
+		if info, err := os.Stat(filepath.Join(skill.Path, dir)); err == nil && info.IsDir() {
+			fmt.Fprintf(&sb, "COPY %s/ ./%s/\n", dir, dir)
+		}
+	}
+	for _, requirements := range []string{"requirements.txt", "scripts/requirements.txt"} {
+		if _, err := os.Stat(filepath.Join(skill.Path, requirements)); err != nil {
+			continue
+		}
+		if requirements == "requirements.txt" {
+			sb.WriteString("COPY requirements.txt ./\n")
+		}
+		fmt.Fprintf(&sb, "RUN pip install --no-cache-dir -r %s\n", requirements)
+		break
+	}
+	return sb.String()
+}
+
+// skillFileName returns the name of the skill's markdown file in dir: SKILL.md, or
+// skill.md when only that exists. Names are compared exactly, as Docker does, even
+// on case-insensitive file systems.
+func skillFileName(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "SKILL.md"
+	}
+	name := "SKILL.md"
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "SKILL.md":
+			return "SKILL.md"
+		case "skill.md":
+			name = "skill.md"
+		}
+	}
+	return name
+}
+
+var (
+	invalidImageNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+	imageNameSeparators   = regexp.MustCompile(`[._-]{2,}`)
+	invalidImageTagChars  = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// defaultImageTag names the image after the skill, tagged with its version or "latest".
+// Both are rewritten into a valid Docker reference: the name is lowercased, and runs of
+// other characters become "-".
+func defaultImageTag(skill *goskills.SkillPackage) string {
+	name := invalidImageNameChars.ReplaceAllString(strings.ToLower(skill.Meta.Name), "-")
+	name = strings.Trim(imageNameSeparators.ReplaceAllString(name, "-"), "._-")
+	if name == "" {
+		name = "skill"
+	}
+
+	version := strings.TrimLeft(invalidImageTagChars.ReplaceAllString(skill.Meta.Version, "-"), ".-")
+	if len(version) > 128 {
+		version = version[:128]
+	}
+	if version == "" {
+		version = "latest"
+	}
+	return name + ":" + version
+}
+
+func init() {
+	packCmd.Flags().StringVarP(&packTag, "tag", "t", "", "Image name and tag, e.g. my-skill:v1.0 (default: <name>:<version>, lowercased and made valid for Docker)")
+	packCmd.Flags().BoolVar(&packPush, "push", false, "Push the image to its registry after building")
+	packCmd.Flags().BoolVar(&packDryRun, "dry-run", false, "Print the generated Dockerfile without building")
+	rootCmd.AddCommand(packCmd)
+}
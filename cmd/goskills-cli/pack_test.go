@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePackSkill creates a skill with a Python script and a requirements.txt.
+func writePackSkill(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"),
+		[]byte("---\nname: pdf-tools\ndescription: Works with \"PDF\" files.\nversion: 1.2.0\n---\nUse the scripts."), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "extract.py"), []byte("print('hi')\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "requirements.txt"), []byte("pypdf\n"), 0644))
+	return dir
+}
+
+func resetPackFlags(t *testing.T) {
+	t.Cleanup(func() {
+		packTag = ""
+		packPush = false
+		packDryRun = false
+	})
+}
+
+func TestPackCmd_DryRun(t *testing.T) {
+	resetPackFlags(t)
+	skillDir := writePackSkill(t)
+
+	output, err := runCLI(t, "pack", skillDir, "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, `FROM python:3-slim
+
+LABEL goskills.skill.name="pdf-tools" \
+      goskills.skill.version="1.2.0" \
+      goskills.skill.description="Works with \"PDF\" files."
+
+WORKDIR /skill
+COPY SKILL.md ./
+COPY scripts/ ./scripts/
+RUN pip install --no-cache-dir -r scripts/requirements.txt
+`, output)
+}
+
+func TestGenerateDockerfile_RootRequirements(t *testing.T) {
+	skillDir := writePackSkill(t)
+	require.NoError(t, os.Rename(filepath.Join(skillDir, "scripts", "requirements.txt"), filepath.Join(skillDir, "requirements.txt")))
+	require.NoError(t, os.Mkdir(filepath.Join(skillDir, "references"), 0755))
+	skillPackage, err := parseSkillDir(skillDir)
+	require.NoError(t, err)
+
+	dockerfile := generateDockerfile(skillPackage)
+	assert.Contains(t, dockerfile, "COPY references/ ./references/\n")
+	assert.NotContains(t, dockerfile, "assets/")
+	assert.Contains(t, dockerfile, "COPY requirements.txt ./\nRUN pip install --no-cache-dir -r requirements.txt\n")
+}
+
+func TestGenerateDockerfile_OpenAISkill(t *testing.T) {
+	skillDir := filepath.Join(t.TempDir(), "Report_Writer")
+	require.NoError(t, os.Mkdir(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "skill.md"),
+		[]byte("# Report Writer\n\nWrites reports.\n\n## Usage\n\nAsk for a report.\n"), 0644))
+	skillPackage, err := parseSkillDir(skillDir)
+	require.NoError(t, err)
+
+	dockerfile := generateDockerfile(skillPackage)
+	assert.Contains(t, dockerfile, "COPY skill.md ./\n")
+	assert.NotContains(t, dockerfile, "SKILL.md")
+	assert.Equal(t, "report-writer:latest", defaultImageTag(skillPackage))
+}
+
+func TestPackCmd_BuildAndPush(t *testing.T) {
+	resetPackFlags(t)
+	skillDir := writePackSkill(t)
+	logDir := t.TempDir()
+
+	// Record each docker invocation's arguments, and the Dockerfile sent to build
+	var calls [][]string
+	old := dockerCommand
+	dockerCommand = func(args ...string) *exec.Cmd {
+		calls = append(calls, args)
+		stdin := filepath.Join(logDir, args[0]+".stdin")
+		return exec.Command("sh", "-c", `cat > "$0"`, stdin)
+	}
+	t.Cleanup(func() { dockerCommand = old })
+
+	output, err := runCLI(t, "pack", skillDir, "--tag", "registry.example.com/pdf-tools:v1", "--push")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Built image registry.example.com/pdf-tools:v1")
+	assert.Contains(t, output, "Pushed image registry.example.com/pdf-tools:v1")
+
+	require.Len(t, calls, 2)
+	assert.Equal(t, []string{"build", "--tag", "registry.example.com/pdf-tools:v1", "--file", "-", skillDir}, calls[0])
+	assert.Equal(t, []string{"push", "registry.example.com/pdf-tools:v1"}, calls[1])
+	dockerfile, err := os.ReadFile(filepath.Join(logDir, "build.stdin"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(dockerfile), "FROM python:3-slim\n"))
+}
+
+func TestDefaultImageTag(t *testing.T) {
+	skillPackage, err := parseSkillDir(writePackSkill(t))
+	require.NoError(t, err)
+	assert.Equal(t, "pdf-tools:1.2.0", defaultImageTag(skillPackage))
+
+	skillPackage.Meta.Version = ""
+	assert.Equal(t, "pdf-tools:latest", defaultImageTag(skillPackage))
+
+	testCases := []struct {
+		name, version, want string
+	}{
+		{"PDF Tools", "1.2.0", "pdf-tools:1.2.0"},
+		{"My__Skill!", "v2", "my-skill:v2"},
+		{"--édition--", "1.0+build.5", "dition:1.0-build.5"},
+		{"???", ".hidden", "skill:hidden"},
+	}
+	for _, tc := range testCases {
+		skillPackage.Meta.Name, skillPackage.Meta.Version = tc.name, tc.version
+		assert.Equal(t, tc.want, defaultImageTag(skillPackage), tc.name)
+	}
+}
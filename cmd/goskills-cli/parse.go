@@ -40,6 +40,9 @@ var parseCmd = &cobra.Command{
 		if skillPackage.Meta.License != "" {
 			fmt.Printf("License: %s\n", skillPackage.Meta.License)
 		}
+		if len(skillPackage.Meta.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(skillPackage.Meta.Tags, ", "))
+		}
 
 		fmt.Println("\n--- Body Snippet (first 500 chars) ---")
 		if len(skillPackage.Body) > 500 {
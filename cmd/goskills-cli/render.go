@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gomarkdown/markdown"
+	mdhtml "github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+	"github.com/pkg/browser"
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+// renderWidth is the column width used for terminal output.
+const renderWidth = 80
+
+var renderFormat string
+
+// openURL opens a URL in the system browser; replaceable in tests.
+var openURL = browser.OpenURL
+
+var renderCmd = &cobra.Command{
+	Use:   "render <skill_directory>",
+	Short: "Renders a skill's SKILL.md body as HTML or for the terminal.",
+	Long: `The render command parses a skill package and renders its body so you can
+review how the instructions read.
+
+Formats:
+  terminal  formatted for an 80-column terminal (default)
+  html      written to a temporary HTML file and opened in the system browser
+  raw       the Markdown body as-is`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		switch renderFormat {
+		case "terminal":
+			_, err = io.WriteString(out, renderTerminal(skillPackage, renderWidth))
+			return err
+		case "raw":
+			_, err = io.WriteString(out, skillPackage.Body)
+			return err
+		case "html":
+			f, err := os.CreateTemp("", "goskills-render-*.html")
+			if err != nil {
+				return fmt.Errorf("failed to create HTML file: %w", err)
+			}
+			if _, err := f.Write(renderHTML(skillPackage)); err != nil {
+				f.Close()
+				return fmt.Errorf("failed to write HTML file: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write HTML file: %w", err)
+			}
+			fmt.Fprintf(out, "Rendered %s to %s\n", skillPackage.Meta.Name, f.Name())
+			if err := openURL("file://" + f.Name()); err != nil {
+				return fmt.Errorf("failed to open browser: %w", err)
+			}
+			return nil
+		default:
+			return fmt.Errorf("invalid --format '%s' (expected html, terminal or raw)", renderFormat)
+		}
+	},
+}
+
+// renderHTML renders the skill body as a standalone HTML page headed by the skill name.
+func renderHTML(skill *goskills.SkillPackage) []byte {
+	p := parser.NewWithExtensions(parser.CommonExtensions | parser.AutoHeadingIDs)
+	renderer := mdhtml.NewRenderer(mdhtml.RendererOptions{Flags: mdhtml.CommonFlags})
+	body := markdown.ToHTML([]byte(skill.Body), p, renderer)
+
+	name := html.EscapeString(skill.Meta.Name)
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n", name, name)
+	if skill.Meta.Description != "" {
+		fmt.Fprintf(&sb, "<p><em>%s</em></p>\n", html.EscapeString(skill.Meta.Description))
+	}
+	sb.Write(body)
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String())
+}
+
+// renderTerminal formats the skill body for a terminal of the given width: headings
+// are bold, paragraphs and list items are wrapped, and fenced code blocks are
+// indented and left unwrapped.
+func renderTerminal(skill *goskills.SkillPackage, width int) string {
+	const bold, reset = "\x1b[1m", "\x1b[0m"
+
+	var sb strings.Builder
+	sb.WriteString(bold + strings.ToUpper(skill.Meta.Name) + reset + "\n")
+	sb.WriteString(strings.Repeat("=", min(len(skill.Meta.Name), width)) + "\n\n")
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString(wrapText(strings.Join(paragraph, " "), "", width))
+			paragraph = nil
+		}
+	}
+
+	inCode := false
+	for _, line := range strings.Split(skill.Body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			sb.WriteString("    " + line + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			sb.WriteString("\n")
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			sb.WriteString(bold + strings.TrimSpace(strings.TrimLeft(trimmed, "#")) + reset + "\n")
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			flush()
+			indent := strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " ")))
+			sb.WriteString(wrapText("• "+trimmed[2:], indent, width))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return sb.String()
+}
+
+// wrapText word-wraps text to width columns, prefixing each line with indent.
+// Continuation lines are indented by two extra spaces.
+func wrapText(text, indent string, width int) string {
+	var sb strings.Builder
+	var words []string
+	prefix := indent
+	lineLen := len([]rune(prefix))
+	for _, word := range strings.Fields(text) {
+		wordLen := len([]rune(word))
+		if len(words) > 0 && lineLen+1+wordLen > width {
+			sb.WriteString(prefix + strings.Join(words, " ") + "\n")
+			words = nil
+			prefix = indent + "  "
+			lineLen = len([]rune(prefix))
+		}
+		if len(words) > 0 {
+			lineLen++
+		}
+		words = append(words, word)
+		lineLen += wordLen
+	}
+	sb.WriteString(prefix + strings.Join(words, " ") + "\n")
+	return sb.String()
+}
+
+func init() {
+	renderCmd.Flags().StringVar(&renderFormat, "format", "terminal", "Output format: html, terminal or raw")
+	rootCmd.AddCommand(renderCmd)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCmd(t *testing.T) {
+	const skillDir = "testdata/diff/new"
+
+	t.Run("terminal", func(t *testing.T) {
+		output, err := runCLI(t, "render", skillDir, "--format", "terminal")
+		require.NoError(t, err)
+		assert.NotEmpty(t, output)
+		for _, line := range strings.Split(output, "\n") {
+			assert.LessOrEqual(t, len([]rune(line)), renderWidth+len("\x1b[1m\x1b[0m"), line)
+		}
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		output, err := runCLI(t, "render", skillDir, "--format", "raw")
+		require.NoError(t, err)
+		skillPackage, err := parseSkillDir(skillDir)
+		require.NoError(t, err)
+		assert.Equal(t, skillPackage.Body, output)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var opened string
+		original := openURL
+		openURL = func(url string) error {
+			opened = url
+			return nil
+		}
+		defer func() { openURL = original }()
+
+		output, err := runCLI(t, "render", skillDir, "--format", "html")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(opened, "file://"))
+		path := strings.TrimPrefix(opened, "file://")
+		defer os.Remove(path)
+		assert.Contains(t, output, path)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		skillPackage, err := parseSkillDir(skillDir)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "<h1>"+skillPackage.Meta.Name+"</h1>")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := runCLI(t, "render", skillDir, "--format", "pdf")
+		assert.Error(t, err)
+	})
+}
+
+func TestWrapText(t *testing.T) {
+	wrapped := wrapText(strings.Repeat("word ", 30), "", 20)
+	lines := strings.Split(strings.TrimSuffix(wrapped, "\n"), "\n")
+	assert.Greater(t, len(lines), 1)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 20)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, "  word"), line)
+		}
+	}
+}
@@ -1,21 +1,39 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"strings"
 
 	"github.com/smallnest/goskills"
 	"github.com/spf13/cobra"
 )
 
+var (
+	searchTop       int
+	searchThreshold float64
+)
+
 var searchCmd = &cobra.Command{
-	Use:   "search [path] [query]",
-	Short: "Searches for skills by name or description.",
-	Long: `The search command scans a directory for valid skill packages and returns a list
-	of skills where the name or description contains the provided query text.
-	The search is case-insensitive.`,
-	Args: cobra.ExactArgs(2),
+	Use:   "search [path] <query>",
+	Short: "Searches for skills by name, description or meaning.",
+	Long: `With a path and a query, the search command scans the directory for valid skill
+packages and returns the skills whose name or description contains the query text.
+The search is case-insensitive.
+
+With only a query, the search is semantic: the query is embedded with the OpenAI
+embeddings API and compared with the skill embeddings saved by
+'goskills-cli index --with-embeddings'. The most similar skills are listed first.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return semanticSearch(cmd, args[0])
+		}
+
 		skillsRoot := args[0]
 		query := strings.ToLower(args[1])
 
@@ -24,7 +42,8 @@ var searchCmd = &cobra.Command{
 			return fmt.Errorf("could not parse skills in directory '%s': %w", skillsRoot, err)
 		}
 
-		fmt.Printf("--- Searching for '%s' in %s ---\n", query, skillsRoot)
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "--- Searching for '%s' in %s ---\n", query, skillsRoot)
 		foundCount := 0
 		for _, skillPackage := range packages {
 			// Case-insensitive search in name and description
@@ -32,19 +51,60 @@ var searchCmd = &cobra.Command{
 			description := strings.ToLower(skillPackage.Meta.Description)
 
 			if strings.Contains(name, query) || strings.Contains(description, query) {
-				fmt.Printf("- %-20s: %s\n", skillPackage.Meta.Name, skillPackage.Meta.Description)
+				fmt.Fprintf(out, "- %-20s: %s\n", skillPackage.Meta.Name, skillPackage.Meta.Description)
 				foundCount++
 			}
 		}
 
 		if foundCount == 0 {
-			fmt.Println("No matching skills found.")
+			fmt.Fprintln(out, "No matching skills found.")
 		}
 
 		return nil
 	},
 }
 
+// semanticSearch ranks the indexed skills by cosine similarity to query.
+func semanticSearch(cmd *cobra.Command, query string) error {
+	path, err := expandHome(defaultEmbeddingsPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no skill embeddings found at %s; run 'goskills-cli index --with-embeddings' first", path)
+	}
+	if err != nil {
+		return err
+	}
+	var store embeddingStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return fmt.Errorf("failed to parse skill embeddings %s: %w", path, err)
+	}
+
+	client, err := newEmbedder()
+	if err != nil {
+		return err
+	}
+	vectors, err := embedTexts(context.Background(), client, store.Model, []string{query})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "--- Semantic search for '%s' ---\n", query)
+	matches := rankSkills(&store, vectors[0], searchTop, searchThreshold)
+	for _, m := range matches {
+		fmt.Fprintf(out, "- %-20s (%.2f): %s\n", m.Name, m.Score, m.Description)
+	}
+	if len(matches) == 0 {
+		fmt.Fprintln(out, "No matching skills found.")
+	}
+	return nil
+}
+
 func init() {
+	searchCmd.Flags().IntVar(&searchTop, "top", 5, "Maximum number of results for semantic search")
+	searchCmd.Flags().Float64Var(&searchThreshold, "threshold", 0.7, "Minimum cosine similarity for semantic search results")
 	rootCmd.AddCommand(searchCmd)
 }
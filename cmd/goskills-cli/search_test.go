@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmbedder embeds text as a bag of known topic words, so texts sharing topics
+// are similar.
+type fakeEmbedder struct {
+	calls int
+}
+
+var fakeEmbeddingTopics = []string{"pdf", "spreadsheet", "email", "document"}
+
+func (f *fakeEmbedder) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	f.calls++
+	req := conv.Convert()
+	var resp openai.EmbeddingResponse
+	for i, input := range req.Input.([]string) {
+		lower := strings.ToLower(input)
+		vector := make([]float32, len(fakeEmbeddingTopics)+1)
+		vector[len(fakeEmbeddingTopics)] = 0.1 // keep every vector non-zero
+		for j, topic := range fakeEmbeddingTopics {
+			vector[j] = float32(strings.Count(lower, topic))
+		}
+		resp.Data = append(resp.Data, openai.Embedding{Index: i, Embedding: vector})
+	}
+	return resp, nil
+}
+
+func useFakeEmbedder(t *testing.T) *fakeEmbedder {
+	t.Helper()
+	fake := &fakeEmbedder{}
+	old := newEmbedder
+	newEmbedder = func() (goskills.EmbeddingClient, error) { return fake, nil }
+	t.Cleanup(func() {
+		newEmbedder = old
+		indexWithEmbeddings = false
+		searchTop = 5
+		searchThreshold = 0.7
+	})
+	return fake
+}
+
+func TestRankSkills(t *testing.T) {
+	store := &embeddingStore{Skills: []skillEmbedding{
+		{skillIndexEntry: skillIndexEntry{Name: "orthogonal"}, Embedding: []float32{0, 1}},
+		{skillIndexEntry: skillIndexEntry{Name: "close"}, Embedding: []float32{1, 0.2}},
+		{skillIndexEntry: skillIndexEntry{Name: "exact"}, Embedding: []float32{1, 0}},
+		{skillIndexEntry: skillIndexEntry{Name: "diagonal"}, Embedding: []float32{1, 1}},
+	}}
+	query := []float32{1, 0}
+
+	names := func(matches []skillMatch) []string {
+		var result []string
+		for _, m := range matches {
+			result = append(result, m.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"exact", "close", "diagonal", "orthogonal"}, names(rankSkills(store, query, 0, -1)))
+	assert.Equal(t, []string{"exact", "close", "diagonal"}, names(rankSkills(store, query, 0, 0.7)))
+	assert.Equal(t, []string{"exact", "close"}, names(rankSkills(store, query, 2, 0.7)))
+	assert.Equal(t, []string{"exact", "close"}, names(rankSkills(store, query, 5, 0.9)))
+	assert.Empty(t, rankSkills(store, []float32{-1, -1}, 5, 0.7))
+}
+
+func TestSemanticSearch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	fake := useFakeEmbedder(t)
+	skillsDir := writeIndexSkills(t, map[string]string{
+		"pdf":   "Extract text from PDF files and fill PDF forms.",
+		"docx":  "Edit document files, including PDF document export.",
+		"xlsx":  "Create and edit spreadsheet files.",
+		"email": "Compose and send email.",
+	})
+
+	_, err := runCLI(t, "index", skillsDir, "--with-embeddings")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.calls, "all skills should be embedded in a single request")
+	_, err = os.Stat(filepath.Join(home, ".goskills", "embeddings.json"))
+	require.NoError(t, err)
+
+	output, err := runCLI(t, "search", "pdf", "--threshold", "0.3")
+	require.NoError(t, err)
+	pdfPos := strings.Index(output, "- pdf ")
+	docxPos := strings.Index(output, "- docx ")
+	require.NotEqual(t, -1, pdfPos, output)
+	require.NotEqual(t, -1, docxPos, output)
+	assert.Less(t, pdfPos, docxPos, "pdf should rank above docx")
+	assert.NotContains(t, output, "- xlsx ")
+	assert.NotContains(t, output, "- email ")
+
+	output, err = runCLI(t, "search", "pdf", "--threshold", "0.3", "--top", "1")
+	require.NoError(t, err)
+	assert.Contains(t, output, "- pdf ")
+	assert.NotContains(t, output, "- docx ")
+
+	output, err = runCLI(t, "search", "weather forecast")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No matching skills found.")
+}
+
+func TestSemanticSearch_NoEmbeddings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useFakeEmbedder(t)
+
+	_, err := runCLI(t, "search", "pdf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "goskills-cli index --with-embeddings")
+}
+
+func TestSearchCmd_Substring(t *testing.T) {
+	skillsDir := writeIndexSkills(t, map[string]string{
+		"pdf":  "Extract text from PDF files.",
+		"xlsx": "Create and edit spreadsheets.",
+	})
+
+	output, err := runCLI(t, "search", skillsDir, "SPREAD")
+	require.NoError(t, err)
+	assert.Contains(t, output, "xlsx")
+	assert.NotContains(t, output, "- pdf")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/smallnest/goskills"
+	"github.com/spf13/cobra"
+)
+
+var sizeCmd = &cobra.Command{
+	Use:   "size <skill_directory>",
+	Short: "Prints how large a skill package is.",
+	Long: `The size command prints the approximate number of tokens in a skill's body, the
+combined size of its reference files and how many scripts and files it has. Use it to
+judge how expensive a skill is to send to an LLM; bodies above 8000 tokens are
+flagged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		size := skillPackage.SizeReport()
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Skill:           %s\n", skillPackage.Meta.Name)
+		fmt.Fprintf(out, "Body tokens:     ~%d\n", size.BodyTokens)
+		fmt.Fprintf(out, "Reference bytes: %d\n", size.ReferenceBytes)
+		fmt.Fprintf(out, "Scripts:         %d\n", size.ScriptCount)
+		fmt.Fprintf(out, "Total files:     %d\n", size.TotalFiles)
+		if size.BodyTokens > goskills.LargeSkillBodyTokens {
+			fmt.Fprintf(out, "Warning: the body exceeds %d tokens; consider moving details into references\n", goskills.LargeSkillBodyTokens)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sizeCmd)
+}
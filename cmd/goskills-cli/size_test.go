@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeCmd(t *testing.T) {
+	skillDir := writePackSkill(t)
+	require.NoError(t, os.Mkdir(filepath.Join(skillDir, "references"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "references", "spec.md"), make([]byte, 1234), 0644))
+
+	output, err := runCLI(t, "size", skillDir)
+	require.NoError(t, err)
+	assert.Equal(t, `Skill:           pdf-tools
+Body tokens:     ~4
+Reference bytes: 1234
+Scripts:         2
+Total files:     4
+`, output)
+
+	// Large bodies are flagged
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: pdf-tools\ndescription: Works with PDF files.\n---\n"+strings.Repeat("word ", 8000)), 0644))
+	output, err = runCLI(t, "size", skillDir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Body tokens:     ~9999\n")
+	assert.Contains(t, output, "Warning: the body exceeds 8000 tokens")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smallnest/goskills/audit"
+	"github.com/smallnest/goskills/skillstats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsAuditLog string
+	statsSince    string
+	statsSkill    string
+	statsReset    bool
+	statsDB       string
+)
+
+// skillStats aggregates audit records for a single skill.
+type skillStats struct {
+	Skill            string
+	Runs             int
+	ToolCalls        int
+	Errors           int
+	TotalDurationMs  int64
+	PromptTokens     int
+	CompletionTokens int
+	ToolCounts       map[string]int
+
+	sessions map[string]bool
+}
+
+// AvgLatency returns the mean tool call duration.
+func (s *skillStats) AvgLatency() time.Duration {
+	if s.ToolCalls == 0 {
+		return 0
+	}
+	return time.Duration(s.TotalDurationMs/int64(s.ToolCalls)) * time.Millisecond
+}
+
+// ErrorRate returns the fraction of tool calls that failed.
+func (s *skillStats) ErrorRate() float64 {
+	if s.ToolCalls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.ToolCalls)
+}
+
+// TopTools returns up to n tool names ordered by call count.
+func (s *skillStats) TopTools(n int) []string {
+	names := make([]string, 0, len(s.ToolCounts))
+	for name := range s.ToolCounts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.ToolCounts[names[i]] != s.ToolCounts[names[j]] {
+			return s.ToolCounts[names[i]] > s.ToolCounts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Displays aggregate skill usage statistics from the audit log.",
+	Long: `The stats command reads the tool call audit log written by
+'goskills run --audit-log' and prints, per skill, the number of runs,
+average tool latency, error rate, most common tool calls and token usage.
+Use --since (e.g. 7d, 12h) to only include recent activity.
+
+With --skill, it instead prints the counters 'goskills run' keeps for that
+skill in ~/.goskills/stats.db: how often it was selected, executed and
+succeeded, failed tool calls, tokens used and the average execution time.
+Add --reset to clear them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statsReset && statsSkill == "" {
+			return fmt.Errorf("--reset requires --skill")
+		}
+		if statsSkill != "" {
+			path, err := expandHome(statsDB)
+			if err != nil {
+				return err
+			}
+			store, err := skillstats.Open(path)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			return runSkillStats(cmd.OutOrStdout(), store, statsSkill, statsReset)
+		}
+
+		var since time.Time
+		if statsSince != "" {
+			d, err := parseSince(statsSince)
+			if err != nil {
+				return err
+			}
+			since = time.Now().Add(-d)
+		}
+
+		path, err := expandHome(statsAuditLog)
+		if err != nil {
+			return err
+		}
+
+		records, err := audit.ReadRecords(path)
+		if err != nil {
+			return err
+		}
+
+		printStats(cmd.OutOrStdout(), aggregateStats(records, since))
+		return nil
+	},
+}
+
+// parseSince parses a look-back window such as "7d", "12h" or "30m".
+func parseSince(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --since value '%s'", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --since value '%s'", value)
+	}
+	return d, nil
+}
+
+// aggregateStats groups records at or after since by skill, ordered by number of runs.
+func aggregateStats(records []audit.Record, since time.Time) []*skillStats {
+	bySkill := make(map[string]*skillStats)
+	for _, rec := range records {
+		if rec.Timestamp.Before(since) {
+			continue
+		}
+		name := rec.Skill
+		if name == "" {
+			name = "(none)"
+		}
+		stats, ok := bySkill[name]
+		if !ok {
+			stats = &skillStats{Skill: name, ToolCounts: map[string]int{}, sessions: map[string]bool{}}
+			bySkill[name] = stats
+		}
+
+		if !stats.sessions[rec.SessionID] {
+			stats.sessions[rec.SessionID] = true
+			stats.Runs++
+		}
+
+		if rec.IsToolCall() {
+			stats.ToolCalls++
+			stats.TotalDurationMs += rec.DurationMs
+			stats.ToolCounts[rec.Tool]++
+			if rec.Error != "" {
+				stats.Errors++
+			}
+		} else {
+			stats.PromptTokens += rec.PromptTokens
+			stats.CompletionTokens += rec.CompletionTokens
+		}
+	}
+
+	result := make([]*skillStats, 0, len(bySkill))
+	for _, stats := range bySkill {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Runs != result[j].Runs {
+			return result[i].Runs > result[j].Runs
+		}
+		return result[i].Skill < result[j].Skill
+	})
+	return result
+}
+
+// runSkillStats prints the counters of skill, or clears them when reset is set.
+func runSkillStats(w io.Writer, store *skillstats.Store, skill string, reset bool) error {
+	if reset {
+		if err := store.Reset(skill); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Reset statistics for %s\n", skill)
+		return nil
+	}
+
+	counters, err := store.Get(skill)
+	if err != nil {
+		return err
+	}
+	printSkillCounters(w, counters)
+	return nil
+}
+
+func printSkillCounters(w io.Writer, c skillstats.Counters) {
+	fmt.Fprintf(w, "--- Skill Statistics: %s ---\n", c.Skill)
+	fmt.Fprintf(w, "  Selected:     %d\n", c.Selected)
+	fmt.Fprintf(w, "  Executions:   %d\n", c.Executions)
+	fmt.Fprintf(w, "  Successful:   %d\n", c.Successes)
+	fmt.Fprintf(w, "  Tool errors:  %d\n", c.ToolErrors)
+	fmt.Fprintf(w, "  Tokens:       %d\n", c.Tokens)
+	fmt.Fprintf(w, "  Avg duration: %s\n", c.AvgDuration().Round(time.Millisecond))
+}
+
+func printStats(w io.Writer, stats []*skillStats) {
+	fmt.Fprintln(w, "--- Skill Usage Statistics ---")
+	if len(stats) == 0 {
+		fmt.Fprintln(w, "No usage recorded.")
+		return
+	}
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "\n%s\n", s.Skill)
+		fmt.Fprintf(w, "  Runs:        %d\n", s.Runs)
+		fmt.Fprintf(w, "  Tool calls:  %d\n", s.ToolCalls)
+		fmt.Fprintf(w, "  Avg latency: %s\n", s.AvgLatency())
+		fmt.Fprintf(w, "  Error rate:  %.1f%%\n", s.ErrorRate()*100)
+		fmt.Fprintf(w, "  Tokens:      %d prompt, %d completion\n", s.PromptTokens, s.CompletionTokens)
+		if top := s.TopTools(3); len(top) > 0 {
+			parts := make([]string, len(top))
+			for i, name := range top {
+				parts[i] = fmt.Sprintf("%s (%d)", name, s.ToolCounts[name])
+			}
+			fmt.Fprintf(w, "  Top tools:   %s\n", strings.Join(parts, ", "))
+		}
+	}
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsAuditLog, "audit-log", "~/.goskills/audit.jsonl", "Path to the audit log written by 'goskills run --audit-log'")
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "Only include activity within this window (e.g. 7d, 24h)")
+	statsCmd.Flags().StringVar(&statsSkill, "skill", "", "Show the usage counters recorded for this skill instead of the audit log summary")
+	statsCmd.Flags().BoolVar(&statsReset, "reset", false, "Clear the usage counters of the skill given with --skill")
+	statsCmd.Flags().StringVar(&statsDB, "stats-db", "~/.goskills/stats.db", "Path to the usage counter database written by 'goskills run'")
+	rootCmd.AddCommand(statsCmd)
+}
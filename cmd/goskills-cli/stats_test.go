@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smallnest/goskills/audit"
+	"github.com/smallnest/goskills/skillstats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedAuditLog(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.NewLogger(path, nil)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	old := now.Add(-10 * 24 * time.Hour)
+	records := []audit.Record{
+		{Kind: audit.KindToolCall, Timestamp: now, SessionID: "a", Skill: "pdf", Tool: "run_shell_code", DurationMs: 100},
+		{Kind: audit.KindToolCall, Timestamp: now, SessionID: "a", Skill: "pdf", Tool: "run_shell_code", DurationMs: 300, Error: "exit status 1"},
+		{Kind: audit.KindToolCall, Timestamp: now, SessionID: "b", Skill: "pdf", Tool: "read_file", DurationMs: 200},
+		{Kind: audit.KindCompletion, Timestamp: now, SessionID: "a", Skill: "pdf", PromptTokens: 100, CompletionTokens: 20},
+		{Kind: audit.KindCompletion, Timestamp: now, SessionID: "b", Skill: "pdf", PromptTokens: 50, CompletionTokens: 10},
+		{Kind: audit.KindToolCall, Timestamp: old, SessionID: "c", Skill: "pdf", Tool: "write_file", DurationMs: 1000},
+		{Kind: audit.KindToolCall, Timestamp: old, SessionID: "d", Skill: "markitdown", Tool: "web_fetch", DurationMs: 50},
+	}
+	for _, rec := range records {
+		require.NoError(t, logger.Log(rec))
+	}
+	return path
+}
+
+func TestAggregateStats(t *testing.T) {
+	records, err := audit.ReadRecords(seedAuditLog(t))
+	require.NoError(t, err)
+
+	stats := aggregateStats(records, time.Time{})
+	require.Len(t, stats, 2)
+
+	pdf := stats[0]
+	assert.Equal(t, "pdf", pdf.Skill)
+	assert.Equal(t, 3, pdf.Runs)
+	assert.Equal(t, 4, pdf.ToolCalls)
+	assert.Equal(t, 1, pdf.Errors)
+	assert.Equal(t, 0.25, pdf.ErrorRate())
+	assert.Equal(t, 400*time.Millisecond, pdf.AvgLatency())
+	assert.Equal(t, 150, pdf.PromptTokens)
+	assert.Equal(t, 30, pdf.CompletionTokens)
+	assert.Equal(t, []string{"run_shell_code", "read_file", "write_file"}, pdf.TopTools(3))
+
+	assert.Equal(t, "markitdown", stats[1].Skill)
+	assert.Equal(t, 1, stats[1].Runs)
+}
+
+func TestAggregateStats_Since(t *testing.T) {
+	records, err := audit.ReadRecords(seedAuditLog(t))
+	require.NoError(t, err)
+
+	stats := aggregateStats(records, time.Now().Add(-7*24*time.Hour))
+	require.Len(t, stats, 1)
+	assert.Equal(t, 2, stats[0].Runs)
+	assert.Equal(t, 3, stats[0].ToolCalls)
+	assert.Equal(t, 200*time.Millisecond, stats[0].AvgLatency())
+}
+
+func TestStatsCmd(t *testing.T) {
+	path := seedAuditLog(t)
+	defer func() { statsAuditLog, statsSince = "~/.goskills/audit.jsonl", "" }()
+
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs([]string{"stats", "--audit-log", path, "--since", "7d"})
+	require.NoError(t, rootCmd.Execute())
+
+	output := buf.String()
+	assert.Contains(t, output, "pdf")
+	assert.Contains(t, output, "Runs:        2")
+	assert.Contains(t, output, "Error rate:  33.3%")
+	assert.Contains(t, output, "Tokens:      150 prompt, 30 completion")
+	assert.Contains(t, output, "Top tools:   run_shell_code (2), read_file (1)")
+	assert.NotContains(t, output, "markitdown")
+}
+
+func TestParseSince(t *testing.T) {
+	d, err := parseSince("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, d)
+
+	d, err = parseSince("90m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, err = parseSince("soon")
+	assert.Error(t, err)
+}
+
+func TestRunSkillStats(t *testing.T) {
+	store, err := skillstats.Open(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Add(skillstats.Counters{Skill: "pdf", Selected: 3, Executions: 2, Successes: 1, ToolErrors: 4, Tokens: 1500, Duration: 3 * time.Second}))
+	require.NoError(t, store.Add(skillstats.Counters{Skill: "pdf", Selected: 1}))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, runSkillStats(buf, store, "pdf", false))
+	output := buf.String()
+	assert.Contains(t, output, "--- Skill Statistics: pdf ---")
+	assert.Contains(t, output, "Selected:     4")
+	assert.Contains(t, output, "Executions:   2")
+	assert.Contains(t, output, "Successful:   1")
+	assert.Contains(t, output, "Tool errors:  4")
+	assert.Contains(t, output, "Tokens:       1500")
+	assert.Contains(t, output, "Avg duration: 1.5s")
+
+	buf.Reset()
+	require.NoError(t, runSkillStats(buf, store, "pdf", true))
+	assert.Contains(t, buf.String(), "Reset statistics for pdf")
+	counters, err := store.Get("pdf")
+	require.NoError(t, err)
+	assert.Zero(t, counters.Selected)
+}
+
+func TestStatsCmd_Skill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.db")
+	store, err := skillstats.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Add(skillstats.Counters{Skill: "pdf", Selected: 2}))
+	require.NoError(t, store.Close())
+	t.Cleanup(func() { statsSkill, statsReset, statsDB = "", false, "~/.goskills/stats.db" })
+
+	output, err := runCLI(t, "stats", "--stats-db", path, "--skill", "pdf")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Selected:     2")
+
+	_, err = runCLI(t, "stats", "--stats-db", path, "--skill", "pdf", "--reset")
+	require.NoError(t, err)
+	statsReset = false
+	output, err = runCLI(t, "stats", "--stats-db", path, "--skill", "pdf")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Selected:     0")
+
+	statsSkill = ""
+	_, err = runCLI(t, "stats", "--reset")
+	assert.ErrorContains(t, err, "--reset requires --skill")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <skill_directory> <expected_hash>",
+	Short: "Verifies a skill package against an expected hash.",
+	Long: `The verify command recomputes the integrity hash of a skill package (see the
+hash command) and compares it with the expected value. It exits with a non-zero
+status if the package has been modified.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+
+		actual, err := skillPackage.Hash()
+		if err != nil {
+			return fmt.Errorf("failed to hash skill: %w", err)
+		}
+
+		expected := strings.ToLower(strings.TrimSpace(args[1]))
+		if actual != expected {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("hash mismatch for skill '%s': expected %s, got %s", skillPackage.Meta.Name, expected, actual)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "OK: skill '%s' matches %s\n", skillPackage.Meta.Name, actual)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/smallnest/goskills/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	visualizeFormat string
+	visualizeOutput string
+)
+
+var visualizeCmd = &cobra.Command{
+	Use:   "visualize <skill_directory>",
+	Short: "Renders a skill's tool-call graph as a Graphviz diagram.",
+	Long: `The visualize command draws the tools a skill offers the LLM and the scripts
+they run: the skill links to each tool, script and inline tools link to the scripts
+they run, and scripts link to the scripts they call. Base tools are drawn
+individually when the skill restricts them with allowed-tools, and as a single node
+otherwise.
+
+--format dot (default) prints the Graphviz DOT source, or writes it to --output.
+--format svg and --format png render the graph with Graphviz's dot command, which
+must be installed, into --output (default <skill-name>.svg or .png).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if visualizeFormat != "dot" && visualizeFormat != "svg" && visualizeFormat != "png" {
+			return fmt.Errorf("invalid format '%s' (expected dot, svg or png)", visualizeFormat)
+		}
+
+		skillPackage, err := parseSkillDir(args[0])
+		if err != nil {
+			return err
+		}
+		g, err := graph.Build(skillPackage)
+		if err != nil {
+			return err
+		}
+		dot := g.DOT()
+		out := cmd.OutOrStdout()
+
+		if visualizeFormat == "dot" {
+			if visualizeOutput == "" {
+				fmt.Fprint(out, dot)
+				return nil
+			}
+			if err := os.WriteFile(visualizeOutput, []byte(dot), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", visualizeOutput, err)
+			}
+			fmt.Fprintf(out, "Wrote %s\n", visualizeOutput)
+			return nil
+		}
+
+		output := visualizeOutput
+		if output == "" {
+			output = skillPackage.Meta.Name + "." + visualizeFormat
+		}
+		render := exec.Command("dot", "-T"+visualizeFormat, "-o", output)
+		if render.Err != nil {
+			return fmt.Errorf("graphviz 'dot' command not found; install Graphviz or use --format dot")
+		}
+		render.Stdin = strings.NewReader(dot)
+		render.Stderr = cmd.ErrOrStderr()
+		if err := render.Run(); err != nil {
+			return fmt.Errorf("dot failed: %w", err)
+		}
+		fmt.Fprintf(out, "Wrote %s\n", output)
+		return nil
+	},
+}
+
+func init() {
+	visualizeCmd.Flags().StringVar(&visualizeFormat, "format", "dot", "Output format: dot, svg or png")
+	visualizeCmd.Flags().StringVarP(&visualizeOutput, "output", "o", "", "File to write; dot prints to stdout by default")
+	rootCmd.AddCommand(visualizeCmd)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetVisualizeFlags(t *testing.T) {
+	t.Cleanup(func() {
+		visualizeFormat = "dot"
+		visualizeOutput = ""
+	})
+}
+
+func TestVisualizeCmd_DOT(t *testing.T) {
+	resetVisualizeFlags(t)
+	skillDir := writePackSkill(t)
+
+	output, err := runCLI(t, "visualize", skillDir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "digraph skill {")
+	assert.Contains(t, output, `"skill" [label="pdf-tools"`)
+	assert.Contains(t, output, `"tool:run_scripts_extract_py" -> "script:scripts/extract.py" [label="runs"];`)
+
+	dotFile := filepath.Join(t.TempDir(), "skill.dot")
+	output, err = runCLI(t, "visualize", skillDir, "-o", dotFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote "+dotFile+"\n", output)
+	data, err := os.ReadFile(dotFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "digraph skill {")
+}
+
+func TestVisualizeCmd_SVG(t *testing.T) {
+	resetVisualizeFlags(t)
+	skillDir := writePackSkill(t)
+
+	// A fake dot copies the DOT source into the file given with -o
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "dot"), []byte("#!/bin/sh\necho \"$1\" > \"$3\"\n/bin/cat >> \"$3\"\n"), 0755))
+	t.Setenv("PATH", binDir)
+
+	svgFile := filepath.Join(t.TempDir(), "skill.svg")
+	output, err := runCLI(t, "visualize", skillDir, "--format", "svg", "--output", svgFile)
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote "+svgFile+"\n", output)
+	data, err := os.ReadFile(svgFile)
+	require.NoError(t, err)
+	assert.Regexp(t, "^-Tsvg\ndigraph skill \\{", string(data))
+}
+
+func TestVisualizeCmd_Errors(t *testing.T) {
+	resetVisualizeFlags(t)
+	skillDir := writePackSkill(t)
+
+	_, err := runCLI(t, "visualize", skillDir, "--format", "pdf")
+	assert.EqualError(t, err, "invalid format 'pdf' (expected dot, svg or png)")
+
+	t.Setenv("PATH", t.TempDir())
+	_, err = runCLI(t, "visualize", skillDir, "--format", "png")
+	assert.EqualError(t, err, "graphviz 'dot' command not found; install Graphviz or use --format dot")
+}
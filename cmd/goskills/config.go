@@ -1,28 +1,73 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/smallnest/goskills"
+	"github.com/smallnest/goskills/ratelimit"
+	"github.com/smallnest/goskills/tool"
 	"github.com/spf13/cobra"
 )
 
 // Config holds the application configuration
 type Config struct {
-	SkillsDir        string
-	Model            string
-	APIBase          string
-	APIKey           string
-	AutoApproveTools bool
-	AllowedScripts   []string
-	Verbose          int
-	Debug            bool
-	Loop             bool
-	SkillName        string
-	McpConfig        string
+	SkillsDir          string
+	Model              string
+	APIBase            string
+	APIKey             string
+	AutoApproveTools   bool
+	AllowedScripts     []string
+	Verbose            int
+	Debug              bool
+	Loop               bool
+	SkillName          string
+	McpConfig          string
+	EnableBrowserTools bool
+	SkillTags          []string
+	AuditLogPath       string
+	AuditRedact        []string
+	ToolCacheTTLs      map[string]time.Duration
+	SelectionBudget    int
+	AllSkills          bool
+	TracePath          string
+	PreferredPython    string
+	Watch              bool
+	SelectionStrategy  string
+	EmbeddingCachePath string
+	ProfileDir         string
+	OutputCacheDir     string
+	MaxIterations      int
+	AllowedEnvVars     []string
+	AllowedRepoPaths   []string           // Absolute paths of the repositories the git tools may read
+	OutputTemplate     *template.Template // Renders the result when --template or --template-file is set
+	ApprovalMode       string
+	SearchSources      []string
+	InjectedDocuments  string // Contents of the --inject-file documents, prepended to the prompt
+	StatsDBPath        string
+	SkillArgs          map[string]string        // Parameters from --skill-arg, passed to skill scripts
+	SystemPrefix       string                   // Text from --system-prefix, prepended to the skill's system message
+	ParallelSkills     int                      // Number of top skill candidates run concurrently; 1 runs only the best
+	SkillVars          map[string]string        // Values from --skill-var for {{skill_var:KEY}} placeholders in skill bodies
+	RateLimits         ratelimit.Limits         // Per-model limits from --rate-limit
+	CheckpointDir      string                   // Directory from --checkpoint-dir for checkpoints written during the run
+	ToolTimeouts       map[string]time.Duration // Per-tool time limits from --tool-timeout
+	EnableDocker       bool                     // Offer the run_docker_command tool
+	DockerImages       []string                 // Images from --allow-docker-image that run_docker_command may use
+	DockerVolumes      []string                 // Host directories from --allow-docker-volume that run_docker_command may mount
 }
 
+// DefaultInjectLimit is the default maximum combined size, in bytes, of the documents
+// injected with --inject-file.
+const DefaultInjectLimit = 100000
+
 // loadConfig loads configuration from flags and environment variables
 func loadConfig(cmd *cobra.Command) (*Config, error) {
 	cfg := &Config{}
@@ -73,6 +118,231 @@ func loadConfig(cmd *cobra.Command) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	cfg.EnableBrowserTools, err = cmd.Flags().GetBool("enable-browser-tools")
+	if err != nil {
+		return nil, err
+	}
+	cfg.EnableDocker, err = cmd.Flags().GetBool("enable-docker")
+	if err != nil {
+		return nil, err
+	}
+	cfg.DockerImages, err = cmd.Flags().GetStringSlice("allow-docker-image")
+	if err != nil {
+		return nil, err
+	}
+	cfg.DockerVolumes, err = cmd.Flags().GetStringSlice("allow-docker-volume")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SkillTags, err = cmd.Flags().GetStringSlice("skill-tags")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditLogPath, err = cmd.Flags().GetString("audit-log")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AuditRedact, err = cmd.Flags().GetStringArray("audit-redact")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SelectionBudget, err = cmd.Flags().GetInt("selection-token-budget")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AllSkills, err = cmd.Flags().GetBool("all-skills")
+	if err != nil {
+		return nil, err
+	}
+	cfg.TracePath, err = cmd.Flags().GetString("trace")
+	if err != nil {
+		return nil, err
+	}
+	cfg.PreferredPython, err = cmd.Flags().GetString("python")
+	if err != nil {
+		return nil, err
+	}
+	cfg.Watch, err = cmd.Flags().GetBool("watch")
+	if err != nil {
+		return nil, err
+	}
+	cfg.ProfileDir, err = cmd.Flags().GetString("profile")
+	if err != nil {
+		return nil, err
+	}
+	cfg.StatsDBPath, err = cmd.Flags().GetString("stats-db")
+	if err != nil {
+		return nil, err
+	}
+	cfg.OutputCacheDir, err = cmd.Flags().GetString("cache-dir")
+	if err != nil {
+		return nil, err
+	}
+	cfg.CheckpointDir, err = cmd.Flags().GetString("checkpoint-dir")
+	if err != nil {
+		return nil, err
+	}
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SelectionStrategy, err = cmd.Flags().GetString("selection-strategy")
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.SelectionStrategy {
+	case goskills.SelectionStrategyLLM, goskills.SelectionStrategyKeyword, goskills.SelectionStrategyEmbeddings:
+	default:
+		return nil, fmt.Errorf("invalid --selection-strategy '%s' (expected llm, keyword or embeddings)", cfg.SelectionStrategy)
+	}
+	if cfg.SelectionStrategy == goskills.SelectionStrategyEmbeddings {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.EmbeddingCachePath = filepath.Join(home, ".goskills", "embedding_cache.json")
+	}
+	cfg.ApprovalMode, err = cmd.Flags().GetString("approval-mode")
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.ApprovalMode {
+	case "", goskills.ApprovalModeCLI, goskills.ApprovalModeGUI, goskills.ApprovalModeAuto:
+	default:
+		return nil, fmt.Errorf("invalid --approval-mode '%s' (expected cli, gui or auto)", cfg.ApprovalMode)
+	}
+	cfg.SearchSources, err = cmd.Flags().GetStringSlice("search-sources")
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range cfg.SearchSources {
+		if !slices.Contains(tool.DefaultSearchSources, source) {
+			return nil, fmt.Errorf("invalid --search-sources entry '%s' (expected duckduckgo, tavily or wikipedia)", source)
+		}
+	}
+	injectFiles, err := cmd.Flags().GetStringArray("inject-file")
+	if err != nil {
+		return nil, err
+	}
+	injectLimit, err := cmd.Flags().GetInt("inject-limit")
+	if err != nil {
+		return nil, err
+	}
+	cfg.InjectedDocuments, err = readInjectedDocuments(injectFiles, injectLimit)
+	if err != nil {
+		return nil, err
+	}
+	skillArgs, err := cmd.Flags().GetStringArray("skill-arg")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SkillArgs, err = parseSkillArgs(skillArgs)
+	if err != nil {
+		return nil, err
+	}
+	skillVars, err := cmd.Flags().GetStringArray("skill-var")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SkillVars, err = parseSkillVars(skillVars)
+	if err != nil {
+		return nil, err
+	}
+	rateLimits, err := cmd.Flags().GetStringArray("rate-limit")
+	if err != nil {
+		return nil, err
+	}
+	cfg.RateLimits, err = parseRateLimits(rateLimits)
+	if err != nil {
+		return nil, err
+	}
+	systemPrefix, err := cmd.Flags().GetString("system-prefix")
+	if err != nil {
+		return nil, err
+	}
+	cfg.SystemPrefix, err = readSystemPrefix(systemPrefix)
+	if err != nil {
+		return nil, err
+	}
+	allowEnv, err := cmd.Flags().GetStringSlice("allow-env")
+	if err != nil {
+		return nil, err
+	}
+	cfg.AllowedEnvVars = append(slices.Clone(tool.DefaultAllowedEnvVars), allowEnv...)
+	allowRepos, err := cmd.Flags().GetStringSlice("allow-repo")
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range allowRepos {
+		absRepo, err := filepath.Abs(repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve --allow-repo path '%s': %w", repo, err)
+		}
+		cfg.AllowedRepoPaths = append(cfg.AllowedRepoPaths, absRepo)
+	}
+	cfg.MaxIterations, err = cmd.Flags().GetInt("max-iterations")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxIterations < 1 || cfg.MaxIterations > goskills.MaxAllowedToolIterations {
+		return nil, fmt.Errorf("invalid --max-iterations %d (expected 1-%d)", cfg.MaxIterations, goskills.MaxAllowedToolIterations)
+	}
+	if cfg.Watch && cfg.Loop {
+		return nil, fmt.Errorf("--watch cannot be combined with --loop")
+	}
+	templateText, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return nil, err
+	}
+	templateFile, err := cmd.Flags().GetString("template-file")
+	if err != nil {
+		return nil, err
+	}
+	cfg.OutputTemplate, err = parseOutputTemplate(templateText, templateFile)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.OutputTemplate != nil && (cfg.Loop || cfg.Watch) {
+		return nil, fmt.Errorf("--template cannot be combined with --loop or --watch")
+	}
+	cfg.ParallelSkills, err = cmd.Flags().GetInt("parallel-skills")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ParallelSkills < 1 {
+		return nil, fmt.Errorf("invalid --parallel-skills %d (expected at least 1)", cfg.ParallelSkills)
+	}
+	if cfg.ParallelSkills > 1 && (cfg.SkillName != "" || cfg.AllSkills || cfg.Loop) {
+		return nil, fmt.Errorf("--parallel-skills cannot be combined with --skill, --all-skills or --loop")
+	}
+	cacheTTLs, err := cmd.Flags().GetStringToString("tool-cache-ttl")
+	if err != nil {
+		return nil, err
+	}
+	if len(cacheTTLs) > 0 {
+		cfg.ToolCacheTTLs = make(map[string]time.Duration, len(cacheTTLs))
+		for name, value := range cacheTTLs {
+			ttl, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --tool-cache-ttl for %s: %w", name, err)
+			}
+			cfg.ToolCacheTTLs[name] = ttl
+		}
+	}
+	toolTimeouts, err := cmd.Flags().GetStringToString("tool-timeout")
+	if err != nil {
+		return nil, err
+	}
+	if len(toolTimeouts) > 0 {
+		cfg.ToolTimeouts = make(map[string]time.Duration, len(toolTimeouts))
+		for name, value := range toolTimeouts {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --tool-timeout for %s: %w", name, err)
+			}
+			cfg.ToolTimeouts[name] = timeout
+		}
+	}
 
 	// 2. Load from environment variables (fallback if flag not set or empty, except bools)
 	// Note: Cobra flags usually handle defaults, but we check env vars here for precedence if needed
@@ -88,6 +358,12 @@ func loadConfig(cmd *cobra.Command) (*Config, error) {
 	if cfg.Model == "" {
 		cfg.Model = os.Getenv("OPENAI_MODEL")
 	}
+	if cfg.OutputCacheDir == "" {
+		cfg.OutputCacheDir = os.Getenv("GOSKILLS_CACHE_DIR")
+	}
+	if noCache {
+		cfg.OutputCacheDir = ""
+	}
 	cfg.APIBase = strings.TrimRight(cfg.APIBase, "/")
 
 	// Resolve SkillsDir to absolute path and expand ~
@@ -104,6 +380,34 @@ func loadConfig(cmd *cobra.Command) (*Config, error) {
 		}
 		cfg.SkillsDir = filepath.Join(home, cfg.SkillsDir[1:])
 	}
+	if strings.HasPrefix(cfg.AuditLogPath, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.AuditLogPath = filepath.Join(home, cfg.AuditLogPath[1:])
+	}
+	if strings.HasPrefix(cfg.StatsDBPath, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.StatsDBPath = filepath.Join(home, cfg.StatsDBPath[1:])
+	}
+	if strings.HasPrefix(cfg.OutputCacheDir, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.OutputCacheDir = filepath.Join(home, cfg.OutputCacheDir[1:])
+	}
+	if strings.HasPrefix(cfg.CheckpointDir, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.CheckpointDir = filepath.Join(home, cfg.CheckpointDir[1:])
+	}
 	absSkillsDir, err := filepath.Abs(cfg.SkillsDir)
 	if err != nil {
 		return nil, err
@@ -113,6 +417,175 @@ func loadConfig(cmd *cobra.Command) (*Config, error) {
 	return cfg, nil
 }
 
+// readInjectedDocuments reads the given files, in order, and wraps each in
+// "--- Document ---" and "--- End ---" markers. It fails when the combined contents
+// exceed limit bytes; a limit of 0 means no limit.
+func readInjectedDocuments(paths []string, limit int) (string, error) {
+	var sb strings.Builder
+	total := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read --inject-file: %w", err)
+		}
+		total += len(data)
+		if limit > 0 && total > limit {
+			return "", fmt.Errorf("injected files exceed --inject-limit of %d bytes", limit)
+		}
+		sb.WriteString("--- Document ---\n")
+		sb.WriteString(strings.TrimRight(string(data), "\n"))
+		sb.WriteString("\n--- End ---\n")
+	}
+	return sb.String(), nil
+}
+
+// parseOutputTemplate parses the --template text or the --template-file contents. It
+// returns nil when neither is set.
+func parseOutputTemplate(text, file string) (*template.Template, error) {
+	switch {
+	case text != "" && file != "":
+		return nil, fmt.Errorf("--template cannot be combined with --template-file")
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --template-file: %w", err)
+		}
+		text = string(data)
+	case text == "":
+		return nil, nil
+	}
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// parseSkillArgs parses --skill-arg values of the form key=value. Keys must consist of
+// letters, digits and underscores; a later value for the same key wins.
+func parseSkillArgs(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	args := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --skill-arg '%s' (expected key=value)", value)
+		}
+		args[key] = val
+	}
+	if err := tool.ValidateSkillArgs(args); err != nil {
+		return nil, fmt.Errorf("invalid --skill-arg: %w", err)
+	}
+	return args, nil
+}
+
+// parseSkillVars parses --skill-var values of the form KEY=VALUE. A later value for the
+// same key wins.
+func parseSkillVars(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	vars := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --skill-var '%s' (expected KEY=VALUE)", value)
+		}
+		vars[key] = val
+	}
+	if err := goskills.ValidateSkillVars(vars); err != nil {
+		return nil, fmt.Errorf("invalid --skill-var: %w", err)
+	}
+	return vars, nil
+}
+
+// parseRateLimits parses --rate-limit values of the form MODEL=RPM/TPM, where 0
+// leaves a limit unset.
+func parseRateLimits(values []string) (ratelimit.Limits, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	limits := make(ratelimit.Limits, len(values))
+	for _, value := range values {
+		model, spec, ok := strings.Cut(value, "=")
+		rpm, tpm, ok2 := strings.Cut(spec, "/")
+		if !ok || !ok2 || model == "" {
+			return nil, fmt.Errorf("invalid --rate-limit '%s' (expected MODEL=RPM/TPM)", value)
+		}
+		var limit ratelimit.ModelLimits
+		var err error
+		if limit.RPM, err = strconv.Atoi(rpm); err != nil || limit.RPM < 0 {
+			return nil, fmt.Errorf("invalid --rate-limit '%s' (RPM must be a non-negative integer)", value)
+		}
+		if limit.TPM, err = strconv.Atoi(tpm); err != nil || limit.TPM < 0 {
+			return nil, fmt.Errorf("invalid --rate-limit '%s' (TPM must be a non-negative integer)", value)
+		}
+		limits[model] = limit
+	}
+	return limits, nil
+}
+
+// readSystemPrefix returns the --system-prefix text, or the contents of the file
+// when the value has the form @<file>.
+func readSystemPrefix(value string) (string, error) {
+	path, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return value, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read --system-prefix file: %w", err)
+	}
+	return string(data), nil
+}
+
+// runnerConfig converts the CLI configuration into the agent's RunnerConfig.
+func (cfg *Config) runnerConfig() goskills.RunnerConfig {
+	return goskills.RunnerConfig{
+		APIKey:             cfg.APIKey,
+		APIBase:            cfg.APIBase,
+		Model:              cfg.Model,
+		SkillsDir:          cfg.SkillsDir,
+		Verbose:            cfg.Verbose,
+		Debug:              cfg.Debug,
+		AutoApproveTools:   cfg.AutoApproveTools,
+		AllowedScripts:     cfg.AllowedScripts,
+		Loop:               cfg.Loop,
+		SkillName:          cfg.SkillName,
+		EnableBrowserTools: cfg.EnableBrowserTools,
+		DiscoveryFilter: goskills.DiscoveryFilter{
+			Tags: cfg.SkillTags,
+		},
+		AuditLogPath:         cfg.AuditLogPath,
+		AuditRedactPatterns:  cfg.AuditRedact,
+		ToolCacheTTLs:        cfg.ToolCacheTTLs,
+		SelectionTokenBudget: cfg.SelectionBudget,
+		AllSkillsMode:        cfg.AllSkills,
+		PreferredPython:      cfg.PreferredPython,
+		SelectionStrategy:    cfg.SelectionStrategy,
+		EmbeddingCachePath:   cfg.EmbeddingCachePath,
+		OutputCacheDir:       cfg.OutputCacheDir,
+		MaxToolIterations:    cfg.MaxIterations,
+		AllowedEnvVars:       cfg.AllowedEnvVars,
+		AllowedRepoPaths:     cfg.AllowedRepoPaths,
+		ApprovalMode:         cfg.ApprovalMode,
+		SearchSources:        cfg.SearchSources,
+		StatsDBPath:          cfg.StatsDBPath,
+		SkillArgs:            cfg.SkillArgs,
+		SystemPrefix:         cfg.SystemPrefix,
+		ParallelSkills:       cfg.ParallelSkills,
+		SkillVars:            cfg.SkillVars,
+		RateLimits:           cfg.RateLimits,
+		CheckpointDir:        cfg.CheckpointDir,
+		ToolTimeouts:         cfg.ToolTimeouts,
+		DockerEnabled:        cfg.EnableDocker,
+		AllowedDockerImages:  cfg.DockerImages,
+		AllowedDockerVolumes: cfg.DockerVolumes,
+	}
+}
+
 // SetupFlags registers the flags with the command
 func setupFlags(cmd *cobra.Command) {
 	// Default to empty string; loadConfig will set the actual default (~/.goskills/skills or testdata/skills for development)
@@ -121,10 +594,75 @@ func setupFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("api-base", "b", "", "OpenAI-compatible API base URL (falls back to OPENAI_API_BASE env var)")
 	cmd.Flags().StringP("api-key", "k", "", "OpenAI-compatible API key (falls back to OPENAI_API_KEY env var)")
 	cmd.Flags().Bool("auto-approve", true, "Auto-approve all tool calls (WARNING: potentially unsafe)")
+	cmd.Flags().String("approval-mode", "", "How tool calls are approved: cli (terminal prompt), gui (zenity dialog) or auto (default: follows --auto-approve)")
 	cmd.Flags().StringSlice("allow-scripts", nil, "Comma-separated list of allowed script names (e.g. 'run_myscript_py')")
 	cmd.Flags().CountP("verbose", "v", "Enable verbose output (-v for basic, -vv for detailed)")
 	cmd.Flags().BoolP("debug", "D", false, "Enable debug output (print LLM requests/responses)")
 	cmd.Flags().BoolP("loop", "l", false, "Enable interactive loop mode")
 	cmd.Flags().String("skill", "", "Force specific skill to use (skip LLM selection)")
+	cmd.RegisterFlagCompletionFunc("skill", completeSkillNames)
 	cmd.Flags().String("mcp-config", "", "Path to MCP configuration file")
+	cmd.Flags().Bool("enable-browser-tools", false, "Enable tools that drive a headless Chrome/Chromium browser (e.g. web_screenshot)")
+	cmd.Flags().Bool("enable-docker", false, "Enable the run_docker_command tool, which runs commands in Docker containers of the images allowed with --allow-docker-image")
+	cmd.Flags().StringSlice("allow-docker-image", nil, "Comma-separated Docker images run_docker_command may use; an image without a tag allows all its tags (default: none)")
+	cmd.Flags().StringSlice("allow-docker-volume", nil, "Comma-separated host directories run_docker_command may mount besides the skill directory (default: none)")
+	cmd.Flags().StringSlice("skill-tags", nil, "Comma-separated list of tags; only skills with at least one matching tag are considered")
+	cmd.Flags().String("audit-log", "", "Append a JSONL audit record for every tool call to this file")
+	cmd.Flags().Int("selection-token-budget", 0, "Approximate token budget for the skill list sent during skill selection (0 = unlimited)")
+	cmd.Flags().Bool("all-skills", false, "Skip skill selection and send all skill bodies to the LLM in one system prompt")
+	cmd.Flags().Int("parallel-skills", 1, "Run the LLM's top N skill candidates concurrently and keep the first successful answer")
+	cmd.Flags().String("stats-db", "~/.goskills/stats.db", "SQLite database of per-skill usage counters shown by 'goskills-cli stats --skill' (empty disables)")
+	cmd.Flags().String("trace", "", "Write a JSON trace of every LLM request/response and tool call to this file when the run ends")
+	cmd.Flags().String("python", "", "Python interpreter for all Python tools, e.g. 'python2' (default: detected per script)")
+	cmd.Flags().String("template", "", "Render the result through this Go template, e.g. '{{.Skill}}: {{.Result}}' (also {{.Duration}} and {{.TokenUsage.TotalTokens}})")
+	cmd.Flags().String("template-file", "", "Render the result through the Go template in this file (see --template)")
+	cmd.Flags().Bool("watch", false, "Re-run the prompt whenever the selected skill's SKILL.md or scripts change (Ctrl+C to stop)")
+	cmd.Flags().String("selection-strategy", goskills.SelectionStrategyLLM, "How to select a skill: llm, keyword (offline BM25) or embeddings")
+	cmd.Flags().String("profile", "", "Write CPU (cpu.prof) and memory (mem.prof) profiles of the run to this directory")
+	cmd.Flags().StringArray("inject-file", nil, "Prepend the contents of this file to the prompt (repeatable; files are injected in order)")
+	cmd.Flags().Int("inject-limit", DefaultInjectLimit, "Maximum combined size in bytes of the files given with --inject-file (0 = unlimited)")
+	cmd.Flags().StringArray("skill-arg", nil, "Pass a key=value parameter to skill scripts as GOSKILLS_ARG_<KEY> and {{.GOSKILLS_ARGS.key}} (repeatable)")
+	cmd.Flags().StringArray("skill-var", nil, "Set a KEY=VALUE variable substituted for {{skill_var:KEY}} in skill bodies (repeatable)")
+	cmd.Flags().StringArray("rate-limit", nil, "Limit requests and tokens per minute of a model as MODEL=RPM/TPM, e.g. 'gpt-4o=500/30000' (0 = unlimited; repeatable)")
+	cmd.Flags().String("checkpoint-dir", "", "Save a checkpoint of the run to this directory after every tool-call iteration, for use with 'goskills run --resume'")
+	cmd.Flags().String("system-prefix", "", "Text prepended to the skill's system prompt, e.g. company name or locale; use @<file> to read it from a file")
+	cmd.Flags().StringSlice("search-sources", nil, "Comma-separated backends the web_search tool queries: duckduckgo, tavily, wikipedia (default: all)")
+	cmd.Flags().StringSlice("allow-repo", nil, "Comma-separated git repositories the git_log and git_diff tools may read (default: none)")
+	cmd.Flags().StringSlice("allow-env", nil, "Comma-separated environment variables the get_env tool may reveal, in addition to HOME, USER, PWD and PATH")
+	cmd.Flags().IntP("max-iterations", "n", goskills.DefaultMaxToolIterations, "Maximum number of tool call round trips per prompt (1-100)")
+	cmd.Flags().String("cache-dir", "", "Cache final answers keyed on skill, prompt and model in this directory (falls back to GOSKILLS_CACHE_DIR env var)")
+	cmd.Flags().Bool("no-cache", false, "Disable the output cache even if --cache-dir or GOSKILLS_CACHE_DIR is set")
+	cmd.Flags().StringToString("tool-cache-ttl", nil, "Cache results of the given tools for a duration, e.g. 'web_fetch=10m,wikipedia_search=1h'")
+	cmd.Flags().StringToString("tool-timeout", nil, "Stop the given tools after a duration, e.g. 'run_shell_code=5m,web_fetch=10s' (0 disables; defaults: web_fetch=30s, run_shell_code=1m, run_python_code=2m, run_docker_command=5m)")
+	cmd.Flags().StringArray("audit-redact", nil, "Regex matching argument names or values to redact in the audit log (repeatable; replaces the built-in patterns)")
+}
+
+// completeSkillNames completes the --skill flag with the skill names recorded in
+// ~/.goskills/index.json by 'goskills-cli index'.
+func completeSkillNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".goskills", "index.json"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var index struct {
+		Skills []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"skills"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, skill := range index.Skills {
+		if strings.HasPrefix(skill.Name, toComplete) {
+			names = append(names, cobra.CompletionWithDesc(skill.Name, skill.Description))
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
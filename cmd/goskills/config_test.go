@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/smallnest/goskills/ratelimit"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -260,3 +263,558 @@ func TestLoadConfig_APITrimTrailingSlash(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig_SkillTags(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	err := cmd.ParseFlags([]string{"--skill-tags", "data,pdf"})
+	assert.NoError(t, err)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"data", "pdf"}, cfg.SkillTags)
+}
+
+func TestLoadConfig_AuditLog(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	err := cmd.ParseFlags([]string{
+		"--audit-log", "/tmp/audit.jsonl",
+		"--audit-redact", `(?i)token`,
+		"--audit-redact", `ghp_[A-Za-z0-9]+`,
+	})
+	assert.NoError(t, err)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/audit.jsonl", cfg.AuditLogPath)
+	assert.Equal(t, []string{`(?i)token`, `ghp_[A-Za-z0-9]+`}, cfg.AuditRedact)
+}
+
+func TestLoadConfig_ToolCacheTTL(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	err := cmd.ParseFlags([]string{"--tool-cache-ttl", "web_fetch=10m,wikipedia_search=1h"})
+	assert.NoError(t, err)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"web_fetch":        10 * time.Minute,
+		"wikipedia_search": time.Hour,
+	}, cfg.ToolCacheTTLs)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--tool-cache-ttl", "web_fetch=soon"}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_ToolTimeouts(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	err := cmd.ParseFlags([]string{"--tool-timeout", "run_shell_code=5m", "--tool-timeout", "web_fetch=0"})
+	assert.NoError(t, err)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"run_shell_code": 5 * time.Minute,
+		"web_fetch":      0,
+	}, cfg.runnerConfig().ToolTimeouts)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--tool-timeout", "run_shell_code=forever"}))
+	_, err = loadConfig(cmd)
+	assert.ErrorContains(t, err, "invalid --tool-timeout for run_shell_code")
+}
+
+func TestLoadConfig_SelectionTokenBudget(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	err := cmd.ParseFlags([]string{"--selection-token-budget", "500"})
+	assert.NoError(t, err)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, cfg.SelectionBudget)
+}
+
+func TestLoadConfig_AllSkills(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.False(t, cfg.AllSkills)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--all-skills"}))
+
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.True(t, cfg.AllSkills)
+}
+
+func TestLoadConfig_Trace(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--trace", "/tmp/run-trace.json"}))
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/run-trace.json", cfg.TracePath)
+}
+
+func TestLoadConfig_PreferredPython(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--python", "python2"}))
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "python2", cfg.PreferredPython)
+}
+
+func TestLoadConfig_Watch(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--watch"}))
+
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Watch)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--watch", "--loop"}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_SelectionStrategy(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "llm", cfg.SelectionStrategy)
+	assert.Empty(t, cfg.EmbeddingCachePath)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--selection-strategy", "embeddings"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "embeddings", cfg.SelectionStrategy)
+	assert.Equal(t, filepath.Join(home, ".goskills", "embedding_cache.json"), cfg.EmbeddingCachePath)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--selection-strategy", "random"}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_OutputCache(t *testing.T) {
+	t.Setenv("GOSKILLS_CACHE_DIR", "")
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.OutputCacheDir)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--cache-dir", "~/cache"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "cache"), cfg.OutputCacheDir)
+
+	t.Setenv("GOSKILLS_CACHE_DIR", "/tmp/goskills-cache")
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/goskills-cache", cfg.OutputCacheDir)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--no-cache"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.OutputCacheDir)
+}
+
+func TestLoadConfig_MaxIterations(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, cfg.MaxIterations)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"-n", "5"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, cfg.MaxIterations)
+
+	for _, value := range []string{"0", "101"} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags([]string{"--max-iterations", value}))
+		_, err = loadConfig(cmd)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadConfig_AllowEnv(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HOME", "USER", "PWD", "PATH"}, cfg.AllowedEnvVars)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--allow-env", "LANG,TZ"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HOME", "USER", "PWD", "PATH", "LANG", "TZ"}, cfg.AllowedEnvVars)
+}
+
+func TestLoadConfig_Docker(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.False(t, cfg.runnerConfig().DockerEnabled)
+	assert.Empty(t, cfg.runnerConfig().AllowedDockerImages)
+	assert.Empty(t, cfg.runnerConfig().AllowedDockerVolumes)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--enable-docker", "--allow-docker-image", "python,alpine:3.20", "--allow-docker-volume", "/srv/data"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.True(t, cfg.runnerConfig().DockerEnabled)
+	assert.Equal(t, []string{"python", "alpine:3.20"}, cfg.runnerConfig().AllowedDockerImages)
+	assert.Equal(t, []string{"/srv/data"}, cfg.runnerConfig().AllowedDockerVolumes)
+}
+
+func TestLoadConfig_AllowRepo(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.AllowedRepoPaths)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--allow-repo", "repo,/srv/other"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(wd, "repo"), "/srv/other"}, cfg.AllowedRepoPaths)
+	assert.Equal(t, cfg.AllowedRepoPaths, cfg.runnerConfig().AllowedRepoPaths)
+}
+
+func TestLoadConfig_Template(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.OutputTemplate)
+
+	file := filepath.Join(t.TempDir(), "output.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte("{{.Result}}"), 0644))
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--template-file", file}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.NotNil(t, cfg.OutputTemplate)
+
+	for _, args := range [][]string{
+		{"--template", "{{.Result}}", "--template-file", file},
+		{"--template", "{{.Result}}", "--loop"},
+		{"--template-file", filepath.Join(t.TempDir(), "missing.tmpl")},
+		{"--template", "{{if}}"},
+	} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags(args))
+		_, err = loadConfig(cmd)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_SystemPrefix(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--system-prefix", "Company: Acme"}))
+	cfg, err := loadConfig(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "Company: Acme", cfg.SystemPrefix)
+	assert.Equal(t, "Company: Acme", cfg.runnerConfig().SystemPrefix)
+
+	file := filepath.Join(t.TempDir(), "prefix.txt")
+	require.NoError(t, os.WriteFile(file, []byte("Locale: de-DE\n"), 0644))
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--system-prefix", "@" + file}))
+	cfg, err = loadConfig(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "Locale: de-DE\n", cfg.SystemPrefix)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--system-prefix", "@" + filepath.Join(t.TempDir(), "missing.txt")}))
+	_, err = loadConfig(cmd)
+	assert.ErrorContains(t, err, "failed to read --system-prefix file")
+}
+
+func TestLoadConfig_ParallelSkills(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--parallel-skills", "3"}))
+	cfg, err := loadConfig(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.runnerConfig().ParallelSkills)
+
+	for _, args := range [][]string{
+		{"--parallel-skills", "0"},
+		{"--parallel-skills", "2", "--skill", "pdf"},
+		{"--parallel-skills", "2", "--all-skills"},
+		{"--parallel-skills", "2", "--loop"},
+	} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags(args))
+		_, err = loadConfig(cmd)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_SkillVars(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--skill-var", "LANGUAGE=German", "--skill-var", "DB_URL=postgres://db?sslmode=disable"}))
+	cfg, err := loadConfig(cmd)
+	require.NoError(t, err)
+	want := map[string]string{"LANGUAGE": "German", "DB_URL": "postgres://db?sslmode=disable"}
+	assert.Equal(t, want, cfg.SkillVars)
+	assert.Equal(t, want, cfg.runnerConfig().SkillVars)
+
+	for _, args := range [][]string{
+		{"--skill-var", "LANGUAGE"},
+		{"--skill-var", "BAD-KEY=x"},
+	} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags(args))
+		_, err = loadConfig(cmd)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_RateLimits(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--rate-limit", "gpt-4o=500/30000", "--rate-limit", "deepseek-v3=60/0"}))
+	cfg, err := loadConfig(cmd)
+	require.NoError(t, err)
+	want := ratelimit.Limits{"gpt-4o": {RPM: 500, TPM: 30000}, "deepseek-v3": {RPM: 60}}
+	assert.Equal(t, want, cfg.RateLimits)
+	assert.Equal(t, want, cfg.runnerConfig().RateLimits)
+
+	for _, args := range [][]string{
+		{"--rate-limit", "gpt-4o=500"},
+		{"--rate-limit", "=1/1"},
+		{"--rate-limit", "gpt-4o=fast/1"},
+		{"--rate-limit", "gpt-4o=1/-5"},
+	} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags(args))
+		_, err = loadConfig(cmd)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLoadConfig_CheckpointDir(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.runnerConfig().CheckpointDir)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--checkpoint-dir", "~/checkpoints"}))
+	cfg, err = loadConfig(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "checkpoints"), cfg.runnerConfig().CheckpointDir)
+}
+
+func TestLoadConfig_ApprovalMode(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.ApprovalMode)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--approval-mode", "gui"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "gui", cfg.ApprovalMode)
+	assert.Equal(t, "gui", cfg.runnerConfig().ApprovalMode)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--approval-mode", "email"}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_SearchSources(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.SearchSources)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--search-sources", "duckduckgo,wikipedia"}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"duckduckgo", "wikipedia"}, cfg.runnerConfig().SearchSources)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--search-sources", "bing"}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_StatsDB(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".goskills", "stats.db"), cfg.runnerConfig().StatsDBPath)
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--stats-db", ""}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.runnerConfig().StatsDBPath)
+}
+
+func TestLoadConfig_InjectFile(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.InjectedDocuments)
+
+	dir := t.TempDir()
+	doc := filepath.Join(dir, "doc.txt")
+	require.NoError(t, os.WriteFile(doc, []byte("0123456789"), 0644))
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--inject-file", doc}))
+	cfg, err = loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, "--- Document ---\n0123456789\n--- End ---\n", cfg.InjectedDocuments)
+
+	// The limit applies to the combined size of all files
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--inject-file", doc, "--inject-file", doc, "--inject-limit", "15"}))
+	_, err = loadConfig(cmd)
+	assert.ErrorContains(t, err, "--inject-limit of 15 bytes")
+
+	cmd = &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--inject-file", filepath.Join(dir, "missing.txt")}))
+	_, err = loadConfig(cmd)
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_SkillArg(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupFlags(cmd)
+	assert.NoError(t, cmd.ParseFlags([]string{"--skill-arg", "url=https://example.com/?a=b", "--skill-arg", "max_pages=3"}))
+	cfg, err := loadConfig(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"url": "https://example.com/?a=b", "max_pages": "3"}, cfg.SkillArgs)
+	assert.Equal(t, cfg.SkillArgs, cfg.runnerConfig().SkillArgs)
+
+	for _, arg := range []string{"url", "bad-key=1", "=1"} {
+		cmd = &cobra.Command{}
+		setupFlags(cmd)
+		assert.NoError(t, cmd.ParseFlags([]string{"--skill-arg", arg}))
+		_, err = loadConfig(cmd)
+		assert.ErrorContains(t, err, "invalid --skill-arg", arg)
+	}
+}
+
+func TestCompleteSkillNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cmd := &cobra.Command{}
+	names, directive := completeSkillNames(cmd, nil, "")
+	assert.Empty(t, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	index := `{"skills_dir": "/skills", "skills": [
+		{"name": "pdf", "description": "Extract text from PDF documents.", "path": "/skills/pdf"},
+		{"name": "pptx", "description": "Create presentations.", "path": "/skills/pptx"},
+		{"name": "xlsx", "description": "Create and edit spreadsheets.", "path": "/skills/xlsx"}
+	]}`
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".goskills"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".goskills", "index.json"), []byte(index), 0644))
+
+	names, directive = completeSkillNames(cmd, nil, "")
+	assert.Equal(t, []string{
+		"pdf\tExtract text from PDF documents.",
+		"pptx\tCreate presentations.",
+		"xlsx\tCreate and edit spreadsheets.",
+	}, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	names, _ = completeSkillNames(cmd, nil, "p")
+	assert.Equal(t, []string{"pdf\tExtract text from PDF documents.", "pptx\tCreate presentations."}, names)
+
+	// The function is registered for the --skill flag
+	setupFlags(cmd)
+	fn, ok := cmd.GetFlagCompletionFunc("skill")
+	require.True(t, ok)
+	names, _ = fn(cmd, nil, "x")
+	assert.Equal(t, []string{"xlsx\tCreate and edit spreadsheets."}, names)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -8,12 +9,19 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"runtime/pprof"
 	"strings"
+	"text/template"
+	"time"
 
+	openai "github.com/sashabaranov/go-openai"
 	"github.com/smallnest/goskills"
 	"github.com/smallnest/goskills/log"
 	goskills_mcp "github.com/smallnest/goskills/mcp"
+	"github.com/smallnest/goskills/trace"
 	"github.com/spf13/cobra"
 )
 
@@ -32,9 +40,6 @@ using Large Language Models (LLMs) like OpenAI's GPT models.`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	// Disable the default completion command
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
-
 	if err := rootCmd.Execute(); err != nil {
 		log.Error("command execution failed: %v", err)
 		os.Exit(1)
@@ -44,12 +49,27 @@ func Execute() {
 func main() {
 	rootCmd.AddCommand(runCmd)
 	setupFlags(runCmd)
+	runCmd.Flags().StringVar(&resumeCheckpoint, "resume", "", "Continue the interrupted run saved in this checkpoint file (see --checkpoint-dir) instead of running a prompt")
 
 	rootCmd.AddCommand(downloadCmd)
 
+	rootCmd.AddCommand(updateCmd)
+
+	rootCmd.AddCommand(searchCmd)
+
+	rootCmd.AddCommand(serveCmd)
+	setupFlags(serveCmd)
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to listen on; use 0.0.0.0 to accept connections from other machines")
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "Port to listen on")
+	serveCmd.Flags().IntVar(&serveGRPCPort, "grpc-port", 0, "Also serve the agent over gRPC on this port; 0 disables gRPC")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Require this bearer token on every request (default: $"+serveTokenEnv+")")
+
 	Execute()
 }
 
+// resumeCheckpoint is the checkpoint file given with run --resume.
+var resumeCheckpoint string
+
 var runCmd = &cobra.Command{
 	Use:   "run [prompt]",
 	Short: "Processes a user request by selecting and executing a skill.",
@@ -64,7 +84,11 @@ You can specify a custom model and API base URL using flags.`,
 	Args: cobra.MinimumNArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		userPrompt := strings.Join(args, " ")
-		if len(args) == 0 {
+		if resumeCheckpoint != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--resume cannot be combined with a prompt")
+			}
+		} else if len(args) == 0 {
 			userPromptBytes, err := io.ReadAll(os.Stdin)
 			if err != nil {
 				return fmt.Errorf("failed to read from stdin: %w", err)
@@ -72,7 +96,7 @@ You can specify a custom model and API base URL using flags.`,
 			userPrompt = strings.TrimSpace(string(userPromptBytes))
 		}
 
-		if userPrompt == "" {
+		if userPrompt == "" && resumeCheckpoint == "" {
 			return cmd.Help()
 		}
 
@@ -80,76 +104,200 @@ You can specify a custom model and API base URL using flags.`,
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
-
-		runnerCfg := goskills.RunnerConfig{
-			APIKey:           cfg.APIKey,
-			APIBase:          cfg.APIBase,
-			Model:            cfg.Model,
-			SkillsDir:        cfg.SkillsDir,
-			Verbose:          cfg.Verbose,
-			Debug:            cfg.Debug,
-			AutoApproveTools: cfg.AutoApproveTools,
-			AllowedScripts:   cfg.AllowedScripts,
-			Loop:             cfg.Loop,
-			SkillName:        cfg.SkillName,
+		if resumeCheckpoint != "" && (cfg.Loop || cfg.Watch) {
+			return fmt.Errorf("--resume cannot be combined with --loop or --watch")
 		}
+		userPrompt = cfg.InjectedDocuments + userPrompt
 
-		ctx := context.Background()
-
-		// Initialize MCP Client
-		var mcpClient *goskills_mcp.Client
-		var mcpConfigPath string
-
-		if cfg.McpConfig != "" {
-			mcpConfigPath = cfg.McpConfig
-		} else {
-			// Check local mcp.json
-			if _, err := os.Stat("mcp.json"); err == nil {
-				mcpConfigPath = "mcp.json"
+		if cfg.ProfileDir != "" {
+			stopProfiling, err := startProfiling(cfg.ProfileDir, cmd.ErrOrStderr())
+			if err != nil {
+				return err
 			}
-			// TODO: Check ~/.claude.json if needed in future
+			defer func() {
+				if err := stopProfiling(); err != nil {
+					log.Warn("failed to write profile: %v", err)
+				}
+			}()
 		}
 
-		if mcpConfigPath != "" {
-			if cfg.Verbose >= 1 {
-				log.Info("loading mcp config from: %s", mcpConfigPath)
-			}
-			mcpConfig, err := goskills_mcp.LoadConfig(mcpConfigPath)
-			if err != nil {
-				log.Warn("failed to load mcp config: %v", err)
-			} else {
-				mcpClient, err = goskills_mcp.NewClient(ctx, mcpConfig)
-				if err != nil {
-					log.Warn("failed to create mcp client: %v", err)
-				} else {
-					defer mcpClient.Close()
-					if cfg.Verbose >= 1 {
-						log.Info("mcp client initialized")
-					}
+		runnerCfg := cfg.runnerConfig()
+
+		if cfg.TracePath != "" {
+			recorder := trace.NewRecorder()
+			runnerCfg.Trace = recorder
+			defer func() {
+				if err := recorder.WriteFile(cfg.TracePath); err != nil {
+					log.Warn("failed to write trace: %v", err)
+				} else if cfg.Verbose >= 1 {
+					log.Info("trace written to %s", cfg.TracePath)
 				}
-			}
+			}()
+		}
+
+		ctx := context.Background()
+
+		mcpClient := loadMCPClient(ctx, cfg)
+		if mcpClient != nil {
+			defer mcpClient.Close()
 		}
 
 		agent, err := goskills.NewAgent(runnerCfg, mcpClient)
 		if err != nil {
 			return fmt.Errorf("failed to create agent: %w", err)
 		}
+		if cfg.Verbose >= 1 {
+			defer func() {
+				log.Info("total estimated cost: $%.4f", agent.TotalCost())
+			}()
+		}
 
 		if runnerCfg.Loop {
 			return agent.RunLoop(ctx, userPrompt)
 		}
 
-		result, err := agent.Run(ctx, userPrompt)
+		if cfg.Watch {
+			watchCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+			return agent.Watch(watchCtx, userPrompt, os.Stdout)
+		}
+
+		start := time.Now()
+		var result string
+		if resumeCheckpoint != "" {
+			if err := agent.RestoreFromCheckpoint(resumeCheckpoint); err != nil {
+				return err
+			}
+			result, err = agent.Resume(ctx)
+		} else {
+			result, err = agent.Run(ctx, userPrompt)
+		}
 		if err != nil {
+			if path := agent.CheckpointPath(); path != "" {
+				if _, statErr := os.Stat(path); statErr == nil {
+					log.Info("continue the run with: goskills run --resume %s", path)
+				}
+			}
 			return err
 		}
 
-		fmt.Println(result)
+		if cfg.OutputTemplate != nil {
+			return renderOutput(cmd.OutOrStdout(), cfg.OutputTemplate, OutputTemplateData{
+				Result:     result,
+				Skill:      agent.ActiveSkill(),
+				Duration:   time.Since(start),
+				TokenUsage: agent.TotalUsage(),
+			})
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), result)
 		return nil
 	},
 }
 
-var forceDownload bool
+// OutputTemplateData is the data available to --template and --template-file.
+type OutputTemplateData struct {
+	Result     string        // The final answer
+	Skill      string        // Name of the skill that produced it
+	Duration   time.Duration // Time taken by the run
+	TokenUsage openai.Usage  // Tokens used by all LLM calls of the run
+}
+
+// renderOutput writes data rendered through tmpl to w, followed by a newline unless the
+// output already ends with one.
+func renderOutput(w io.Writer, tmpl *template.Template, data OutputTemplateData) error {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return fmt.Errorf("failed to render output template: %w", err)
+	}
+	output := sb.String()
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	_, err := io.WriteString(w, output)
+	return err
+}
+
+// loadMCPClient connects to the MCP servers configured by --mcp-config, or by
+// mcp.json in the current directory. It returns nil when none are configured or the
+// connection fails; the caller must close a non-nil client.
+func loadMCPClient(ctx context.Context, cfg *Config) *goskills_mcp.Client {
+	var mcpConfigPath string
+
+	if cfg.McpConfig != "" {
+		mcpConfigPath = cfg.McpConfig
+	} else {
+		// Check local mcp.json
+		if _, err := os.Stat("mcp.json"); err == nil {
+			mcpConfigPath = "mcp.json"
+		}
+		// TODO: Check ~/.claude.json if needed in future
+	}
+
+	if mcpConfigPath == "" {
+		return nil
+	}
+	if cfg.Verbose >= 1 {
+		log.Info("loading mcp config from: %s", mcpConfigPath)
+	}
+	mcpConfig, err := goskills_mcp.LoadConfig(mcpConfigPath)
+	if err != nil {
+		log.Warn("failed to load mcp config: %v", err)
+		return nil
+	}
+	mcpClient, err := goskills_mcp.NewClient(ctx, mcpConfig)
+	if err != nil {
+		log.Warn("failed to create mcp client: %v", err)
+		return nil
+	}
+	if cfg.Verbose >= 1 {
+		log.Info("mcp client initialized")
+	}
+	return mcpClient
+}
+
+// startProfiling starts a CPU profile written to dir/cpu.prof. The returned function
+// stops it, writes a heap profile to dir/mem.prof and prints both paths to stderr.
+func startProfiling(dir string, stderr io.Writer) (func() error, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	cpuPath := filepath.Join(dir, "cpu.prof")
+	cpuFile, err := os.Create(cpuPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpuFile.Close(); err != nil {
+			return fmt.Errorf("failed to close CPU profile: %w", err)
+		}
+		fmt.Fprintf(stderr, "CPU profile written to %s\n", cpuPath)
+
+		memPath := filepath.Join(dir, "mem.prof")
+		memFile, err := os.Create(memPath)
+		if err != nil {
+			return fmt.Errorf("failed to create memory profile: %w", err)
+		}
+		defer memFile.Close()
+		runtime.GC() // Get up-to-date statistics
+		if err := pprof.WriteHeapProfile(memFile); err != nil {
+			return fmt.Errorf("failed to write memory profile: %w", err)
+		}
+		fmt.Fprintf(stderr, "Memory profile written to %s\n", memPath)
+		return nil
+	}, nil
+}
+
+var (
+	forceDownload bool
+	autoUpgrade   bool
+)
 
 var downloadCmd = &cobra.Command{
 	Use:   "download <github_url>",
@@ -187,25 +335,60 @@ Examples:
 		}
 		targetDir := filepath.Join(skillsDir, skillName)
 
-		// Check if skill already exists
-		if _, err := os.Stat(targetDir); err == nil {
-			if forceDownload {
-				log.Info("Skill '%s' already exists, removing due to -f flag...", skillName)
-				if err := os.RemoveAll(targetDir); err != nil {
-					return fmt.Errorf("failed to remove existing directory: %w", err)
-				}
-			} else {
-				return fmt.Errorf("skill '%s' already exists in %s (use -f to force overwrite)", skillName, targetDir)
+		if _, err := os.Stat(targetDir); err != nil {
+			log.Info("Downloading skill '%s' from GitHub...", skillName)
+
+			// Download files from GitHub
+			if err := downloadGitHubDirectory(owner, repo, branch, dirPath, targetDir); err != nil {
+				return fmt.Errorf("failed to download skill: %w", err)
 			}
+
+			recordInstall(skillName, githubURL, targetDir)
+			log.Info("Successfully downloaded skill to: %s", targetDir)
+			return nil
 		}
 
-		log.Info("Downloading skill '%s' from GitHub...", skillName)
+		// The skill already exists: download the remote copy next to it so the
+		// versions can be compared before anything is replaced
+		log.Info("Skill '%s' already exists, checking remote version...", skillName)
+		stagingDir, err := os.MkdirTemp(skillsDir, ".download-"+skillName+"-")
+		if err != nil {
+			return fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		defer os.RemoveAll(stagingDir)
 
-		// Download files from GitHub
-		if err := downloadGitHubDirectory(owner, repo, branch, dirPath, targetDir); err != nil {
+		if err := downloadGitHubDirectory(owner, repo, branch, dirPath, stagingDir); err != nil {
 			return fmt.Errorf("failed to download skill: %w", err)
 		}
 
+		localVersion := skillVersion(targetDir)
+		remoteVersion := skillVersion(stagingDir)
+		action, err := decideUpgrade(localVersion, remoteVersion, forceDownload, autoUpgrade)
+		if err != nil {
+			return fmt.Errorf("skill '%s' already exists in %s: %w", skillName, targetDir, err)
+		}
+
+		switch action {
+		case upgradeSkip:
+			log.Info("Skill '%s' is up to date (installed %s, remote %s)", skillName, localVersion, remoteVersion)
+			return nil
+		case upgradePrompt:
+			fmt.Fprintf(cmd.OutOrStdout(), "Upgrade skill '%s' from %s to %s? (y/N): ", skillName, localVersion, remoteVersion)
+			answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+				log.Info("Upgrade of skill '%s' cancelled", skillName)
+				return nil
+			}
+		}
+
+		if err := os.RemoveAll(targetDir); err != nil {
+			return fmt.Errorf("failed to remove existing directory: %w", err)
+		}
+		if err := os.Rename(stagingDir, targetDir); err != nil {
+			return fmt.Errorf("failed to install downloaded skill: %w", err)
+		}
+
+		recordInstall(skillName, githubURL, targetDir)
 		log.Info("Successfully downloaded skill to: %s", targetDir)
 		return nil
 	},
@@ -213,6 +396,46 @@ Examples:
 
 func init() {
 	downloadCmd.Flags().BoolVarP(&forceDownload, "force", "f", false, "Force remove existing directory before downloading")
+	downloadCmd.Flags().BoolVar(&autoUpgrade, "auto-upgrade", false, "Replace an existing skill without prompting when the remote version is newer")
+}
+
+// upgradeAction describes what download should do with an already installed skill.
+type upgradeAction int
+
+const (
+	upgradeSkip    upgradeAction = iota // Keep the installed skill
+	upgradePrompt                       // Ask the user before replacing it
+	upgradeReplace                      // Replace it without asking
+)
+
+// decideUpgrade chooses how to handle an existing skill given the installed and remote
+// versions. When either version is not valid semver it falls back to the force flag,
+// returning an error if force is not set.
+func decideUpgrade(localVersion, remoteVersion string, force, autoUpgrade bool) (upgradeAction, error) {
+	if force {
+		return upgradeReplace, nil
+	}
+
+	cmp, err := goskills.CompareVersions(remoteVersion, localVersion)
+	if err != nil {
+		return upgradeSkip, fmt.Errorf("cannot compare versions (%v); use -f to force overwrite", err)
+	}
+	if cmp <= 0 {
+		return upgradeSkip, nil
+	}
+	if autoUpgrade {
+		return upgradeReplace, nil
+	}
+	return upgradePrompt, nil
+}
+
+// skillVersion returns the version declared in the skill's frontmatter, or "" if unavailable.
+func skillVersion(dir string) string {
+	pkg, err := goskills.ParseSkillPackage(dir)
+	if err != nil {
+		return ""
+	}
+	return pkg.Meta.Version
 }
 
 // parseGitHubURL parses a GitHub URL and extracts owner, repo, branch, and directory path
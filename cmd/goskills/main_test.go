@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/pprof/profile"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideUpgrade(t *testing.T) {
+	testCases := []struct {
+		name          string
+		local, remote string
+		force, auto   bool
+		expected      upgradeAction
+		expectErr     bool
+	}{
+		{name: "remote newer prompts", local: "1.0.0", remote: "1.1.0", expected: upgradePrompt},
+		{name: "remote newer auto-upgrades", local: "1.0.0", remote: "1.1.0", auto: true, expected: upgradeReplace},
+		{name: "same version skips", local: "1.1.0", remote: "1.1.0", auto: true, expected: upgradeSkip},
+		{name: "remote older skips", local: "2.0.0", remote: "1.9.0", auto: true, expected: upgradeSkip},
+		{name: "force replaces older remote", local: "2.0.0", remote: "1.9.0", force: true, expected: upgradeReplace},
+		{name: "missing local version needs force", local: "", remote: "1.0.0", expectErr: true},
+		{name: "invalid remote version needs force", local: "1.0.0", remote: "latest", auto: true, expectErr: true},
+		{name: "invalid version with force replaces", local: "", remote: "", force: true, expected: upgradeReplace},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			action, err := decideUpgrade(tc.local, tc.remote, tc.force, tc.auto)
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "-f")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, action)
+		})
+	}
+}
+
+func TestSkillVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"),
+		[]byte("---\nname: demo\ndescription: Demo skill\nversion: 1.4.2\n---\nBody"), 0644))
+	assert.Equal(t, "1.4.2", skillVersion(dir))
+
+	assert.Equal(t, "", skillVersion(filepath.Join(dir, "missing")))
+}
+
+func TestStartProfiling(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "profiles")
+	stderr := new(bytes.Buffer)
+
+	stop, err := startProfiling(dir, stderr)
+	require.NoError(t, err)
+
+	// Do some work so the profiles have something to record.
+	var sb strings.Builder
+	for i := 0; i < 100000; i++ {
+		sb.WriteString("profile")
+	}
+	_ = sb.String()
+
+	require.NoError(t, stop())
+
+	for _, name := range []string{"cpu.prof", "mem.prof"} {
+		path := filepath.Join(dir, name)
+		f, err := os.Open(path)
+		require.NoError(t, err, name)
+		p, err := profile.Parse(f)
+		f.Close()
+		require.NoError(t, err, "%s is not a valid pprof profile", name)
+		assert.NotEmpty(t, p.SampleType, name)
+		assert.Contains(t, stderr.String(), path)
+	}
+}
+
+func TestRunCmd_InjectFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	upstream, requests := newUpstreamLLM(t, "42 pages")
+
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "reader")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: reader\ndescription: Answers questions about documents\n---\nAnswer from the documents."), 0644))
+	docs := t.TempDir()
+	first := filepath.Join(docs, "first.txt")
+	second := filepath.Join(docs, "second.txt")
+	require.NoError(t, os.WriteFile(first, []byte("The report has 42 pages.\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("It was written in 2024."), 0644))
+
+	cmd := &cobra.Command{RunE: runCmd.RunE}
+	setupFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--api-key", "test-key",
+		"--api-base", upstream.URL,
+		"--model", "test-model",
+		"--skills-dir", skillsDir,
+		"--skill", "reader",
+		"--inject-file", first,
+		"--inject-file", second,
+	}))
+	require.NoError(t, cmd.RunE(cmd, []string{"How many pages?"}))
+
+	require.NotEmpty(t, *requests)
+	messages := (*requests)[0].Messages
+	assert.Equal(t, "--- Document ---\nThe report has 42 pages.\n--- End ---\n"+
+		"--- Document ---\nIt was written in 2024.\n--- End ---\n"+
+		"How many pages?", messages[len(messages)-1].Content)
+}
+
+func TestRunCmd_Template(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	upstream, requests := newUpstreamLLM(t, "Hello, Ada!")
+
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "greeter")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: greeter\ndescription: Greets people\n---\nGreet the user."), 0644))
+
+	newCmd := func(template string) (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{RunE: runCmd.RunE}
+		setupFlags(cmd)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, cmd.ParseFlags([]string{
+			"--api-key", "test-key",
+			"--api-base", upstream.URL,
+			"--model", "test-model",
+			"--skills-dir", skillsDir,
+			"--skill", "greeter",
+			"--template", template,
+		}))
+		return cmd, &out
+	}
+
+	cmd, out := newCmd(`{"skill": "{{.Skill}}", "answer": "{{.Result}}"}`)
+	require.NoError(t, cmd.RunE(cmd, []string{"Greet Ada"}))
+	assert.Equal(t, `{"skill": "greeter", "answer": "Hello, Ada!"}`+"\n", out.String())
+
+	// A template syntax error is reported before the LLM is called
+	*requests = nil
+	cmd, out = newCmd(`{{.Result`)
+	err := cmd.RunE(cmd, []string{"Greet Ada"})
+	assert.ErrorContains(t, err, "invalid output template")
+	assert.Empty(t, *requests)
+	assert.Empty(t, out.String())
+}
+
+func TestRunCmd_Resume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	upstream, requests := newUpstreamLLM(t, "Hello again, Ada!")
+
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "greeter")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: greeter\ndescription: Greets people\n---\nGreet the user."), 0644))
+	checkpoint := filepath.Join(t.TempDir(), "run.json")
+	require.NoError(t, os.WriteFile(checkpoint, []byte(`{
+  "messages": [
+    {"role": "system", "content": "Greet the user."},
+    {"role": "user", "content": "Greet Ada"}
+  ],
+  "selected-skill": "greeter",
+  "last-prompt": "Greet Ada"
+}`), 0644))
+
+	resumeCheckpoint = checkpoint
+	defer func() { resumeCheckpoint = "" }()
+	cmd := &cobra.Command{RunE: runCmd.RunE}
+	setupFlags(cmd)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--api-key", "test-key",
+		"--api-base", upstream.URL,
+		"--model", "test-model",
+		"--skills-dir", skillsDir,
+	}))
+	require.NoError(t, cmd.RunE(cmd, nil))
+	assert.Equal(t, "Hello again, Ada!\n", out.String())
+
+	// The restored conversation is sent as is, without selecting a skill
+	require.Len(t, *requests, 1)
+	messages := (*requests)[0].Messages
+	require.Len(t, messages, 2)
+	assert.Equal(t, "Greet the user.", messages[0].Content)
+	assert.Equal(t, "Greet Ada", messages[1].Content)
+
+	assert.EqualError(t, cmd.RunE(cmd, []string{"Greet Bob"}), "--resume cannot be combined with a prompt")
+}
+
+func TestRenderOutput(t *testing.T) {
+	tmpl, err := parseOutputTemplate("{{.Skill}} took {{.Duration}} and {{.TokenUsage.TotalTokens}} tokens:\n{{.Result}}\n", "")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, renderOutput(&out, tmpl, OutputTemplateData{
+		Result:     "done",
+		Skill:      "pdf",
+		Duration:   1500 * time.Millisecond,
+		TokenUsage: openai.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	}))
+	assert.Equal(t, "pdf took 1.5s and 120 tokens:\ndone\n", out.String())
+
+	// Unknown fields fail when rendering
+	tmpl, err = parseOutputTemplate("{{.Missing}}", "")
+	require.NoError(t, err)
+	assert.Error(t, renderOutput(&out, tmpl, OutputTemplateData{}))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smallnest/goskills/registry"
+	"github.com/smallnest/goskills/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchIndexURL string
+	searchCacheTTL time.Duration
+	searchInstall  bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Searches the skill registry",
+	Long: `Searches the hosted skill index for skills whose name or description contains
+every word of the query. The index is cached in ~/.goskills/registry-index.json
+and fetched again once the cache is older than --cache-ttl.
+
+Install a result with 'goskills download <github_url>', or pass --install to
+install the best match into ~/.goskills/skills.
+Examples:
+  goskills search pdf
+  goskills search --install pdf forms`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cachePath, err := registry.DefaultCachePath()
+		if err != nil {
+			return err
+		}
+		reg := &registry.SkillRegistry{
+			IndexURL:  searchIndexURL,
+			CachePath: cachePath,
+			CacheTTL:  searchCacheTTL,
+		}
+		entries, err := reg.Search(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		printRegistryEntries(cmd.OutOrStdout(), entries)
+		if !searchInstall || len(entries) == 0 {
+			return nil
+		}
+
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return installRegistryEntry(cmd.OutOrStdout(), reg, entries[0], filepath.Join(homeDir, ".goskills", "skills"))
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchIndexURL, "index-url", registry.DefaultIndexURL, "URL of the skill registry index")
+	searchCmd.Flags().DurationVar(&searchCacheTTL, "cache-ttl", registry.DefaultCacheTTL, "How long the cached index is used before it is fetched again")
+	searchCmd.Flags().BoolVar(&searchInstall, "install", false, "Install the best match into ~/.goskills/skills")
+}
+
+// printRegistryEntries prints the search results, best match first.
+func printRegistryEntries(out io.Writer, entries []registry.RegistryEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No skills found.")
+		return
+	}
+	for _, e := range entries {
+		fmt.Fprintf(out, "%s %s  (%d stars, %d downloads)\n", e.Name, displayVersion(e.Version), e.Stars, e.Downloads)
+		if e.Description != "" {
+			fmt.Fprintf(out, "    %s\n", e.Description)
+		}
+		fmt.Fprintf(out, "    %s\n", e.GitHubURL)
+	}
+}
+
+// installRegistryEntry downloads entry into skillsDir and records it in the manifest.
+// An installed skill of the same name is left untouched.
+func installRegistryEntry(out io.Writer, reg *registry.SkillRegistry, entry registry.RegistryEntry, skillsDir string) error {
+	targetDir := filepath.Join(skillsDir, entry.Name)
+	if _, err := os.Stat(targetDir); err == nil {
+		ui.New(out).Warning(fmt.Sprintf("Skill '%s' already exists in %s; use 'goskills update' to upgrade it", entry.Name, targetDir))
+		return nil
+	}
+	if err := reg.Download(entry, targetDir); err != nil {
+		os.RemoveAll(targetDir)
+		return err
+	}
+	recordInstall(entry.Name, entry.GitHubURL, targetDir)
+	ui.New(out).Success(fmt.Sprintf("Skill '%s' installed to %s", entry.Name, targetDir))
+	return nil
+}
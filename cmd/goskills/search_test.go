@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smallnest/goskills/manifest"
+	"github.com/smallnest/goskills/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintRegistryEntries(t *testing.T) {
+	out := new(bytes.Buffer)
+	printRegistryEntries(out, []registry.RegistryEntry{
+		{Name: "pdf", Description: "PDF tools.", Version: "1.2.0", GitHubURL: "https://github.com/acme/skills/tree/main/pdf", Stars: 85, Downloads: 1200},
+		{Name: "notes", GitHubURL: "https://github.com/acme/notes"},
+	})
+	assert.Equal(t, `pdf 1.2.0  (85 stars, 1200 downloads)
+    PDF tools.
+    https://github.com/acme/skills/tree/main/pdf
+notes (none)  (0 stars, 0 downloads)
+    https://github.com/acme/notes
+`, out.String())
+
+	out.Reset()
+	printRegistryEntries(out, nil)
+	assert.Equal(t, "No skills found.\n", out.String())
+}
+
+func TestInstallRegistryEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/raw/SKILL.md" {
+			w.Write([]byte("---\nname: pdf\ndescription: PDF tools\nversion: 1.2.0\n---\nBody"))
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]string{
+			{"name": "SKILL.md", "path": "pdf/SKILL.md", "type": "file", "download_url": server.URL + "/raw/SKILL.md"},
+		})
+	}))
+	defer server.Close()
+
+	reg := &registry.SkillRegistry{GitHubAPIURL: server.URL}
+	entry := registry.RegistryEntry{Name: "pdf", GitHubURL: "https://github.com/acme/skills/tree/main/pdf"}
+	skillsDir := t.TempDir()
+
+	out := new(bytes.Buffer)
+	require.NoError(t, installRegistryEntry(out, reg, entry, skillsDir))
+	assert.Contains(t, out.String(), "Skill 'pdf' installed")
+	assert.Equal(t, "1.2.0", skillVersion(filepath.Join(skillsDir, "pdf")))
+
+	manifestPath, err := manifest.DefaultPath()
+	require.NoError(t, err)
+	m, err := manifest.Load(manifestPath)
+	require.NoError(t, err)
+	recorded, ok := m.Get("pdf")
+	require.True(t, ok)
+	assert.Equal(t, entry.GitHubURL, recorded.SourceURL)
+
+	// An installed skill is not overwritten
+	require.NoError(t, os.WriteFile(filepath.Join(skillsDir, "pdf", "local.txt"), []byte("keep"), 0644))
+	out.Reset()
+	require.NoError(t, installRegistryEntry(out, reg, entry, skillsDir))
+	assert.Contains(t, out.String(), "already exists")
+	assert.FileExists(t, filepath.Join(skillsDir, "pdf", "local.txt"))
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	goskills_agent "github.com/smallnest/goskills/agent"
+	"github.com/smallnest/goskills/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serveHost     string
+	servePort     int
+	serveGRPCPort int
+	serveToken    string
+)
+
+// serveTokenEnv is the environment variable read when --token is not given.
+const serveTokenEnv = "GOSKILLS_SERVE_TOKEN"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serves the agent as an OpenAI-compatible chat completions API.",
+	Long: `Starts an HTTP server implementing the OpenAI /v1/chat/completions endpoint so
+existing OpenAI clients can use goskills. The last user message of each request is
+used as the prompt and a skill is selected for it automatically. Streaming
+responses are supported with "stream": true: while the agent works, the stream
+carries SSE comments naming each tool call and result, plus keepalives, and the
+answer follows as a single chunk.
+
+Requests are answered one at a time, each in a fresh conversation; a request
+waits for the one in progress to finish.
+
+GET /api/agent-state returns the agent's state for monitoring: the number of LLM
+and tool calls, the tokens used, the last selected skill and the MCP server
+connection statuses.
+
+With --grpc-port the agent is also served over gRPC (service goskills.v1.GoSkillsService,
+see agent/goskills.proto) for Go services that embed goskills.
+
+The server listens on 127.0.0.1 unless --host is given. With --token (or the
+GOSKILLS_SERVE_TOKEN environment variable) every request must send the header
+"Authorization: Bearer <token>", or the same "authorization" metadata over gRPC. As tools run on this machine, serving on a
+non-loopback address without a token is refused while tool calls are
+auto-approved.
+
+All flags of the run command apply to every request.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if cfg.Loop || cfg.Watch {
+			return errors.New("--loop and --watch cannot be used with serve")
+		}
+		token := serveToken
+		if token == "" {
+			token = os.Getenv(serveTokenEnv)
+		}
+		autoApprove := cfg.ApprovalMode == goskills.ApprovalModeAuto || (cfg.ApprovalMode == "" && cfg.AutoApproveTools)
+		if err := checkServeExposure(serveHost, token, autoApprove); err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		mcpClient := loadMCPClient(ctx, cfg)
+		if mcpClient != nil {
+			defer mcpClient.Close()
+		}
+
+		runnerCfg := cfg.runnerConfig()
+		agent, err := goskills.NewAgent(runnerCfg, mcpClient)
+		if err != nil {
+			return fmt.Errorf("failed to create agent: %w", err)
+		}
+
+		if serveGRPCPort > 0 {
+			// The gRPC service gets an agent of its own so requests over both
+			// protocols can run at the same time
+			grpcAgent, err := goskills.NewAgent(runnerCfg, mcpClient)
+			if err != nil {
+				return fmt.Errorf("failed to create agent: %w", err)
+			}
+			listener, err := net.Listen("tcp", net.JoinHostPort(serveHost, strconv.Itoa(serveGRPCPort)))
+			if err != nil {
+				return fmt.Errorf("failed to listen on gRPC port: %w", err)
+			}
+			grpcServer := grpc.NewServer(grpcAuthOptions(token)...)
+			goskills_agent.NewServer(grpcAgent).Register(grpcServer)
+			defer grpcServer.GracefulStop()
+			go func() {
+				if err := grpcServer.Serve(listener); err != nil {
+					log.Error("gRPC server failed: %v", err)
+				}
+			}()
+			log.Info("serving gRPC API on %s", listener.Addr())
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort(serveHost, strconv.Itoa(servePort)))
+		if err != nil {
+			return fmt.Errorf("failed to listen: %w", err)
+		}
+		chat := newChatServer(agent, runnerCfg.Model)
+		chat.token = token
+		server := &http.Server{Handler: chat.handler()}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}()
+
+		log.Info("serving OpenAI-compatible API on http://%s/v1", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// promptRunner runs a single prompt with the given conversation history and reports
+// the agent's state. *goskills.Agent implements it.
+type promptRunner interface {
+	RunWithMessages(ctx context.Context, userPrompt string, messages []openai.ChatCompletionMessage) (string, []openai.ChatCompletionMessage, error)
+	Introspect() goskills.AgentState
+}
+
+// checkServeExposure refuses to let anyone who can reach host run tools on this
+// machine: without a token, tool calls may only be auto-approved on a loopback address.
+func checkServeExposure(host, token string, autoApprove bool) error {
+	if token == "" && autoApprove && !isLoopbackHost(host) {
+		return fmt.Errorf("refusing to serve on %q without --token while tool calls are auto-approved", host)
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host only accepts connections from this machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validBearerToken reports whether the Authorization header value carries token.
+func validBearerToken(header, token string) bool {
+	got, ok := strings.CutPrefix(header, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// grpcAuthOptions makes a gRPC server reject calls whose "authorization" metadata
+// does not carry token. No authentication is added when token is empty.
+func grpcAuthOptions(token string) []grpc.ServerOption {
+	if token == "" {
+		return nil
+	}
+	authorize := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range md.Get("authorization") {
+			if validBearerToken(header, token) {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := authorize(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// streamKeepAlive is how often a streaming response sends an SSE comment while
+// no other progress is reported, so proxies and clients do not time it out.
+var streamKeepAlive = 15 * time.Second
+
+// chatServer adapts an agent to the OpenAI chat completions API. Requests are
+// handled one at a time, each in a fresh conversation.
+type chatServer struct {
+	mu    sync.Mutex
+	agent promptRunner
+	model string
+	token string // Bearer token every request must send; no authentication when empty
+}
+
+func newChatServer(agent promptRunner, model string) *chatServer {
+	return &chatServer{agent: agent, model: model}
+}
+
+func (s *chatServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/api/agent-state", s.handleAgentState)
+	if s.token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validBearerToken(r.Header.Get("Authorization"), s.token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAPIError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *chatServer) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "only POST is supported")
+		return
+	}
+
+	var req openai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	prompt := lastUserMessage(req.Messages)
+	if prompt == "" {
+		writeAPIError(w, http.StatusBadRequest, "request has no user message")
+		return
+	}
+
+	model := req.Model
+	if model == "" {
+		model = s.model
+	}
+	id := "chatcmpl-" + randomID()
+	created := time.Now().Unix()
+
+	if req.Stream {
+		s.streamCompletion(r.Context(), w, prompt, id, created, model)
+		return
+	}
+
+	s.mu.Lock()
+	result, _, err := s.agent.RunWithMessages(r.Context(), prompt, nil)
+	s.mu.Unlock()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   model,
+		Choices: []openai.ChatCompletionChoice{{
+			Index: 0,
+			Message: openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: result,
+			},
+			FinishReason: openai.FinishReasonStop,
+		}},
+	})
+}
+
+// handleAgentState returns the agent's goskills.AgentState as JSON. A request that is
+// being answered is finished first, as the agent is only inspected between requests.
+func (s *chatServer) handleAgentState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "only GET is supported")
+		return
+	}
+	s.mu.Lock()
+	state := s.agent.Introspect()
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// streamCompletion answers prompt as server-sent chat completion chunks. The
+// assistant role is sent at once; while the agent runs, every tool call and result
+// is reported as an SSE comment, with a keepalive comment after streamKeepAlive of
+// silence. The answer follows as one chunk, then a chunk with finish_reason "stop".
+// If the run fails, an error event in the OpenAI API error format ends the stream.
+func (s *chatServer) streamCompletion(ctx context.Context, w http.ResponseWriter, prompt, id string, created int64, model string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	sse := &sseWriter{w: w}
+
+	chunk := func(delta openai.ChatCompletionStreamChoiceDelta, finish openai.FinishReason) {
+		sse.data(openai.ChatCompletionStreamResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   model,
+			Choices: []openai.ChatCompletionStreamChoice{{Index: 0, Delta: delta, FinishReason: finish}},
+		})
+	}
+	chunk(openai.ChatCompletionStreamChoiceDelta{Role: openai.ChatMessageRoleAssistant}, "")
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(streamKeepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if time.Since(sse.lastWrite()) >= streamKeepAlive {
+					sse.comment("keepalive")
+				}
+			}
+		}
+	}()
+	ctx = goskills.WithToolEvents(ctx, func(ev goskills.ToolEvent) {
+		if ev.Type == goskills.ToolEventCall {
+			sse.comment("tool_call " + ev.ToolName)
+		} else {
+			sse.comment("tool_result " + ev.ToolName)
+		}
+	})
+
+	s.mu.Lock()
+	result, _, err := s.agent.RunWithMessages(ctx, prompt, nil)
+	s.mu.Unlock()
+	close(done)
+
+	if err != nil {
+		sse.data(map[string]any{
+			"error": map[string]any{
+				"message": err.Error(),
+				"type":    "server_error",
+			},
+		})
+	} else {
+		chunk(openai.ChatCompletionStreamChoiceDelta{Content: result}, "")
+		chunk(openai.ChatCompletionStreamChoiceDelta{}, openai.FinishReasonStop)
+	}
+	sse.write("data: [DONE]\n\n")
+}
+
+// sseWriter writes server-sent events from several goroutines, flushing each one.
+type sseWriter struct {
+	mu   sync.Mutex
+	w    http.ResponseWriter
+	last time.Time
+}
+
+func (e *sseWriter) write(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fmt.Fprint(e.w, event)
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	e.last = time.Now()
+}
+
+func (e *sseWriter) lastWrite() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
+
+// data sends v as the JSON payload of a data event.
+func (e *sseWriter) data(v any) {
+	payload, _ := json.Marshal(v)
+	e.write("data: " + string(payload) + "\n\n")
+}
+
+// comment sends an SSE comment, which clients ignore. Line breaks in text are
+// replaced so that it cannot end the comment and inject an event.
+func (e *sseWriter) comment(text string) {
+	text = strings.NewReplacer("\r", " ", "\n", " ").Replace(text)
+	e.write(": " + text + "\n\n")
+}
+
+// lastUserMessage returns the text of the last user message, joining the text
+// parts of multi-part content.
+func lastUserMessage(messages []openai.ChatCompletionMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role != openai.ChatMessageRoleUser {
+			continue
+		}
+		if msg.Content != "" {
+			return msg.Content
+		}
+		var text string
+		for _, part := range msg.MultiContent {
+			if part.Type == openai.ChatMessagePartTypeText {
+				text += part.Text
+			}
+		}
+		return text
+	}
+	return ""
+}
+
+// writeAPIError writes an error in the OpenAI API error format.
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	errType := "invalid_request_error"
+	if status >= http.StatusInternalServerError {
+		errType = "server_error"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"message": message,
+			"type":    errType,
+		},
+	})
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
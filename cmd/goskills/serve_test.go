@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills"
+	goskills_agent "github.com/smallnest/goskills/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newUpstreamLLM starts a fake OpenAI API that answers every chat completion with answer
+// and records the prompts it receives.
+func newUpstreamLLM(t *testing.T, answer string) (*httptest.Server, *[]openai.ChatCompletionRequest) {
+	t.Helper()
+	var requests []openai.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Choices: []openai.ChatCompletionChoice{{
+				Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: answer},
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// newTestChatServer starts the chat completions API backed by a real agent that talks
+// to a fake upstream LLM, and returns a go-openai client for it.
+func newTestChatServer(t *testing.T, answer string) (*openai.Client, *[]openai.ChatCompletionRequest) {
+	client, requests, _ := newTestServer(t, answer)
+	return client, requests
+}
+
+// newTestServer is like newTestChatServer but also returns the server's URL.
+func newTestServer(t *testing.T, answer string) (*openai.Client, *[]openai.ChatCompletionRequest, string) {
+	t.Helper()
+	upstream, requests := newUpstreamLLM(t, answer)
+
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "greeter")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: greeter\ndescription: Greets people\n---\nGreet the user."), 0644))
+
+	agent, err := goskills.NewAgent(goskills.RunnerConfig{
+		APIKey:    "test-key",
+		APIBase:   upstream.URL,
+		Model:     "upstream-model",
+		SkillsDir: skillsDir,
+		SkillName: "greeter",
+	}, nil)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newChatServer(agent, "upstream-model").handler())
+	t.Cleanup(server.Close)
+
+	config := openai.DefaultConfig("unused")
+	config.BaseURL = server.URL + "/v1"
+	return openai.NewClientWithConfig(config), requests, server.URL
+}
+
+func TestServe_ChatCompletion(t *testing.T) {
+	client, requests := newTestChatServer(t, "Hello, Ada!")
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model: "goskills",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "You are helpful."},
+			{Role: openai.ChatMessageRoleUser, Content: "Say hi to Bob"},
+			{Role: openai.ChatMessageRoleAssistant, Content: "Hi Bob!"},
+			{Role: openai.ChatMessageRoleUser, Content: "Now greet Ada"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "Hello, Ada!", resp.Choices[0].Message.Content)
+	assert.Equal(t, openai.ChatMessageRoleAssistant, resp.Choices[0].Message.Role)
+	assert.Equal(t, openai.FinishReasonStop, resp.Choices[0].FinishReason)
+	assert.Equal(t, "goskills", resp.Model)
+	assert.True(t, strings.HasPrefix(resp.ID, "chatcmpl-"))
+
+	// The last user message is the prompt sent to the upstream LLM.
+	require.Len(t, *requests, 1)
+	upstreamMessages := (*requests)[0].Messages
+	assert.Equal(t, "Now greet Ada", upstreamMessages[len(upstreamMessages)-1].Content)
+	assert.Equal(t, "upstream-model", (*requests)[0].Model)
+}
+
+func TestServe_ChatCompletionStream(t *testing.T) {
+	client, _ := newTestChatServer(t, "Hello, Ada!")
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "goskills",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Greet Ada"}},
+		Stream:   true,
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content strings.Builder
+	var finish openai.FinishReason
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.Len(t, chunk.Choices, 1)
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		if chunk.Choices[0].FinishReason != "" {
+			finish = chunk.Choices[0].FinishReason
+		}
+	}
+	assert.Equal(t, "Hello, Ada!", content.String())
+	assert.Equal(t, openai.FinishReasonStop, finish)
+}
+
+// slowRunner is a promptRunner that answers after a delay, or fails with err.
+type slowRunner struct {
+	delay time.Duration
+	err   error
+}
+
+func (r *slowRunner) RunWithMessages(ctx context.Context, userPrompt string, messages []openai.ChatCompletionMessage) (string, []openai.ChatCompletionMessage, error) {
+	time.Sleep(r.delay)
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	return "Done.", nil, nil
+}
+
+func (r *slowRunner) Introspect() goskills.AgentState {
+	return goskills.AgentState{}
+}
+
+// postStream sends a streaming chat completion request for prompt and returns the raw
+// server-sent events.
+func postStream(t *testing.T, serverURL, prompt string) string {
+	t.Helper()
+	body, err := json.Marshal(openai.ChatCompletionRequest{
+		Model:    "goskills",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: prompt}},
+		Stream:   true,
+	})
+	require.NoError(t, err)
+	resp, err := http.Post(serverURL+"/v1/chat/completions", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	events, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(events)
+}
+
+func TestServe_ChatCompletionStreamToolProgress(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(target, []byte("notes"), 0644))
+
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "Read it."}
+		if calls.Add(1) == 1 {
+			args, _ := json.Marshal(map[string]string{"filePath": target})
+			msg = openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{{
+				ID:       "call-1",
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: "read_file", Arguments: string(args)},
+			}}}
+		}
+		json.NewEncoder(w).Encode(openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{Message: msg}}})
+	}))
+	defer upstream.Close()
+
+	skillsDir := t.TempDir()
+	skillDir := filepath.Join(skillsDir, "reader")
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"),
+		[]byte("---\nname: reader\ndescription: Reads files\n---\nRead the file."), 0644))
+	agent, err := goskills.NewAgent(goskills.RunnerConfig{
+		APIKey:           "test-key",
+		APIBase:          upstream.URL,
+		Model:            "upstream-model",
+		SkillsDir:        skillsDir,
+		SkillName:        "reader",
+		AutoApproveTools: true,
+	}, nil)
+	require.NoError(t, err)
+	server := httptest.NewServer(newChatServer(agent, "upstream-model").handler())
+	defer server.Close()
+
+	events := postStream(t, server.URL, "Read the notes")
+	callAt := strings.Index(events, ": tool_call read_file\n\n")
+	resultAt := strings.Index(events, ": tool_result read_file\n\n")
+	answerAt := strings.Index(events, `"content":"Read it."`)
+	require.True(t, callAt >= 0 && resultAt >= 0 && answerAt >= 0, events)
+	assert.Less(t, callAt, resultAt)
+	assert.Less(t, resultAt, answerAt)
+	assert.True(t, strings.HasSuffix(events, "data: [DONE]\n\n"))
+}
+
+func TestServe_ChatCompletionStreamKeepAlive(t *testing.T) {
+	old := streamKeepAlive
+	streamKeepAlive = 10 * time.Millisecond
+	defer func() { streamKeepAlive = old }()
+
+	server := httptest.NewServer(newChatServer(&slowRunner{delay: 200 * time.Millisecond}, "model").handler())
+	defer server.Close()
+
+	events := postStream(t, server.URL, "Take your time")
+	assert.Less(t, strings.Index(events, `"role":"assistant"`), strings.Index(events, ": keepalive\n\n"))
+	assert.Less(t, strings.Index(events, ": keepalive\n\n"), strings.Index(events, `"content":"Done."`))
+}
+
+func TestServe_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(newChatServer(&slowRunner{err: errors.New("no skill fits")}, "model").handler())
+	defer server.Close()
+
+	events := postStream(t, server.URL, "Impossible")
+	assert.Contains(t, events, `data: {"error":{"message":"no skill fits","type":"server_error"}}`)
+	assert.NotContains(t, events, "finish_reason\":\"stop")
+	assert.True(t, strings.HasSuffix(events, "data: [DONE]\n\n"))
+}
+
+func TestSSEWriter_CommentCannotInjectEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&sseWriter{w: rec}).comment("tool_call evil\n\ndata: [DONE]")
+	assert.Equal(t, ": tool_call evil  data: [DONE]\n\n", rec.Body.String())
+}
+
+func TestServe_AgentState(t *testing.T) {
+	client, _, serverURL := newTestServer(t, "Hello, Ada!")
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "goskills",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Greet Ada"}},
+	})
+	require.NoError(t, err)
+
+	resp, err := http.Get(serverURL + "/api/agent-state")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var state goskills.AgentState
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+	assert.Equal(t, "greeter", state.LastSkillName)
+	assert.Equal(t, 1, state.TotalAPICallCount)
+	assert.Zero(t, state.TotalToolCalls)
+
+	resp, err = http.Post(serverURL+"/api/agent-state", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServe_BearerToken(t *testing.T) {
+	chat := newChatServer(nil, "model")
+	chat.token = "s3cret"
+	server := httptest.NewServer(chat.handler())
+	defer server.Close()
+
+	for _, header := range []string{"", "Bearer wrong", "s3cret", "Basic s3cret"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/agent-state", nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+	}
+
+	// An authorized request reaches the handler, which rejects the method
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/api/agent-state", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+// echoRunner answers every prompt with the prompt itself.
+type echoRunner struct{}
+
+func (echoRunner) RunWithMessages(_ context.Context, prompt string, messages []openai.ChatCompletionMessage) (string, []openai.ChatCompletionMessage, error) {
+	return prompt, messages, nil
+}
+
+func (echoRunner) ActiveSkill() string { return "echo" }
+
+func TestServe_GRPCBearerToken(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpcAuthOptions("s3cret")...)
+	goskills_agent.NewServer(echoRunner{}).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	client := goskills_agent.NewGoSkillsServiceClient(conn)
+	req := &goskills_agent.ExecuteRequest{Prompt: "hi"}
+
+	for _, header := range []string{"", "Bearer wrong", "s3cret"} {
+		ctx := context.Background()
+		if header != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", header)
+		}
+		_, err := client.Execute(ctx, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), header)
+		stream, err := client.ExecuteStream(ctx, req)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), header)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret")
+	resp, err := client.Execute(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "hi", resp.GetResult())
+	stream, err := client.ExecuteStream(ctx, req)
+	require.NoError(t, err)
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "hi", ev.GetContent())
+}
+
+func TestCheckServeExposure(t *testing.T) {
+	assert.NoError(t, checkServeExposure("127.0.0.1", "", true))
+	assert.NoError(t, checkServeExposure("::1", "", true))
+	assert.NoError(t, checkServeExposure("localhost", "", true))
+	assert.NoError(t, checkServeExposure("0.0.0.0", "token", true))
+	assert.NoError(t, checkServeExposure("0.0.0.0", "", false))
+	assert.ErrorContains(t, checkServeExposure("0.0.0.0", "", true), "without --token")
+	assert.ErrorContains(t, checkServeExposure("", "", true), "without --token")
+	assert.ErrorContains(t, checkServeExposure("192.168.1.10", "", true), "without --token")
+}
+
+func TestServe_InvalidRequests(t *testing.T) {
+	client, requests := newTestChatServer(t, "unused")
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "goskills",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: "No user message"}},
+	})
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.HTTPStatusCode)
+	assert.Empty(t, *requests)
+
+	server := httptest.NewServer(newChatServer(nil, "model").handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/v1/chat/completions")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
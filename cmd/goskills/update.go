@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/smallnest/goskills"
+	"github.com/smallnest/goskills/log"
+	"github.com/smallnest/goskills/manifest"
+	"github.com/smallnest/goskills/ui"
+	"github.com/spf13/cobra"
+)
+
+var checkUpdates bool
+
+// fetchSkill downloads the skill at a GitHub URL into targetDir; replaceable in tests.
+var fetchSkill = func(githubURL, targetDir string) error {
+	owner, repo, branch, dirPath, err := parseGitHubURL(githubURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse GitHub URL: %w", err)
+	}
+	return downloadGitHubDirectory(owner, repo, branch, dirPath, targetDir)
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update [skill-name]",
+	Short: "Upgrades installed skills from the URLs they were downloaded from",
+	Long: `Re-downloads each skill recorded in ~/.goskills/manifest.json (or only the named
+skill) from its source URL and replaces the installed copy when the remote version
+is newer. Use --check to only report available upgrades.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		skillsDir := filepath.Join(homeDir, ".goskills", "skills")
+		manifestPath, err := manifest.DefaultPath()
+		if err != nil {
+			return err
+		}
+		m, err := manifest.Load(manifestPath)
+		if err != nil {
+			return err
+		}
+
+		names := m.Names()
+		if len(args) == 1 {
+			names = []string{args[0]}
+		}
+		if len(names) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No skills recorded in the manifest; install skills with 'goskills download'.")
+			return nil
+		}
+
+		var errs []error
+		changed := false
+		for _, name := range names {
+			upgraded, err := updateSkill(cmd.OutOrStdout(), m, skillsDir, name, checkUpdates)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				continue
+			}
+			changed = changed || upgraded
+		}
+		if changed {
+			if err := m.Save(manifestPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	},
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&checkUpdates, "check", false, "Report available upgrades without applying them")
+}
+
+// updateSkill downloads the named skill from its recorded source URL and installs it
+// when the remote version is newer, updating the manifest entry. With check set it only
+// reports what would change. It returns whether the skill was upgraded.
+func updateSkill(out io.Writer, m *manifest.Manifest, skillsDir, name string, check bool) (bool, error) {
+	entry, ok := m.Get(name)
+	if !ok || entry.SourceURL == "" {
+		return false, fmt.Errorf("no source URL recorded for skill '%s'; reinstall it with 'goskills download <url>'", name)
+	}
+
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create skills directory: %w", err)
+	}
+	stagingDir, err := os.MkdirTemp(skillsDir, ".update-"+name+"-")
+	if err != nil {
+		return false, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := fetchSkill(entry.SourceURL, stagingDir); err != nil {
+		return false, fmt.Errorf("failed to download skill: %w", err)
+	}
+
+	targetDir := filepath.Join(skillsDir, name)
+	localVersion := skillVersion(targetDir)
+	remoteVersion := skillVersion(stagingDir)
+	if _, err := os.Stat(targetDir); err == nil {
+		cmp, err := goskills.CompareVersions(remoteVersion, localVersion)
+		if err != nil {
+			return false, fmt.Errorf("cannot compare versions: %w", err)
+		}
+		if cmp <= 0 {
+			fmt.Fprintf(out, "%s is up to date (%s)\n", name, localVersion)
+			return false, nil
+		}
+	}
+
+	if check {
+		ui.New(out).Warning(fmt.Sprintf("%s can be upgraded from %s to %s", name, displayVersion(localVersion), remoteVersion))
+		return false, nil
+	}
+
+	if err := os.RemoveAll(targetDir); err != nil {
+		return false, fmt.Errorf("failed to remove existing directory: %w", err)
+	}
+	if err := os.Rename(stagingDir, targetDir); err != nil {
+		return false, fmt.Errorf("failed to install downloaded skill: %w", err)
+	}
+
+	entry.Version = remoteVersion
+	entry.InstalledAt = time.Now().UTC()
+	m.Set(entry)
+	ui.New(out).Success(fmt.Sprintf("%s upgraded from %s to %s", name, displayVersion(localVersion), remoteVersion))
+	return true, nil
+}
+
+// recordInstall stores the source URL and version of a freshly installed skill in the
+// manifest. Failures are only logged since the skill itself was installed.
+func recordInstall(name, sourceURL, skillDir string) {
+	manifestPath, err := manifest.DefaultPath()
+	if err != nil {
+		log.Warn("failed to record skill in manifest: %v", err)
+		return
+	}
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		log.Warn("failed to record skill in manifest: %v", err)
+		return
+	}
+	m.Set(manifest.Entry{
+		Name:        name,
+		SourceURL:   sourceURL,
+		Version:     skillVersion(skillDir),
+		InstalledAt: time.Now().UTC(),
+	})
+	if err := m.Save(manifestPath); err != nil {
+		log.Warn("failed to record skill in manifest: %v", err)
+	}
+}
+
+// displayVersion returns v, or "(none)" when it is empty.
+func displayVersion(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smallnest/goskills/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeVersionedSkill(t *testing.T, dir, name, version string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	content := fmt.Sprintf("---\nname: %s\ndescription: Demo skill\nversion: %s\n---\nBody", name, version)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+}
+
+// fakeFetchSkill replaces fetchSkill with one that writes the given remote version and
+// counts how often it is called.
+func fakeFetchSkill(t *testing.T, name, remoteVersion string) *int {
+	t.Helper()
+	calls := 0
+	original := fetchSkill
+	fetchSkill = func(githubURL, targetDir string) error {
+		calls++
+		writeVersionedSkill(t, targetDir, name, remoteVersion)
+		return nil
+	}
+	t.Cleanup(func() { fetchSkill = original })
+	return &calls
+}
+
+func TestUpdateSkill(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeVersionedSkill(t, filepath.Join(skillsDir, "demo"), "demo", "1.0.0")
+	m := &manifest.Manifest{}
+	m.Set(manifest.Entry{Name: "demo", SourceURL: "https://github.com/o/r/tree/main/demo", Version: "1.0.0"})
+	fakeFetchSkill(t, "demo", "1.2.0")
+
+	// --check reports the upgrade without applying it
+	out := new(bytes.Buffer)
+	upgraded, err := updateSkill(out, m, skillsDir, "demo", true)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+	assert.Contains(t, out.String(), "demo can be upgraded from 1.0.0 to 1.2.0")
+	assert.Equal(t, "1.0.0", skillVersion(filepath.Join(skillsDir, "demo")))
+
+	out.Reset()
+	upgraded, err = updateSkill(out, m, skillsDir, "demo", false)
+	require.NoError(t, err)
+	assert.True(t, upgraded)
+	assert.Contains(t, out.String(), "demo upgraded from 1.0.0 to 1.2.0")
+	assert.Equal(t, "1.2.0", skillVersion(filepath.Join(skillsDir, "demo")))
+	entry, _ := m.Get("demo")
+	assert.Equal(t, "1.2.0", entry.Version)
+	assert.False(t, entry.InstalledAt.IsZero())
+
+	// No staging directories are left behind
+	entries, err := os.ReadDir(skillsDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestUpdateSkill_UpToDate(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeVersionedSkill(t, filepath.Join(skillsDir, "demo"), "demo", "2.0.0")
+	m := &manifest.Manifest{}
+	m.Set(manifest.Entry{Name: "demo", SourceURL: "https://github.com/o/r/tree/main/demo", Version: "2.0.0"})
+	fakeFetchSkill(t, "demo", "2.0.0")
+
+	out := new(bytes.Buffer)
+	upgraded, err := updateSkill(out, m, skillsDir, "demo", false)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+	assert.Contains(t, out.String(), "demo is up to date (2.0.0)")
+}
+
+func TestUpdateSkill_MissingSourceURL(t *testing.T) {
+	calls := fakeFetchSkill(t, "demo", "1.0.0")
+	m := &manifest.Manifest{}
+	m.Set(manifest.Entry{Name: "local"})
+
+	for _, name := range []string{"local", "unknown"} {
+		_, err := updateSkill(new(bytes.Buffer), m, t.TempDir(), name, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no source URL recorded")
+	}
+	assert.Zero(t, *calls)
+}
+
+func TestUpdateCmd_SavesManifest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeVersionedSkill(t, filepath.Join(home, ".goskills", "skills", "demo"), "demo", "1.0.0")
+	manifestPath := filepath.Join(home, ".goskills", "manifest.json")
+	m := &manifest.Manifest{}
+	m.Set(manifest.Entry{Name: "demo", SourceURL: "https://github.com/o/r/tree/main/demo", Version: "1.0.0"})
+	m.Set(manifest.Entry{Name: "orphan"})
+	require.NoError(t, m.Save(manifestPath))
+	fakeFetchSkill(t, "demo", "1.1.0")
+
+	out := new(bytes.Buffer)
+	updateCmd.SetOut(out)
+	err := updateCmd.RunE(updateCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "orphan")
+	assert.Contains(t, out.String(), "demo upgraded from 1.0.0 to 1.1.0")
+
+	saved, err := manifest.Load(manifestPath)
+	require.NoError(t, err)
+	entry, _ := saved.Get("demo")
+	assert.Equal(t, "1.1.0", entry.Version)
+}
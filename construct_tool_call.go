@@ -0,0 +1,104 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/tool"
+	"github.com/smallnest/goskills/trace"
+)
+
+// constructToolCall answers a construct_tool_call request: it asks the LLM, in a
+// separate conversation, for the JSON arguments of the named tool and returns the
+// resulting call under the ID of tc, ready to be executed in its place. Errors are
+// *skerrors.ToolError.
+func (a *Agent) constructToolCall(ctx context.Context, tc openai.ToolCall, availableTools []openai.Tool) (constructed openai.ToolCall, err error) {
+	defer func() {
+		if err != nil {
+			err = &skerrors.ToolError{ToolName: tool.ConstructToolCallName, Err: err}
+		}
+	}()
+
+	var params struct {
+		ToolName          string `json:"tool_name"`
+		ParamsDescription string `json:"params_description"`
+	}
+	if err := json.Unmarshal([]byte(tc.Function.Arguments), &params); err != nil {
+		return openai.ToolCall{}, fmt.Errorf("failed to unmarshal %s arguments: %w", tool.ConstructToolCallName, err)
+	}
+	if params.ToolName == tool.ConstructToolCallName {
+		return openai.ToolCall{}, fmt.Errorf("%s cannot call itself", tool.ConstructToolCallName)
+	}
+	var target *openai.FunctionDefinition
+	for _, t := range availableTools {
+		if t.Function != nil && t.Function.Name == params.ToolName {
+			target = t.Function
+			break
+		}
+	}
+	if target == nil {
+		return openai.ToolCall{}, fmt.Errorf("unknown tool '%s'", params.ToolName)
+	}
+
+	schema, err := json.Marshal(target.Parameters)
+	if err != nil {
+		return openai.ToolCall{}, fmt.Errorf("failed to marshal parameters of %s: %w", target.Name, err)
+	}
+	req := openai.ChatCompletionRequest{
+		Model: a.cfg.Model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "You construct the JSON arguments of a function call. Respond with ONLY a JSON object that matches the function's parameters schema, nothing else.",
+			},
+			{
+				Role: openai.ChatMessageRoleUser,
+				Content: fmt.Sprintf("Function: %s\nDescription: %s\nParameters schema: %s\n\nArguments to construct: %s",
+					target.Name, target.Description, schema, params.ParamsDescription),
+			},
+		},
+		Temperature: 0,
+	}
+
+	a.debugPrintRequest(req)
+	a.traceRequest(trace.StageExecution, req)
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return openai.ToolCall{}, newAPIError("ChatCompletion", err)
+	}
+	a.debugPrintResponse(resp)
+	a.traceResponse(trace.StageExecution, resp)
+	a.trackCost(resp.Usage)
+	if len(resp.Choices) == 0 {
+		return openai.ToolCall{}, errors.New("no choices in response")
+	}
+
+	arguments := stripCodeFence(resp.Choices[0].Message.Content)
+	var args map[string]any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return openai.ToolCall{}, fmt.Errorf("constructed arguments for %s are not a JSON object: %w", target.Name, err)
+	}
+	return openai.ToolCall{
+		ID:       tc.ID,
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: target.Name, Arguments: arguments},
+	}, nil
+}
+
+// stripCodeFence removes a Markdown code fence, such as ```json ... ```, around s.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:] // Drop the language tag
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
@@ -0,0 +1,128 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolMessage returns the content of the tool result for callID in req.
+func toolMessage(t *testing.T, req openai.ChatCompletionRequest, callID string) string {
+	t.Helper()
+	for _, msg := range req.Messages {
+		if msg.Role == openai.ChatMessageRoleTool && msg.ToolCallID == callID {
+			return msg.Content
+		}
+	}
+	t.Fatalf("no tool result for %s", callID)
+	return ""
+}
+
+func TestContinueSkillWithTools_ConstructToolCall(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", tool.ConstructToolCallName, `{"tool_name": "write_file", "params_description": "write the word hello to `+out+`"}`),
+		textResponse("```json\n{\"filePath\": \"" + out + "\", \"content\": \"hello\"}\n```"),
+		textResponse("done"),
+	}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true}}
+	skill := &SkillPackage{Path: t.TempDir(), Body: "Write files.", Meta: SkillMeta{Name: "writer"}}
+
+	result, err := agent.executeSkillWithTools(context.Background(), "save hello", skill)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// The second request constructs the arguments in a conversation of its own
+	require.Len(t, mockClient.requests, 3)
+	construct := mockClient.requests[1]
+	require.Len(t, construct.Messages, 2)
+	assert.Empty(t, construct.Tools)
+	assert.Contains(t, construct.Messages[1].Content, "Function: write_file")
+	assert.Contains(t, construct.Messages[1].Content, `"filePath"`)
+	assert.Contains(t, construct.Messages[1].Content, "write the word hello to "+out)
+
+	// The constructed call's result answers the original call
+	assert.Equal(t, "Successfully wrote to file: "+out, toolMessage(t, mockClient.requests[2], "call-1"))
+}
+
+func TestContinueSkillWithTools_ConstructToolCallErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      string
+		responses []openai.ChatCompletionResponse
+		want      string
+	}{
+		{
+			name: "unknown tool",
+			args: `{"tool_name": "launch_rocket", "params_description": "now"}`,
+			want: "unknown tool 'launch_rocket'",
+		},
+		{
+			name: "calling itself",
+			args: `{"tool_name": "construct_tool_call", "params_description": "again"}`,
+			want: "construct_tool_call cannot call itself",
+		},
+		{
+			name:      "invalid JSON",
+			args:      `{"tool_name": "read_file", "params_description": "the README"}`,
+			responses: []openai.ChatCompletionResponse{textResponse("filePath=README.md")},
+			want:      "constructed arguments for read_file are not a JSON object",
+		},
+		{
+			name:      "failing tool",
+			args:      `{"tool_name": "read_file", "params_description": "a missing file"}`,
+			responses: []openai.ChatCompletionResponse{textResponse(`{"filePath": "/nonexistent/file.txt"}`)},
+			want:      "failed to read file '/nonexistent/file.txt'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			responses := append([]openai.ChatCompletionResponse{toolCallResponse("call-1", tool.ConstructToolCallName, tc.args)}, tc.responses...)
+			responses = append(responses, textResponse("gave up"))
+			mockClient := NewMockOpenAIClient(responses, nil)
+			agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true}}
+			skill := &SkillPackage{Path: t.TempDir(), Body: "Do things.", Meta: SkillMeta{Name: "doer"}}
+
+			result, err := agent.executeSkillWithTools(context.Background(), "go", skill)
+			require.NoError(t, err)
+			assert.Equal(t, "gave up", result)
+			last := mockClient.requests[len(mockClient.requests)-1]
+			assert.Contains(t, toolMessage(t, last, "call-1"), tc.want)
+		})
+	}
+}
+
+func TestConstructToolCall_APIError(t *testing.T) {
+	agent := &Agent{
+		client: NewMockOpenAIClient(nil, &openai.APIError{HTTPStatusCode: 500, Message: "down"}),
+		cfg:    RunnerConfig{Model: "test-model"},
+	}
+	call := toolCallResponse("call-1", tool.ConstructToolCallName, `{"tool_name": "read_file", "params_description": "x"}`).Choices[0].Message.ToolCalls[0]
+
+	_, err := agent.constructToolCall(context.Background(), call, tool.GetBaseTools())
+	var toolErr *skerrors.ToolError
+	require.True(t, errors.As(err, &toolErr))
+	assert.Equal(t, tool.ConstructToolCallName, toolErr.ToolName)
+	var apiErr *skerrors.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 500, apiErr.StatusCode)
+}
+
+func TestStripCodeFence(t *testing.T) {
+	assert.Equal(t, `{"a": 1}`, stripCodeFence("```json\n{\"a\": 1}\n```"))
+	assert.Equal(t, `{"a": 1}`, stripCodeFence("```\n{\"a\": 1}\n```\n"))
+	assert.Equal(t, `{"a": 1}`, stripCodeFence(` {"a": 1} `))
+}
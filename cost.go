@@ -0,0 +1,83 @@
+package goskills
+
+import (
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+)
+
+// DefaultPricingTable holds the USD price per million prompt and completion tokens
+// for common models. Entries in RunnerConfig.PricingTable take precedence.
+var DefaultPricingTable = map[string][2]float64{
+	"gpt-4o":        {2.50, 10.00},
+	"gpt-4o-mini":   {0.15, 0.60},
+	"gpt-4.1":       {2.00, 8.00},
+	"gpt-4.1-mini":  {0.40, 1.60},
+	"gpt-4.1-nano":  {0.10, 0.40},
+	"gpt-4-turbo":   {10.00, 30.00},
+	"gpt-3.5-turbo": {0.50, 1.50},
+	"o1":            {15.00, 60.00},
+	"o3-mini":       {1.10, 4.40},
+	"deepseek-chat": {0.27, 1.10},
+	"deepseek-v3":   {0.27, 1.10},
+}
+
+// lookupPricing finds the prices for model, preferring an exact match and otherwise the
+// longest table key that prefixes model (so "gpt-4o-2024-08-06" uses "gpt-4o").
+func lookupPricing(model string, table map[string][2]float64) ([2]float64, bool) {
+	if price, ok := table[model]; ok {
+		return price, true
+	}
+	var best string
+	for name := range table {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return [2]float64{}, false
+	}
+	return table[best], true
+}
+
+// EstimateCost returns the USD cost of usage for model according to custom, falling back
+// to DefaultPricingTable. It reports false when the model has no known price.
+func EstimateCost(model string, usage openai.Usage, custom map[string][2]float64) (float64, bool) {
+	price, ok := lookupPricing(model, custom)
+	if !ok {
+		price, ok = lookupPricing(model, DefaultPricingTable)
+	}
+	if !ok {
+		return 0, false
+	}
+	return (float64(usage.PromptTokens)*price[0] + float64(usage.CompletionTokens)*price[1]) / 1e6, true
+}
+
+// trackCost counts an LLM call, adds its token usage and estimated cost to the running
+// totals and, at Verbose >= 1, prints the cost.
+func (a *Agent) trackCost(usage openai.Usage) {
+	a.apiCalls++
+	a.totalUsage.PromptTokens += usage.PromptTokens
+	a.totalUsage.CompletionTokens += usage.CompletionTokens
+	a.totalUsage.TotalTokens += usage.TotalTokens
+	cost, ok := EstimateCost(a.cfg.Model, usage, a.cfg.PricingTable)
+	if !ok {
+		return
+	}
+	a.totalCost += cost
+	if a.cfg.Verbose >= 1 {
+		log.Info("[cost: $%.4f running $%.4f]", cost, a.totalCost)
+	}
+}
+
+// TotalCost returns the estimated USD cost of all LLM calls made by the agent so far.
+// It is zero when the model is not in the pricing table.
+func (a *Agent) TotalCost() float64 {
+	return a.totalCost
+}
+
+// TotalUsage returns the combined token usage of all LLM calls made by the agent so far.
+func (a *Agent) TotalUsage() openai.Usage {
+	return a.totalUsage
+}
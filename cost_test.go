@@ -0,0 +1,68 @@
+package goskills
+
+import (
+	"context"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCost(t *testing.T) {
+	usage := openai.Usage{PromptTokens: 1000, CompletionTokens: 500}
+
+	// gpt-4o: $2.50 per million prompt tokens, $10.00 per million completion tokens
+	cost, ok := EstimateCost("gpt-4o", usage, nil)
+	require.True(t, ok)
+	assert.InDelta(t, 0.0075, cost, 1e-12)
+
+	// Dated model names fall back to the longest matching prefix
+	cost, ok = EstimateCost("gpt-4o-mini-2024-07-18", usage, nil)
+	require.True(t, ok)
+	assert.InDelta(t, 0.00045, cost, 1e-12)
+
+	// Custom pricing overrides the defaults and adds new models
+	custom := map[string][2]float64{"gpt-4o": {1, 2}, "my-model": {3, 4}}
+	cost, ok = EstimateCost("gpt-4o", usage, custom)
+	require.True(t, ok)
+	assert.InDelta(t, 0.002, cost, 1e-12)
+	cost, ok = EstimateCost("my-model", usage, custom)
+	require.True(t, ok)
+	assert.InDelta(t, 0.005, cost, 1e-12)
+
+	_, ok = EstimateCost("unknown-model", usage, custom)
+	assert.False(t, ok)
+}
+
+func TestAgent_TotalCost(t *testing.T) {
+	withUsage := func(content string, prompt, completion int) openai.ChatCompletionResponse {
+		resp := textResponse(content)
+		resp.Usage = openai.Usage{PromptTokens: prompt, CompletionTokens: completion}
+		return resp
+	}
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		withUsage("first", 2000, 100),
+		withUsage("second", 3000, 400),
+	}, nil)
+	agent := &Agent{
+		client: client,
+		cfg: RunnerConfig{
+			Model:        "priced-model",
+			Verbose:      1,
+			PricingTable: map[string][2]float64{"priced-model": {1.5, 6}},
+		},
+	}
+	skill := &SkillPackage{Meta: SkillMeta{Name: "test"}}
+
+	_, err := agent.continueSkillWithTools(context.Background(), "one", skill)
+	require.NoError(t, err)
+	// 2000*1.5/1e6 + 100*6/1e6
+	assert.InDelta(t, 0.0036, agent.TotalCost(), 1e-12)
+
+	_, err = agent.continueSkillWithTools(context.Background(), "two", skill)
+	require.NoError(t, err)
+	// plus 3000*1.5/1e6 + 400*6/1e6
+	assert.InDelta(t, 0.0105, agent.TotalCost(), 1e-12)
+	assert.Equal(t, openai.Usage{PromptTokens: 5000, CompletionTokens: 500}, agent.TotalUsage())
+}
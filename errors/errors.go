@@ -0,0 +1,83 @@
+// Package errors defines the error types returned by goskills, so callers can tell
+// failure modes apart with errors.As instead of matching error strings.
+package errors
+
+import "fmt"
+
+// ToolError is returned when a tool call fails, including calls to unknown tools and
+// calls with malformed arguments.
+type ToolError struct {
+	ToolName string
+	Err      error
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool execution failed for %s: %v", e.ToolName, e.Err)
+}
+
+func (e *ToolError) Unwrap() error { return e.Err }
+
+// SkillNotFoundError is returned when a skill is requested or selected that is not
+// among the available skills.
+type SkillNotFoundError struct {
+	Name      string
+	Available []string // Names of the skills that were available, if known
+}
+
+func (e *SkillNotFoundError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("skill '%s' not found", e.Name)
+	}
+	return fmt.Sprintf("skill '%s' not found. Available skills: %v", e.Name, e.Available)
+}
+
+// SelectionError is returned when choosing a skill for a prompt fails.
+type SelectionError struct {
+	Strategy string // The selection strategy that failed, e.g. "llm" or "keyword"
+	Err      error
+}
+
+func (e *SelectionError) Error() string {
+	return fmt.Sprintf("failed during skill selection (strategy: %s): %v", e.Strategy, e.Err)
+}
+
+func (e *SelectionError) Unwrap() error { return e.Err }
+
+// MaxIterationsError is returned when a prompt is still making tool calls after the
+// maximum number of tool call round trips.
+type MaxIterationsError struct {
+	Limit int
+}
+
+func (e *MaxIterationsError) Error() string {
+	return fmt.Sprintf("exceeded maximum tool call iterations (%d)", e.Limit)
+}
+
+// BudgetExceededError is returned when a run needs more of a limited resource, such as
+// tokens, than its budget allows.
+type BudgetExceededError struct {
+	Resource string // What the budget limits, e.g. "tokens"
+	Budget   int
+	Used     int
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s budget of %d exceeded (%d used)", e.Resource, e.Budget, e.Used)
+}
+
+// APIError is returned when a request to the LLM API fails. StatusCode is the HTTP
+// status of the response, or 0 if no response was received.
+type APIError struct {
+	Operation  string // The API call that failed, e.g. "ChatCompletion"
+	StatusCode int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s error: %v", e.Operation, e.Err)
+	}
+	return fmt.Sprintf("%s error (status %d): %v", e.Operation, e.StatusCode, e.Err)
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorMessages(t *testing.T) {
+	cause := errors.New("boom")
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{&ToolError{ToolName: "read_file", Err: cause}, "tool execution failed for read_file: boom"},
+		{&SkillNotFoundError{Name: "pdf"}, "skill 'pdf' not found"},
+		{&SkillNotFoundError{Name: "pdf", Available: []string{"docx"}}, "skill 'pdf' not found. Available skills: [docx]"},
+		{&SelectionError{Strategy: "keyword", Err: cause}, "failed during skill selection (strategy: keyword): boom"},
+		{&MaxIterationsError{Limit: 5}, "exceeded maximum tool call iterations (5)"},
+		{&BudgetExceededError{Resource: "tokens", Budget: 100, Used: 120}, "tokens budget of 100 exceeded (120 used)"},
+		{&APIError{Operation: "ChatCompletion", Err: cause}, "ChatCompletion error: boom"},
+		{&APIError{Operation: "ChatCompletion", StatusCode: 429, Err: cause}, "ChatCompletion error (status 429): boom"},
+	}
+	for _, tc := range testCases {
+		assert.EqualError(t, tc.err, tc.want)
+	}
+}
+
+func TestErrorsAs(t *testing.T) {
+	cause := errors.New("boom")
+	err := fmt.Errorf("run failed: %w", &SelectionError{Strategy: "llm", Err: &APIError{Operation: "ChatCompletion", StatusCode: 500, Err: cause}})
+
+	var selectionErr *SelectionError
+	require.True(t, errors.As(err, &selectionErr))
+	assert.Equal(t, "llm", selectionErr.Strategy)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 500, apiErr.StatusCode)
+	assert.ErrorIs(t, err, cause)
+
+	var toolErr *ToolError
+	assert.False(t, errors.As(err, &toolErr))
+}
@@ -0,0 +1,193 @@
+// Package graph builds the graph of the tools a skill offers the LLM and the scripts
+// they run, and renders it as a Graphviz DOT diagram.
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smallnest/goskills"
+	"github.com/smallnest/goskills/tool"
+)
+
+// NodeKind is the kind of a graph node.
+type NodeKind string
+
+const (
+	KindSkill  NodeKind = "skill"
+	KindTool   NodeKind = "tool"
+	KindScript NodeKind = "script"
+)
+
+// Node is a skill, tool or script.
+type Node struct {
+	ID    string
+	Label string
+	Kind  NodeKind
+}
+
+// Edge connects two nodes by their IDs.
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// SkillGraph is the tool-call graph of a skill: the skill links to every tool it
+// offers, script tools and inline tools link to the scripts they run, and scripts link
+// to the scripts they call.
+type SkillGraph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// baseToolsID is the node standing for all base tools when the skill does not restrict
+// them with allowed-tools.
+const baseToolsID = "tools"
+
+// Build builds the graph of skill, reading its scripts to find the scripts they call.
+// The base tools are shown individually when the skill lists them in allowed-tools and
+// as a single node otherwise.
+func Build(skill *goskills.SkillPackage) (*SkillGraph, error) {
+	g := &SkillGraph{}
+	skillID := "skill"
+	g.addNode(skillID, skill.Meta.Name, KindSkill)
+
+	if len(skill.Meta.AllowedTools) == 0 {
+		g.addNode(baseToolsID, fmt.Sprintf("base tools (%d)", len(tool.GetBaseTools())), KindTool)
+		g.addEdge(skillID, baseToolsID, "")
+	} else {
+		tools, _ := goskills.GenerateToolDefinitions(&goskills.SkillPackage{Meta: goskills.SkillMeta{AllowedTools: skill.Meta.AllowedTools}})
+		for _, t := range tools {
+			g.addNode(toolID(t.Function.Name), t.Function.Name, KindTool)
+			g.addEdge(skillID, toolID(t.Function.Name), "")
+		}
+	}
+
+	// Script tools run their script
+	_, scriptMap := goskills.GenerateToolDefinitions(&goskills.SkillPackage{Path: skill.Path, Resources: skill.Resources})
+	scriptTools := make(map[string]string, len(scriptMap))
+	for name, path := range scriptMap {
+		scriptTools[path] = name
+	}
+	contents := make(map[string]string, len(skill.Resources.Scripts))
+	for _, script := range skill.Resources.Scripts {
+		path := filepath.Join(skill.Path, script)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script %s: %w", script, err)
+		}
+		contents[script] = string(data)
+
+		name := scriptTools[path]
+		g.addNode(toolID(name), name, KindTool)
+		g.addNode(scriptID(script), script, KindScript)
+		g.addEdge(skillID, toolID(name), "")
+		g.addEdge(toolID(name), scriptID(script), "runs")
+	}
+
+	// Inline tools run the scripts their command names
+	for _, inline := range skill.Meta.Tools {
+		g.addNode(toolID(inline.Name), inline.Name, KindTool)
+		g.addEdge(skillID, toolID(inline.Name), "")
+		for _, script := range skill.Resources.Scripts {
+			if mentionsScript(inline.Command, script) {
+				g.addEdge(toolID(inline.Name), scriptID(script), "runs")
+			}
+		}
+	}
+
+	// Scripts call the scripts they name
+	for _, caller := range skill.Resources.Scripts {
+		for _, callee := range skill.Resources.Scripts {
+			if caller != callee && mentionsScript(contents[caller], callee) {
+				g.addEdge(scriptID(caller), scriptID(callee), "calls")
+			}
+		}
+	}
+	return g, nil
+}
+
+func toolID(name string) string {
+	return "tool:" + name
+}
+
+func scriptID(path string) string {
+	return "script:" + filepath.ToSlash(path)
+}
+
+// mentionsScript reports whether text names the script by its file name, on its own or
+// at the end of a path.
+func mentionsScript(text, script string) bool {
+	name := filepath.Base(script)
+	for i := 0; ; {
+		j := strings.Index(text[i:], name)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(name)
+		if (start == 0 || !isNameByte(text[start-1])) && (end == len(text) || !isNameByte(text[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isNameByte reports whether c can be part of a file name next to a script's name.
+func isNameByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (g *SkillGraph) addNode(id, label string, kind NodeKind) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return
+		}
+	}
+	g.Nodes = append(g.Nodes, Node{ID: id, Label: label, Kind: kind})
+}
+
+func (g *SkillGraph) addEdge(from, to, label string) {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Label: label})
+}
+
+// nodeAttrs are the DOT attributes of each node kind.
+var nodeAttrs = map[NodeKind]string{
+	KindSkill:  `shape=box, style="rounded,filled", fillcolor=lightblue`,
+	KindTool:   `shape=ellipse`,
+	KindScript: `shape=note, style=filled, fillcolor=lightyellow`,
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g *SkillGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph skill {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, %s];\n", quote(n.ID), quote(n.Label), nodeAttrs[n.Kind])
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", quote(e.From), quote(e.To), quote(e.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", quote(e.From), quote(e.To))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// quote returns s as a DOT string literal.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
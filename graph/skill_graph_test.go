@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smallnest/goskills"
+	"github.com/smallnest/goskills/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSkill creates a skill with three scripts, where report.sh calls extract.py, and an
+// inline tool running report.sh.
+func writeSkill(t *testing.T, allowedTools string) *goskills.SkillPackage {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(`---
+name: pdf-report
+description: Builds "PDF" reports.
+`+allowedTools+`
+tools:
+  - name: build_report
+    description: Builds the report.
+    command: bash scripts/report.sh {{.input}}
+---
+Use the tools.
+`), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "extract.py"), []byte("print('text')\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "report.sh"),
+		[]byte("#!/bin/sh\npython3 \"$(dirname \"$0\")/extract.py\" \"$1\" > report.txt\n"), 0644))
+	// Names containing another script's name are not calls of it
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "cleanup.sh"), []byte("rm -f old_extract.py.bak\n"), 0644))
+
+	skill, err := goskills.ParseSkillPackage(dir)
+	require.NoError(t, err)
+	return skill
+}
+
+func TestBuild(t *testing.T) {
+	g, err := Build(writeSkill(t, ""))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Node{
+		{ID: "skill", Label: "pdf-report", Kind: KindSkill},
+		{ID: "tools", Label: fmt.Sprintf("base tools (%d)", len(tool.GetBaseTools())), Kind: KindTool},
+		{ID: "tool:run_scripts_cleanup_sh", Label: "run_scripts_cleanup_sh", Kind: KindTool},
+		{ID: "script:scripts/cleanup.sh", Label: "scripts/cleanup.sh", Kind: KindScript},
+		{ID: "tool:run_scripts_extract_py", Label: "run_scripts_extract_py", Kind: KindTool},
+		{ID: "script:scripts/extract.py", Label: "scripts/extract.py", Kind: KindScript},
+		{ID: "tool:run_scripts_report_sh", Label: "run_scripts_report_sh", Kind: KindTool},
+		{ID: "script:scripts/report.sh", Label: "scripts/report.sh", Kind: KindScript},
+		{ID: "tool:build_report", Label: "build_report", Kind: KindTool},
+	}, g.Nodes)
+	assert.Equal(t, []Edge{
+		{From: "skill", To: "tools"},
+		{From: "skill", To: "tool:run_scripts_cleanup_sh"},
+		{From: "tool:run_scripts_cleanup_sh", To: "script:scripts/cleanup.sh", Label: "runs"},
+		{From: "skill", To: "tool:run_scripts_extract_py"},
+		{From: "tool:run_scripts_extract_py", To: "script:scripts/extract.py", Label: "runs"},
+		{From: "skill", To: "tool:run_scripts_report_sh"},
+		{From: "tool:run_scripts_report_sh", To: "script:scripts/report.sh", Label: "runs"},
+		{From: "skill", To: "tool:build_report"},
+		{From: "tool:build_report", To: "script:scripts/report.sh", Label: "runs"},
+		{From: "script:scripts/report.sh", To: "script:scripts/extract.py", Label: "calls"},
+	}, g.Edges)
+}
+
+func TestBuild_AllowedTools(t *testing.T) {
+	g, err := Build(writeSkill(t, "allowed-tools: [read_file, run_python_script]"))
+	require.NoError(t, err)
+
+	var tools []string
+	for _, n := range g.Nodes {
+		if n.Kind == KindTool {
+			tools = append(tools, n.Label)
+		}
+	}
+	assert.Subset(t, tools, []string{"read_file", "run_python_script", "list_tools", "skill_info", "build_report"})
+	for _, n := range g.Nodes {
+		assert.NotEqual(t, "tools", n.ID, "the base tools are listed individually")
+	}
+	assert.NotContains(t, tools, "write_file")
+}
+
+func TestSkillGraph_DOT(t *testing.T) {
+	g, err := Build(writeSkill(t, ""))
+	require.NoError(t, err)
+	g.Nodes[0].Label = `say "hi"`
+
+	dot := g.DOT()
+	assert.Regexp(t, `^digraph skill \{\n  rankdir=LR;\n`, dot)
+	assert.Contains(t, dot, `  "skill" [label="say \"hi\"", shape=box, style="rounded,filled", fillcolor=lightblue];`)
+	assert.Contains(t, dot, `  "tool:build_report" [label="build_report", shape=ellipse];`)
+	assert.Contains(t, dot, `  "script:scripts/extract.py" [label="scripts/extract.py", shape=note, style=filled, fillcolor=lightyellow];`)
+	assert.Contains(t, dot, `  "skill" -> "tool:build_report";`)
+	assert.Contains(t, dot, `  "tool:build_report" -> "script:scripts/report.sh" [label="runs"];`)
+	assert.Contains(t, dot, `  "script:scripts/report.sh" -> "script:scripts/extract.py" [label="calls"];`)
+	assert.NotContains(t, dot, `"script:scripts/cleanup.sh" -> `)
+	assert.Regexp(t, `\n}\n$`, dot)
+}
@@ -0,0 +1,38 @@
+package goskills
+
+// TokenUsage is the number of tokens used by LLM calls.
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// AgentState is a snapshot of an agent's state for debugging and monitoring.
+type AgentState struct {
+	MessageCount      int               `json:"message_count"`                 // Messages in the conversation history
+	LastSkillName     string            `json:"last_skill_name"`               // Skill executed most recently, as ActiveSkill returns
+	TotalToolCalls    int               `json:"total_tool_calls"`              // Tools executed, including failed calls and MCP tools
+	TotalAPICallCount int               `json:"total_api_call_count"`          // Completed LLM calls, including skill selection
+	TotalTokensUsed   TokenUsage        `json:"total_tokens_used"`             // Tokens used by all completed LLM calls
+	MCPServerStatuses map[string]string `json:"mcp_server_statuses,omitempty"` // Connection status by MCP server name; nil without an MCP client
+}
+
+// Introspect returns a snapshot of the agent's state. Like the agent's other methods,
+// it must not be called while a prompt is running.
+func (a *Agent) Introspect() AgentState {
+	state := AgentState{
+		MessageCount:      len(a.messages),
+		LastSkillName:     a.activeSkill,
+		TotalToolCalls:    a.toolCalls,
+		TotalAPICallCount: a.apiCalls,
+		TotalTokensUsed: TokenUsage{
+			PromptTokens:     a.totalUsage.PromptTokens,
+			CompletionTokens: a.totalUsage.CompletionTokens,
+			TotalTokens:      a.totalUsage.TotalTokens,
+		},
+	}
+	if a.mcpClient != nil {
+		state.MCPServerStatuses = a.mcpClient.ServerStatuses()
+	}
+	return state
+}
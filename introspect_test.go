@@ -0,0 +1,52 @@
+package goskills
+
+import (
+	"context"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_Introspect(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "notes", "", "Take notes with the shell.")
+	responses := []openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "run_shell_code", `{"code":"echo one"}`),
+		toolCallResponse("call-2", "run_shell_code", `{"code":"exit 1"}`),
+		textResponse("one"),
+	}
+	for i := range responses {
+		responses[i].Usage = openai.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}
+	}
+	agent := &Agent{
+		client: NewMockOpenAIClient(responses, nil),
+		cfg:    RunnerConfig{Model: "test", SkillsDir: skillsDir, SkillName: "notes", AutoApproveTools: true},
+	}
+	assert.Equal(t, AgentState{}, agent.Introspect())
+
+	_, err := agent.Run(context.Background(), "count")
+	require.NoError(t, err)
+
+	assert.Equal(t, AgentState{
+		MessageCount:      7, // system, user, two tool call rounds and the answer
+		LastSkillName:     "notes",
+		TotalToolCalls:    2,
+		TotalAPICallCount: 3,
+		TotalTokensUsed:   TokenUsage{PromptTokens: 30, CompletionTokens: 6, TotalTokens: 36},
+	}, agent.Introspect())
+}
+
+func TestAgent_IntrospectMCPServers(t *testing.T) {
+	client, err := mcp.NewClient(context.Background(), &mcp.Config{MCPServers: map[string]mcp.MCPServer{
+		"missing": {Type: "stdio", Command: "goskills-test-no-such-command"},
+	}})
+	require.NoError(t, err)
+	agent := &Agent{mcpClient: client}
+
+	statuses := agent.Introspect().MCPServerStatuses
+	require.Len(t, statuses, 1)
+	assert.Contains(t, statuses["missing"], "failed: ")
+}
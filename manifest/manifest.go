@@ -0,0 +1,99 @@
+// Package manifest records where installed skills came from, so they can be updated later.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry describes one installed skill.
+type Entry struct {
+	Name        string    `json:"name"`
+	SourceURL   string    `json:"source_url"`
+	Version     string    `json:"version,omitempty"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Manifest maps skill names to their entries.
+type Manifest struct {
+	Skills map[string]Entry `json:"skills"`
+}
+
+// DefaultPath returns ~/.goskills/manifest.json.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".goskills", "manifest.json"), nil
+}
+
+// Load reads the manifest at path. A missing file yields an empty manifest.
+func Load(path string) (*Manifest, error) {
+	m := &Manifest{Skills: make(map[string]Entry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Skills == nil {
+		m.Skills = make(map[string]Entry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest to path, creating its directory if needed. The file is
+// replaced atomically so a failed write never leaves a truncated manifest behind.
+func (m *Manifest) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Get returns the entry for the named skill.
+func (m *Manifest) Get(name string) (Entry, bool) {
+	e, ok := m.Skills[name]
+	return e, ok
+}
+
+// Set adds or replaces the entry for e.Name.
+func (m *Manifest) Set(e Entry) {
+	if m.Skills == nil {
+		m.Skills = make(map[string]Entry)
+	}
+	m.Skills[e.Name] = e
+}
+
+// Names returns the recorded skill names in sorted order.
+func (m *Manifest) Names() []string {
+	names := make([]string, 0, len(m.Skills))
+	for name := range m.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
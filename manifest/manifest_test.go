@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	m, err := Load(filepath.Join(t.TempDir(), "manifest.json"))
+	require.NoError(t, err)
+	assert.Empty(t, m.Skills)
+	assert.Empty(t, m.Names())
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "manifest.json")
+	installed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	m := &Manifest{}
+	m.Set(Entry{Name: "zeta", SourceURL: "https://github.com/o/r/tree/main/zeta", Version: "1.0.0", InstalledAt: installed})
+	m.Set(Entry{Name: "alpha", SourceURL: "https://github.com/o/alpha"})
+	require.NoError(t, m.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "zeta"}, loaded.Names())
+	entry, ok := loaded.Get("zeta")
+	require.True(t, ok)
+	assert.Equal(t, "1.0.0", entry.Version)
+	assert.True(t, installed.Equal(entry.InstalledAt))
+	_, ok = loaded.Get("missing")
+	assert.False(t, ok)
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+	_, err := Load(path)
+	assert.Error(t, err)
+}
@@ -12,11 +12,13 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/sashabaranov/go-openai"
+	"golang.org/x/oauth2"
 )
 
 // Client manages connections to multiple MCP servers.
 type Client struct {
 	sessions   map[string]*mcp.ClientSession
+	failures   map[string]error // Last connection error of servers that are not connected
 	config     *Config
 	maxRetries int
 }
@@ -30,6 +32,7 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 
 	c := &Client{
 		sessions:   make(map[string]*mcp.ClientSession),
+		failures:   make(map[string]error),
 		config:     config,
 		maxRetries: maxRetries,
 	}
@@ -38,6 +41,7 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		if err := c.connectToServer(ctx, name, server); err != nil {
 			// Log error but continue connecting to other servers
 			fmt.Fprintf(os.Stderr, "Failed to connect to MCP server %s: %v\n", name, err)
+			c.failures[name] = err
 		}
 	}
 
@@ -47,19 +51,45 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 func (c *Client) connectToServer(ctx context.Context, name string, server MCPServer) error {
 	var transport mcp.Transport
 
+	var tokenSource oauth2.TokenSource
+	if server.OAuth2 != nil {
+		if server.Type != "sse" && server.Type != "websocket" {
+			return fmt.Errorf("oauth2 is only supported for sse and websocket servers")
+		}
+		var err error
+		tokenSource, err = newTokenSource(ctx, name, server.OAuth2)
+		if err != nil {
+			return err
+		}
+	}
+
 	if server.Type == "sse" {
 		sseTransport := &mcp.SSEClientTransport{
 			Endpoint: server.URL,
 		}
+		var rt http.RoundTripper = http.DefaultTransport
 		if len(server.Headers) > 0 {
-			sseTransport.HTTPClient = &http.Client{
-				Transport: &headerTransport{
-					Transport: http.DefaultTransport,
-					Headers:   server.Headers,
-				},
+			rt = &headerTransport{
+				Transport: rt,
+				Headers:   server.Headers,
 			}
 		}
+		if tokenSource != nil {
+			rt = &oauth2.Transport{Source: tokenSource, Base: rt}
+		}
+		if rt != http.DefaultTransport {
+			sseTransport.HTTPClient = &http.Client{Transport: rt}
+		}
 		transport = sseTransport
+	} else if server.Type == "websocket" {
+		if _, err := reconnectInterval(server); err != nil {
+			return err
+		}
+		transport = &websocketTransport{
+			URL:         server.URL,
+			Headers:     server.Headers,
+			TokenSource: tokenSource,
+		}
 	} else {
 		// Default to stdio
 		cmd := exec.Command(server.Command, server.Args...)
@@ -116,6 +146,22 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// ServerStatuses returns the connection status of every configured server: "connected",
+// "failed: <error>" when connecting to it failed, or "not connected".
+func (c *Client) ServerStatuses() map[string]string {
+	statuses := make(map[string]string, len(c.config.MCPServers))
+	for name := range c.config.MCPServers {
+		if err, ok := c.failures[name]; ok {
+			statuses[name] = "failed: " + err.Error()
+		} else if _, ok := c.sessions[name]; ok {
+			statuses[name] = "connected"
+		} else {
+			statuses[name] = "not connected"
+		}
+	}
+	return statuses
+}
+
 // GetTools fetches tools from all connected servers and converts them to OpenAI tools.
 func (c *Client) GetTools(ctx context.Context) ([]openai.Tool, error) {
 	var allTools []openai.Tool
@@ -171,8 +217,9 @@ func (c *Client) CallTool(ctx context.Context, name string, args map[string]any)
 			return result, nil
 		}
 
-		// Check if it's a connection-related error
-		if c.isConnectionError(err) {
+		// Check if it's a connection-related error. WebSocket servers are only
+		// reconnected when their config asks for it.
+		if c.isConnectionError(err) && (server.Type != "websocket" || server.Reconnect) {
 			log.Printf("Connection error detected for server %s, attempting reconnection (%d/%d): %v",
 				serverName, i+1, c.maxRetries, err)
 
@@ -184,14 +231,23 @@ func (c *Client) CallTool(ctx context.Context, name string, args map[string]any)
 			// Wait with exponential backoff before reconnecting
 			if i < c.maxRetries-1 {
 				backoff := time.Second * time.Duration(i+1)
+				if interval, _ := reconnectInterval(server); interval > 0 {
+					backoff = interval
+				}
 				log.Printf("Waiting %v before reconnecting...", backoff)
-				time.Sleep(backoff)
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+					return nil, fmt.Errorf("failed to call tool: %w", ctx.Err())
+				}
 
 				// Attempt to reconnect
 				if reconnectErr := c.connectToServer(ctx, serverName, server); reconnectErr != nil {
 					log.Printf("Reconnection failed: %v", reconnectErr)
+					c.failures[serverName] = reconnectErr
 					continue
 				}
+				delete(c.failures, serverName)
 				log.Printf("Reconnection successful for server %s", serverName)
 				continue
 			}
@@ -204,6 +260,19 @@ func (c *Client) CallTool(ctx context.Context, name string, args map[string]any)
 	return nil, fmt.Errorf("failed to call tool after %d retries", c.maxRetries)
 }
 
+// reconnectInterval returns the configured delay between WebSocket reconnection
+// attempts, or zero when none is set.
+func reconnectInterval(server MCPServer) (time.Duration, error) {
+	if server.ReconnectInterval == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(server.ReconnectInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reconnectInterval %q: %w", server.ReconnectInterval, err)
+	}
+	return interval, nil
+}
+
 // isConnectionError checks if an error is related to connection issues
 func (c *Client) isConnectionError(err error) bool {
 	if err == nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -87,6 +88,23 @@ func TestNewClient(t *testing.T) {
 	assert.NotNil(t, client.sessions)
 }
 
+func TestClient_ServerStatuses(t *testing.T) {
+	config := &Config{
+		MCPServers: map[string]MCPServer{
+			"missing": {Type: "stdio", Command: "goskills-test-no-such-command"},
+		},
+	}
+
+	client, err := NewClient(context.Background(), config)
+	assert.NoError(t, err)
+	statuses := client.ServerStatuses()
+	assert.Len(t, statuses, 1)
+	assert.Contains(t, statuses["missing"], "failed: ")
+
+	client = &Client{sessions: map[string]*mcp.ClientSession{"up": nil}, config: &Config{MCPServers: map[string]MCPServer{"up": {}, "down": {}}}}
+	assert.Equal(t, map[string]string{"up": "connected", "down": "not connected"}, client.ServerStatuses())
+}
+
 // TestNewClient_EmptyConfig tests client creation with empty config
 func TestNewClient_EmptyConfig(t *testing.T) {
 	config := &Config{
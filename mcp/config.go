@@ -18,9 +18,14 @@ type MCPServer struct {
 	Args        []string          `json:"args"`
 	Env         map[string]string `json:"env,omitempty"`
 	Description string            `json:"description,omitempty"`
-	Type        string            `json:"type,omitempty"`    // "stdio" (default) or "sse"
-	URL         string            `json:"url,omitempty"`     // For SSE
-	Headers     map[string]string `json:"headers,omitempty"` // For SSE
+	Type        string            `json:"type,omitempty"`    // "stdio" (default), "sse" or "websocket"
+	URL         string            `json:"url,omitempty"`     // For SSE and WebSocket
+	Headers     map[string]string `json:"headers,omitempty"` // For SSE and WebSocket
+
+	Reconnect         bool   `json:"reconnect,omitempty"`         // For WebSocket: reconnect and re-initialize the session when the connection drops
+	ReconnectInterval string `json:"reconnectInterval,omitempty"` // For WebSocket: delay between attempts, e.g. "5s"
+
+	OAuth2 *OAuth2Config `json:"oauth2,omitempty"` // For SSE and WebSocket: authenticate with a Bearer token
 }
 
 // LoadConfig loads the MCP configuration from the specified path.
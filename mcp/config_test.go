@@ -73,6 +73,37 @@ func TestLoadConfig_SSE(t *testing.T) {
 	assert.Equal(t, "Bearer token", remoteServer.Headers["Authorization"])
 }
 
+func TestLoadConfig_OAuth2(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "claude_oauth2.json")
+
+	configContent := `{
+  "mcpServers": {
+    "remote": {
+      "type": "sse",
+      "url": "https://example.com/sse",
+      "oauth2": {
+        "clientId": "goskills",
+        "authUrl": "https://auth.example.com/device",
+        "tokenUrl": "https://auth.example.com/token",
+        "scopes": ["tools"]
+      }
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	config, err := LoadConfig(configPath)
+	require.NoError(t, err)
+
+	oauth := config.MCPServers["remote"].OAuth2
+	require.NotNil(t, oauth)
+	assert.Equal(t, "goskills", oauth.ClientID)
+	assert.Equal(t, "https://auth.example.com/device", oauth.AuthURL)
+	assert.Equal(t, "https://auth.example.com/token", oauth.TokenURL)
+	assert.Equal(t, []string{"tools"}, oauth.Scopes)
+}
+
 func TestLoadConfig_FileNotFound(t *testing.T) {
 	_, err := LoadConfig("/non/existent/path.json")
 	assert.Error(t, err)
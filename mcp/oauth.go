@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// OAuth2Config configures OAuth2 authentication for an SSE or WebSocket server.
+// Tokens are obtained with the device authorization flow secured by PKCE and
+// cached in ~/.goskills/tokens/<server-name>.json.
+type OAuth2Config struct {
+	ClientID string   `json:"clientId"`
+	AuthURL  string   `json:"authUrl"`  // Device authorization endpoint
+	TokenURL string   `json:"tokenUrl"` // Token endpoint, also used for refreshes
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// authPrompt receives the verification instructions of the device flow.
+var authPrompt io.Writer = os.Stderr
+
+// TokenPath returns ~/.goskills/tokens/<server>.json.
+func TokenPath(server string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".goskills", "tokens", server+".json"), nil
+}
+
+func (c *OAuth2Config) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: c.ClientID,
+		Scopes:   c.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       c.AuthURL,
+			DeviceAuthURL: c.AuthURL,
+			TokenURL:      c.TokenURL,
+		},
+	}
+}
+
+// newTokenSource returns a token source for the named server. A cached token is
+// reused when present, after refreshing it if it has expired; otherwise, or when
+// the refresh fails, the cache is discarded and the device flow is run and its
+// result cached. Tokens refreshed later are written back as well.
+func newTokenSource(ctx context.Context, name string, cfg *OAuth2Config) (oauth2.TokenSource, error) {
+	if cfg.ClientID == "" || cfg.AuthURL == "" || cfg.TokenURL == "" {
+		return nil, fmt.Errorf("oauth2 requires clientId, authUrl and tokenUrl")
+	}
+	path, err := TokenPath(name)
+	if err != nil {
+		return nil, err
+	}
+	conf := cfg.oauth2Config()
+
+	tok, err := loadToken(path)
+	if err != nil {
+		return nil, err
+	}
+	// Refreshes outlive the connect call, so they must not inherit its cancellation.
+	refreshCtx := context.WithoutCancel(ctx)
+	if tok != nil {
+		// An expired cached token is refreshed now, so that a revoked refresh
+		// token sends the user through the device flow instead of failing every dial.
+		ts := &fileTokenSource{path: path, src: conf.TokenSource(refreshCtx, tok), last: tok.AccessToken}
+		if _, err = ts.Token(); err == nil {
+			return ts, nil
+		}
+		fmt.Fprintf(authPrompt, "Cached token for MCP server %s is no longer valid: %v\n", name, err)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove token: %w", err)
+		}
+	}
+
+	tok, err = deviceFlow(ctx, name, conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveToken(path, tok); err != nil {
+		return nil, err
+	}
+	src := conf.TokenSource(refreshCtx, tok)
+	return &fileTokenSource{path: path, src: src, last: tok.AccessToken}, nil
+}
+
+// deviceFlow asks the user to authorize the device and waits for the token.
+func deviceFlow(ctx context.Context, name string, conf *oauth2.Config) (*oauth2.Token, error) {
+	verifier := oauth2.GenerateVerifier()
+	da, err := conf.DeviceAuth(ctx, oauth2.S256ChallengeOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 device authorization failed: %w", err)
+	}
+
+	if da.VerificationURIComplete != "" {
+		fmt.Fprintf(authPrompt, "To authorize MCP server %s, visit %s\n", name, da.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(authPrompt, "To authorize MCP server %s, visit %s and enter code %s\n", name, da.VerificationURI, da.UserCode)
+	}
+
+	tok, err := conf.DeviceAccessToken(ctx, da, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token exchange failed: %w", err)
+	}
+	return tok, nil
+}
+
+// fileTokenSource writes every newly issued token back to its cache file.
+type fileTokenSource struct {
+	path string
+	src  oauth2.TokenSource
+
+	mu   sync.Mutex
+	last string
+}
+
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tok.AccessToken != s.last {
+		if err := saveToken(s.path, tok); err != nil {
+			return nil, err
+		}
+		s.last = tok.AccessToken
+	}
+	return tok, nil
+}
+
+// loadToken reads a cached token. A missing file yields a nil token.
+func loadToken(path string) (*oauth2.Token, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	var tok oauth2.Token
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, fmt.Errorf("failed to parse token %s: %w", path, err)
+	}
+	return &tok, nil
+}
+
+// saveToken writes tok to path, readable only by the current user.
+func saveToken(path string, tok *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	return nil
+}
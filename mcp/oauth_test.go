@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newMockOAuth2Server serves a device authorization endpoint at /device and a
+// token endpoint at /token. The device grant yields "access-1" after checking the
+// PKCE verifier; refreshing "refresh-1" yields "access-2" and any other refresh
+// token is rejected with invalid_grant.
+func newMockOAuth2Server(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var challenge atomic.Value
+	var deviceRequests atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		deviceRequests.Add(1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "goskills", r.Form.Get("client_id"))
+		assert.Equal(t, "S256", r.Form.Get("code_challenge_method"))
+		challenge.Store(r.Form.Get("code_challenge"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-1234",
+			"verification_uri": "https://example.com/activate",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			assert.Equal(t, "device-code", r.Form.Get("device_code"))
+			assert.Equal(t, challenge.Load(), oauth2.S256ChallengeFromVerifier(r.Form.Get("code_verifier")))
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "access-1",
+				"token_type":    "Bearer",
+				"refresh_token": "refresh-1",
+				"expires_in":    3600,
+			})
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "access-2",
+				"token_type":    "Bearer",
+				"refresh_token": "refresh-2",
+				"expires_in":    3600,
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unsupported_grant_type"})
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &deviceRequests
+}
+
+func mockOAuth2Config(server *httptest.Server) *OAuth2Config {
+	return &OAuth2Config{
+		ClientID: "goskills",
+		AuthURL:  server.URL + "/device",
+		TokenURL: server.URL + "/token",
+		Scopes:   []string{"tools"},
+	}
+}
+
+func setupTokenHome(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	var prompt bytes.Buffer
+	old := authPrompt
+	authPrompt = &prompt
+	t.Cleanup(func() { authPrompt = old })
+	return &prompt
+}
+
+func TestNewTokenSource_DeviceFlow(t *testing.T) {
+	prompt := setupTokenHome(t)
+	oauthServer, _ := newMockOAuth2Server(t)
+
+	ts, err := newTokenSource(context.Background(), "remote", mockOAuth2Config(oauthServer))
+	require.NoError(t, err)
+	assert.Contains(t, prompt.String(), "https://example.com/activate")
+	assert.Contains(t, prompt.String(), "ABCD-1234")
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+
+	path, err := TokenPath("remote")
+	require.NoError(t, err)
+	stored, err := loadToken(path)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "access-1", stored.AccessToken)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+}
+
+func TestNewTokenSource_CachedToken(t *testing.T) {
+	setupTokenHome(t)
+	oauthServer, deviceRequests := newMockOAuth2Server(t)
+
+	path, err := TokenPath("remote")
+	require.NoError(t, err)
+	require.NoError(t, saveToken(path, &oauth2.Token{AccessToken: "cached", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}))
+
+	ts, err := newTokenSource(context.Background(), "remote", mockOAuth2Config(oauthServer))
+	require.NoError(t, err)
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "cached", tok.AccessToken)
+	assert.Zero(t, deviceRequests.Load())
+}
+
+func TestNewTokenSource_Refresh(t *testing.T) {
+	setupTokenHome(t)
+	oauthServer, _ := newMockOAuth2Server(t)
+
+	path, err := TokenPath("remote")
+	require.NoError(t, err)
+	require.NoError(t, saveToken(path, &oauth2.Token{
+		AccessToken:  "expired",
+		TokenType:    "Bearer",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	}))
+
+	ts, err := newTokenSource(context.Background(), "remote", mockOAuth2Config(oauthServer))
+	require.NoError(t, err)
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", tok.AccessToken)
+
+	stored, err := loadToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, "access-2", stored.AccessToken)
+	assert.Equal(t, "refresh-2", stored.RefreshToken)
+}
+
+func TestNewTokenSource_RevokedRefreshToken(t *testing.T) {
+	prompt := setupTokenHome(t)
+	oauthServer, deviceRequests := newMockOAuth2Server(t)
+
+	path, err := TokenPath("remote")
+	require.NoError(t, err)
+	require.NoError(t, saveToken(path, &oauth2.Token{
+		AccessToken:  "expired",
+		TokenType:    "Bearer",
+		RefreshToken: "revoked",
+		Expiry:       time.Now().Add(-time.Minute),
+	}))
+
+	ts, err := newTokenSource(context.Background(), "remote", mockOAuth2Config(oauthServer))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), deviceRequests.Load())
+	assert.Contains(t, prompt.String(), "invalid_grant")
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+
+	stored, err := loadToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", stored.AccessToken)
+	assert.Equal(t, "refresh-1", stored.RefreshToken)
+}
+
+func TestNewTokenSource_MissingFields(t *testing.T) {
+	setupTokenHome(t)
+	_, err := newTokenSource(context.Background(), "remote", &OAuth2Config{ClientID: "goskills"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "authUrl")
+}
+
+func TestConnectToServer_OAuth2SSE(t *testing.T) {
+	setupTokenHome(t)
+	oauthServer, _ := newMockOAuth2Server(t)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake", Version: "0.0.1"}, nil)
+	handler := mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	var unauthorized atomic.Int32
+	sseServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			unauthorized.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer sseServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client := &Client{sessions: map[string]*mcp.ClientSession{}, config: &Config{}}
+	err := client.connectToServer(ctx, "remote", MCPServer{
+		Type:   "sse",
+		URL:    sseServer.URL,
+		OAuth2: mockOAuth2Config(oauthServer),
+	})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Contains(t, client.sessions, "remote")
+	assert.Zero(t, unauthorized.Load())
+}
+
+func TestConnectToServer_OAuth2WebSocket(t *testing.T) {
+	setupTokenHome(t)
+	oauthServer, _ := newMockOAuth2Server(t)
+
+	path, err := TokenPath("ws")
+	require.NoError(t, err)
+	require.NoError(t, saveToken(path, &oauth2.Token{AccessToken: "cached", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}))
+
+	var gotHeader atomic.Value
+	fake := newFakeMCPServer(t, nil)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader.Store(r.Header.Get("Authorization"))
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	client := &Client{sessions: map[string]*mcp.ClientSession{}, config: &Config{}}
+	err = client.connectToServer(context.Background(), "ws", MCPServer{
+		Type:   "websocket",
+		URL:    wsURL(proxy),
+		OAuth2: mockOAuth2Config(oauthServer),
+	})
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "Bearer cached", gotHeader.Load())
+}
+
+func TestConnectToServer_OAuth2RequiresRemoteServer(t *testing.T) {
+	client := &Client{sessions: map[string]*mcp.ClientSession{}, config: &Config{}}
+	err := client.connectToServer(context.Background(), "local", MCPServer{
+		Command: "echo",
+		OAuth2:  &OAuth2Config{ClientID: "goskills"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported for sse and websocket")
+}
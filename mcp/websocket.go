@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
+)
+
+// websocketTransport is an mcp.Transport that exchanges JSON-RPC messages as
+// WebSocket text frames, one message per frame.
+type websocketTransport struct {
+	URL         string
+	Headers     map[string]string
+	TokenSource oauth2.TokenSource // Supplies the Bearer token, fetched again on every dial
+	Dialer      *websocket.Dialer
+}
+
+// Connect dials the server and returns the resulting connection.
+func (t *websocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &websocketConn{conn: conn, closed: make(chan struct{})}, nil
+}
+
+func (t *websocketTransport) dial(ctx context.Context) (*websocket.Conn, error) {
+	dialer := t.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	header := http.Header{}
+	for k, v := range t.Headers {
+		header.Set(k, v)
+	}
+	if t.TokenSource != nil {
+		tok, err := t.TokenSource.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get oauth2 token: %w", err)
+		}
+		header.Set("Authorization", tok.Type()+" "+tok.AccessToken)
+	}
+
+	conn, _, err := dialer.DialContext(ctx, t.URL, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial websocket %s: %w", t.URL, err)
+	}
+	return conn, nil
+}
+
+// websocketConn is an mcp.Connection over a WebSocket. A dropped connection is
+// reported to the session rather than re-dialed: a new connection needs a new
+// initialize handshake, which the client performs when it reconnects the session.
+type websocketConn struct {
+	conn *websocket.Conn
+
+	writeMu   sync.Mutex // gorilla/websocket allows only one concurrent writer
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Read returns the next JSON-RPC message received from the server.
+func (c *websocketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		if c.isClosed() {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("websocket connection closed: %w", err)
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+// Write sends msg to the server as a single text frame.
+func (c *websocketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("websocket connection closed: %w", err)
+	}
+	return nil
+}
+
+func (c *websocketConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the connection. It is safe to call multiple times.
+func (c *websocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.writeMu.Lock()
+		_ = c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		c.writeMu.Unlock()
+		err = c.conn.Close()
+		if errors.Is(err, websocket.ErrCloseSent) {
+			err = nil
+		}
+	})
+	return err
+}
+
+// SessionID returns an empty string; WebSocket connections carry no session ID.
+func (c *websocketConn) SessionID() string {
+	return ""
+}
@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// connTransport hands an existing connection to an MCP server.
+type connTransport struct {
+	conn mcp.Connection
+}
+
+func (t *connTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	return t.conn, nil
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func newNotification(t *testing.T, method string) []byte {
+	t.Helper()
+	return []byte(`{"jsonrpc":"2.0","method":"` + method + `"}`)
+}
+
+// newFakeMCPServer starts a WebSocket server that serves an MCP server with an echo tool.
+// Like any MCP server, it rejects calls on a connection until it has been initialized.
+// If onConnect is not nil, it is called with every accepted connection.
+func newFakeMCPServer(t *testing.T, onConnect func(ws *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	type echoInput struct {
+		Text string `json:"text"`
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "Echoes the input."},
+		func(ctx context.Context, req *mcp.CallToolRequest, in echoInput) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: in.Text}}}, nil, nil
+		})
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		if onConnect != nil {
+			onConnect(ws)
+		}
+		conn := &websocketConn{conn: ws, closed: make(chan struct{})}
+		session, err := server.Connect(context.Background(), &connTransport{conn: conn}, nil)
+		if err != nil {
+			return
+		}
+		session.Wait()
+	}))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func TestNewClient_WebSocket(t *testing.T) {
+	httpServer := newFakeMCPServer(t, nil)
+
+	config := &Config{
+		MCPServers: map[string]MCPServer{
+			"ws": {Type: "websocket", URL: wsURL(httpServer)},
+		},
+	}
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, config)
+	require.NoError(t, err)
+	defer client.Close()
+	require.Contains(t, client.sessions, "ws")
+
+	tools, err := client.GetTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "ws__echo", tools[0].Function.Name)
+
+	result, err := client.CallTool(ctx, "ws__echo", map[string]any{"text": "hello"})
+	require.NoError(t, err)
+	callResult, ok := result.(*mcp.CallToolResult)
+	require.True(t, ok)
+	require.Len(t, callResult.Content, 1)
+	assert.Equal(t, "hello", callResult.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestWebSocketTransport_Headers(t *testing.T) {
+	var gotHeader atomic.Value
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader.Store(r.Header.Get("Authorization"))
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		ws.WriteMessage(websocket.TextMessage, newNotification(t, "notifications/ready"))
+		ws.ReadMessage()
+	}))
+	defer httpServer.Close()
+
+	transport := &websocketTransport{URL: wsURL(httpServer), Headers: map[string]string{"Authorization": "Bearer token"}}
+	conn, err := transport.Connect(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msg, err := conn.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "notifications/ready", msg.(*jsonrpc.Request).Method)
+	assert.Equal(t, "Bearer token", gotHeader.Load())
+}
+
+// newDroppableMCPServer starts a fake MCP server and returns a function that drops
+// the most recent connection, along with a count of accepted connections.
+func newDroppableMCPServer(t *testing.T) (*httptest.Server, func(), *atomic.Int32) {
+	t.Helper()
+	var connections atomic.Int32
+	var last atomic.Pointer[websocket.Conn]
+	httpServer := newFakeMCPServer(t, func(ws *websocket.Conn) {
+		connections.Add(1)
+		last.Store(ws)
+	})
+	drop := func() { last.Load().Close() }
+	return httpServer, drop, &connections
+}
+
+func TestCallTool_WebSocketReconnect(t *testing.T) {
+	httpServer, drop, connections := newDroppableMCPServer(t)
+
+	config := &Config{
+		MCPServers: map[string]MCPServer{
+			"ws": {Type: "websocket", URL: wsURL(httpServer), Reconnect: true, ReconnectInterval: "10ms"},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, config)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.CallTool(ctx, "ws__echo", map[string]any{"text": "first"})
+	require.NoError(t, err)
+
+	drop()
+
+	// The server rejects calls before initialize, so this only succeeds if the
+	// client dials a new connection and initializes a new session on it.
+	result, err := client.CallTool(ctx, "ws__echo", map[string]any{"text": "second"})
+	require.NoError(t, err)
+	assert.Equal(t, "second", result.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestCallTool_WebSocketNoReconnect(t *testing.T) {
+	httpServer, drop, connections := newDroppableMCPServer(t)
+
+	config := &Config{
+		MCPServers: map[string]MCPServer{
+			"ws": {Type: "websocket", URL: wsURL(httpServer)},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := NewClient(ctx, config)
+	require.NoError(t, err)
+	defer client.Close()
+
+	drop()
+
+	_, err = client.CallTool(ctx, "ws__echo", map[string]any{"text": "lost"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), connections.Load())
+}
+
+func TestWebSocketTransport_ReadReportsDrop(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		ws.Close()
+	}))
+	defer httpServer.Close()
+
+	conn, err := (&websocketTransport{URL: wsURL(httpServer)}).Connect(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Read(context.Background())
+	assert.Error(t, err)
+}
+
+func TestWebSocketTransport_CloseUnblocksRead(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		ws.ReadMessage()
+	}))
+	defer httpServer.Close()
+
+	transport := &websocketTransport{URL: wsURL(httpServer)}
+	conn, err := transport.Connect(context.Background())
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(context.Background())
+		done <- err
+	}()
+
+	require.NoError(t, conn.Close())
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read did not return after Close")
+	}
+	assert.NoError(t, conn.Close())
+}
+
+func TestConnectToServer_InvalidReconnectInterval(t *testing.T) {
+	client := &Client{sessions: map[string]*mcp.ClientSession{}, config: &Config{}}
+	err := client.connectToServer(context.Background(), "ws", MCPServer{
+		Type:              "websocket",
+		URL:               "ws://127.0.0.1:0",
+		ReconnectInterval: "soon",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid reconnectInterval")
+}
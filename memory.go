@@ -0,0 +1,144 @@
+package goskills
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+	"github.com/smallnest/goskills/tool"
+	"github.com/smallnest/goskills/trace"
+)
+
+const (
+	// DefaultMemorySummarizationThreshold is the message count above which older
+	// conversation history is summarized when RunnerConfig leaves it unset.
+	DefaultMemorySummarizationThreshold = 20
+
+	// memoryKeepTurns is the number of most recent user turns kept verbatim.
+	memoryKeepTurns = 4
+)
+
+const memorySummaryPrefix = "Summary of the earlier conversation:\n"
+
+// maybeSummarizeMemory condenses the history older than the last memoryKeepTurns user
+// turns into a single summary message once the history grows past the configured
+// threshold. Leading system messages are always kept. A failed summarization leaves
+// the history untouched.
+func (a *Agent) maybeSummarizeMemory(ctx context.Context) {
+	threshold := a.cfg.MemorySummarizationThreshold
+	if threshold <= 0 || len(a.messages) <= threshold {
+		return
+	}
+
+	// Leading system messages (the skill prompt) are never summarized.
+	start := 0
+	for start < len(a.messages) && a.messages[start].Role == openai.ChatMessageRoleSystem &&
+		!strings.HasPrefix(a.messages[start].Content, memorySummaryPrefix) {
+		start++
+	}
+
+	// Cut at the beginning of the memoryKeepTurns-th most recent user turn so that
+	// tool calls are never separated from their results.
+	cut, turns := -1, 0
+	for i := len(a.messages) - 1; i >= start; i-- {
+		if a.messages[i].Role == openai.ChatMessageRoleUser {
+			turns++
+			if turns == memoryKeepTurns {
+				cut = i
+				break
+			}
+		}
+	}
+	if cut <= start {
+		return
+	}
+	// Only an earlier summary precedes the kept turns: summarizing it again would
+	// cost an LLM call on every iteration and lose more of it each time.
+	if cut == start+1 && strings.HasPrefix(a.messages[start].Content, memorySummaryPrefix) {
+		return
+	}
+
+	old := a.messages[start:cut]
+	summary, err := a.summarizeMessages(ctx, old)
+	if err != nil {
+		log.Warn("failed to summarize conversation memory: %v", err)
+		return
+	}
+
+	messages := make([]openai.ChatCompletionMessage, 0, start+1+len(a.messages)-cut)
+	messages = append(messages, a.messages[:start]...)
+	messages = append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: memorySummaryPrefix + summary,
+	})
+	messages = append(messages, a.messages[cut:]...)
+
+	if a.cfg.Verbose >= 1 {
+		log.Info("summarized %d older messages; history reduced from %d to %d messages", len(old), len(a.messages), len(messages))
+	}
+	a.messages = messages
+}
+
+// summarizeMessages asks the LLM for a concise summary of messages.
+func (a *Agent) summarizeMessages(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		content := strings.TrimPrefix(msg.Content, memorySummaryPrefix)
+		for _, tc := range msg.ToolCalls {
+			content += fmt.Sprintf("\n[called %s with %s]", tc.Function.Name, tc.Function.Arguments)
+		}
+		transcript.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, strings.TrimSpace(content)))
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.cfg.Model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "Summarize the following conversation between a user and an assistant using tools. Keep facts, decisions, file paths, tool results and open questions needed to continue the conversation. Reply with the summary only.",
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: transcript.String(),
+			},
+		},
+	}
+
+	a.debugPrintRequest(req)
+	a.traceRequest(trace.StageSummarization, req)
+	start := time.Now()
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", newAPIError("ChatCompletion", err)
+	}
+	a.debugPrintResponse(resp)
+	a.traceResponse(trace.StageSummarization, resp)
+	a.trackCost(resp.Usage)
+	if a.auditLogger != nil {
+		a.auditCompletion(start, resp.Usage)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty summarization response")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// memoryPrompt returns the facts in the memory store formatted for the system message,
+// or "" when memory is disabled, empty or unreadable.
+func (a *Agent) memoryPrompt() string {
+	if a.cfg.MemoryStorePath == "" {
+		return ""
+	}
+	store, err := tool.NewMemoryStore(a.cfg.MemoryStorePath)
+	if err != nil {
+		log.Warn("failed to load memory store: %v", err)
+		return ""
+	}
+	if store.Len() == 0 {
+		return ""
+	}
+	return "\n\nCurrent memory (facts saved with memory_set in earlier runs):\n" + store.String()
+}
@@ -0,0 +1,186 @@
+package goskills
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conversationHistory builds a system prompt followed by the given number of
+// user/assistant turns.
+func conversationHistory(turns int) []openai.ChatCompletionMessage {
+	messages := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: "skill prompt"}}
+	for i := 1; i <= turns; i++ {
+		messages = append(messages,
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf("question %d", i)},
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: fmt.Sprintf("answer %d", i)},
+		)
+	}
+	return messages
+}
+
+func textResponse(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleAssistant,
+		Content: content,
+	}}}}
+}
+
+func TestContinueSkillWithTools_SummarizesMemory(t *testing.T) {
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		textResponse("the user asked questions 1 to 7"),
+		textResponse("final answer"),
+	}, nil)
+	agent := &Agent{
+		client:   client,
+		cfg:      RunnerConfig{Model: "test-model", MemorySummarizationThreshold: 10},
+		messages: conversationHistory(10),
+	}
+
+	result, err := agent.continueSkillWithTools(context.Background(), "question 11", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+	require.NoError(t, err)
+	assert.Equal(t, "final answer", result)
+
+	// The first request summarizes the old history.
+	require.Len(t, client.requests, 2)
+	summaryReq := client.requests[0]
+	assert.Empty(t, summaryReq.Tools)
+	transcript := summaryReq.Messages[len(summaryReq.Messages)-1].Content
+	assert.Contains(t, transcript, "question 1\n")
+	assert.Contains(t, transcript, "answer 7")
+	assert.NotContains(t, transcript, "question 8")
+	assert.NotContains(t, transcript, "skill prompt")
+
+	// The second request carries the system prompt, the summary and the last 4 turns.
+	messages := client.requests[1].Messages
+	assert.Equal(t, "skill prompt", messages[0].Content)
+	assert.Equal(t, openai.ChatMessageRoleSystem, messages[1].Role)
+	assert.Equal(t, memorySummaryPrefix+"the user asked questions 1 to 7", messages[1].Content)
+	assert.Equal(t, "question 8", messages[2].Content)
+	assert.Equal(t, "question 11", messages[len(messages)-1].Content)
+	assert.Len(t, messages, 2+4*2-1)
+
+	for _, msg := range agent.messages {
+		assert.NotEqual(t, "question 1", msg.Content, "old messages should be removed")
+	}
+}
+
+func TestMaybeSummarizeMemory_BelowThreshold(t *testing.T) {
+	client := NewMockOpenAIClient(nil, nil)
+	agent := &Agent{
+		client:   client,
+		cfg:      RunnerConfig{MemorySummarizationThreshold: 30},
+		messages: conversationHistory(10),
+	}
+
+	agent.maybeSummarizeMemory(context.Background())
+	assert.Empty(t, client.requests)
+	assert.Len(t, agent.messages, 21)
+}
+
+func TestMaybeSummarizeMemory_Disabled(t *testing.T) {
+	client := NewMockOpenAIClient(nil, nil)
+	agent := &Agent{client: client, messages: conversationHistory(10)}
+
+	agent.maybeSummarizeMemory(context.Background())
+	assert.Empty(t, client.requests)
+}
+
+func TestMaybeSummarizeMemory_ResummarizesPreviousSummary(t *testing.T) {
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("merged summary")}, nil)
+	messages := conversationHistory(0)
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: memorySummaryPrefix + "first summary"})
+	messages = append(messages, conversationHistory(6)[1:]...)
+	agent := &Agent{client: client, cfg: RunnerConfig{MemorySummarizationThreshold: 5}, messages: messages}
+
+	agent.maybeSummarizeMemory(context.Background())
+	require.Len(t, client.requests, 1)
+	assert.Contains(t, client.requests[0].Messages[1].Content, "system: first summary")
+
+	summaries := 0
+	for _, msg := range agent.messages {
+		if strings.HasPrefix(msg.Content, memorySummaryPrefix) {
+			summaries++
+			assert.Equal(t, memorySummaryPrefix+"merged summary", msg.Content)
+		}
+	}
+	assert.Equal(t, 1, summaries)
+	assert.Len(t, agent.messages, 2+4*2)
+}
+
+func TestContinueSkillWithTools_KeepsSummaryOfLongTurns(t *testing.T) {
+	// The last 4 turns alone exceed the threshold, so only the summary could be cut
+	messages := conversationHistory(0)
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: memorySummaryPrefix + "first summary"})
+	for i := 1; i <= 3; i++ {
+		messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf("question %d", i)})
+		for j := 0; j < 4; j++ {
+			messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: fmt.Sprintf("step %d.%d", i, j)})
+		}
+	}
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("final answer")}, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{Model: "test-model", MemorySummarizationThreshold: 5}, messages: messages}
+
+	result, err := agent.continueSkillWithTools(context.Background(), "question 4", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+	require.NoError(t, err)
+	assert.Equal(t, "final answer", result)
+
+	// No summarization request is sent; the first summary is passed on unchanged
+	require.Len(t, client.requests, 1)
+	assert.Equal(t, memorySummaryPrefix+"first summary", client.requests[0].Messages[1].Content)
+}
+
+func TestMaybeSummarizeMemory_ErrorKeepsHistory(t *testing.T) {
+	client := NewMockOpenAIClient(nil, fmt.Errorf("llm unavailable"))
+	agent := &Agent{client: client, cfg: RunnerConfig{MemorySummarizationThreshold: 5}, messages: conversationHistory(10)}
+
+	agent.maybeSummarizeMemory(context.Background())
+	assert.Len(t, client.requests, 1)
+	assert.Len(t, agent.messages, 21)
+}
+
+func TestExecuteSkillWithTools_MemoryStore(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "memory.json")
+	skill := &SkillPackage{Path: t.TempDir(), Meta: SkillMeta{Name: "greeter"}, Body: "Greet the user."}
+
+	// The first run remembers a fact; nothing is injected while the store is empty
+	first := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "memory_set", `{"key": "name", "value": "Ada"}`),
+		textResponse("nice to meet you"),
+	}, nil)
+	agent := &Agent{client: first, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true, MemoryStorePath: storePath}}
+	_, err := agent.executeSkillWithTools(context.Background(), "my name is Ada", skill)
+	require.NoError(t, err)
+	require.Len(t, first.requests, 2)
+	assert.NotContains(t, first.requests[0].Messages[0].Content, "Current memory")
+	toolResult := first.requests[1].Messages[len(first.requests[1].Messages)-1]
+	assert.Equal(t, "Remembered name", toolResult.Content)
+
+	// A new agent, as after a restart, sees the fact in its system message and can read it back
+	second := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-2", "memory_get", `{"key": "name"}`),
+		textResponse("hello Ada"),
+	}, nil)
+	agent = &Agent{client: second, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true, MemoryStorePath: storePath}}
+	_, err = agent.executeSkillWithTools(context.Background(), "hello again", skill)
+	require.NoError(t, err)
+	require.Len(t, second.requests, 2)
+	system := second.requests[0].Messages[0]
+	assert.Equal(t, openai.ChatMessageRoleSystem, system.Role)
+	assert.Contains(t, system.Content, "Current memory")
+	assert.Contains(t, system.Content, "- name: Ada")
+	toolResult = second.requests[1].Messages[len(second.requests[1].Messages)-1]
+	assert.Equal(t, "Ada", toolResult.Content)
+}
+
+func TestExecuteToolCall_MemoryDisabled(t *testing.T) {
+	agent := &Agent{}
+	_, err := agent.executeToolCall(context.Background(), openai.ToolCall{Function: openai.FunctionCall{Name: "memory_get", Arguments: `{"key": "name"}`}}, nil, nil)
+	assert.ErrorContains(t, err, "memory store is not configured")
+}
@@ -0,0 +1,94 @@
+package goskills
+
+import (
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// repeatedToolCall is a kept tool call whose identical repetitions were removed.
+type repeatedToolCall struct {
+	signature string // Tool name and arguments
+	content   string // Tool result
+	index     int    // Index of the tool result in the deduplicated messages
+	repeats   int
+}
+
+// DeduplicateMessages collapses consecutive tool calls that call the same tool with the
+// same arguments and return the same result. Only the first call and its result are
+// kept; the result notes how often the call was repeated. Repetitions are removed
+// together with their results, so every remaining tool call still has its result, and an
+// assistant message is dropped when none of its tool calls and no content remain. The
+// messages are not modified.
+func DeduplicateMessages(messages []openai.ChatCompletionMessage) []openai.ChatCompletionMessage {
+	result := make([]openai.ChatCompletionMessage, 0, len(messages))
+	var repeated []*repeatedToolCall
+	var last *repeatedToolCall
+
+	for i := 0; i < len(messages); {
+		msg := messages[i]
+		if msg.Role != openai.ChatMessageRoleAssistant || len(msg.ToolCalls) == 0 {
+			result = append(result, msg)
+			last = nil
+			i++
+			continue
+		}
+
+		// The tool results of msg follow it
+		end := i + 1
+		for end < len(messages) && messages[end].Role == openai.ChatMessageRoleTool {
+			end++
+		}
+		toolResults := messages[i+1 : end]
+		i = end
+
+		contents := make(map[string]string, len(toolResults))
+		for _, r := range toolResults {
+			contents[r.ToolCallID] = r.Content
+		}
+		var calls []openai.ToolCall
+		dropped := make(map[string]bool)
+		kept := make(map[string]*repeatedToolCall)
+		for _, tc := range msg.ToolCalls {
+			content, ok := contents[tc.ID]
+			if !ok {
+				calls = append(calls, tc)
+				last = nil
+				continue
+			}
+			signature := tc.Function.Name + "\x00" + tc.Function.Arguments
+			if last != nil && last.signature == signature && last.content == content {
+				last.repeats++
+				dropped[tc.ID] = true
+				continue
+			}
+			calls = append(calls, tc)
+			last = &repeatedToolCall{signature: signature, content: content, index: -1}
+			kept[tc.ID] = last
+			repeated = append(repeated, last)
+		}
+
+		if len(calls) > 0 || msg.Content != "" {
+			if len(calls) < len(msg.ToolCalls) {
+				msg.ToolCalls = calls
+			}
+			result = append(result, msg)
+		}
+		for _, r := range toolResults {
+			if dropped[r.ToolCallID] {
+				continue
+			}
+			if k, ok := kept[r.ToolCallID]; ok && k.index < 0 {
+				k.index = len(result)
+			}
+			result = append(result, r)
+		}
+	}
+
+	for _, r := range repeated {
+		if r.repeats > 0 && r.index >= 0 {
+			result[r.index].Content = fmt.Sprintf("%s\n\n[This tool call was repeated %d more time(s) with identical output; the repetitions were removed.]", r.content, r.repeats)
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package goskills
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callMessage(calls ...openai.ToolCall) openai.ChatCompletionMessage {
+	return openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, ToolCalls: calls}
+}
+
+func newToolCall(id, name, args string) openai.ToolCall {
+	return openai.ToolCall{ID: id, Type: openai.ToolTypeFunction, Function: openai.FunctionCall{Name: name, Arguments: args}}
+}
+
+func toolResultMessage(id, content string) openai.ChatCompletionMessage {
+	return openai.ChatCompletionMessage{Role: openai.ChatMessageRoleTool, ToolCallID: id, Content: content}
+}
+
+const repeatNote = "\n\n[This tool call was repeated %d more time(s) with identical output; the repetitions were removed.]"
+
+func TestDeduplicateMessages_RemovesDuplicates(t *testing.T) {
+	user := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "list files"}
+	messages := []openai.ChatCompletionMessage{
+		user,
+		callMessage(newToolCall("1", "run_shell_code", `{"code":"ls"}`)),
+		toolResultMessage("1", "a.txt"),
+		callMessage(newToolCall("2", "run_shell_code", `{"code":"ls"}`)),
+		toolResultMessage("2", "a.txt"),
+		callMessage(newToolCall("3", "run_shell_code", `{"code":"ls"}`)),
+		toolResultMessage("3", "a.txt"),
+	}
+	original := append([]openai.ChatCompletionMessage(nil), messages...)
+
+	deduplicated := DeduplicateMessages(messages)
+	assert.Equal(t, []openai.ChatCompletionMessage{
+		user,
+		callMessage(newToolCall("1", "run_shell_code", `{"code":"ls"}`)),
+		toolResultMessage("1", "a.txt"+fmt.Sprintf(repeatNote, 2)),
+	}, deduplicated)
+	assert.Equal(t, original, messages, "the input is not modified")
+
+	// Identical calls within a single message are collapsed as well
+	deduplicated = DeduplicateMessages([]openai.ChatCompletionMessage{
+		callMessage(newToolCall("1", "read_file", `{"path":"a"}`), newToolCall("2", "read_file", `{"path":"a"}`)),
+		toolResultMessage("1", "A"),
+		toolResultMessage("2", "A"),
+	})
+	assert.Equal(t, []openai.ChatCompletionMessage{
+		callMessage(newToolCall("1", "read_file", `{"path":"a"}`)),
+		toolResultMessage("1", "A"+fmt.Sprintf(repeatNote, 1)),
+	}, deduplicated)
+}
+
+func TestDeduplicateMessages_PreservesNonDuplicates(t *testing.T) {
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: "system"},
+		{Role: openai.ChatMessageRoleUser, Content: "question"},
+		// Same tool, different arguments
+		callMessage(newToolCall("1", "read_file", `{"path":"a"}`)),
+		toolResultMessage("1", "A"),
+		callMessage(newToolCall("2", "read_file", `{"path":"b"}`)),
+		toolResultMessage("2", "A"),
+		// Same call, different output
+		callMessage(newToolCall("3", "run_shell_code", `{"code":"date"}`)),
+		toolResultMessage("3", "10:00"),
+		callMessage(newToolCall("4", "run_shell_code", `{"code":"date"}`)),
+		toolResultMessage("4", "10:01"),
+		{Role: openai.ChatMessageRoleAssistant, Content: "done"},
+	}
+	assert.Equal(t, messages, DeduplicateMessages(messages))
+	assert.Empty(t, DeduplicateMessages(nil))
+}
+
+func TestDeduplicateMessages_Mixed(t *testing.T) {
+	messages := []openai.ChatCompletionMessage{
+		callMessage(newToolCall("1", "read_file", `{"path":"a"}`)),
+		toolResultMessage("1", "A"),
+		// Keeps its other call and its content when the repetition is removed
+		{Role: openai.ChatMessageRoleAssistant, Content: "reading again", ToolCalls: []openai.ToolCall{
+			newToolCall("2", "read_file", `{"path":"a"}`),
+			newToolCall("3", "read_file", `{"path":"b"}`),
+		}},
+		toolResultMessage("2", "A"),
+		toolResultMessage("3", "B"),
+		// Not consecutive with the first read of a
+		callMessage(newToolCall("4", "read_file", `{"path":"a"}`)),
+		toolResultMessage("4", "A"),
+		{Role: openai.ChatMessageRoleUser, Content: "again"},
+		// A user message in between ends a run of repetitions
+		callMessage(newToolCall("5", "read_file", `{"path":"a"}`)),
+		toolResultMessage("5", "A"),
+		callMessage(newToolCall("6", "read_file", `{"path":"a"}`)),
+		toolResultMessage("6", "A"),
+	}
+
+	assert.Equal(t, []openai.ChatCompletionMessage{
+		callMessage(newToolCall("1", "read_file", `{"path":"a"}`)),
+		toolResultMessage("1", "A"+fmt.Sprintf(repeatNote, 1)),
+		{Role: openai.ChatMessageRoleAssistant, Content: "reading again", ToolCalls: []openai.ToolCall{
+			newToolCall("3", "read_file", `{"path":"b"}`),
+		}},
+		toolResultMessage("3", "B"),
+		callMessage(newToolCall("4", "read_file", `{"path":"a"}`)),
+		toolResultMessage("4", "A"),
+		{Role: openai.ChatMessageRoleUser, Content: "again"},
+		callMessage(newToolCall("5", "read_file", `{"path":"a"}`)),
+		toolResultMessage("5", "A"+fmt.Sprintf(repeatNote, 1)),
+	}, DeduplicateMessages(messages))
+}
+
+func TestContinueSkillWithTools_DeduplicatesRequests(t *testing.T) {
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "run_shell_code", `{"code":"echo hi"}`),
+		toolCallResponse("call-2", "run_shell_code", `{"code":"echo hi"}`),
+		textResponse("done"),
+	}, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{Model: "test"}}
+
+	output, err := agent.continueSkillWithTools(context.Background(), "say hi", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+	require.NoError(t, err)
+	assert.Equal(t, "done", output)
+
+	require.Len(t, client.requests, 3)
+	last := client.requests[2].Messages
+	require.Len(t, last, 3, "the repeated call is sent once")
+	assert.Equal(t, "call-1", last[2].ToolCallID)
+	assert.Contains(t, last[2].Content, "repeated 1 more time(s)")
+	assert.Len(t, agent.messages, 6, "the history keeps every call")
+}
@@ -0,0 +1,67 @@
+package goskills
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+)
+
+// outputCacheKey identifies the final answer for a prompt run against a skill, system
+// prefix and model after the conversation history, so a follow-up turn only hits
+// answers given after the same conversation.
+func outputCacheKey(history []openai.ChatCompletionMessage, systemPrefix, skillBody, userPrompt, model string) string {
+	h := sha256.New()
+	// Length-prefixed fields cannot run into each other
+	for _, field := range []string{systemPrefix, skillBody, userPrompt, model} {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	json.NewEncoder(h).Encode(history)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// outputCachePath returns the cache file for key inside dir.
+func outputCachePath(dir, key string) string {
+	return filepath.Join(dir, key+".txt")
+}
+
+// readOutputCache returns the cached output for key, reporting whether it was found.
+func (a *Agent) readOutputCache(key string) (string, bool) {
+	data, err := os.ReadFile(outputCachePath(a.cfg.OutputCacheDir, key))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("failed to read output cache: %v", err)
+		}
+		return "", false
+	}
+	return string(data), true
+}
+
+// writeOutputCache stores output for key. The file is written to a temporary name and
+// renamed so concurrent readers never see a partial result.
+func (a *Agent) writeOutputCache(key, output string) error {
+	if err := os.MkdirAll(a.cfg.OutputCacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(a.cfg.OutputCacheDir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(output); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write output cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close output cache file: %w", err)
+	}
+	return os.Rename(tmp.Name(), outputCachePath(a.cfg.OutputCacheDir, key))
+}
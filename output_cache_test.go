@@ -0,0 +1,114 @@
+package goskills
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinueSkillWithTools_OutputCache(t *testing.T) {
+	dir := t.TempDir()
+	skill := &SkillPackage{Meta: SkillMeta{Name: "test"}, Body: "Answer questions."}
+	newAgent := func(client *MockOpenAIClient) *Agent {
+		return &Agent{client: client, cfg: RunnerConfig{Model: "test-model", OutputCacheDir: dir}}
+	}
+
+	// Miss: the LLM is called and the answer is written to the cache.
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("first answer")}, nil)
+	result, err := newAgent(client).continueSkillWithTools(context.Background(), "question", skill)
+	require.NoError(t, err)
+	assert.Equal(t, "first answer", result)
+	assert.Len(t, client.requests, 1)
+
+	data, err := os.ReadFile(filepath.Join(dir, outputCacheKey(nil, "", skill.Body, "question", "test-model")+".txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "first answer", string(data))
+
+	// Hit: the same prompt is answered without calling the LLM.
+	client = NewMockOpenAIClient(nil, nil)
+	agent := newAgent(client)
+	result, err = agent.continueSkillWithTools(context.Background(), "question", skill)
+	require.NoError(t, err)
+	assert.Equal(t, "first answer", result)
+	assert.Empty(t, client.requests)
+	require.Len(t, agent.messages, 2)
+	assert.Equal(t, openai.ChatMessageRoleAssistant, agent.messages[1].Role)
+
+	// A different prompt invalidates the cached answer.
+	client = NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("second answer")}, nil)
+	result, err = newAgent(client).continueSkillWithTools(context.Background(), "another question", skill)
+	require.NoError(t, err)
+	assert.Equal(t, "second answer", result)
+	assert.Len(t, client.requests, 1)
+}
+
+func TestContinueSkillWithTools_OutputCacheHistory(t *testing.T) {
+	dir := t.TempDir()
+	skill := &SkillPackage{Meta: SkillMeta{Name: "test"}, Body: "Answer questions."}
+	followUp := func(history []openai.ChatCompletionMessage, answer string) (string, *MockOpenAIClient) {
+		client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse(answer)}, nil)
+		agent := &Agent{client: client, messages: history, cfg: RunnerConfig{Model: "test-model", OutputCacheDir: dir}}
+		result, err := agent.continueSkillWithTools(context.Background(), "yes, do it", skill)
+		require.NoError(t, err)
+		return result, client
+	}
+	deleteFiles := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: "clean up my files"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "Delete all temporary files?"},
+	}
+	sendEmail := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: "write to Bob"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "Send the email now?"},
+	}
+
+	result, client := followUp(deleteFiles, "deleted")
+	assert.Equal(t, "deleted", result)
+	assert.Len(t, client.requests, 1)
+
+	// The same follow-up after another conversation misses the cache
+	result, client = followUp(sendEmail, "sent")
+	assert.Equal(t, "sent", result)
+	assert.Len(t, client.requests, 1)
+
+	result, client = followUp(deleteFiles, "unused")
+	assert.Equal(t, "deleted", result)
+	assert.Empty(t, client.requests)
+}
+
+func TestContinueSkillWithTools_OutputCacheSystemPrefix(t *testing.T) {
+	dir := t.TempDir()
+	skill := &SkillPackage{Meta: SkillMeta{Name: "test"}, Body: "Answer questions."}
+	ask := func(prefix, answer string) (string, *MockOpenAIClient) {
+		client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse(answer)}, nil)
+		agent := &Agent{client: client, cfg: RunnerConfig{Model: "test-model", OutputCacheDir: dir, SystemPrefix: prefix}}
+		result, err := agent.continueSkillWithTools(context.Background(), "question", skill)
+		require.NoError(t, err)
+		return result, client
+	}
+
+	result, client := ask("Answer in English.", "hello")
+	assert.Equal(t, "hello", result)
+	assert.Len(t, client.requests, 1)
+
+	// Another system prefix changes the prompt, so it misses the cache
+	result, client = ask("Answer in French.", "bonjour")
+	assert.Equal(t, "bonjour", result)
+	assert.Len(t, client.requests, 1)
+}
+
+func TestOutputCacheKey(t *testing.T) {
+	history := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "earlier"}}
+	key := outputCacheKey(history, "", "body", "prompt", "model")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, outputCacheKey(history, "", "body", "prompt", "model"))
+	assert.NotEqual(t, key, outputCacheKey(history, "", "changed body", "prompt", "model"))
+	assert.NotEqual(t, key, outputCacheKey(history, "", "body", "prompt", "other-model"))
+	assert.NotEqual(t, key, outputCacheKey(nil, "", "body", "prompt", "model"))
+	assert.NotEqual(t, key, outputCacheKey(history, "Answer in French.", "body", "prompt", "model"))
+	assert.NotEqual(t, outputCacheKey(nil, "", "ab", "c", "model"), outputCacheKey(nil, "", "a", "bc", "model"))
+}
@@ -0,0 +1,191 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/log"
+	"github.com/smallnest/goskills/skillstats"
+	"github.com/smallnest/goskills/trace"
+)
+
+// RaceResult is the winning attempt of an ExecutionRacer.
+type RaceResult struct {
+	Skill  string
+	Result string
+}
+
+// ExecutionRacer runs one attempt per skill concurrently and keeps the first that
+// succeeds. The remaining attempts are cancelled through their context.
+type ExecutionRacer struct {
+	Run func(ctx context.Context, skill string) (string, error)
+}
+
+// Race starts Run for every skill and returns the first successful result. It cancels
+// the other attempts and waits for them to return, so no attempt outlives the call.
+// When every attempt fails the error joins their errors.
+func (r ExecutionRacer) Race(ctx context.Context, skills []string) (RaceResult, error) {
+	if len(skills) == 0 {
+		return RaceResult{}, errors.New("no skills to run")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		skill  string
+		result string
+		err    error
+	}
+	outcomes := make(chan outcome, len(skills))
+	for _, skill := range skills {
+		go func(skill string) {
+			result, err := r.Run(ctx, skill)
+			outcomes <- outcome{skill: skill, result: result, err: err}
+		}(skill)
+	}
+
+	var winner *RaceResult
+	var errs []error
+	for range skills {
+		o := <-outcomes
+		switch {
+		case winner != nil:
+			// Attempts finishing after the winner were cancelled; their errors do not matter
+		case o.err == nil:
+			winner = &RaceResult{Skill: o.skill, Result: o.result}
+			cancel()
+		default:
+			errs = append(errs, fmt.Errorf("%s: %w", o.skill, o.err))
+		}
+	}
+	if winner == nil {
+		return RaceResult{}, fmt.Errorf("all %d skills failed: %w", len(skills), errors.Join(errs...))
+	}
+	return *winner, nil
+}
+
+// runParallelSkills asks the LLM for the cfg.ParallelSkills best skills and executes
+// them concurrently, each on its own copy of the conversation. The first successful
+// answer wins and its conversation becomes the agent's; the cost of every attempt is
+// counted.
+func (a *Agent) runParallelSkills(ctx context.Context, userPrompt string) (string, error) {
+	availableSkills, err := a.discoverSkills(a.cfg.SkillsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover skills: %w", err)
+	}
+	if len(availableSkills) == 0 {
+		return "", errors.New("no valid skills found")
+	}
+
+	names, err := a.selectTopNSkills(ctx, userPrompt, availableSkills, a.cfg.ParallelSkills)
+	if err != nil {
+		return "", &skerrors.SelectionError{Strategy: SelectionStrategyLLM, Err: err}
+	}
+	if a.cfg.Verbose >= 1 {
+		log.Info("running %d skills in parallel: %v", len(names), names)
+	}
+
+	forks := make(map[string]*Agent, len(names))
+	for _, name := range names {
+		fork := a.fork()
+		fork.messages = slices.Clone(a.messages)
+		forks[name] = fork
+		a.recordSkillStats(skillstats.Counters{Skill: name, Selected: 1})
+	}
+
+	racer := ExecutionRacer{Run: func(ctx context.Context, name string) (string, error) {
+		skill := availableSkills[name]
+		return forks[name].executeSkillWithTools(ctx, userPrompt, &skill)
+	}}
+	winner, err := racer.Race(ctx, names)
+
+	for _, fork := range forks {
+		a.totalCost += fork.totalCost
+		a.totalUsage.PromptTokens += fork.totalUsage.PromptTokens
+		a.totalUsage.CompletionTokens += fork.totalUsage.CompletionTokens
+		a.totalUsage.TotalTokens += fork.totalUsage.TotalTokens
+		a.apiCalls += fork.apiCalls
+		a.toolCalls += fork.toolCalls
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fork := forks[winner.Skill]
+	a.messages = fork.messages
+	a.activeSkill = fork.activeSkill
+	a.inlineTools = fork.inlineTools
+	a.tools = fork.tools
+	if a.cfg.Verbose >= 1 {
+		log.Info("skill %s answered first", winner.Skill)
+	}
+	return winner.Result, nil
+}
+
+// selectTopNSkills asks the LLM for up to n skills suited to userPrompt, best first.
+// Names the LLM makes up are dropped; it fails only if no valid name remains.
+func (a *Agent) selectTopNSkills(ctx context.Context, userPrompt string, skills map[string]SkillPackage, n int) ([]string, error) {
+	var sb strings.Builder
+	sb.WriteString("User Request: " + userPrompt + "\n\n")
+	a.writeSkillList(&sb, userPrompt, skills)
+	sb.WriteString(fmt.Sprintf("\nWhich %d skills from the above list are the most appropriate for the user request? ", n))
+	sb.WriteString("List them best first, one skill name per line. Respond with ONLY the skill names, nothing else.")
+
+	skillPrompt := ""
+	if a.cfg.SelectionTokenBudget <= 0 {
+		skillPrompt = SkillsToPrompt(skills)
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: a.cfg.Model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "You are a skill selection assistant. Your ONLY job is to rank the most appropriate skills from the available list. Never answer the question yourself.\n" + skillPrompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: sb.String(),
+			},
+		},
+		Temperature: 0,
+	}
+
+	a.debugPrintRequest(req)
+	a.traceRequest(trace.StageSelection, req)
+	resp, err := a.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, newAPIError("ChatCompletion", err)
+	}
+	a.debugPrintResponse(resp)
+	a.traceResponse(trace.StageSelection, resp)
+	a.trackCost(resp.Usage)
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("no choices in skill selection response")
+	}
+
+	content := resp.Choices[0].Message.Content
+	var names []string
+	for _, line := range strings.FieldsFunc(content, func(r rune) bool { return r == '\n' || r == ',' }) {
+		// Tolerate list markers such as "1." or "-" and quotes around the name
+		line = strings.Trim(strings.TrimSpace(line), "-*'\"` ")
+		line = strings.TrimSpace(strings.TrimLeft(line, "0123456789.)"))
+		name := extractSkillName(line, skills)
+		if _, ok := skills[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		if len(names) == n {
+			break
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no known skill in response %q", strings.TrimSpace(content))
+	}
+	return names, nil
+}
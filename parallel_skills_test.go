@@ -0,0 +1,145 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionRacer_FirstSuccessWins(t *testing.T) {
+	var cancelled atomic.Bool
+	failed := make(chan struct{})
+	racer := ExecutionRacer{Run: func(ctx context.Context, skill string) (string, error) {
+		switch skill {
+		case "slow":
+			<-ctx.Done()
+			cancelled.Store(true)
+			return "", ctx.Err()
+		case "fail":
+			defer close(failed)
+			return "", errors.New("boom")
+		default:
+			// Finish after the failure so the failure does not end the race
+			<-failed
+			return "fast answer", nil
+		}
+	}}
+
+	result, err := racer.Race(context.Background(), []string{"slow", "fail", "fast"})
+	require.NoError(t, err)
+	assert.Equal(t, RaceResult{Skill: "fast", Result: "fast answer"}, result)
+	assert.True(t, cancelled.Load(), "the slow attempt should be cancelled before Race returns")
+}
+
+func TestExecutionRacer_AllFail(t *testing.T) {
+	racer := ExecutionRacer{Run: func(ctx context.Context, skill string) (string, error) {
+		return "", errors.New(skill + " broke")
+	}}
+
+	_, err := racer.Race(context.Background(), []string{"a", "b"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "all 2 skills failed")
+	assert.Contains(t, err.Error(), "a: a broke")
+	assert.Contains(t, err.Error(), "b: b broke")
+
+	_, err = racer.Race(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestSelectTopNSkills(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"pdf-reader":   {Meta: SkillMeta{Name: "pdf-reader", Description: "Read PDFs."}},
+		"csv-analyzer": {Meta: SkillMeta{Name: "csv-analyzer", Description: "Analyze CSV files."}},
+		"xlsx":         {Meta: SkillMeta{Name: "xlsx", Description: "Edit spreadsheets."}},
+	}
+
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		textResponse("1. pdf-reader\n2. made-up-skill\n- 'csv-analyzer'\npdf-reader\nxlsx"),
+	}, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{Model: "test-model"}}
+	names, err := agent.selectTopNSkills(context.Background(), "compare the report with the data", skills, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pdf-reader", "csv-analyzer"}, names)
+	require.Len(t, client.requests, 1)
+	assert.Contains(t, client.requests[0].Messages[1].Content, "Which 2 skills")
+
+	client = NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("none of them")}, nil)
+	agent = &Agent{client: client, cfg: RunnerConfig{Model: "test-model"}}
+	_, err = agent.selectTopNSkills(context.Background(), "hello", skills, 2)
+	assert.ErrorContains(t, err, "no known skill")
+}
+
+// parallelSkillsClient ranks the skills "alpha" and "beta". Alpha blocks until its
+// request is cancelled; beta answers right away.
+type parallelSkillsClient struct {
+	mu       sync.Mutex
+	launched []string
+}
+
+func (c *parallelSkillsClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	system := req.Messages[0].Content
+	switch {
+	case strings.HasPrefix(system, "You are a skill selection assistant"):
+		return withUsage(textResponse("alpha\nbeta"), 10), nil
+	case strings.HasPrefix(system, "Alpha"):
+		c.record("alpha")
+		<-ctx.Done()
+		return openai.ChatCompletionResponse{}, ctx.Err()
+	default:
+		c.record("beta")
+		return withUsage(textResponse("beta answer"), 5), nil
+	}
+}
+
+func (c *parallelSkillsClient) record(skill string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.launched = append(c.launched, skill)
+}
+
+func withUsage(resp openai.ChatCompletionResponse, tokens int) openai.ChatCompletionResponse {
+	resp.Usage = openai.Usage{PromptTokens: tokens, TotalTokens: tokens}
+	return resp
+}
+
+func TestRun_ParallelSkills(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "alpha", "", "Alpha instructions.")
+	writeTestSkill(t, tmpDir, "beta", "", "Beta instructions.")
+	writeTestSkill(t, tmpDir, "gamma", "", "Gamma instructions.")
+
+	client := &parallelSkillsClient{}
+	agent := &Agent{client: client, cfg: RunnerConfig{Model: "test-model", SkillsDir: tmpDir, ParallelSkills: 2}}
+
+	result, err := agent.Run(context.Background(), "do it")
+	require.NoError(t, err)
+	assert.Equal(t, "beta answer", result)
+	assert.Equal(t, "beta", agent.ActiveSkill())
+	assert.ElementsMatch(t, []string{"alpha", "beta"}, client.launched)
+	assert.Equal(t, 15, agent.TotalUsage().TotalTokens)
+
+	// The winner's conversation becomes the agent's history
+	require.Len(t, agent.messages, 3)
+	assert.True(t, strings.HasPrefix(agent.messages[0].Content, "Beta instructions."))
+	assert.Equal(t, "beta answer", agent.messages[2].Content)
+}
+
+func TestRun_ParallelSkillsSelectionError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "alpha", "", "Alpha instructions.")
+
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("nothing fits")}, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{Model: "test-model", SkillsDir: tmpDir, ParallelSkills: 3}}
+
+	_, err := agent.Run(context.Background(), "do it")
+	var selErr *skerrors.SelectionError
+	assert.ErrorAs(t, err, &selErr)
+}
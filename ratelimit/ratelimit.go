@@ -0,0 +1,221 @@
+// Package ratelimit keeps LLM calls within per-model request and token rate limits.
+// Calls that would exceed a limit wait until the limit allows them instead of
+// failing with HTTP 429 and being retried.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// ChatClient is the chat completion client wrapped by RateLimitedOpenAIClient.
+type ChatClient interface {
+	CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+}
+
+// EmbeddingClient is implemented by wrapped clients that also create embeddings.
+type EmbeddingClient interface {
+	CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+// ModelLimits are the rate limits of a single model.
+type ModelLimits struct {
+	RPM int `json:"rpm" yaml:"rpm"` // Requests per minute; 0 means unlimited
+	TPM int `json:"tpm" yaml:"tpm"` // Prompt and completion tokens per minute; 0 means unlimited
+}
+
+// Limits maps model names to their rate limits.
+type Limits map[string]ModelLimits
+
+// RateLimitedOpenAIClient wraps a ChatClient and blocks calls that would exceed the
+// limits of their model. Each limit is a token bucket that holds a minute's worth of
+// capacity and refills continuously, so short bursts up to the limit are allowed.
+// Models without limits are not restricted. It is safe for concurrent use.
+type RateLimitedOpenAIClient struct {
+	client ChatClient
+
+	mu      sync.Mutex
+	limits  Limits
+	buckets map[string]*modelBuckets
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// New wraps client with the given per-model limits.
+func New(client ChatClient, limits Limits) *RateLimitedOpenAIClient {
+	return &RateLimitedOpenAIClient{
+		client:  client,
+		limits:  limits,
+		buckets: make(map[string]*modelBuckets),
+		now:     time.Now,
+		after:   time.After,
+	}
+}
+
+// CreateChatCompletion waits until the request's model has capacity for one more
+// request and the request's estimated tokens, then calls the wrapped client. Once
+// the response arrives the estimate is corrected with the reported token usage.
+// It returns ctx.Err() if ctx is done while waiting.
+func (c *RateLimitedOpenAIClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	estimate := EstimateTokens(req)
+	if err := c.wait(ctx, req.Model, estimate); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	resp, err := c.client.CreateChatCompletion(ctx, req)
+	if err == nil && resp.Usage.TotalTokens > 0 {
+		c.adjust(req.Model, resp.Usage.TotalTokens-estimate)
+	}
+	return resp, err
+}
+
+// CreateEmbeddings calls the wrapped client after waiting for the embedding model's
+// request limit. It fails if the wrapped client does not create embeddings.
+func (c *RateLimitedOpenAIClient) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	client, ok := c.client.(EmbeddingClient)
+	if !ok {
+		return openai.EmbeddingResponse{}, errors.New("the wrapped client does not support embeddings")
+	}
+	if err := c.wait(ctx, string(conv.Convert().Model), 0); err != nil {
+		return openai.EmbeddingResponse{}, err
+	}
+	return client.CreateEmbeddings(ctx, conv)
+}
+
+// EstimateTokens approximates the tokens a request uses before it is sent: about
+// four characters per token of message content, plus MaxTokens for the completion.
+func EstimateTokens(req openai.ChatCompletionRequest) int {
+	chars := 0
+	for _, msg := range req.Messages {
+		chars += len(msg.Content)
+		for _, part := range msg.MultiContent {
+			chars += len(part.Text)
+		}
+		for _, tc := range msg.ToolCalls {
+			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	return (chars+3)/4 + req.MaxTokens
+}
+
+// wait blocks until model has capacity for a request of the given tokens and takes it.
+func (c *RateLimitedOpenAIClient) wait(ctx context.Context, model string, tokens int) error {
+	for {
+		c.mu.Lock()
+		b := c.bucketsFor(model)
+		if b == nil {
+			c.mu.Unlock()
+			return nil
+		}
+		now := c.now()
+		delay := b.take(now, float64(tokens))
+		c.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.after(delay):
+		}
+	}
+}
+
+// adjust corrects model's token bucket by the difference between the tokens a
+// request used and its estimate.
+func (c *RateLimitedOpenAIClient) adjust(model string, diff int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b := c.bucketsFor(model); b != nil && b.tokens != nil {
+		b.tokens.refill(c.now())
+		b.tokens.available = math.Min(b.tokens.available-float64(diff), b.tokens.capacity)
+	}
+}
+
+// bucketsFor returns the buckets of model, creating them on first use, or nil if the
+// model is not limited. c.mu must be held.
+func (c *RateLimitedOpenAIClient) bucketsFor(model string) *modelBuckets {
+	if b, ok := c.buckets[model]; ok {
+		return b
+	}
+	limits, ok := c.limits[model]
+	if !ok || (limits.RPM <= 0 && limits.TPM <= 0) {
+		return nil
+	}
+	now := c.now()
+	b := &modelBuckets{
+		requests: newBucket(limits.RPM, now),
+		tokens:   newBucket(limits.TPM, now),
+	}
+	c.buckets[model] = b
+	return b
+}
+
+// modelBuckets are the request and token buckets of a model. A nil bucket is unlimited.
+type modelBuckets struct {
+	requests *bucket
+	tokens   *bucket
+}
+
+// take takes one request and the given tokens if both are available and returns 0.
+// Otherwise it takes nothing and returns how long to wait before trying again.
+// A request needing more tokens than a minute allows waits for a full bucket.
+func (b *modelBuckets) take(now time.Time, tokens float64) time.Duration {
+	var delay time.Duration
+	if b.requests != nil {
+		b.requests.refill(now)
+		delay = max(delay, b.requests.delay(1))
+	}
+	if b.tokens != nil {
+		b.tokens.refill(now)
+		tokens = math.Min(tokens, b.tokens.capacity)
+		delay = max(delay, b.tokens.delay(tokens))
+	}
+	if delay > 0 {
+		return delay
+	}
+	if b.requests != nil {
+		b.requests.available--
+	}
+	if b.tokens != nil {
+		b.tokens.available -= tokens
+	}
+	return 0
+}
+
+// bucket is a token bucket holding up to capacity units, refilled at capacity per minute.
+type bucket struct {
+	capacity  float64
+	available float64
+	last      time.Time
+}
+
+// newBucket returns a full bucket for a per-minute limit, or nil if limit is not positive.
+func newBucket(limit int, now time.Time) *bucket {
+	if limit <= 0 {
+		return nil
+	}
+	return &bucket{capacity: float64(limit), available: float64(limit), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.available = math.Min(b.capacity, b.available+b.capacity*elapsed.Minutes())
+		b.last = now
+	}
+}
+
+// delay returns how long until n units are available.
+func (b *bucket) delay(n float64) time.Duration {
+	missing := n - b.available
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / b.capacity * float64(time.Minute)))
+}
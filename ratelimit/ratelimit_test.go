@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient answers every request and reports usage tokens.
+type fakeClient struct {
+	mu     sync.Mutex
+	models []string
+	usage  int
+}
+
+func (c *fakeClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.models = append(c.models, req.Model)
+	return openai.ChatCompletionResponse{Usage: openai.Usage{TotalTokens: c.usage}}, nil
+}
+
+// fakeClock is a clock whose timers fire immediately by advancing the time.
+type fakeClock struct {
+	now    time.Time
+	waited time.Duration
+}
+
+// newTestClient returns a client whose waits advance a fake clock instead of sleeping.
+func newTestClient(inner ChatClient, limits Limits) (*RateLimitedOpenAIClient, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := New(inner, limits)
+	c.now = func() time.Time { return clock.now }
+	c.after = func(d time.Duration) <-chan time.Time {
+		clock.now = clock.now.Add(d)
+		clock.waited += d
+		ch := make(chan time.Time, 1)
+		ch <- clock.now
+		return ch
+	}
+	return c, clock
+}
+
+func request(model string, chars int) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:    model,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: strings.Repeat("a", chars)}},
+	}
+}
+
+func TestRateLimitedOpenAIClient_RPM(t *testing.T) {
+	c, clock := newTestClient(&fakeClient{}, Limits{"gpt-4o": {RPM: 2}})
+
+	for range 2 {
+		_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o", 4))
+		require.NoError(t, err)
+	}
+	assert.Zero(t, clock.waited, "a burst up to the limit does not wait")
+
+	// The bucket refills at 2 requests per minute
+	_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o", 4))
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, clock.waited)
+}
+
+func TestRateLimitedOpenAIClient_TPM(t *testing.T) {
+	inner := &fakeClient{}
+	c, clock := newTestClient(inner, Limits{"gpt-4o": {TPM: 100}})
+
+	// 240 characters are estimated as 60 tokens
+	_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o", 240))
+	require.NoError(t, err)
+	assert.Zero(t, clock.waited)
+
+	// 40 tokens are left and 20 more are needed, a fifth of a minute's worth
+	_, err = c.CreateChatCompletion(context.Background(), request("gpt-4o", 240))
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Second, clock.waited)
+
+	// A request larger than the limit waits for a full bucket instead of forever
+	clock.waited = 0
+	_, err = c.CreateChatCompletion(context.Background(), request("gpt-4o", 4000))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, clock.waited)
+	assert.Len(t, inner.models, 3)
+}
+
+func TestRateLimitedOpenAIClient_UsageCorrectsEstimate(t *testing.T) {
+	// Every response reports 100 tokens although 10 were estimated
+	c, clock := newTestClient(&fakeClient{usage: 100}, Limits{"gpt-4o": {TPM: 100}})
+
+	_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o", 40))
+	require.NoError(t, err)
+	assert.Zero(t, clock.waited)
+
+	_, err = c.CreateChatCompletion(context.Background(), request("gpt-4o", 40))
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Second, clock.waited, "the bucket is empty after the actual usage")
+}
+
+func TestRateLimitedOpenAIClient_PerModelIsolation(t *testing.T) {
+	inner := &fakeClient{}
+	c, clock := newTestClient(inner, Limits{
+		"gpt-4o":      {RPM: 1},
+		"gpt-4o-mini": {RPM: 1},
+	})
+
+	for _, model := range []string{"gpt-4o", "gpt-4o-mini", "unlimited", "unlimited"} {
+		_, err := c.CreateChatCompletion(context.Background(), request(model, 4))
+		require.NoError(t, err)
+	}
+	assert.Zero(t, clock.waited, "each model has a bucket of its own")
+
+	_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o-mini", 4))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, clock.waited)
+	assert.Equal(t, []string{"gpt-4o", "gpt-4o-mini", "unlimited", "unlimited", "gpt-4o-mini"}, inner.models)
+}
+
+func TestRateLimitedOpenAIClient_Blocks(t *testing.T) {
+	inner := &fakeClient{}
+	c := New(inner, Limits{"gpt-4o": {RPM: 1}})
+
+	_, err := c.CreateChatCompletion(context.Background(), request("gpt-4o", 4))
+	require.NoError(t, err)
+
+	// The next request would wait a minute; it is abandoned when its context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.CreateChatCompletion(ctx, request("gpt-4o", 4))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Len(t, inner.models, 1, "the blocked request is never sent")
+}
+
+func TestRateLimitedOpenAIClient_Embeddings(t *testing.T) {
+	c := New(&fakeClient{}, nil)
+	_, err := c.CreateEmbeddings(context.Background(), openai.EmbeddingRequestStrings{Input: []string{"x"}})
+	assert.EqualError(t, err, "the wrapped client does not support embeddings")
+}
+
+func TestEstimateTokens(t *testing.T) {
+	req := openai.ChatCompletionRequest{
+		MaxTokens: 50,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: strings.Repeat("a", 10)},
+			{Role: openai.ChatMessageRoleAssistant, ToolCalls: []openai.ToolCall{{Function: openai.FunctionCall{Name: "read_file", Arguments: "{}"}}}},
+		},
+	}
+	assert.Equal(t, 6+50, EstimateTokens(req))
+}
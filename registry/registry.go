@@ -0,0 +1,299 @@
+// Package registry discovers skills in a hosted index and downloads them from GitHub.
+//
+// The index is a JSON document listing the published skills:
+//
+//	{"skills": [{"name": "pdf", "description": "...", "version": "1.2.0",
+//	  "github_url": "https://github.com/owner/repo/tree/main/pdf",
+//	  "downloads": 1200, "stars": 85}]}
+//
+// It is cached locally and fetched again once the cache is older than CacheTTL.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultIndexURL is the index used when SkillRegistry.IndexURL is empty.
+const DefaultIndexURL = "https://registry.goskills.io/index.json"
+
+// DefaultCacheTTL is how long a cached index is used before it is fetched again.
+const DefaultCacheTTL = time.Hour
+
+// DefaultGitHubAPIURL is the GitHub API used by Download.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// RegistryEntry describes a published skill.
+type RegistryEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version,omitempty"`
+	GitHubURL   string `json:"github_url"`
+	Downloads   int    `json:"downloads"`
+	Stars       int    `json:"stars"`
+}
+
+// index is the document served at the index URL.
+type index struct {
+	Skills []RegistryEntry `json:"skills"`
+}
+
+// SkillRegistry searches a skill index and downloads its skills. The zero value
+// uses DefaultIndexURL without a local cache.
+type SkillRegistry struct {
+	IndexURL     string        // URL of the index; defaults to DefaultIndexURL
+	CachePath    string        // File caching the index; empty disables caching
+	CacheTTL     time.Duration // Age after which the cached index is refreshed; defaults to DefaultCacheTTL
+	GitHubAPIURL string        // GitHub API base URL; defaults to DefaultGitHubAPIURL
+	HTTPClient   *http.Client  // Defaults to http.DefaultClient
+}
+
+// DefaultCachePath returns ~/.goskills/registry-index.json.
+func DefaultCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".goskills", "registry-index.json"), nil
+}
+
+// Search returns the entries whose name or description contains every word of query,
+// ignoring case. Entries matching by name come first, then the most starred and most
+// downloaded. An empty query returns every entry.
+func (r *SkillRegistry) Search(query string) ([]RegistryEntry, error) {
+	idx, err := r.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	type match struct {
+		entry  RegistryEntry
+		inName bool
+	}
+	var matches []match
+	for _, entry := range idx.Skills {
+		name := strings.ToLower(entry.Name)
+		text := name + " " + strings.ToLower(entry.Description)
+		all, inName := true, len(words) > 0
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				all = false
+				break
+			}
+			inName = inName && strings.Contains(name, w)
+		}
+		if all {
+			matches = append(matches, match{entry: entry, inName: inName})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.inName != b.inName {
+			return a.inName
+		}
+		if a.entry.Stars != b.entry.Stars {
+			return a.entry.Stars > b.entry.Stars
+		}
+		return a.entry.Downloads > b.entry.Downloads
+	})
+	entries := make([]RegistryEntry, len(matches))
+	for i, m := range matches {
+		entries[i] = m.entry
+	}
+	return entries, nil
+}
+
+// loadIndex returns the cached index while it is fresh, and fetches it otherwise.
+// If fetching fails, a stale cached index is used instead.
+func (r *SkillRegistry) loadIndex() (*index, error) {
+	ttl := r.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	var cached []byte
+	if r.CachePath != "" {
+		if info, err := os.Stat(r.CachePath); err == nil {
+			if data, err := os.ReadFile(r.CachePath); err == nil {
+				cached = data
+				if time.Since(info.ModTime()) < ttl {
+					return parseIndex(data)
+				}
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read registry cache: %w", err)
+		}
+	}
+
+	data, err := r.fetchIndex()
+	if err != nil {
+		if cached != nil {
+			return parseIndex(cached)
+		}
+		return nil, err
+	}
+	idx, err := parseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.CachePath != "" {
+		if err := os.MkdirAll(filepath.Dir(r.CachePath), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create registry cache directory: %w", err)
+		}
+		if err := os.WriteFile(r.CachePath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write registry cache: %w", err)
+		}
+	}
+	return idx, nil
+}
+
+func (r *SkillRegistry) fetchIndex() ([]byte, error) {
+	indexURL := r.IndexURL
+	if indexURL == "" {
+		indexURL = DefaultIndexURL
+	}
+	resp, err := r.client().Get(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registry index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry index %s returned status %d", indexURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry index: %w", err)
+	}
+	return data, nil
+}
+
+func parseIndex(data []byte) (*index, error) {
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse registry index: %w", err)
+	}
+	return &idx, nil
+}
+
+func (r *SkillRegistry) client() *http.Client {
+	if r.HTTPClient != nil {
+		return r.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Download downloads the skill directory at entry.GitHubURL into targetDir, which is
+// created if needed. The URL has the form https://github.com/owner/repo, for a skill
+// at the root of the default branch, or https://github.com/owner/repo/tree/branch/path.
+func (r *SkillRegistry) Download(entry RegistryEntry, targetDir string) error {
+	owner, repo, branch, dir, err := parseGitHubURL(entry.GitHubURL)
+	if err != nil {
+		return fmt.Errorf("skill %s: %w", entry.Name, err)
+	}
+	if err := r.downloadDir(owner, repo, branch, dir, targetDir); err != nil {
+		return fmt.Errorf("failed to download skill %s: %w", entry.Name, err)
+	}
+	return nil
+}
+
+// githubContent is a file or directory listed by the GitHub contents API.
+type githubContent struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	DownloadURL string `json:"download_url"`
+}
+
+// downloadDir downloads the files of a repository directory recursively.
+func (r *SkillRegistry) downloadDir(owner, repo, branch, dir, targetDir string) error {
+	apiURL := r.GitHubAPIURL
+	if apiURL == "" {
+		apiURL = DefaultGitHubAPIURL
+	}
+	contentsURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s", strings.TrimSuffix(apiURL, "/"), owner, repo, dir)
+	if branch != "" {
+		contentsURL += "?ref=" + url.QueryEscape(branch)
+	}
+
+	resp, err := r.client().Get(contentsURL)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API returned status %d for %s", resp.StatusCode, contentsURL)
+	}
+	var contents []githubContent
+	if err := json.NewDecoder(resp.Body).Decode(&contents); err != nil {
+		return fmt.Errorf("failed to decode GitHub API response: %w", err)
+	}
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", targetDir, err)
+	}
+	for _, item := range contents {
+		// Names come from the server; never let them leave targetDir
+		if item.Name != path.Base(item.Name) || item.Name == "." || item.Name == ".." {
+			return fmt.Errorf("invalid file name %q", item.Name)
+		}
+		itemPath := filepath.Join(targetDir, item.Name)
+		switch item.Type {
+		case "file":
+			if err := r.downloadFile(item.DownloadURL, itemPath); err != nil {
+				return fmt.Errorf("failed to download %s: %w", item.Path, err)
+			}
+		case "dir":
+			if err := r.downloadDir(owner, repo, branch, item.Path, itemPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *SkillRegistry) downloadFile(fileURL, dest string) error {
+	resp, err := r.client().Get(fileURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseGitHubURL splits a GitHub URL into owner, repository, branch and directory.
+// The branch is empty for the repository's default branch.
+func parseGitHubURL(githubURL string) (owner, repo, branch, dir string, err error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(githubURL, "https://"), "http://")
+	trimmed, ok := strings.CutPrefix(trimmed, "github.com/")
+	parts := strings.Split(strings.Trim(trimmed, "/"), "/")
+	switch {
+	case ok && len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], "", "", nil
+	case ok && len(parts) >= 4 && parts[2] == "tree":
+		return parts[0], parts[1], parts[3], strings.Join(parts[4:], "/"), nil
+	}
+	return "", "", "", "", fmt.Errorf("invalid GitHub URL '%s' (expected https://github.com/owner/repo or https://github.com/owner/repo/tree/branch/path)", githubURL)
+}
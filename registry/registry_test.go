@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureIndex = `{"skills": [
+  {"name": "pdf", "description": "Extract text and tables from PDF files.", "version": "1.2.0",
+   "github_url": "https://github.com/acme/skills/tree/main/pdf", "downloads": 1200, "stars": 85},
+  {"name": "csv-analyzer", "description": "Analyze CSV and PDF exports.", "version": "0.3.0",
+   "github_url": "https://github.com/acme/skills/tree/main/csv-analyzer", "downloads": 5000, "stars": 120},
+  {"name": "pdf-forms", "description": "Fill in PDF forms.", "version": "2.0.0",
+   "github_url": "https://github.com/acme/pdf-forms", "downloads": 300, "stars": 12},
+  {"name": "slack-gif", "description": "Create animated GIFs for Slack.",
+   "github_url": "https://github.com/acme/skills/tree/main/slack-gif", "downloads": 40, "stars": 3}
+]}`
+
+// newIndexServer serves fixtureIndex and counts the requests for it.
+func newIndexServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(fixtureIndex))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func names(entries []RegistryEntry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func TestSkillRegistry_Search(t *testing.T) {
+	server, _ := newIndexServer(t)
+	r := &SkillRegistry{IndexURL: server.URL}
+
+	entries, err := r.Search("PDF")
+	require.NoError(t, err)
+	// Name matches first, then by stars
+	assert.Equal(t, []string{"pdf", "pdf-forms", "csv-analyzer"}, names(entries))
+	assert.Equal(t, RegistryEntry{
+		Name:        "pdf",
+		Description: "Extract text and tables from PDF files.",
+		Version:     "1.2.0",
+		GitHubURL:   "https://github.com/acme/skills/tree/main/pdf",
+		Downloads:   1200,
+		Stars:       85,
+	}, entries[0])
+
+	entries, err = r.Search("pdf tables")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pdf"}, names(entries))
+
+	entries, err = r.Search("kubernetes")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = r.Search("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"csv-analyzer", "pdf", "pdf-forms", "slack-gif"}, names(entries))
+}
+
+func TestSkillRegistry_Cache(t *testing.T) {
+	server, hits := newIndexServer(t)
+	cachePath := filepath.Join(t.TempDir(), "cache", "index.json")
+	r := &SkillRegistry{IndexURL: server.URL, CachePath: cachePath, CacheTTL: time.Hour}
+
+	for range 3 {
+		_, err := r.Search("pdf")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), hits.Load(), "the cached index is used within the TTL")
+	data, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	assert.JSONEq(t, fixtureIndex, string(data))
+
+	// An expired cache is refreshed
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(cachePath, old, old))
+	_, err = r.Search("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), hits.Load())
+
+	// When the registry is unreachable, a stale cache is better than nothing
+	require.NoError(t, os.Chtimes(cachePath, old, old))
+	server.Close()
+	entries, err := r.Search("slack")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slack-gif"}, names(entries))
+}
+
+func TestSkillRegistry_SearchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	_, err := (&SkillRegistry{IndexURL: server.URL}).Search("pdf")
+	assert.ErrorContains(t, err, "returned status 503")
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer bad.Close()
+	_, err = (&SkillRegistry{IndexURL: bad.URL}).Search("pdf")
+	assert.ErrorContains(t, err, "failed to parse registry index")
+}
+
+// newGitHubServer fakes the GitHub contents API for acme/skills with a pdf skill
+// containing SKILL.md and scripts/extract.py.
+func newGitHubServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/acme/skills/contents/pdf", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		json.NewEncoder(w).Encode([]githubContent{
+			{Name: "SKILL.md", Path: "pdf/SKILL.md", Type: "file", DownloadURL: server.URL + "/raw/SKILL.md"},
+			{Name: "scripts", Path: "pdf/scripts", Type: "dir"},
+		})
+	})
+	mux.HandleFunc("/repos/acme/skills/contents/pdf/scripts", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]githubContent{
+			{Name: "extract.py", Path: "pdf/scripts/extract.py", Type: "file", DownloadURL: server.URL + "/raw/extract.py"},
+		})
+	})
+	mux.HandleFunc("/repos/acme/skills/contents/evil", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]githubContent{
+			{Name: "../escape.txt", Path: "evil/../escape.txt", Type: "file", DownloadURL: server.URL + "/raw/SKILL.md"},
+		})
+	})
+	mux.HandleFunc("/raw/SKILL.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("---\nname: pdf\ndescription: PDF tools\n---\nUse the scripts.\n"))
+	})
+	mux.HandleFunc("/raw/extract.py", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("print('text')\n"))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSkillRegistry_Download(t *testing.T) {
+	github := newGitHubServer(t)
+	r := &SkillRegistry{GitHubAPIURL: github.URL}
+	target := filepath.Join(t.TempDir(), "pdf")
+
+	err := r.Download(RegistryEntry{Name: "pdf", GitHubURL: "https://github.com/acme/skills/tree/main/pdf"}, target)
+	require.NoError(t, err)
+	skill, err := os.ReadFile(filepath.Join(target, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(skill), "name: pdf")
+	script, err := os.ReadFile(filepath.Join(target, "scripts", "extract.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "print('text')\n", string(script))
+
+	err = r.Download(RegistryEntry{Name: "evil", GitHubURL: "https://github.com/acme/skills/tree/main/evil"}, filepath.Join(t.TempDir(), "evil"))
+	assert.ErrorContains(t, err, `invalid file name "../escape.txt"`)
+
+	err = r.Download(RegistryEntry{Name: "missing", GitHubURL: "https://github.com/acme/skills/tree/main/missing"}, t.TempDir())
+	assert.ErrorContains(t, err, "GitHub API returned status 404")
+
+	err = r.Download(RegistryEntry{Name: "bad", GitHubURL: "https://gitlab.com/acme/skills"}, t.TempDir())
+	assert.ErrorContains(t, err, "invalid GitHub URL")
+}
+
+func TestParseGitHubURL(t *testing.T) {
+	owner, repo, branch, dir, err := parseGitHubURL("https://github.com/acme/pdf-forms")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "pdf-forms", "", ""}, []string{owner, repo, branch, dir})
+
+	owner, repo, branch, dir, err = parseGitHubURL("https://github.com/acme/skills/tree/dev/skills/pdf/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "skills", "dev", "skills/pdf"}, []string{owner, repo, branch, dir})
+
+	_, _, _, _, err = parseGitHubURL("https://github.com/acme")
+	assert.Error(t, err)
+}
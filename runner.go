@@ -1,19 +1,30 @@
 package goskills
 
 import (
-	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/audit"
+	skerrors "github.com/smallnest/goskills/errors"
 	"github.com/smallnest/goskills/log"
 	"github.com/smallnest/goskills/mcp"
+	"github.com/smallnest/goskills/ratelimit"
+	"github.com/smallnest/goskills/skillstats"
 	"github.com/smallnest/goskills/tool"
+	"github.com/smallnest/goskills/trace"
 )
 
 // OpenAIChatClient interface for dependency injection and testing
@@ -27,20 +38,137 @@ type Agent struct {
 	cfg       RunnerConfig
 	messages  []openai.ChatCompletionMessage // Stores the conversation history
 	mcpClient *mcp.Client
+
+	sessionID   string                // Identifies this agent's session in the audit log
+	activeSkill string                // Name of the skill currently being executed
+	lastPrompt  string                // User prompt of the skill execution in progress
+	inlineTools map[string]InlineTool // Frontmatter tools of the active skill, by name
+	tools       []openai.Tool         // Tools offered to the LLM for the active skill, listed by list_tools
+	auditLogger *audit.Logger         // Nil when audit logging is disabled
+	toolCache   ToolCache             // Nil when tool caching is disabled
+	selector    SkillSelector         // Created on first use from cfg.SelectionStrategy
+	totalCost   float64               // Estimated USD cost of all LLM calls so far
+	totalUsage  openai.Usage          // Token usage of all LLM calls so far
+	apiCalls    int                   // Number of completed LLM calls so far
+	toolCalls   int                   // Number of tools executed so far
+	cliApproval *CLIApprovalHandler   // Reused so buffered terminal input is not lost between prompts; its reader is shared with RunLoop
+	stats       *skillstats.Store     // Nil when skill usage counters are disabled
 }
 
 // RunnerConfig holds all the necessary configuration for the runner.
 type RunnerConfig struct {
-	APIKey           string
-	APIBase          string
-	Model            string
-	SkillsDir        string
-	Verbose          int
-	Debug            bool
-	AutoApproveTools bool
-	AllowedScripts   []string
-	Loop             bool
-	SkillName        string
+	APIKey                       string
+	APIBase                      string
+	Model                        string
+	SkillsDir                    string
+	Verbose                      int
+	Debug                        bool
+	AutoApproveTools             bool
+	AllowedScripts               []string
+	Loop                         bool
+	SkillName                    string
+	EnableBrowserTools           bool // Expose tools that drive a headless browser, such as web_screenshot
+	DiscoveryFilter              DiscoveryFilter
+	AuditLogPath                 string                   // Append a JSONL record for every tool call to this file
+	AuditRedactPatterns          []string                 // Regexes for argument names/values to redact; defaults to audit.DefaultRedactPatterns
+	ToolCache                    ToolCache                // Cache for tool output; an in-memory cache is used if nil and ToolCacheTTLs is set
+	ToolCacheTTLs                map[string]time.Duration // Per-tool cache lifetime; only listed tools are cached
+	SelectionTokenBudget         int                      // When > 0, list skills compactly within this many tokens during selection
+	AllSkillsMode                bool                     // Skip skill selection and give the LLM every skill body in one system prompt
+	AllSkillsTokenLimit          int                      // Token limit for the combined skill bodies in AllSkillsMode; defaults to DefaultAllSkillsTokenLimit
+	MemorySummarizationThreshold int                      // Summarize older history past this many messages; NewAgent uses DefaultMemorySummarizationThreshold for 0, negative disables
+	Trace                        *trace.Recorder          // Records every LLM request/response and tool call when set
+	PreferredPython              string                   // Python interpreter for all Python tools; detected per script (python2/python3) when empty
+	MaxWatchRuns                 int                      // Stop Watch after this many re-runs; 0 watches until the context is cancelled
+	SelectionStrategy            string                   // How skills are selected: SelectionStrategyLLM (default), SelectionStrategyKeyword or SelectionStrategyEmbeddings
+	EmbeddingModel               string                   // Embedding model for the embeddings strategy; defaults to text-embedding-3-small
+	EmbeddingCachePath           string                   // JSON file caching skill embeddings across runs; in-memory only when empty
+	SkillSelector                SkillSelector            // Custom skill selector; overrides SelectionStrategy when set
+	OutputCacheDir               string                   // Cache final answers by (history, skill body, prompt, model) in this directory; disabled when empty
+	MaxToolIterations            int                      // Maximum LLM round trips per prompt while tools are being called (1-100); defaults to DefaultMaxToolIterations
+	AllowedEnvVars               []string                 // Environment variables the get_env tool may reveal; defaults to tool.DefaultAllowedEnvVars
+	PricingTable                 map[string][2]float64    // USD per million prompt and completion tokens by model; overrides DefaultPricingTable
+	ApprovalMode                 string                   // ApprovalModeCLI, ApprovalModeGUI or ApprovalModeAuto; follows AutoApproveTools when empty
+	ApprovalHandler              ToolApprovalHandler      // Custom approval handler, e.g. a WebSocketApprovalHandler; overrides ApprovalMode
+	SearchSources                []string                 // Backends queried by web_search; defaults to tool.DefaultSearchSources
+	MemoryStorePath              string                   // JSON file backing the memory_set and memory_get tools; NewAgent uses ~/.goskills/memory.json, empty disables memory
+	StatsDBPath                  string                   // SQLite database of per-skill usage counters (see package skillstats); empty disables them
+	MaxSkillBodyBytes            int                      // Truncate skill bodies longer than this in the system prompt; 0 means no limit
+	SkillFallbackThreshold       int                      // Select another skill after this many consecutive tool errors; NewAgent uses DefaultSkillFallbackThreshold for 0, negative disables
+	SkillArgs                    map[string]string        // Parameters passed to skill scripts as GOSKILLS_ARG_<KEY> env vars and {{.GOSKILLS_ARGS.key}}
+	AllowedRepoPaths             []string                 // Repositories the git_log and git_diff tools may read, including subdirectories; empty disables them
+	SystemPrefix                 string                   // Prepended to the skill's system message, e.g. company name or locale; empty adds nothing
+	ParallelSkills               int                      // When > 1, run the LLM's top N skill candidates concurrently and keep the first successful answer
+	SkillVars                    map[string]string        // Values for {{skill_var:KEY}} placeholders in skill bodies (see SkillPackage.Template)
+	RateLimits                   ratelimit.Limits         // Per-model requests and tokens per minute; LLM calls wait rather than exceed them
+	CheckpointDir                string                   // Write a checkpoint to <dir>/<session>.json after every tool-call iteration; empty disables checkpoints
+	ToolTimeouts                 map[string]time.Duration // Per-tool execution time limit; unlisted tools use DefaultToolTimeouts, <= 0 disables the limit; NewAgent rejects limits for tools that cannot be stopped, such as read_file
+	DockerEnabled                bool                     // Expose the run_docker_command tool, which runs commands in containers with the docker CLI
+	AllowedDockerImages          []string                 // Images run_docker_command may use; an image without a tag allows all its tags
+	AllowedDockerVolumes         []string                 // Host directories, besides the active skill's, run_docker_command may mount
+}
+
+// allSkillsName is the name of the pseudo skill combining all skills in AllSkillsMode.
+const allSkillsName = "all-skills"
+
+// DefaultAllSkillsTokenLimit is the token limit for combined skill bodies when
+// RunnerConfig.AllSkillsMode is set without an AllSkillsTokenLimit.
+const DefaultAllSkillsTokenLimit = 32000
+
+// DefaultToolTimeouts limits how long these tools may run unless RunnerConfig.ToolTimeouts
+// lists them. Other tools have no time limit by default.
+var DefaultToolTimeouts = map[string]time.Duration{
+	"web_fetch":          30 * time.Second,
+	"run_shell_code":     60 * time.Second,
+	"run_python_code":    120 * time.Second,
+	"run_docker_command": 5 * time.Minute,
+}
+
+// uninterruptibleTools are the built-in tools that cannot be stopped while they run,
+// so NewAgent refuses a RunnerConfig.ToolTimeouts entry for them.
+var uninterruptibleTools = map[string]bool{
+	"read_file":             true,
+	"batch_read_files":      true,
+	"compare_files":         true,
+	"code_search":           true,
+	"parse_structured_data": true,
+	"write_file":            true,
+	"search_replace_file":   true,
+	"copy_file":             true,
+	"move_file":             true,
+	"get_env":               true,
+	"memory_set":            true,
+	"memory_get":            true,
+	tool.ListToolsName:      true,
+	tool.SkillInfoName:      true,
+}
+
+// DefaultMaxToolIterations and MaxAllowedToolIterations bound RunnerConfig.MaxToolIterations.
+const (
+	DefaultMaxToolIterations = 20
+	MaxAllowedToolIterations = 100
+)
+
+// DiscoveryFilter restricts which skills are considered during discovery.
+// An empty filter matches every skill.
+type DiscoveryFilter struct {
+	Tags []string // Include only skills tagged with at least one of these tags
+}
+
+// Matches reports whether the skill metadata satisfies the filter.
+// Tags are compared case-insensitively.
+func (f DiscoveryFilter) Matches(meta SkillMeta) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, want := range f.Tags {
+		for _, tag := range meta.Tags {
+			if strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(tag)) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // NewAgent creates and initializes a new Agent.
@@ -51,23 +179,137 @@ func NewAgent(cfg RunnerConfig, mcpClient *mcp.Client) (*Agent, error) {
 	if cfg.Model == "" {
 		cfg.Model = "gpt-4o" // Default model
 	}
+	if cfg.MemorySummarizationThreshold == 0 {
+		cfg.MemorySummarizationThreshold = DefaultMemorySummarizationThreshold
+	}
+	if len(cfg.AllowedEnvVars) == 0 {
+		cfg.AllowedEnvVars = tool.DefaultAllowedEnvVars
+	}
+	if cfg.MemoryStorePath == "" {
+		if path, err := tool.DefaultMemoryStorePath(); err == nil {
+			cfg.MemoryStorePath = path
+		}
+	}
+	if cfg.SkillFallbackThreshold == 0 {
+		cfg.SkillFallbackThreshold = DefaultSkillFallbackThreshold
+	}
+	if err := tool.ValidateSkillArgs(cfg.SkillArgs); err != nil {
+		return nil, err
+	}
+	if err := ValidateSkillVars(cfg.SkillVars); err != nil {
+		return nil, err
+	}
+	switch cfg.ApprovalMode {
+	case "", ApprovalModeCLI, ApprovalModeGUI, ApprovalModeAuto:
+	default:
+		return nil, fmt.Errorf("invalid approval mode '%s' (expected cli, gui or auto)", cfg.ApprovalMode)
+	}
+	if cfg.MaxToolIterations == 0 {
+		cfg.MaxToolIterations = DefaultMaxToolIterations
+	}
+	if cfg.MaxToolIterations < 1 || cfg.MaxToolIterations > MaxAllowedToolIterations {
+		return nil, fmt.Errorf("max tool iterations must be between 1 and %d, got %d", MaxAllowedToolIterations, cfg.MaxToolIterations)
+	}
+	for name, timeout := range cfg.ToolTimeouts {
+		if timeout > 0 && uninterruptibleTools[name] {
+			return nil, fmt.Errorf("tool %s cannot be stopped once started, so it does not support a timeout", name)
+		}
+	}
 
 	openaiConfig := openai.DefaultConfig(cfg.APIKey)
 	if cfg.APIBase != "" {
 		openaiConfig.BaseURL = cfg.APIBase
 	}
-	client := openai.NewClientWithConfig(openaiConfig)
+	var client OpenAIChatClient = openai.NewClientWithConfig(openaiConfig)
+	if len(cfg.RateLimits) > 0 {
+		client = ratelimit.New(client, cfg.RateLimits)
+	}
+
+	var auditLogger *audit.Logger
+	if cfg.AuditLogPath != "" {
+		var patterns []*regexp.Regexp
+		for _, p := range cfg.AuditRedactPatterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid audit redact pattern '%s': %w", p, err)
+			}
+			patterns = append(patterns, re)
+		}
+		var err error
+		auditLogger, err = audit.NewLogger(cfg.AuditLogPath, patterns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stats *skillstats.Store
+	if cfg.StatsDBPath != "" {
+		var err error
+		if stats, err = skillstats.Open(cfg.StatsDBPath); err != nil {
+			return nil, err
+		}
+	}
+
+	toolCache := cfg.ToolCache
+	if toolCache == nil && len(cfg.ToolCacheTTLs) > 0 {
+		toolCache = NewMemoryToolCache()
+	}
 
 	return &Agent{
-		client:    client,
-		cfg:       cfg,
-		messages:  []openai.ChatCompletionMessage{}, // Initialize empty message history
-		mcpClient: mcpClient,
+		client:      client,
+		cfg:         cfg,
+		messages:    []openai.ChatCompletionMessage{}, // Initialize empty message history
+		mcpClient:   mcpClient,
+		sessionID:   newSessionID(),
+		auditLogger: auditLogger,
+		toolCache:   toolCache,
+		stats:       stats,
 	}, nil
 }
 
+// newSessionID returns a random identifier for an agent session.
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// ActiveSkill returns the name of the skill the agent executed most recently, or ""
+// if no skill has been executed yet.
+func (a *Agent) ActiveSkill() string {
+	return a.activeSkill
+}
+
 // Run executes the main skill selection and execution logic for a single turn.
+// The turn is appended to the agent's own conversation history.
 func (a *Agent) Run(ctx context.Context, userPrompt string) (string, error) {
+	result, messages, err := a.RunWithMessages(ctx, userPrompt, a.messages)
+	a.messages = messages
+	return result, err
+}
+
+// RunWithMessages is like Run but uses messages as the conversation history for this
+// call instead of the agent's own history, which is left untouched. It returns the
+// result and a new slice holding messages followed by this turn; messages itself is
+// not modified. The slice is returned even when err is non-nil so callers can inspect
+// the partial turn.
+func (a *Agent) RunWithMessages(ctx context.Context, userPrompt string, messages []openai.ChatCompletionMessage) (string, []openai.ChatCompletionMessage, error) {
+	saved := a.messages
+	a.messages = append(make([]openai.ChatCompletionMessage, 0, len(messages)), messages...)
+	defer func() { a.messages = saved }()
+
+	result, err := a.run(ctx, userPrompt)
+	return result, a.messages, err
+}
+
+// run selects a skill for userPrompt and executes it against a.messages.
+func (a *Agent) run(ctx context.Context, userPrompt string) (string, error) {
+	if a.cfg.ParallelSkills > 1 && a.cfg.SkillName == "" && !a.cfg.AllSkillsMode {
+		return a.runParallelSkills(ctx, userPrompt)
+	}
+
 	selectedSkill, err := a.selectAndPrepareSkill(ctx, userPrompt)
 	if err != nil {
 		return "", err
@@ -82,6 +324,68 @@ func (a *Agent) Run(ctx context.Context, userPrompt string) (string, error) {
 	return a.executeSkillWithTools(ctx, userPrompt, selectedSkill)
 }
 
+// BatchResult holds the outcome of a single prompt executed by RunBatch.
+type BatchResult struct {
+	Prompt   string
+	Result   string
+	Error    error
+	Duration time.Duration
+}
+
+// RunBatch runs each prompt as an independent Run and returns the results in the
+// same order as prompts. At most maxConcurrency prompts run at once; a value of zero
+// or less runs them all concurrently. Every prompt gets its own message history.
+// The returned error is non-nil only if ctx is cancelled before all prompts finish.
+func (a *Agent) RunBatch(ctx context.Context, prompts []string, maxConcurrency int) ([]BatchResult, error) {
+	if maxConcurrency <= 0 || maxConcurrency > len(prompts) {
+		maxConcurrency = len(prompts)
+	}
+
+	results := make([]BatchResult, len(prompts))
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, prompt := range prompts {
+		results[i].Prompt = prompt
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Error = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, prompt string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			results[i].Result, results[i].Error = a.fork().Run(ctx, prompt)
+			results[i].Duration = time.Since(start)
+		}(i, prompt)
+	}
+
+	wg.Wait()
+	return results, ctx.Err()
+}
+
+// fork returns a copy of the agent that shares its client, configuration and
+// integrations but starts with an empty message history and its own session ID.
+func (a *Agent) fork() *Agent {
+	return &Agent{
+		client:      a.client,
+		cfg:         a.cfg,
+		messages:    []openai.ChatCompletionMessage{},
+		mcpClient:   a.mcpClient,
+		sessionID:   newSessionID(),
+		auditLogger: a.auditLogger,
+		toolCache:   a.toolCache,
+		stats:       a.stats,
+		cliApproval: a.terminalApproval(), // Forks run concurrently and take turns on the one terminal
+	}
+}
+
 // RunLoop starts an interactive session for a selected skill.
 func (a *Agent) RunLoop(ctx context.Context, initialPrompt string) error {
 	selectedSkill, err := a.selectAndPrepareSkill(ctx, initialPrompt)
@@ -89,7 +393,7 @@ func (a *Agent) RunLoop(ctx context.Context, initialPrompt string) error {
 		return err
 	}
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := a.stdinReader()
 	currentPrompt := initialPrompt
 
 	for {
@@ -139,6 +443,18 @@ func (a *Agent) selectAndPrepareSkill(ctx context.Context, userPrompt string) (*
 	}
 
 	// --- STEP 2: SKILL SELECTION ---
+	if a.cfg.AllSkillsMode && a.cfg.SkillName == "" {
+		limit := a.cfg.AllSkillsTokenLimit
+		if limit <= 0 {
+			limit = DefaultAllSkillsTokenLimit
+		}
+		combined, included := combineSkills(a.cfg.SkillsDir, availableSkills, limit)
+		if a.cfg.Verbose >= 1 {
+			log.Info("all-skills mode: skipping skill selection, using %d of %d skills: %v", len(included), len(availableSkills), included)
+		}
+		return combined, nil
+	}
+
 	var selectedSkillName string
 
 	// If skill is explicitly specified via --skill flag, use it directly
@@ -148,26 +464,32 @@ func (a *Agent) selectAndPrepareSkill(ctx context.Context, userPrompt string) (*
 			log.Info("using explicitly specified skill: %s", selectedSkillName)
 		}
 	} else {
-		// Otherwise, ask LLM to select the best skill
+		// Otherwise, let the configured strategy select the best skill
+		if a.selector == nil {
+			if a.selector, err = newSkillSelector(a); err != nil {
+				return nil, err
+			}
+		}
 		if a.cfg.Verbose >= 1 {
-			log.Info("asking llm to select the best skill")
+			log.Info("selecting the best skill (strategy: %s)", a.selectionStrategy())
 		}
-		selectedSkillName, err = a.selectSkill(ctx, userPrompt, availableSkills)
+		selectedSkillName, err = a.selector.Select(ctx, userPrompt, availableSkills)
 		if err != nil {
-			return nil, fmt.Errorf("failed during skill selection: %w", err)
+			return nil, &skerrors.SelectionError{Strategy: a.selectionStrategy(), Err: err}
 		}
 		if a.cfg.Verbose >= 1 {
-			log.Info("llm selected skill: %s", selectedSkillName)
+			log.Info("%s strategy selected skill: %s", a.selectionStrategy(), selectedSkillName)
 		}
 	}
 
 	selectedSkill, ok := availableSkills[selectedSkillName]
 	if !ok {
-		return nil, fmt.Errorf("skill '%s' not found. Available skills: %v", selectedSkillName, getAvailableSkillNames(availableSkills))
+		return nil, &skerrors.SkillNotFoundError{Name: selectedSkillName, Available: getAvailableSkillNames(availableSkills)}
 	}
 	if a.cfg.Verbose >= 1 {
 		log.Info("selected skill: %s", selectedSkillName)
 	}
+	a.recordSkillStats(skillstats.Counters{Skill: selectedSkillName, Selected: 1})
 	return &selectedSkill, nil
 }
 
@@ -180,17 +502,91 @@ func getAvailableSkillNames(skills map[string]SkillPackage) []string {
 	return names
 }
 
+// newAPIError wraps an error from the LLM client, recording the HTTP status code when
+// the API responded.
+func newAPIError(operation string, err error) *skerrors.APIError {
+	apiErr := &skerrors.APIError{Operation: operation, Err: err}
+	var openaiErr *openai.APIError
+	var requestErr *openai.RequestError
+	switch {
+	case errors.As(err, &openaiErr):
+		apiErr.StatusCode = openaiErr.HTTPStatusCode
+	case errors.As(err, &requestErr):
+		apiErr.StatusCode = requestErr.HTTPStatusCode
+	}
+	return apiErr
+}
+
+// combineSkills merges skill bodies, sorted by name, into a single pseudo skill rooted
+// at skillsRoot, skipping skills whose bodies would push the total past maxTokens (the
+// first skill is always kept). The scripts of every included skill remain callable as
+// tools. It returns the combined package and the names of the included skills.
+func combineSkills(skillsRoot string, skills map[string]SkillPackage, maxTokens int) (*SkillPackage, []string) {
+	names := getAvailableSkillNames(skills)
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("You have access to the following skills. Pick the technique most relevant to the user's request and follow its instructions; ignore the others.\n")
+
+	combined := &SkillPackage{Path: skillsRoot, Meta: SkillMeta{Name: allSkillsName}}
+	var included []string
+	used := 0
+	for _, name := range names {
+		skill := skills[name]
+		body, _, err := skill.ReadBody(0)
+		if err != nil {
+			log.Warn("skipping skill %s: %v", name, err)
+			continue
+		}
+		cost := estimateTokens(body)
+		if len(included) > 0 && used+cost > maxTokens {
+			continue
+		}
+		used += cost
+		included = append(included, name)
+
+		sb.WriteString(fmt.Sprintf("\n# Skill: %s\n", name))
+		if skill.Meta.Description != "" {
+			sb.WriteString(fmt.Sprintf("Description: %s\n", skill.Meta.Description))
+		}
+		sb.WriteString(fmt.Sprintf("Skill Root Path: %s\n\n", skill.Path))
+		sb.WriteString(body)
+		sb.WriteString("\n")
+
+		rel, err := filepath.Rel(skillsRoot, skill.Path)
+		if err != nil {
+			continue
+		}
+		for _, script := range skill.Resources.Scripts {
+			combined.Resources.Scripts = append(combined.Resources.Scripts, filepath.Join(rel, script))
+		}
+	}
+
+	combined.Body = sb.String()
+	combined.Meta.Description = "Combined context of skills: " + strings.Join(included, ", ")
+	return combined, included
+}
+
+// discoverSkills parses the skills under skillsRoot that match cfg.DiscoveryFilter. Only
+// their frontmatter is read; bodies are streamed from disk when a skill runs.
 func (a *Agent) discoverSkills(skillsRoot string) (map[string]SkillPackage, error) {
-	packages, err := ParseSkillPackages(skillsRoot)
+	packages, err := ParseSkillPackagesLazy(skillsRoot)
 	if err != nil {
 		return nil, err
 	}
 
 	skills := make(map[string]SkillPackage, len(packages))
 	for _, pkg := range packages {
-		if pkg != nil {
-			skills[pkg.Meta.Name] = *pkg
+		if pkg == nil {
+			continue
+		}
+		if !a.cfg.DiscoveryFilter.Matches(pkg.Meta) {
+			if a.cfg.Verbose >= 2 {
+				log.Debug("skipping skill %s: no tag matches %v", pkg.Meta.Name, a.cfg.DiscoveryFilter.Tags)
+			}
+			continue
 		}
+		skills[pkg.Meta.Name] = *pkg
 	}
 
 	return skills, nil
@@ -199,10 +595,7 @@ func (a *Agent) discoverSkills(skillsRoot string) (map[string]SkillPackage, erro
 func (a *Agent) selectSkill(ctx context.Context, userPrompt string, skills map[string]SkillPackage) (string, error) {
 	var sb strings.Builder
 	sb.WriteString("User Request: " + "" + userPrompt + "" + "\n\n")
-	sb.WriteString("Available Skills:\n")
-	for name, skill := range skills {
-		sb.WriteString(fmt.Sprintf("- %s: %s\n", name, skill.Meta.Description))
-	}
+	a.writeSkillList(&sb, userPrompt, skills)
 	sb.WriteString("\nSelection Guidelines:\n")
 	sb.WriteString("- For pure mathematical calculations (arithmetic, trigonometry, logarithms, etc.), ALWAYS prefer 'calculator-skill' over spreadsheet skills\n")
 	sb.WriteString("- Only choose spreadsheet skills (xlsx, csv) when the user needs to create/read/modify spreadsheet FILES\n")
@@ -210,7 +603,11 @@ func (a *Agent) selectSkill(ctx context.Context, userPrompt string, skills map[s
 	sb.WriteString("\nBased on the user request and guidelines above, which single skill is the most appropriate to use?")
 	sb.WriteString("\n\nIMPORTANT: You MUST select exactly one skill from the above list, even if the request seems simple. Respond with ONLY the skill name, nothing else. Do not explain your choice or answer the question directly.")
 
-	skillPrompt := SkillsToPrompt(skills)
+	// With a token budget the compact list above is the only description of the skills
+	skillPrompt := ""
+	if a.cfg.SelectionTokenBudget <= 0 {
+		skillPrompt = SkillsToPrompt(skills)
+	}
 
 	// Use a temporary message history for skill selection
 	selectionMessages := []openai.ChatCompletionMessage{
@@ -231,11 +628,14 @@ func (a *Agent) selectSkill(ctx context.Context, userPrompt string, skills map[s
 	}
 
 	a.debugPrintRequest(req)
+	a.traceRequest(trace.StageSelection, req)
 	resp, err := a.client.CreateChatCompletion(ctx, req)
 	if err != nil {
-		return "", err
+		return "", newAPIError("ChatCompletion", err)
 	}
 	a.debugPrintResponse(resp)
+	a.traceResponse(trace.StageSelection, resp)
+	a.trackCost(resp.Usage)
 
 	content := strings.TrimSpace(resp.Choices[0].Message.Content)
 	content = strings.Trim(content, "'\"")
@@ -253,6 +653,19 @@ func (a *Agent) selectSkill(ctx context.Context, userPrompt string, skills map[s
 	return skillName, nil
 }
 
+// writeSkillList writes the "Available Skills" section of a selection prompt, listed
+// compactly when cfg.SelectionTokenBudget is set.
+func (a *Agent) writeSkillList(sb *strings.Builder, userPrompt string, skills map[string]SkillPackage) {
+	sb.WriteString("Available Skills:\n")
+	if a.cfg.SelectionTokenBudget > 0 {
+		sb.WriteString(SkillsToCompactPromptForRequest(skills, a.cfg.SelectionTokenBudget, userPrompt) + "\n")
+	} else {
+		for name, skill := range skills {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", name, skill.Meta.Description))
+		}
+	}
+}
+
 // extractSkillName extracts the skill name from AI response content
 func extractSkillName(content string, skills map[string]SkillPackage) string {
 	// First, check if the content is already a valid skill name
@@ -326,63 +739,171 @@ func (a *Agent) debugPrintResponse(resp openai.ChatCompletionResponse) {
 	fmt.Fprintln(os.Stderr, strings.Repeat("=", 60))
 }
 
+// traceRequest records an LLM request in the execution trace, if tracing is enabled.
+func (a *Agent) traceRequest(stage string, req openai.ChatCompletionRequest) {
+	if a.cfg.Trace != nil {
+		a.cfg.Trace.RecordRequest(stage, req)
+	}
+}
+
+// traceResponse records an LLM response in the execution trace, if tracing is enabled.
+func (a *Agent) traceResponse(stage string, resp openai.ChatCompletionResponse) {
+	if a.cfg.Trace != nil {
+		a.cfg.Trace.RecordResponse(stage, resp)
+	}
+}
+
 // executeSkillWithTools sets up the initial system prompt and starts the tool-use conversation.
 func (a *Agent) executeSkillWithTools(ctx context.Context, userPrompt string, skill *SkillPackage) (string, error) {
-	// Prepare the system message once
+	body, err := a.skillBody(skill)
+	if err != nil {
+		return "", err
+	}
+	if tokens := approxTokens(body); a.cfg.Verbose >= 1 && tokens > LargeSkillBodyTokens {
+		log.Warn("skill %s body is about %d tokens, more than %d; consider moving details into references", skill.Meta.Name, tokens, LargeSkillBodyTokens)
+	}
+
+	// Prepare the system message once, with the facts remembered in earlier runs
+	systemMessage := a.skillSystemMessage(skill, body)
+	systemMessage.Content += a.memoryPrompt()
+	a.messages = append(a.messages, systemMessage)
+
+	return a.continueSkillWithTools(ctx, userPrompt, skill)
+}
+
+// skillBody streams the skill's body, truncated to cfg.MaxSkillBodyBytes.
+func (a *Agent) skillBody(skill *SkillPackage) (string, error) {
+	templated, err := a.templatedSkill(skill)
+	if err != nil {
+		return "", fmt.Errorf("failed to load skill %s: %w", skill.Meta.Name, err)
+	}
+	body, truncated, err := templated.ReadBody(a.cfg.MaxSkillBodyBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to load skill %s: %w", skill.Meta.Name, err)
+	}
+	if truncated {
+		log.Warn("skill %s body exceeds %d bytes and was truncated", skill.Meta.Name, a.cfg.MaxSkillBodyBytes)
+		body += fmt.Sprintf("\n\n[Skill instructions truncated after %d bytes.]", a.cfg.MaxSkillBodyBytes)
+	}
+	return body, nil
+}
+
+// skillSystemMessage returns the system message that instructs the LLM to follow skill,
+// whose instructions are body. cfg.SystemPrefix, if any, comes first.
+func (a *Agent) skillSystemMessage(skill *SkillPackage, body string) openai.ChatCompletionMessage {
 	var skillBody strings.Builder
-	skillBody.WriteString(skill.Body)
+	if prefix := strings.TrimSpace(a.cfg.SystemPrefix); prefix != "" {
+		skillBody.WriteString(prefix)
+		skillBody.WriteString("\n\n")
+	}
+	skillBody.WriteString(body)
 	skillBody.WriteString("\n\n##如果SKILL中没有要调用脚本的必要，则不要调用Tool,尤其是run_shell_script工具，直接根据SKILL的描述直接生成答案。\n\n ## SKILL CONTEXT\n")
 	skillBody.WriteString(fmt.Sprintf("Skill Root Path: %s\n", skill.Path))
-	a.messages = append(a.messages, openai.ChatCompletionMessage{
+	return openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleSystem,
 		Content: skillBody.String(),
-	})
-
-	return a.continueSkillWithTools(ctx, userPrompt, skill)
+	}
 }
 
 // continueSkillWithTools continues a conversation with a new user prompt.
 func (a *Agent) continueSkillWithTools(ctx context.Context, userPrompt string, skill *SkillPackage) (string, error) {
+	if d, slow := isSlowSkill(skill); a.cfg.Verbose >= 1 && slow {
+		log.Warn("skill %s is expected to take about %s", skill.Meta.Name, d)
+	}
+	history := a.messages
 	a.messages = append(a.messages, openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleUser,
 		Content: userPrompt,
 	})
 
-	availableTools, scriptMap := GenerateToolDefinitions(skill)
-
-	availableTools = append(availableTools, tool.GetBaseTools()...)
-
-	// Add MCP tools if client is available
-	if a.mcpClient != nil {
-		mcpTools, err := a.mcpClient.GetTools(ctx)
+	var cacheKey string
+	if a.cfg.OutputCacheDir != "" {
+		templated, err := a.templatedSkill(skill)
 		if err != nil {
-			log.Warn("failed to get mcp tools: %v", err)
-		} else {
-			availableTools = append(availableTools, mcpTools...)
+			return "", fmt.Errorf("failed to load skill %s: %w", skill.Meta.Name, err)
+		}
+		body, _, err := templated.ReadBody(0)
+		if err != nil {
+			return "", fmt.Errorf("failed to load skill %s: %w", skill.Meta.Name, err)
+		}
+		cacheKey = outputCacheKey(history, a.cfg.SystemPrefix, body, userPrompt, a.cfg.Model)
+		if output, ok := a.readOutputCache(cacheKey); ok {
+			if a.cfg.Verbose >= 1 {
+				log.Info("output cache hit for skill %s", skill.Meta.Name)
+			}
+			a.messages = append(a.messages, openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: output,
+			})
+			return output, nil
 		}
 	}
 
+	return a.runToolLoop(ctx, userPrompt, skill, cacheKey)
+}
+
+// runToolLoop sends a.messages to the LLM and executes the tools it calls until it
+// answers without calling any. A non-empty cacheKey stores the answer in the output cache.
+func (a *Agent) runToolLoop(ctx context.Context, userPrompt string, skill *SkillPackage, cacheKey string) (string, error) {
+	a.lastPrompt = userPrompt
+	availableTools, scriptMap := a.prepareSkillTools(ctx, skill)
+
 	var finalResponse strings.Builder
 
-	for range 20 { // Limit to 20 iterations to prevent infinite loops
+	start := time.Now()
+	recordExecution := func(success bool) {
+		delta := skillstats.Counters{Skill: skill.Meta.Name, Executions: 1, Duration: time.Since(start)}
+		if success {
+			delta.Successes = 1
+		}
+		a.recordSkillStats(delta)
+	}
+
+	// Consecutive tool errors of the current skill, and the skills that were abandoned
+	var errorHistory []string
+	failedSkills := make(map[string]bool)
+
+	maxIterations := a.cfg.MaxToolIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxToolIterations
+	}
+	for range maxIterations { // Limit iterations to prevent infinite loops
+		a.maybeSummarizeMemory(ctx)
+
+		// Repeated identical tool calls only waste tokens; the history keeps them all
 		req := openai.ChatCompletionRequest{
 			Model:    a.cfg.Model,
-			Messages: a.messages, // Use agent's messages
+			Messages: DeduplicateMessages(a.messages),
 			Tools:    availableTools,
 		}
 
 		a.debugPrintRequest(req)
+		a.traceRequest(trace.StageExecution, req)
+		start := time.Now()
 		resp, err := a.client.CreateChatCompletion(ctx, req)
 		if err != nil {
-			return "", fmt.Errorf("ChatCompletion error: %w", err)
+			recordExecution(false)
+			return "", newAPIError("ChatCompletion", err)
 		}
 		a.debugPrintResponse(resp)
+		a.traceResponse(trace.StageExecution, resp)
+		a.trackCost(resp.Usage)
+		a.recordSkillStats(skillstats.Counters{Skill: skill.Meta.Name, Tokens: resp.Usage.TotalTokens})
+		if a.auditLogger != nil {
+			a.auditCompletion(start, resp.Usage)
+		}
 
 		msg := resp.Choices[0].Message
 		a.messages = append(a.messages, msg) // Append LLM's response
 
 		if msg.ToolCalls == nil {
 			finalResponse.WriteString(msg.Content)
+			if cacheKey != "" {
+				if err := a.writeOutputCache(cacheKey, finalResponse.String()); err != nil {
+					log.Warn("failed to write output cache: %v", err)
+				}
+			}
+			recordExecution(true)
 			return finalResponse.String(), nil
 		}
 
@@ -391,49 +912,61 @@ func (a *Agent) continueSkillWithTools(ctx context.Context, userPrompt string, s
 				log.Info("calling tool: %s with args: %s", tc.Function.Name, tc.Function.Arguments)
 			}
 
-			if !a.cfg.AutoApproveTools {
-				fmt.Print("⚠️  Allow this tool execution? [y/N]: ")
-				var input string
-				if _, err := fmt.Scanln(&input); err != nil {
-					// Handle scan error, default to denying
-					log.Error("tool execution denied due to input error: %v", err)
-					a.messages = append(a.messages, openai.ChatCompletionMessage{
-						Role:       openai.ChatMessageRoleTool,
-						ToolCallID: tc.ID,
-						Content:    "Error: Input scanning failed.",
-					})
+			// construct_tool_call is replaced by the call it constructs, which then runs
+			// like any other; the result is reported under the original call ID
+			var constructErr error
+			if tc.Function.Name == tool.ConstructToolCallName {
+				var constructed openai.ToolCall
+				constructed, constructErr = a.constructToolCall(ctx, tc, availableTools)
+				if constructErr == nil {
+					tc = constructed
+					if a.cfg.Verbose >= 1 {
+						log.Info("constructed tool call: %s with args: %s", tc.Function.Name, tc.Function.Arguments)
+					}
+				}
+			}
+
+			emitToolEvent(ctx, ToolEvent{Type: ToolEventCall, ToolName: tc.Function.Name, ToolCallID: tc.ID, Content: tc.Function.Arguments})
+
+			if handler := a.approvalHandler(); handler != nil && constructErr == nil {
+				approved, err := handler.RequestApproval(tc.Function.Name, tc.Function.Arguments)
+				if err != nil {
+					// Treat approval errors as a denial
+					log.Error("tool execution denied due to approval error: %v", err)
+					a.appendToolResult(ctx, tc, "Error: Tool approval failed.")
 					continue
 				}
-				if strings.ToLower(strings.TrimSpace(input)) != "y" {
+				if !approved {
 					log.Info("tool execution denied by user: %s", tc.Function.Name)
-					a.messages = append(a.messages, openai.ChatCompletionMessage{
-						Role:       openai.ChatMessageRoleTool,
-						ToolCallID: tc.ID,
-						Content:    "Error: User denied tool execution.",
-					})
+					a.appendToolResult(ctx, tc, "Error: User denied tool execution.")
 					continue
 				}
 			}
 
 			var toolOutput string
 			var err error
+			toolStart := time.Now()
+			a.toolCalls++
 
-			// Check if it is an MCP tool
-			if a.mcpClient != nil && strings.Contains(tc.Function.Name, "__") {
-				var args map[string]any
-				if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
-					toolOutput = fmt.Sprintf("Error unmarshalling arguments: %v", err)
-				} else {
-					var result any
-					result, err = a.mcpClient.CallTool(ctx, tc.Function.Name, args)
-					if err == nil {
-						// Convert result to string/JSON
-						resBytes, _ := json.Marshal(result)
-						toolOutput = string(resBytes)
-					}
+			if constructErr != nil {
+				err = constructErr
+				if a.auditLogger != nil {
+					a.auditToolCall(tc, toolStart, "", err)
 				}
+			} else if a.mcpClient != nil && strings.Contains(tc.Function.Name, "__") {
+				toolOutput, err = a.callMCPTool(ctx, tc)
+			} else {
+				toolOutput, err = a.executeToolCall(ctx, tc, scriptMap, skill)
+			}
+			if a.cfg.Trace != nil {
+				a.cfg.Trace.RecordToolCall(tc.Function.Name, tc.Function.Arguments, toolOutput, time.Since(toolStart), err)
+			}
+
+			if err != nil {
+				errorHistory = append(errorHistory, summarizeToolError(tc.Function.Name, err))
+				a.recordSkillStats(skillstats.Counters{Skill: skill.Meta.Name, ToolErrors: 1})
 			} else {
-				toolOutput, err = a.executeToolCall(tc, scriptMap, skill.Path)
+				errorHistory = nil
 			}
 
 			if err != nil {
@@ -441,26 +974,156 @@ func (a *Agent) continueSkillWithTools(ctx context.Context, userPrompt string, s
 				// Provide detailed error information to help LLM understand what went wrong
 				errorMsg := fmt.Sprintf("Tool execution failed: %s\nError details: %v\nTool name: %s\nArguments: %s\n\nYou can try:\n1. Retry with different parameters\n2. Use a different tool to fix it\n3. Modify your approach",
 					tc.Function.Name, err, tc.Function.Name, tc.Function.Arguments)
-				a.messages = append(a.messages, openai.ChatCompletionMessage{
-					Role:       openai.ChatMessageRoleTool,
-					ToolCallID: tc.ID,
-					Content:    errorMsg,
-				})
+				a.appendToolResult(ctx, tc, errorMsg)
 			} else {
-				a.messages = append(a.messages, openai.ChatCompletionMessage{
-					Role:       openai.ChatMessageRoleTool,
-					ToolCallID: tc.ID,
-					Content:    toolOutput,
-				})
+				a.appendToolResult(ctx, tc, toolOutput)
+			}
+		}
+
+		if a.shouldFallBack(len(errorHistory)) {
+			failedSkills[skill.Meta.Name] = true
+			next, err := a.reselectSkill(ctx, userPrompt, failedSkills, errorHistory)
+			var nextBody string
+			if err == nil && next != nil {
+				nextBody, err = a.skillBody(next)
+			}
+			if err != nil {
+				log.Warn("skill fallback failed: %v", err)
+			} else if next != nil {
+				a.logSkillFallback(skill.Meta.Name, next.Meta.Name)
+				a.messages = append(a.messages, a.skillSystemMessage(next, nextBody),
+					skillFallbackMessage(skill.Meta.Name, next.Meta.Name, userPrompt, errorHistory))
+				skill = next
+				availableTools, scriptMap = a.prepareSkillTools(ctx, skill)
+				// The answer no longer comes from the skill the cache key was computed for
+				cacheKey = ""
 			}
+			errorHistory = nil
 		}
+		a.saveCheckpoint()
 	}
-	return "", errors.New("exceeded maximum tool call iterations")
+	recordExecution(false)
+	return "", &skerrors.MaxIterationsError{Limit: maxIterations}
 }
 
-func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]string, skillPath string) (string, error) {
-	var toolOutput string
-	var err error
+// appendToolResult adds the result of tc to the conversation and reports it as a
+// ToolEventResult.
+func (a *Agent) appendToolResult(ctx context.Context, tc openai.ToolCall, content string) {
+	a.messages = append(a.messages, openai.ChatCompletionMessage{
+		Role:       openai.ChatMessageRoleTool,
+		ToolCallID: tc.ID,
+		Content:    content,
+	})
+	emitToolEvent(ctx, ToolEvent{Type: ToolEventResult, ToolName: tc.Function.Name, ToolCallID: tc.ID, Content: content})
+}
+
+// prepareSkillTools makes skill the active skill and returns the tools offered to the
+// LLM while it runs, along with the paths of the skill's script tools.
+func (a *Agent) prepareSkillTools(ctx context.Context, skill *SkillPackage) ([]openai.Tool, map[string]string) {
+	a.activeSkill = skill.Meta.Name
+	a.inlineTools = make(map[string]InlineTool, len(skill.Meta.Tools))
+	for _, inline := range skill.Meta.Tools {
+		a.inlineTools[inline.Name] = inline
+	}
+
+	availableTools, scriptMap := GenerateToolDefinitions(skill)
+
+	availableTools = append(availableTools, tool.GetBaseTools()...)
+	if a.cfg.EnableBrowserTools {
+		availableTools = append(availableTools, tool.GetBrowserTools()...)
+	}
+	if a.cfg.DockerEnabled {
+		availableTools = append(availableTools, tool.GetDockerTools()...)
+	}
+	availableTools = applyToolOverrides(availableTools, skill.Meta.ToolOverrides)
+
+	// Add MCP tools if client is available
+	if a.mcpClient != nil {
+		mcpTools, err := a.mcpClient.GetTools(ctx)
+		if err != nil {
+			log.Warn("failed to get mcp tools: %v", err)
+		} else {
+			availableTools = append(availableTools, mcpTools...)
+		}
+	}
+	a.tools = availableTools
+	return availableTools, scriptMap
+}
+
+// resolveSkillFile resolves a relative path against the skill directory when the
+// file exists there, so tools can refer to files shipped with the skill.
+func resolveSkillFile(skillPath, path string) string {
+	if !filepath.IsAbs(path) && skillPath != "" {
+		resolvedPath := filepath.Join(skillPath, path)
+		if _, err := os.Stat(resolvedPath); err == nil {
+			return resolvedPath
+		}
+	}
+	return path
+}
+
+// formatSearchMatches renders code_search results one match per line, as grep -n does.
+func formatSearchMatches(matches []tool.SearchMatch) string {
+	if len(matches) == 0 {
+		return "No matches found."
+	}
+	var sb strings.Builder
+	for _, m := range matches {
+		fmt.Fprintf(&sb, "%s:%d: %s\n", m.File, m.LineNumber, m.Line)
+	}
+	if len(matches) == tool.MaxCodeSearchMatches {
+		fmt.Fprintf(&sb, "(results limited to %d matches)\n", tool.MaxCodeSearchMatches)
+	}
+	return sb.String()
+}
+
+// executeToolCall runs a built-in, script or inline tool for the active skill,
+// which may be nil outside a skill. Errors are *skerrors.ToolError.
+func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ToolCall, scriptMap map[string]string, skill *SkillPackage) (toolOutput string, err error) {
+	var skillPath string
+	if skill != nil {
+		skillPath = skill.Path
+	}
+
+	if a.auditLogger != nil {
+		start := time.Now()
+		defer func() {
+			a.auditToolCall(toolCall, start, toolOutput, err)
+		}()
+	}
+
+	if ttl := a.cfg.ToolCacheTTLs[toolCall.Function.Name]; ttl > 0 && a.toolCache != nil {
+		key := toolCacheKey(toolCall.Function.Name, toolCall.Function.Arguments)
+		if cached, ok := a.toolCache.Get(key); ok {
+			if a.cfg.Verbose >= 1 {
+				log.Info("using cached result for tool: %s", toolCall.Function.Name)
+			}
+			return cached, nil
+		}
+		defer func() {
+			if err == nil {
+				a.toolCache.Set(key, toolOutput, ttl)
+			}
+		}()
+	}
+
+	// Every failure, including malformed arguments and unknown tools, is a ToolError
+	defer func() {
+		if err != nil {
+			err = &skerrors.ToolError{ToolName: toolCall.Function.Name, Err: err}
+		}
+	}()
+
+	if timeout := a.toolTimeout(toolCall.Function.Name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		defer func() {
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("timed out after %s: %w", timeout, err)
+			}
+		}()
+	}
 
 	switch toolCall.Function.Name {
 	case "run_shell_code":
@@ -471,8 +1134,8 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal run_shell_code arguments: %w", err)
 		}
-		shellTool := tool.ShellTool{}
-		toolOutput, err = shellTool.Run(params.Args, params.Code)
+		shellTool := tool.ShellTool{SkillArgs: a.cfg.SkillArgs}
+		toolOutput, err = shellTool.Run(ctx, params.Args, params.Code)
 	case "run_shell_script":
 		var params struct {
 			ScriptPath string   `json:"scriptPath"`
@@ -481,7 +1144,7 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal run_shell_script arguments: %w", err)
 		}
-		toolOutput, err = tool.RunShellScript(params.ScriptPath, params.Args)
+		toolOutput, err = tool.RunShellScriptContext(ctx, params.ScriptPath, params.Args, tool.SkillArgsEnv(a.cfg.SkillArgs))
 	case "run_python_code":
 		var params struct {
 			Code string         `json:"code"`
@@ -490,8 +1153,8 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal run_python_code arguments: %w", err)
 		}
-		pythonTool := tool.PythonTool{}
-		toolOutput, err = pythonTool.Run(params.Args, params.Code)
+		pythonTool := tool.PythonTool{Preferred: a.cfg.PreferredPython, SkillArgs: a.cfg.SkillArgs}
+		toolOutput, err = pythonTool.Run(ctx, params.Args, params.Code)
 	case "run_python_script":
 		var params struct {
 			ScriptPath string   `json:"scriptPath"`
@@ -500,7 +1163,26 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal run_python_script arguments: %w", err)
 		}
-		toolOutput, err = tool.RunPythonScript(params.ScriptPath, params.Args)
+		toolOutput, err = tool.RunPythonScriptContext(ctx, params.ScriptPath, params.Args, a.cfg.PreferredPython, tool.SkillArgsEnv(a.cfg.SkillArgs))
+	case "run_node_code":
+		var params struct {
+			Code string         `json:"code"`
+			Args map[string]any `json:"args"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal run_node_code arguments: %w", err)
+		}
+		nodeTool := tool.NodeTool{SkillArgs: a.cfg.SkillArgs}
+		toolOutput, err = nodeTool.Run(ctx, params.Args, params.Code)
+	case "run_node_script":
+		var params struct {
+			ScriptPath string   `json:"scriptPath"`
+			Args       []string `json:"args"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal run_node_script arguments: %w", err)
+		}
+		toolOutput, err = tool.RunNodeScriptContext(ctx, params.ScriptPath, params.Args, tool.SkillArgsEnv(a.cfg.SkillArgs))
 	case "read_file":
 		var params struct {
 			FilePath string `json:"filePath"`
@@ -508,14 +1190,65 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal read_file arguments: %w", err)
 		}
-		path := params.FilePath
-		if !filepath.IsAbs(path) && skillPath != "" {
-			resolvedPath := filepath.Join(skillPath, path)
-			if _, err := os.Stat(resolvedPath); err == nil {
-				path = resolvedPath
-			}
-		}
+		path := resolveSkillFile(skillPath, params.FilePath)
 		toolOutput, err = tool.ReadFile(path)
+	case "batch_read_files":
+		var params struct {
+			FilePaths []string `json:"file_paths"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal batch_read_files arguments: %w", err)
+		}
+		var contents map[string]any
+		contents, err = tool.BatchReadFilesIn(skillPath, params.FilePaths)
+		if err == nil {
+			var data []byte
+			data, err = json.Marshal(contents)
+			toolOutput = string(data)
+		}
+	case "compare_files":
+		var params struct {
+			FileA        string `json:"file_a"`
+			FileB        string `json:"file_b"`
+			ContextLines *int   `json:"context_lines"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal compare_files arguments: %w", err)
+		}
+		contextLines := tool.DefaultDiffContextLines
+		if params.ContextLines != nil {
+			contextLines = *params.ContextLines
+		}
+		toolOutput, err = tool.CompareFilesWithContext(resolveSkillFile(skillPath, params.FileA), resolveSkillFile(skillPath, params.FileB), contextLines)
+	case "code_search":
+		var params struct {
+			Directory     string `json:"directory"`
+			Pattern       string `json:"pattern"`
+			FilePattern   string `json:"file_pattern"`
+			CaseSensitive bool   `json:"case_sensitive"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal code_search arguments: %w", err)
+		}
+		var matches []tool.SearchMatch
+		matches, err = tool.CodeSearch(resolveSkillFile(skillPath, params.Directory), params.Pattern, params.FilePattern, params.CaseSensitive)
+		if err == nil {
+			toolOutput = formatSearchMatches(matches)
+		}
+	case "parse_structured_data":
+		var params struct {
+			FilePath string `json:"file_path"`
+			Format   string `json:"format"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal parse_structured_data arguments: %w", err)
+		}
+		path := resolveSkillFile(skillPath, params.FilePath)
+		var table tool.TableData
+		table, err = tool.ParseStructuredDataWithFormat(path, params.Format)
+		if err == nil {
+			toolOutput = table.String()
+		}
 	case "write_file":
 		var params struct {
 			FilePath string `json:"filePath"`
@@ -528,6 +1261,50 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err == nil {
 			toolOutput = fmt.Sprintf("Successfully wrote to file: %s", params.FilePath)
 		}
+	case "search_replace_file":
+		var params struct {
+			FilePath    string `json:"file_path"`
+			Search      string `json:"search"`
+			Replacement string `json:"replacement"`
+			Count       *int   `json:"count"`
+			UseRegex    bool   `json:"use_regex"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal search_replace_file arguments: %w", err)
+		}
+		count := -1
+		if params.Count != nil {
+			count = *params.Count
+		}
+		var replaced int
+		if params.UseRegex {
+			replaced, err = tool.SearchReplaceFileRegexp(params.FilePath, params.Search, params.Replacement, count)
+		} else {
+			replaced, err = tool.SearchReplaceFile(params.FilePath, params.Search, params.Replacement, count)
+		}
+		if err == nil {
+			toolOutput = fmt.Sprintf("Made %d replacement(s) in %s", replaced, params.FilePath)
+		}
+	case "copy_file", "move_file":
+		var params struct {
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+			Overwrite   bool   `json:"overwrite"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal %s arguments: %w", toolCall.Function.Name, err)
+		}
+		if toolCall.Function.Name == "copy_file" {
+			err = tool.CopyFileWithOverwrite(params.Source, params.Destination, params.Overwrite)
+			if err == nil {
+				toolOutput = fmt.Sprintf("Successfully copied %s to %s", params.Source, params.Destination)
+			}
+		} else {
+			err = tool.MoveFileWithOverwrite(params.Source, params.Destination, params.Overwrite)
+			if err == nil {
+				toolOutput = fmt.Sprintf("Successfully moved %s to %s", params.Source, params.Destination)
+			}
+		}
 	case "wikipedia_search":
 		var params struct {
 			Query string `json:"query"`
@@ -535,7 +1312,7 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal wikipedia_search arguments: %w", err)
 		}
-		toolOutput, err = tool.WikipediaSearch(params.Query)
+		toolOutput, err = tool.WikipediaSearchContext(ctx, params.Query)
 	case "tavily_search":
 		var params struct {
 			Query string `json:"query"`
@@ -543,7 +1320,7 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal tavily_search arguments: %w", err)
 		}
-		toolOutput, err = tool.TavilySearch(params.Query)
+		toolOutput, err = tool.TavilySearchContext(ctx, params.Query)
 	case "web_fetch":
 		var params struct {
 			URL string `json:"url"`
@@ -551,9 +1328,156 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
 			return "", fmt.Errorf("failed to unmarshal web_fetch arguments: %w", err)
 		}
-		toolOutput, err = tool.WebFetch(params.URL)
+		toolOutput, err = tool.WebFetch(ctx, params.URL)
+	case "get_env":
+		var params struct {
+			Keys []string `json:"keys"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal get_env arguments: %w", err)
+		}
+		allowed := a.cfg.AllowedEnvVars
+		if len(allowed) == 0 {
+			allowed = tool.DefaultAllowedEnvVars
+		}
+		toolOutput, err = tool.GetEnv(params.Keys, allowed)
+	case tool.ListToolsName:
+		toolOutput, err = tool.ListTools(a.tools)
+	case tool.SkillInfoName:
+		if skill == nil {
+			return "", errors.New("no active skill")
+		}
+		toolOutput, err = skillInfo(skill)
+	case "git_log":
+		var params struct {
+			RepoPath   string `json:"repo_path"`
+			Branch     string `json:"branch"`
+			MaxCommits int    `json:"max_commits"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal git_log arguments: %w", err)
+		}
+		if err = tool.CheckRepoPath(params.RepoPath, a.cfg.AllowedRepoPaths); err != nil {
+			return "", err
+		}
+		toolOutput, err = tool.GitLogContext(ctx, params.RepoPath, params.Branch, params.MaxCommits)
+	case "git_diff":
+		var params struct {
+			RepoPath string `json:"repo_path"`
+			FromRef  string `json:"from_ref"`
+			ToRef    string `json:"to_ref"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal git_diff arguments: %w", err)
+		}
+		if err = tool.CheckRepoPath(params.RepoPath, a.cfg.AllowedRepoPaths); err != nil {
+			return "", err
+		}
+		toolOutput, err = tool.GitDiffContext(ctx, params.RepoPath, params.FromRef, params.ToRef)
+	case "memory_set", "memory_get":
+		var params struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal %s arguments: %w", toolCall.Function.Name, err)
+		}
+		if a.cfg.MemoryStorePath == "" {
+			return "", errors.New("memory store is not configured")
+		}
+		var store *tool.MemoryStore
+		if store, err = tool.NewMemoryStore(a.cfg.MemoryStorePath); err != nil {
+			return "", err
+		}
+		if toolCall.Function.Name == "memory_set" {
+			if err = store.Set(params.Key, params.Value); err == nil {
+				toolOutput = fmt.Sprintf("Remembered %s", params.Key)
+			}
+		} else if value, ok := store.Get(params.Key); ok {
+			toolOutput = value
+		} else {
+			toolOutput = fmt.Sprintf("Nothing is remembered for %s", params.Key)
+		}
+	case "execute_sql":
+		var params struct {
+			Driver string `json:"driver"`
+			DSN    string `json:"dsn"`
+			Query  string `json:"query"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal execute_sql arguments: %w", err)
+		}
+		toolOutput, err = tool.ExecuteSQLContext(ctx, params.Driver, params.DSN, params.Query)
+	case "web_search":
+		var params struct {
+			Query string `json:"query"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal web_search arguments: %w", err)
+		}
+		toolOutput, err = tool.AggregateSearchContext(ctx, params.Query, a.cfg.SearchSources)
+	case "read_url_raw":
+		var params struct {
+			URL      string `json:"url"`
+			MaxBytes int    `json:"max_bytes"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal read_url_raw arguments: %w", err)
+		}
+		toolOutput, err = tool.ReadURLContext(ctx, params.URL, params.MaxBytes)
+	case "web_screenshot":
+		if !a.cfg.EnableBrowserTools {
+			return "", errors.New("browser tools are not enabled")
+		}
+		var params struct {
+			URL    string `json:"url"`
+			Width  int    `json:"width"`
+			Height int    `json:"height"`
+			WaitMs *int   `json:"wait_ms"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal web_screenshot arguments: %w", err)
+		}
+		wait := 500 * time.Millisecond
+		if params.WaitMs != nil {
+			wait = time.Duration(*params.WaitMs) * time.Millisecond
+		}
+		var png []byte
+		png, err = tool.WebScreenshotWithWait(ctx, params.URL, params.Width, params.Height, wait)
+		if err == nil {
+			toolOutput = base64.StdEncoding.EncodeToString(png)
+		}
+	case "run_docker_command":
+		if !a.cfg.DockerEnabled {
+			return "", errors.New("Docker not enabled")
+		}
+		var params struct {
+			Image   string            `json:"image"`
+			Command string            `json:"command"`
+			Volumes []tool.Mount      `json:"volumes"`
+			Env     map[string]string `json:"env"`
+		}
+		if err = json.Unmarshal([]byte(toolCall.Function.Arguments), &params); err != nil {
+			return "", fmt.Errorf("failed to unmarshal run_docker_command arguments: %w", err)
+		}
+		if err = tool.CheckDockerImage(params.Image, a.cfg.AllowedDockerImages); err != nil {
+			return "", err
+		}
+		allowedVolumes := a.cfg.AllowedDockerVolumes
+		if skillPath != "" {
+			allowedVolumes = append([]string{skillPath}, allowedVolumes...)
+		}
+		for i, m := range params.Volumes {
+			params.Volumes[i].Source = resolveSkillFile(skillPath, m.Source)
+		}
+		if err = tool.CheckDockerVolumes(params.Volumes, allowedVolumes); err != nil {
+			return "", err
+		}
+		toolOutput, err = tool.RunDockerCommandContext(ctx, params.Image, params.Command, params.Volumes, params.Env)
 	default:
-		if scriptPath, ok := scriptMap[toolCall.Function.Name]; ok {
+		if inline, ok := a.inlineTools[toolCall.Function.Name]; ok {
+			toolOutput, err = runInlineTool(ctx, inline, toolCall.Function.Arguments, a.cfg.SkillArgs)
+		} else if scriptPath, ok := scriptMap[toolCall.Function.Name]; ok {
 			var params struct {
 				Args []string `json:"args"`
 			}
@@ -562,13 +1486,16 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 					return "", fmt.Errorf("failed to unmarshal script arguments: %w", err)
 				}
 			}
-			if strings.HasSuffix(scriptPath, ".py") {
-				toolOutput, err = tool.RunPythonScript(scriptPath, params.Args)
-			} else {
-				toolOutput, err = tool.RunShellScript(scriptPath, params.Args)
+			switch filepath.Ext(scriptPath) {
+			case ".py":
+				toolOutput, err = tool.RunPythonScriptContext(ctx, scriptPath, params.Args, a.cfg.PreferredPython, tool.SkillArgsEnv(a.cfg.SkillArgs))
+			case ".js", ".mjs", ".cjs", ".ts":
+				toolOutput, err = tool.RunNodeScriptContext(ctx, scriptPath, params.Args, tool.SkillArgsEnv(a.cfg.SkillArgs))
+			default:
+				toolOutput, err = tool.RunShellScriptContext(ctx, scriptPath, params.Args, tool.SkillArgsEnv(a.cfg.SkillArgs))
 			}
 		} else {
-			return "", fmt.Errorf("unknown tool: %s", toolCall.Function.Name)
+			return "", errors.New("unknown tool")
 		}
 	}
 
@@ -577,7 +1504,87 @@ func (a *Agent) executeToolCall(toolCall openai.ToolCall, scriptMap map[string]s
 		if toolCall.Function.Arguments != "" {
 			log.Debug("raw arguments: %s", toolCall.Function.Arguments)
 		}
-		return "", fmt.Errorf("tool execution failed for %s: %w", toolCall.Function.Name, err)
+		return "", err
 	}
 	return toolOutput, nil
 }
+
+// callMCPTool calls an MCP tool on its server and returns the result as JSON.
+func (a *Agent) callMCPTool(ctx context.Context, toolCall openai.ToolCall) (toolOutput string, err error) {
+	if a.auditLogger != nil {
+		start := time.Now()
+		defer func() {
+			a.auditToolCall(toolCall, start, toolOutput, err)
+		}()
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+		return fmt.Sprintf("Error unmarshalling arguments: %v", err), nil
+	}
+	timeout := a.toolTimeout(toolCall.Function.Name)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	result, err := a.mcpClient.CallTool(ctx, toolCall.Function.Name, args)
+	if err != nil {
+		if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		return "", &skerrors.ToolError{ToolName: toolCall.Function.Name, Err: err}
+	}
+	resBytes, _ := json.Marshal(result)
+	return string(resBytes), nil
+}
+
+// toolTimeout returns how long the named tool may run, or 0 for no limit.
+func (a *Agent) toolTimeout(name string) time.Duration {
+	if timeout, ok := a.cfg.ToolTimeouts[name]; ok {
+		return timeout
+	}
+	return DefaultToolTimeouts[name]
+}
+
+// auditToolCall appends a record of the tool call to the audit log.
+// Failures are logged but never interrupt the agent.
+func (a *Agent) auditToolCall(toolCall openai.ToolCall, start time.Time, output string, callErr error) {
+	rec := audit.Record{
+		Kind:         audit.KindToolCall,
+		Timestamp:    start.UTC(),
+		SessionID:    a.sessionID,
+		Skill:        a.activeSkill,
+		Tool:         toolCall.Function.Name,
+		OutputLength: len(output),
+		DurationMs:   time.Since(start).Milliseconds(),
+	}
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &rec.Arguments); err != nil {
+			rec.Arguments = map[string]any{"raw": toolCall.Function.Arguments}
+		}
+	}
+	if callErr != nil {
+		rec.Error = callErr.Error()
+	}
+
+	if err := a.auditLogger.Log(rec); err != nil {
+		log.Warn("failed to write audit log: %v", err)
+	}
+}
+
+// auditCompletion appends a record of an LLM completion and its token usage to the audit log.
+func (a *Agent) auditCompletion(start time.Time, usage openai.Usage) {
+	rec := audit.Record{
+		Kind:             audit.KindCompletion,
+		Timestamp:        start.UTC(),
+		SessionID:        a.sessionID,
+		Skill:            a.activeSkill,
+		DurationMs:       time.Since(start).Milliseconds(),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	}
+	if err := a.auditLogger.Log(rec); err != nil {
+		log.Warn("failed to write audit log: %v", err)
+	}
+}
@@ -3,12 +3,25 @@ package goskills
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/audit"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/mcp"
+	"github.com/smallnest/goskills/ratelimit"
+	"github.com/smallnest/goskills/tool"
+	"github.com/smallnest/goskills/trace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,11 +29,13 @@ import (
 // MockOpenAIClient is a mock implementation of OpenAIChatClient for testing
 type MockOpenAIClient struct {
 	responses []openai.ChatCompletionResponse
+	requests  []openai.ChatCompletionRequest // Requests received, in order
 	callCount int
 	err       error
 }
 
 func (m *MockOpenAIClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	m.requests = append(m.requests, req)
 	if m.err != nil {
 		return openai.ChatCompletionResponse{}, m.err
 	}
@@ -506,11 +521,76 @@ func TestExecuteToolCall_RunShellCode(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "hello")
 }
 
+func TestExecuteToolCall_Timeout(t *testing.T) {
+	agent := &Agent{cfg: RunnerConfig{
+		ToolTimeouts: map[string]time.Duration{"run_shell_code": 100 * time.Millisecond},
+	}}
+	toolCall := openai.ToolCall{Function: openai.FunctionCall{Name: "run_shell_code", Arguments: `{"code": "sleep 5"}`}}
+
+	start := time.Now()
+	_, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.Less(t, time.Since(start), 3*time.Second)
+	var toolErr *skerrors.ToolError
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, "run_shell_code", toolErr.ToolName)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "timed out after 100ms")
+
+	// A timeout of 0 disables the limit
+	agent.cfg.ToolTimeouts["run_shell_code"] = 0
+	toolCall.Function.Arguments = `{"code": "sleep 0.2; echo done"}`
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "done")
+}
+
+func TestExecuteToolCall_NodeTimeout(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+	scriptPath := filepath.Join(t.TempDir(), "wait.js")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("setTimeout(() => console.log('done'), 5000);\n"), 0644))
+	agent := &Agent{cfg: RunnerConfig{ToolTimeouts: map[string]time.Duration{
+		"run_node_code":   100 * time.Millisecond,
+		"run_node_script": 100 * time.Millisecond,
+		"run_wait":        100 * time.Millisecond,
+	}}}
+
+	for _, toolCall := range []openai.ToolCall{
+		{Function: openai.FunctionCall{Name: "run_node_code", Arguments: `{"code": "setTimeout(() => {}, 5000)"}`}},
+		{Function: openai.FunctionCall{Name: "run_node_script", Arguments: fmt.Sprintf(`{"scriptPath": %q}`, scriptPath)}},
+		{Function: openai.FunctionCall{Name: "run_wait", Arguments: `{}`}},
+	} {
+		start := time.Now()
+		_, err := agent.executeToolCall(context.Background(), toolCall, map[string]string{"run_wait": scriptPath}, nil)
+		assert.Less(t, time.Since(start), 3*time.Second, toolCall.Function.Name)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, toolCall.Function.Name)
+		assert.ErrorContains(t, err, "timed out after 100ms", toolCall.Function.Name)
+	}
+}
+
+func TestNewAgent_UninterruptibleToolTimeout(t *testing.T) {
+	_, err := NewAgent(RunnerConfig{APIKey: "key", ToolTimeouts: map[string]time.Duration{"read_file": time.Second}}, nil)
+	assert.ErrorContains(t, err, "tool read_file cannot be stopped once started")
+
+	// Disabling the limit is always allowed
+	_, err = NewAgent(RunnerConfig{APIKey: "key", ToolTimeouts: map[string]time.Duration{"read_file": 0, "run_node_code": time.Second}}, nil)
+	assert.NoError(t, err)
+}
+
+func TestAgent_ToolTimeout(t *testing.T) {
+	agent := &Agent{cfg: RunnerConfig{ToolTimeouts: map[string]time.Duration{"web_fetch": time.Minute, "read_file": time.Second}}}
+	assert.Equal(t, time.Minute, agent.toolTimeout("web_fetch"))
+	assert.Equal(t, time.Second, agent.toolTimeout("read_file"))
+	assert.Equal(t, DefaultToolTimeouts["run_python_code"], agent.toolTimeout("run_python_code"))
+	assert.Zero(t, agent.toolTimeout("write_file"))
+}
+
 // TestExecuteToolCall_ReadFile tests executeToolCall for reading files
 func TestExecuteToolCall_ReadFile(t *testing.T) {
 	// Create a temporary file
@@ -534,11 +614,56 @@ func TestExecuteToolCall_ReadFile(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, testContent)
 }
 
+// TestExecuteToolCall_ParseStructuredData tests that relative paths resolve against the skill directory
+func TestExecuteToolCall_ParseStructuredData(t *testing.T) {
+	skillDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "data.txt"), []byte("name: widget\nprice: 2\n"), 0644))
+
+	toolCall := openai.ToolCall{
+		ID:   "test-id",
+		Type: openai.ToolTypeFunction,
+		Function: openai.FunctionCall{
+			Name:      "parse_structured_data",
+			Arguments: `{"file_path": "data.txt", "format": "yaml"}`,
+		},
+	}
+
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true}}
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, &SkillPackage{Path: skillDir})
+	assert.NoError(t, err)
+	assert.Equal(t, "| name | price |\n| --- | --- |\n| widget | 2 |\n", output)
+}
+
+// TestExecuteToolCall_GetEnv tests that get_env only reveals allowlisted variables
+func TestExecuteToolCall_GetEnv(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	t.Setenv("GOSKILLS_TEST_TOKEN", "secret")
+
+	toolCall := openai.ToolCall{
+		ID:   "test-id",
+		Type: openai.ToolTypeFunction,
+		Function: openai.FunctionCall{
+			Name:      "get_env",
+			Arguments: `{"keys": ["HOME", "GOSKILLS_TEST_TOKEN"]}`,
+		},
+	}
+
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true}}
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"HOME": "/home/test", "GOSKILLS_TEST_TOKEN": "<redacted>"}`, output)
+
+	agent.cfg.AllowedEnvVars = []string{"GOSKILLS_TEST_TOKEN"}
+	output, err = agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"HOME": "<redacted>", "GOSKILLS_TEST_TOKEN": "secret"}`, output)
+}
+
 // TestExecuteToolCall_WriteFile tests executeToolCall for writing files
 func TestExecuteToolCall_WriteFile(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -561,7 +686,7 @@ func TestExecuteToolCall_WriteFile(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "Successfully wrote to file")
 
@@ -571,6 +696,29 @@ func TestExecuteToolCall_WriteFile(t *testing.T) {
 	assert.Equal(t, testContent, string(content))
 }
 
+func TestExecuteToolCall_SearchReplaceFile(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "config.txt")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("port=80 port=81"), 0644))
+	agent := &Agent{}
+	call := func(arguments string) (string, error) {
+		return agent.executeToolCall(context.Background(), openai.ToolCall{
+			Function: openai.FunctionCall{Name: "search_replace_file", Arguments: arguments},
+		}, nil, nil)
+	}
+
+	output, err := call(fmt.Sprintf(`{"file_path": %q, "search": "port=", "replacement": "p="}`, tmpFile))
+	require.NoError(t, err)
+	assert.Equal(t, "Made 2 replacement(s) in "+tmpFile, output)
+
+	output, err = call(fmt.Sprintf(`{"file_path": %q, "search": "p=(\\d+)", "replacement": "port=${1}0", "count": 1, "use_regex": true}`, tmpFile))
+	require.NoError(t, err)
+	assert.Equal(t, "Made 1 replacement(s) in "+tmpFile, output)
+
+	content, err := os.ReadFile(tmpFile)
+	require.NoError(t, err)
+	assert.Equal(t, "port=800 p=81", string(content))
+}
+
 // TestExecuteToolCall_UnknownTool tests error handling for unknown tools
 func TestExecuteToolCall_UnknownTool(t *testing.T) {
 	agent := &Agent{
@@ -588,10 +736,13 @@ func TestExecuteToolCall_UnknownTool(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.Error(t, err)
 	assert.Empty(t, output)
-	assert.Contains(t, err.Error(), "unknown tool")
+	var toolErr *skerrors.ToolError
+	require.True(t, errors.As(err, &toolErr))
+	assert.Equal(t, "unknown_tool", toolErr.ToolName)
+	assert.EqualError(t, toolErr.Err, "unknown tool")
 }
 
 // TestExecuteToolCall_InvalidJSON tests error handling for invalid JSON arguments
@@ -611,10 +762,13 @@ func TestExecuteToolCall_InvalidJSON(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.Error(t, err)
 	assert.Empty(t, output)
-	assert.Contains(t, err.Error(), "failed to unmarshal")
+	var toolErr *skerrors.ToolError
+	require.True(t, errors.As(err, &toolErr))
+	assert.Equal(t, "read_file", toolErr.ToolName)
+	assert.Contains(t, toolErr.Err.Error(), "failed to unmarshal")
 }
 
 // TestExecuteSkillWithTools tests executeSkillWithTools method
@@ -721,12 +875,11 @@ func TestContinueSkillWithTools_WithToolCalls(t *testing.T) {
 	assert.Equal(t, "File content processed", result)
 }
 
-// TestContinueSkillWithTools_MaxIterations tests that the function stops after max iterations
-func TestContinueSkillWithTools_MaxIterations(t *testing.T) {
-	// Create responses that always return tool calls (infinite loop scenario)
-	mockResponses := make([]openai.ChatCompletionResponse, 25)
-	for i := range mockResponses {
-		mockResponses[i] = openai.ChatCompletionResponse{
+// toolCallResponses returns n responses that each request another tool call.
+func toolCallResponses(n int) []openai.ChatCompletionResponse {
+	responses := make([]openai.ChatCompletionResponse, n)
+	for i := range responses {
+		responses[i] = openai.ChatCompletionResponse{
 			Choices: []openai.ChatCompletionChoice{
 				{
 					Message: openai.ChatCompletionMessage{
@@ -747,14 +900,20 @@ func TestContinueSkillWithTools_MaxIterations(t *testing.T) {
 			},
 		}
 	}
+	return responses
+}
 
-	mockClient := NewMockOpenAIClient(mockResponses, nil)
+// TestContinueSkillWithTools_MaxIterations tests that the function stops after max iterations
+func TestContinueSkillWithTools_MaxIterations(t *testing.T) {
+	// Create responses that always return tool calls (infinite loop scenario)
+	mockClient := NewMockOpenAIClient(toolCallResponses(40), nil)
 
 	agent := &Agent{
 		client: mockClient,
 		cfg: RunnerConfig{
-			Model:            "test-model",
-			AutoApproveTools: true,
+			Model:             "test-model",
+			AutoApproveTools:  true,
+			MaxToolIterations: 30,
 		},
 		messages: []openai.ChatCompletionMessage{},
 	}
@@ -767,8 +926,44 @@ func TestContinueSkillWithTools_MaxIterations(t *testing.T) {
 
 	result, err := agent.continueSkillWithTools(context.Background(), "test prompt", &skill)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "exceeded maximum tool call iterations")
+	var iterErr *skerrors.MaxIterationsError
+	require.True(t, errors.As(err, &iterErr))
+	assert.Equal(t, 30, iterErr.Limit)
+	assert.Empty(t, result)
+	assert.Len(t, mockClient.requests, 30)
+}
+
+// TestContinueSkillWithTools_MaxIterationsOverride tests that a lower limit terminates earlier
+func TestContinueSkillWithTools_MaxIterationsOverride(t *testing.T) {
+	mockClient := NewMockOpenAIClient(toolCallResponses(25), nil)
+
+	agent := &Agent{
+		client: mockClient,
+		cfg: RunnerConfig{
+			Model:             "test-model",
+			AutoApproveTools:  true,
+			MaxToolIterations: 5,
+		},
+	}
+
+	result, err := agent.continueSkillWithTools(context.Background(), "test prompt", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+	var iterErr *skerrors.MaxIterationsError
+	require.True(t, errors.As(err, &iterErr))
+	assert.Equal(t, 5, iterErr.Limit)
+	assert.EqualError(t, err, "exceeded maximum tool call iterations (5)")
 	assert.Empty(t, result)
+	assert.Len(t, mockClient.requests, 5)
+}
+
+func TestNewAgent_MaxToolIterations(t *testing.T) {
+	agent, err := NewAgent(RunnerConfig{APIKey: "test-key"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxToolIterations, agent.cfg.MaxToolIterations)
+
+	for _, n := range []int{-1, 101} {
+		_, err = NewAgent(RunnerConfig{APIKey: "test-key", MaxToolIterations: n}, nil)
+		assert.Error(t, err, n)
+	}
 }
 
 // TestDiscoverSkills_RealDirectory tests discoverSkills with testdata
@@ -810,7 +1005,7 @@ func TestExecuteToolCall_RunPythonCode(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "hello from python")
 }
@@ -843,7 +1038,7 @@ func TestExecuteToolCall_CustomScript(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, scriptMap, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, scriptMap, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "custom script output")
 }
@@ -872,7 +1067,7 @@ func TestExecuteToolCall_RunShellScript(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "shell script output")
 }
@@ -901,7 +1096,7 @@ func TestExecuteToolCall_RunPythonScript(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "python script output")
 }
@@ -935,7 +1130,7 @@ func TestExecuteToolCall_ReadFileRelativePath(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, nil, skillPath)
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, &SkillPackage{Path: skillPath})
 	assert.NoError(t, err)
 	assert.Contains(t, output, testContent)
 }
@@ -968,7 +1163,7 @@ func TestExecuteToolCall_CustomPythonScript(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, scriptMap, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, scriptMap, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "custom python output")
 }
@@ -1001,7 +1196,7 @@ func TestExecuteToolCall_CustomScriptWithEmptyArgs(t *testing.T) {
 		},
 	}
 
-	output, err := agent.executeToolCall(toolCall, scriptMap, "")
+	output, err := agent.executeToolCall(context.Background(), toolCall, scriptMap, nil)
 	assert.NoError(t, err)
 	assert.Contains(t, output, "no args")
 }
@@ -1027,7 +1222,10 @@ func TestContinueSkillWithTools_APIError(t *testing.T) {
 
 	result, err := agent.continueSkillWithTools(context.Background(), "test prompt", &skill)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "ChatCompletion error")
+	var apiErr *skerrors.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "ChatCompletion", apiErr.Operation)
+	assert.Zero(t, apiErr.StatusCode)
 	assert.Empty(t, result)
 }
 
@@ -1101,7 +1299,874 @@ This is a test skill.`
 	}
 
 	skill, err := agent.selectAndPrepareSkill(context.Background(), "test prompt")
-	assert.Error(t, err)
 	assert.Nil(t, skill)
-	assert.Contains(t, err.Error(), "not found")
+	var notFound *skerrors.SkillNotFoundError
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "non-existent-skill", notFound.Name)
+	assert.NotEmpty(t, notFound.Available)
+}
+
+// TestExecuteToolCall_WebScreenshotDisabled tests that browser tools are rejected unless enabled
+func TestExecuteToolCall_WebScreenshotDisabled(t *testing.T) {
+	agent := &Agent{
+		cfg: RunnerConfig{
+			AutoApproveTools: true,
+		},
+	}
+
+	toolCall := openai.ToolCall{
+		ID:   "test-id",
+		Type: openai.ToolTypeFunction,
+		Function: openai.FunctionCall{
+			Name:      "web_screenshot",
+			Arguments: `{"url": "http://example.com"}`,
+		},
+	}
+
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.Error(t, err)
+	assert.Empty(t, output)
+	assert.Contains(t, err.Error(), "browser tools are not enabled")
+}
+
+func TestExecuteToolCall_RunDockerCommand(t *testing.T) {
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho \"docker $*\"\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	toolCall := openai.ToolCall{Function: openai.FunctionCall{
+		Name:      "run_docker_command",
+		Arguments: `{"image": "python:3.12", "command": "python -V", "env": {"A": "1"}}`,
+	}}
+
+	agent := &Agent{cfg: RunnerConfig{AllowedDockerImages: []string{"python"}}}
+	_, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.ErrorContains(t, err, "Docker not enabled")
+
+	agent.cfg.DockerEnabled = true
+	output, err := agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	require.NoError(t, err)
+	assert.Regexp(t, `^docker run --rm --name goskills-\w+ -e A=1 python:3.12 sh -c python -V\n$`, output)
+
+	agent.cfg.AllowedDockerImages = []string{"alpine"}
+	_, err = agent.executeToolCall(context.Background(), toolCall, nil, nil)
+	assert.ErrorContains(t, err, "docker image 'python:3.12' is not in the allowed images")
+}
+
+func TestExecuteToolCall_RunDockerCommandVolumes(t *testing.T) {
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho \"docker $*\"\n"), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	skillDir := writeTestSkill(t, t.TempDir(), "data-skill", "", "Process data.")
+	require.NoError(t, os.Mkdir(filepath.Join(skillDir, "data"), 0755))
+	dataDir := t.TempDir()
+	agent := &Agent{cfg: RunnerConfig{DockerEnabled: true, AllowedDockerImages: []string{"alpine"}}}
+	run := func(volumes string) (string, error) {
+		return agent.executeToolCall(context.Background(), openai.ToolCall{Function: openai.FunctionCall{
+			Name:      "run_docker_command",
+			Arguments: `{"image": "alpine", "command": "ls", "volumes": ` + volumes + `}`,
+		}}, nil, &SkillPackage{Path: skillDir})
+	}
+
+	// Relative sources are resolved against the skill directory and mounted read-only
+	output, err := run(`[{"source": "data", "target": "/data"}, {"source": "` + skillDir + `", "target": "/skill", "writable": true}]`)
+	require.NoError(t, err)
+	assert.Contains(t, output, "-v "+filepath.Join(skillDir, "data")+":/data:ro -v "+skillDir+":/skill alpine")
+
+	_, err = run(`[{"source": "` + dataDir + `", "target": "/data"}]`)
+	assert.ErrorContains(t, err, "is not in the skill directory or the allowed volume paths")
+	_, err = run(`[{"source": "/", "target": "/host"}]`)
+	assert.ErrorContains(t, err, "is not in the skill directory or the allowed volume paths")
+
+	agent.cfg.AllowedDockerVolumes = []string{dataDir}
+	_, err = run(`[{"source": "` + dataDir + `", "target": "/data"}]`)
+	assert.NoError(t, err)
+}
+
+// writeTestSkill creates a minimal SKILL.md package named name under root.
+// extraFrontmatter is inserted verbatim into the YAML frontmatter.
+func writeTestSkill(t testing.TB, root, name, extraFrontmatter, body string) string {
+	t.Helper()
+	return writeSkillMD(t, root, name, "---\nname: "+name+"\ndescription: The "+name+" skill\n"+extraFrontmatter+"---\n"+body)
+}
+
+// writeSkillMD creates the skill directory root/name whose SKILL.md has the given content.
+func writeSkillMD(t testing.TB, root, name, content string) string {
+	t.Helper()
+	skillDir := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(skillDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644))
+	return skillDir
+}
+
+// TestDiscoverSkills_TagFilter tests that the discovery filter includes and excludes skills by tag
+func TestDiscoverSkills_TagFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "csv-analyzer", "tags: [data, python]\n", "Analyze CSV files.")
+	writeTestSkill(t, tmpDir, "pdf-reader", "tags: [pdf]\n", "Read PDF files.")
+	writeTestSkill(t, tmpDir, "untagged", "", "No tags here.")
+
+	testCases := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{
+			name:     "empty filter keeps all skills",
+			tags:     nil,
+			expected: []string{"csv-analyzer", "pdf-reader", "untagged"},
+		},
+		{
+			name:     "single tag",
+			tags:     []string{"data"},
+			expected: []string{"csv-analyzer"},
+		},
+		{
+			name:     "any of several tags",
+			tags:     []string{"pdf", "python"},
+			expected: []string{"csv-analyzer", "pdf-reader"},
+		},
+		{
+			name:     "case insensitive",
+			tags:     []string{"PDF"},
+			expected: []string{"pdf-reader"},
+		},
+		{
+			name:     "no match",
+			tags:     []string{"images"},
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agent := &Agent{
+				cfg: RunnerConfig{
+					DiscoveryFilter: DiscoveryFilter{Tags: tc.tags},
+				},
+			}
+
+			skills, err := agent.discoverSkills(tmpDir)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expected, getAvailableSkillNames(skills))
+		})
+	}
+}
+
+// TestExecuteToolCall_CopyAndMoveFile tests the copy_file and move_file tools
+func TestExecuteToolCall_CopyAndMoveFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("data"), 0644))
+	copied := filepath.Join(tmpDir, "copies", "copy.txt")
+	moved := filepath.Join(tmpDir, "moved", "moved.txt")
+
+	agent := &Agent{
+		cfg: RunnerConfig{
+			AutoApproveTools: true,
+		},
+	}
+
+	call := func(name string, args map[string]any) (string, error) {
+		argsJSON, _ := json.Marshal(args)
+		return agent.executeToolCall(context.Background(), openai.ToolCall{
+			ID:       "test-id",
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: name, Arguments: string(argsJSON)},
+		}, nil, nil)
+	}
+
+	output, err := call("copy_file", map[string]any{"source": src, "destination": copied})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Successfully copied")
+	assert.FileExists(t, copied)
+
+	_, err = call("copy_file", map[string]any{"source": src, "destination": copied})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	_, err = call("copy_file", map[string]any{"source": src, "destination": copied, "overwrite": true})
+	assert.NoError(t, err)
+
+	output, err = call("move_file", map[string]any{"source": src, "destination": moved})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Successfully moved")
+	assert.NoFileExists(t, src)
+	assert.FileExists(t, moved)
+}
+
+// TestExecuteToolCall_AuditLog tests that tool calls are appended to the audit log
+func TestExecuteToolCall_AuditLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	auditPath := filepath.Join(tmpDir, "audit.jsonl")
+
+	agent, err := NewAgent(RunnerConfig{
+		APIKey:              "test-key",
+		AuditLogPath:        auditPath,
+		AuditRedactPatterns: []string{`(?i)content`},
+	}, nil)
+	require.NoError(t, err)
+	agent.activeSkill = "writer"
+
+	target := filepath.Join(tmpDir, "out.txt")
+	args, _ := json.Marshal(map[string]string{"filePath": target, "content": "top secret"})
+	output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "write_file", Arguments: string(args)},
+	}, nil, nil)
+	require.NoError(t, err)
+
+	_, err = agent.executeToolCall(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "read_file", Arguments: `{"filePath": "/does/not/exist"}`},
+	}, nil, nil)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+	assert.Equal(t, agent.sessionID, first["session_id"])
+	assert.Equal(t, "writer", first["skill"])
+	assert.Equal(t, "write_file", first["tool"])
+	assert.Equal(t, "[REDACTED]", first["arguments"].(map[string]any)["content"])
+	assert.Equal(t, float64(len(output)), first["output_length"])
+	assert.NotContains(t, first, "error")
+
+	assert.Equal(t, "read_file", second["tool"])
+	assert.Contains(t, second["error"], "tool execution failed for read_file")
+}
+
+// TestRunToolLoop_AuditLogMCPAndConstructedCalls tests that tool calls not run by
+// executeToolCall are audited too
+func TestRunToolLoop_AuditLogMCPAndConstructedCalls(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "notes", "", "Take notes.")
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLogger, err := audit.NewLogger(auditPath, nil)
+	require.NoError(t, err)
+	mcpClient, err := mcp.NewClient(context.Background(), &mcp.Config{MCPServers: map[string]mcp.MCPServer{
+		"missing": {Type: "stdio", Command: "goskills-test-no-such-command"},
+	}})
+	require.NoError(t, err)
+
+	agent := &Agent{
+		client: NewMockOpenAIClient([]openai.ChatCompletionResponse{
+			toolCallResponse("call-1", "missing__lookup", `{"q":"x"}`),
+			toolCallResponse("call-2", tool.ConstructToolCallName, `{"tool_name":"no_such_tool","params_description":"x"}`),
+			textResponse("done"),
+		}, nil),
+		cfg:         RunnerConfig{Model: "test", SkillsDir: skillsDir, SkillName: "notes", AutoApproveTools: true},
+		auditLogger: auditLogger,
+		mcpClient:   mcpClient,
+	}
+	_, err = agent.Run(context.Background(), "go")
+	require.NoError(t, err)
+
+	var tools []string
+	data, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var rec audit.Record
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		if rec.Kind == audit.KindToolCall {
+			tools = append(tools, rec.Tool)
+			assert.NotEmpty(t, rec.Error, rec.Tool)
+		}
+	}
+	assert.Equal(t, []string{"missing__lookup", tool.ConstructToolCallName}, tools)
+}
+
+// TestNewAgent_InvalidAuditPattern tests that invalid redact patterns are rejected
+func TestNewAgent_InvalidAuditPattern(t *testing.T) {
+	_, err := NewAgent(RunnerConfig{
+		APIKey:              "test-key",
+		AuditLogPath:        filepath.Join(t.TempDir(), "audit.jsonl"),
+		AuditRedactPatterns: []string{"("},
+	}, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid audit redact pattern")
+}
+
+// TestExecuteToolCall_ToolCache tests that cached tool results skip re-execution
+func TestExecuteToolCall_ToolCache(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "calls.txt")
+	agent := &Agent{
+		cfg: RunnerConfig{
+			ToolCacheTTLs: map[string]time.Duration{"run_shell_code": time.Minute},
+		},
+		toolCache: NewMemoryToolCache(),
+	}
+
+	call := func(code string) string {
+		args, _ := json.Marshal(map[string]any{"code": code})
+		output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+			Function: openai.FunctionCall{Name: "run_shell_code", Arguments: string(args)},
+		}, nil, nil)
+		require.NoError(t, err)
+		return output
+	}
+
+	code := fmt.Sprintf("echo run >> %s; echo done", counter)
+	assert.Equal(t, "done\n", call(code))
+	assert.Equal(t, "done\n", call(code)) // cache hit
+
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "run\n", string(data), "cache hit must not execute the tool again")
+
+	// Different arguments miss the cache and populate it
+	call(code + " ")
+	data, err = os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "run\nrun\n", string(data))
+
+	// Tools without a TTL are never cached
+	agent.cfg.ToolCacheTTLs = nil
+	call(code)
+	data, err = os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "run\nrun\nrun\n", string(data))
+}
+
+// TestSelectSkill_TokenBudget tests that a selection token budget uses the compact skill list
+func TestSelectSkill_TokenBudget(t *testing.T) {
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "pdf"}}}},
+	}, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{SelectionTokenBudget: 16}}
+
+	skills := map[string]SkillPackage{
+		"pdf":  {Meta: SkillMeta{Name: "pdf", Description: "Extract text and tables from PDF files, fill forms, and merge documents."}},
+		"xlsx": {Meta: SkillMeta{Name: "xlsx", Description: "Create, edit and analyze spreadsheets with formulas and charts."}},
+	}
+
+	skillName, err := agent.selectSkill(context.Background(), "summarise this pdf", skills)
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", skillName)
+
+	require.Len(t, client.requests, 1)
+	messages := client.requests[0].Messages
+	assert.NotContains(t, messages[0].Content, "<available_skills>")
+	assert.Contains(t, messages[1].Content, "pdf: Extract text and tables from PDF files, fill forms, and merge documents.")
+	assert.Contains(t, messages[1].Content, "xlsx:")
+	assert.NotContains(t, messages[1].Content, "formulas and charts")
+}
+
+// concurrentEchoClient answers every request with the last user message, recording
+// the peak number of concurrent calls and the message count of each request.
+type concurrentEchoClient struct {
+	mu           sync.Mutex
+	inFlight     int
+	peak         int
+	messageCount map[string]int
+}
+
+func (c *concurrentEchoClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	last := req.Messages[len(req.Messages)-1].Content
+
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.messageCount[last] = len(req.Messages)
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if last == "fail" {
+		return openai.ChatCompletionResponse{}, fmt.Errorf("model unavailable")
+	}
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "echo: " + last}}},
+	}, nil
+}
+
+// TestRunBatch tests concurrency limiting, result ordering and independent histories
+func TestRunBatch(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "echo", "", "Echo the request.")
+
+	client := &concurrentEchoClient{messageCount: map[string]int{}}
+	agent := &Agent{
+		client: client,
+		cfg: RunnerConfig{
+			SkillsDir:        skillsDir,
+			SkillName:        "echo",
+			AutoApproveTools: true,
+		},
+	}
+
+	prompts := []string{"one", "two", "fail", "four", "five", "six"}
+	results, err := agent.RunBatch(context.Background(), prompts, 2)
+	require.NoError(t, err)
+	require.Len(t, results, len(prompts))
+
+	for i, result := range results {
+		assert.Equal(t, prompts[i], result.Prompt)
+		assert.Greater(t, result.Duration, time.Duration(0))
+		if result.Prompt == "fail" {
+			assert.Error(t, result.Error)
+			continue
+		}
+		assert.NoError(t, result.Error)
+		assert.Equal(t, "echo: "+result.Prompt, result.Result)
+	}
+
+	assert.LessOrEqual(t, client.peak, 2, "concurrency limit exceeded")
+	assert.Equal(t, 2, client.peak, "prompts should run concurrently")
+	for _, prompt := range prompts {
+		// System prompt + this prompt only: no history leaked from other runs
+		assert.Equal(t, 2, client.messageCount[prompt], "prompt %q saw a shared history", prompt)
+	}
+	assert.Empty(t, agent.messages, "the original agent's history must be untouched")
+}
+
+// TestRunBatch_CancelledContext tests that prompts not yet started report the context error
+func TestRunBatch_CancelledContext(t *testing.T) {
+	agent := &Agent{client: &concurrentEchoClient{messageCount: map[string]int{}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := agent.RunBatch(ctx, []string{"a", "b"}, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Error(t, result.Error)
+	}
+}
+
+// TestRun_AllSkillsMode tests that all skill bodies are sent in one system prompt without a selection call
+func TestRun_AllSkillsMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "csv-analyzer", "", "Analyze CSV files with pandas.")
+	pdfDir := writeTestSkill(t, tmpDir, "pdf-reader", "", "Read PDF files with pdftotext.")
+	require.NoError(t, os.MkdirAll(filepath.Join(pdfDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pdfDir, "scripts", "extract.py"), []byte("print('ok')\n"), 0644))
+
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "done"}}}},
+	}, nil)
+	agent := &Agent{
+		client: client,
+		cfg: RunnerConfig{
+			Model:            "test-model",
+			SkillsDir:        tmpDir,
+			AutoApproveTools: true,
+			AllSkillsMode:    true,
+		},
+	}
+
+	result, err := agent.Run(context.Background(), "summarize report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+
+	// Only the execution request is made; there is no skill-selection round-trip.
+	require.Len(t, client.requests, 1)
+	req := client.requests[0]
+	require.NotEmpty(t, req.Messages)
+	system := req.Messages[0]
+	assert.Equal(t, openai.ChatMessageRoleSystem, system.Role)
+	assert.Contains(t, system.Content, "Analyze CSV files with pandas.")
+	assert.Contains(t, system.Content, "Read PDF files with pdftotext.")
+	assert.Contains(t, system.Content, "# Skill: csv-analyzer")
+	assert.Contains(t, system.Content, "# Skill: pdf-reader")
+
+	var toolNames []string
+	for _, tl := range req.Tools {
+		toolNames = append(toolNames, tl.Function.Name)
+	}
+	assert.Contains(t, toolNames, "run_pdf_reader_scripts_extract_py")
+}
+
+// TestCombineSkills_TokenLimit tests that skills past the token limit are left out
+func TestCombineSkills_TokenLimit(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"a": {Meta: SkillMeta{Name: "a"}, Path: "/skills/a", Body: "one two three"},
+		"b": {Meta: SkillMeta{Name: "b"}, Path: "/skills/b", Body: "four five six seven eight"},
+		"c": {Meta: SkillMeta{Name: "c"}, Path: "/skills/c", Body: "nine"},
+	}
+
+	combined, included := combineSkills("/skills", skills, 5)
+	assert.Equal(t, []string{"a", "c"}, included)
+	assert.Contains(t, combined.Body, "one two three")
+	assert.Contains(t, combined.Body, "nine")
+	assert.NotContains(t, combined.Body, "four five")
+	assert.Equal(t, "/skills", combined.Path)
+}
+
+// TestRun_Trace tests that a run records LLM requests, responses and tool calls in order
+func TestRun_Trace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "test-skill", "", "Read the notes file.")
+	notesPath := filepath.Join(tmpDir, "notes.txt")
+	require.NoError(t, os.WriteFile(notesPath, []byte("remember the milk"), 0644))
+
+	readArgs := fmt.Sprintf(`{"filePath": %q}`, notesPath)
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "test-skill"}}}},
+		{
+			Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{
+				Role: openai.ChatMessageRoleAssistant,
+				ToolCalls: []openai.ToolCall{{
+					ID:       "call_1",
+					Type:     openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: "read_file", Arguments: readArgs},
+				}},
+			}}},
+			Usage: openai.Usage{PromptTokens: 40, CompletionTokens: 8},
+		},
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "Buy milk."}}}},
+	}, nil)
+
+	recorder := trace.NewRecorder()
+	agent := &Agent{
+		client: client,
+		cfg: RunnerConfig{
+			Model:            "test-model",
+			SkillsDir:        tmpDir,
+			AutoApproveTools: true,
+			Trace:            recorder,
+		},
+	}
+
+	result, err := agent.Run(context.Background(), "what do my notes say?")
+	require.NoError(t, err)
+	assert.Equal(t, "Buy milk.", result)
+
+	events := recorder.Events()
+	var sequence []string
+	for _, ev := range events {
+		sequence = append(sequence, ev.Stage+":"+ev.Type)
+	}
+	assert.Equal(t, []string{
+		"selection:llm_request",
+		"selection:llm_response",
+		"execution:llm_request",
+		"execution:llm_response",
+		"execution:tool_call",
+		"execution:llm_request",
+		"execution:llm_response",
+	}, sequence)
+
+	assert.Equal(t, "test-model", events[0].Model)
+	assert.Equal(t, "test-skill", events[1].Content)
+	assert.Contains(t, events[2].Tools, "read_file")
+	require.Len(t, events[3].ToolCalls, 1)
+	assert.Equal(t, 40, events[3].Usage.PromptTokens)
+	assert.Equal(t, "read_file", events[4].Tool)
+	assert.Equal(t, readArgs, events[4].Arguments)
+	assert.Equal(t, "remember the milk", events[4].Output)
+	assert.Empty(t, events[4].Error)
+	assert.Equal(t, openai.ChatMessageRoleTool, events[5].Messages[len(events[5].Messages)-1].Role)
+	assert.Equal(t, "Buy milk.", events[6].Content)
+}
+
+// TestRunWithMessages tests that the given history is used and returned with the new turn
+// appended, without touching the caller's slice or the agent's own history
+func TestRunWithMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "test-skill", "", "Answer questions.")
+
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "Paris."}}}},
+	}, nil)
+	agentHistory := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "agent history"}}
+	agent := &Agent{
+		client:   client,
+		cfg:      RunnerConfig{Model: "test-model", SkillsDir: tmpDir, SkillName: "test-skill", AutoApproveTools: true},
+		messages: agentHistory,
+	}
+
+	// Spare capacity would let an append write into the caller's backing array.
+	history := make([]openai.ChatCompletionMessage, 2, 10)
+	history[0] = openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "I am planning a trip to France."}
+	history[1] = openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "Sounds fun!"}
+
+	result, messages, err := agent.RunWithMessages(context.Background(), "What is its capital?", history)
+	require.NoError(t, err)
+	assert.Equal(t, "Paris.", result)
+
+	require.Len(t, messages, 5)
+	assert.Equal(t, history, messages[:2])
+	assert.Equal(t, openai.ChatMessageRoleSystem, messages[2].Role)
+	assert.Equal(t, "What is its capital?", messages[3].Content)
+	assert.Equal(t, "Paris.", messages[4].Content)
+
+	// The request carried the supplied history rather than the agent's.
+	require.Len(t, client.requests, 1)
+	assert.Equal(t, "I am planning a trip to France.", client.requests[0].Messages[0].Content)
+
+	assert.Len(t, history, 2)
+	assert.Equal(t, openai.ChatCompletionMessage{}, history[:3][2], "caller's backing array was modified")
+	assert.Equal(t, agentHistory, agent.messages)
+}
+
+// TestRun_AppendsToAgentHistory tests that Run keeps accumulating the agent's own history
+func TestRun_AppendsToAgentHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "test-skill", "", "Answer questions.")
+
+	client := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "first"}}}},
+		{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "second"}}}},
+	}, nil)
+	agent := &Agent{
+		client: client,
+		cfg:    RunnerConfig{Model: "test-model", SkillsDir: tmpDir, SkillName: "test-skill", AutoApproveTools: true},
+	}
+
+	_, err := agent.Run(context.Background(), "one")
+	require.NoError(t, err)
+	assert.Len(t, agent.messages, 3)
+
+	_, err = agent.Run(context.Background(), "two")
+	require.NoError(t, err)
+	assert.Len(t, agent.messages, 6)
+	assert.Equal(t, "one", client.requests[1].Messages[1].Content)
+}
+
+func TestExecuteSkillWithTools_ListTools(t *testing.T) {
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "list_tools", "{}"),
+		textResponse("done"),
+	}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true}}
+	skill := &SkillPackage{
+		Path: t.TempDir(),
+		Meta: SkillMeta{
+			Name:  "greeter",
+			Tools: []InlineTool{{Name: "greet", Description: "Greets someone.", Command: "echo hi"}},
+		},
+	}
+
+	_, err := agent.executeSkillWithTools(context.Background(), "what can you do?", skill)
+	require.NoError(t, err)
+	require.Len(t, mockClient.requests, 2)
+
+	// The listed tools are exactly the tools offered in the request, each once
+	var listed []tool.ToolSummary
+	messages := mockClient.requests[1].Messages
+	require.NoError(t, json.Unmarshal([]byte(messages[len(messages)-1].Content), &listed))
+	var offered []tool.ToolSummary
+	seen := make(map[string]bool)
+	for _, def := range mockClient.requests[0].Tools {
+		if !seen[def.Function.Name] {
+			seen[def.Function.Name] = true
+			offered = append(offered, tool.ToolSummary{Name: def.Function.Name, Description: def.Function.Description})
+		}
+	}
+	assert.Equal(t, offered, listed)
+	assert.Contains(t, listed, tool.ToolSummary{Name: "greet", Description: "Greets someone."})
+}
+
+func TestExecuteSkillWithTools_SkillArgs(t *testing.T) {
+	skillDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(skillDir, "scripts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "scripts", "fetch.sh"), []byte("echo \"script $GOSKILLS_ARG_URL\"\n"), 0755))
+
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "run_shell_code", `{"code": "echo \"code $GOSKILLS_ARG_URL {{.GOSKILLS_ARGS.url}}\""}`),
+		toolCallResponse("call-2", "run_scripts_fetch_sh", "{}"),
+		toolCallResponse("call-3", "greet", "{}"),
+		textResponse("done"),
+	}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{
+		Model:            "test-model",
+		AutoApproveTools: true,
+		SkillArgs:        map[string]string{"url": "https://example.com"},
+	}}
+	skill := &SkillPackage{
+		Path:      skillDir,
+		Resources: SkillResources{Scripts: []string{"scripts/fetch.sh"}},
+		Meta: SkillMeta{
+			Name:  "fetcher",
+			Tools: []InlineTool{{Name: "greet", Description: "Greets the URL.", Command: "echo \"inline {{.GOSKILLS_ARGS.url}}\""}},
+		},
+	}
+
+	_, err := agent.executeSkillWithTools(context.Background(), "fetch it", skill)
+	require.NoError(t, err)
+	require.Len(t, mockClient.requests, 4)
+
+	var outputs []string
+	for _, msg := range mockClient.requests[3].Messages {
+		if msg.Role == openai.ChatMessageRoleTool {
+			outputs = append(outputs, strings.TrimSpace(msg.Content))
+		}
+	}
+	assert.Equal(t, []string{
+		"code https://example.com https://example.com",
+		"script https://example.com",
+		"inline https://example.com",
+	}, outputs)
+}
+
+func TestExecuteSkillWithTools_SystemPrefix(t *testing.T) {
+	skill := &SkillPackage{Path: "/skills/greeter", Body: "Greet the user.", Meta: SkillMeta{Name: "greeter"}}
+
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("hi")}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", SystemPrefix: "Company: Acme\nLocale: de-DE\n"}}
+	_, err := agent.executeSkillWithTools(context.Background(), "hello", skill)
+	require.NoError(t, err)
+	require.Len(t, mockClient.requests, 1)
+	system := mockClient.requests[0].Messages[0]
+	assert.Equal(t, openai.ChatMessageRoleSystem, system.Role)
+	assert.True(t, strings.HasPrefix(system.Content, "Company: Acme\nLocale: de-DE\n\nGreet the user."), system.Content)
+
+	// An empty or blank prefix leaves the system message untouched
+	for _, prefix := range []string{"", "  \n"} {
+		mockClient = NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("hi")}, nil)
+		agent = &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", SystemPrefix: prefix}}
+		_, err = agent.executeSkillWithTools(context.Background(), "hello", skill)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(mockClient.requests[0].Messages[0].Content, "Greet the user."), prefix)
+	}
+}
+
+func TestNewAgent_InvalidSkillArgs(t *testing.T) {
+	_, err := NewAgent(RunnerConfig{APIKey: "key", SkillArgs: map[string]string{"bad-key": "x"}}, nil)
+	assert.ErrorContains(t, err, "invalid skill argument key 'bad-key'")
+}
+
+func TestNewAgent_RateLimits(t *testing.T) {
+	agent, err := NewAgent(RunnerConfig{APIKey: "key"}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &openai.Client{}, agent.client)
+
+	agent, err = NewAgent(RunnerConfig{APIKey: "key", RateLimits: ratelimit.Limits{"gpt-4o": {RPM: 60}}}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &ratelimit.RateLimitedOpenAIClient{}, agent.client)
+	_, ok := agent.client.(EmbeddingClient)
+	assert.True(t, ok, "the rate limited client still creates embeddings")
+}
+
+func TestExecuteToolCall_BatchReadFiles(t *testing.T) {
+	skillDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "a.md"), []byte("alpha"), 0644))
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true}}
+
+	output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+		ID:       "call-1",
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: "batch_read_files", Arguments: `{"file_paths": ["a.md", "missing.md"]}`},
+	}, nil, &SkillPackage{Path: skillDir})
+	require.NoError(t, err)
+
+	var contents map[string]any
+	require.NoError(t, json.Unmarshal([]byte(output), &contents))
+	assert.Equal(t, "alpha", contents["a.md"])
+	assert.Contains(t, contents["missing.md"], "error")
+}
+
+func TestExecuteToolCall_CompareFiles(t *testing.T) {
+	skillDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "old.txt"), []byte("a\nb\nc\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "new.txt"), []byte("a\nB\nc\n"), 0644))
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true}}
+
+	output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+		ID:       "call-1",
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: "compare_files", Arguments: `{"file_a": "old.txt", "file_b": "new.txt", "context_lines": 0}`},
+	}, nil, &SkillPackage{Path: skillDir})
+	require.NoError(t, err)
+	assert.Contains(t, output, "@@ -2 +2 @@\n-b\n+B\n")
+	assert.NotContains(t, output, " a\n")
+}
+
+func TestExecuteToolCall_CodeSearch(t *testing.T) {
+	skillDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(skillDir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(skillDir, "src", "app.py"), []byte("import os\ndef main():\n    pass\n"), 0644))
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true}}
+
+	call := func(args string) string {
+		output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+			ID:       "call-1",
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: "code_search", Arguments: args},
+		}, nil, &SkillPackage{Path: skillDir})
+		require.NoError(t, err)
+		return output
+	}
+	assert.Equal(t, "app.py:2: def main():\n", call(`{"directory": "src", "pattern": "def \\w+", "file_pattern": "*.py"}`))
+	assert.Equal(t, "No matches found.", call(`{"directory": "src", "pattern": "class"}`))
+}
+
+func TestNewAPIError(t *testing.T) {
+	err := newAPIError("ChatCompletion", &openai.APIError{HTTPStatusCode: 429, Message: "rate limited"})
+	assert.Equal(t, 429, err.StatusCode)
+	assert.EqualError(t, err, "ChatCompletion error (status 429): error, status code: 429, status: , message: rate limited")
+
+	err = newAPIError("ChatCompletion", fmt.Errorf("wrapped: %w", &openai.RequestError{HTTPStatusCode: 502, Err: errors.New("bad gateway")}))
+	assert.Equal(t, 502, err.StatusCode)
+
+	err = newAPIError("ChatCompletion", errors.New("connection refused"))
+	assert.Zero(t, err.StatusCode)
+	assert.EqualError(t, err, "ChatCompletion error: connection refused")
+}
+
+func TestSelectAndPrepareSkill_SelectionError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "test-skill", "", "Body")
+	agent := &Agent{
+		client: NewMockOpenAIClient(nil, &openai.APIError{HTTPStatusCode: 503, Message: "unavailable"}),
+		cfg:    RunnerConfig{Model: "test-model", SkillsDir: tmpDir},
+	}
+
+	_, err := agent.selectAndPrepareSkill(context.Background(), "test prompt")
+	var selectionErr *skerrors.SelectionError
+	require.True(t, errors.As(err, &selectionErr))
+	assert.Equal(t, SelectionStrategyLLM, selectionErr.Strategy)
+	var apiErr *skerrors.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 503, apiErr.StatusCode)
+}
+
+func TestExecuteToolCall_GitTools(t *testing.T) {
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main\n"), 0644))
+	_, err = worktree.Add("main.go")
+	require.NoError(t, err)
+	_, err = worktree.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Ada", Email: "ada@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	call := func(agent *Agent, name, args string) (string, error) {
+		return agent.executeToolCall(context.Background(), openai.ToolCall{
+			ID:       "call-1",
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: name, Arguments: args},
+		}, nil, nil)
+	}
+	args := fmt.Sprintf(`{"repo_path": %q}`, repoDir)
+
+	agent := &Agent{cfg: RunnerConfig{AutoApproveTools: true, AllowedRepoPaths: []string{repoDir}}}
+	output, err := call(agent, "git_log", args)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Author: Ada <ada@example.com>")
+	assert.Contains(t, output, "    Initial commit\n")
+
+	output, err = call(agent, "git_diff", fmt.Sprintf(`{"repo_path": %q, "from_ref": "HEAD"}`, repoDir))
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	// Repositories outside the allowed paths are rejected
+	agent = &Agent{cfg: RunnerConfig{AutoApproveTools: true, AllowedRepoPaths: []string{t.TempDir()}}}
+	_, err = call(agent, "git_log", args)
+	assert.ErrorContains(t, err, "is not in the allowed repository paths")
+	_, err = call(agent, "git_diff", fmt.Sprintf(`{"repo_path": %q, "from_ref": "HEAD"}`, repoDir))
+	assert.ErrorContains(t, err, "is not in the allowed repository paths")
 }
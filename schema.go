@@ -0,0 +1,103 @@
+package goskills
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// SkillSchemaFile is the optional file in a skill directory holding the JSON Schema
+// its frontmatter must satisfy.
+const SkillSchemaFile = "schema.json"
+
+// ValidationError reports every way a skill's frontmatter violates its schema.json.
+type ValidationError struct {
+	Path       string   // Skill directory
+	Violations []string // One entry per violation, prefixed with the offending field
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("frontmatter of skill %s does not match %s:\n  - %s",
+		e.Path, SkillSchemaFile, strings.Join(e.Violations, "\n  - "))
+}
+
+// validateFrontmatterSchema validates the YAML frontmatter against the skill's schema.json.
+// Skills without a schema.json are accepted as is.
+func validateFrontmatterSchema(dirPath string, frontmatter []byte) error {
+	schemaPath := filepath.Join(dirPath, SkillSchemaFile)
+	schemaData, err := os.ReadFile(schemaPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", SkillSchemaFile, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaPath, bytes.NewReader(schemaData)); err != nil {
+		return fmt.Errorf("invalid %s: %w", SkillSchemaFile, err)
+	}
+	schema, err := compiler.Compile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", SkillSchemaFile, err)
+	}
+
+	doc, err := frontmatterToJSON(frontmatter)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(doc)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	vErr := &ValidationError{Path: dirPath}
+	collectViolations(verr, &vErr.Violations)
+	return vErr
+}
+
+// frontmatterToJSON converts YAML frontmatter into the JSON value model the validator expects.
+func frontmatterToJSON(frontmatter []byte) (any, error) {
+	var doc any
+	if err := yaml.Unmarshal(frontmatter, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter cannot be represented as JSON: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// collectViolations appends the leaf errors of a validation error tree.
+func collectViolations(verr *jsonschema.ValidationError, violations *[]string) {
+	if len(verr.Causes) == 0 {
+		location := verr.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		*violations = append(*violations, fmt.Sprintf("%s: %s", location, verr.Message))
+		return
+	}
+	for _, cause := range verr.Causes {
+		collectViolations(cause, violations)
+	}
+}
@@ -0,0 +1,139 @@
+package goskills
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// OpenBody returns a reader over the skill's Markdown body. Packages parsed with
+// ParseSkillPackageLazy stream the body from SKILL.md; the caller must close the reader.
+func (s *SkillPackage) OpenBody() (io.ReadCloser, error) {
+	if s.openBody == nil {
+		return io.NopCloser(strings.NewReader(s.Body)), nil
+	}
+	return s.openBody()
+}
+
+// ReadBody reads at most maxBytes of the skill's body; maxBytes <= 0 reads all of it.
+// A truncated body ends before the first UTF-8 character that does not fit completely.
+// truncated reports whether the body was longer than maxBytes.
+func (s *SkillPackage) ReadBody(maxBytes int) (body string, truncated bool, err error) {
+	r, err := s.OpenBody()
+	if err != nil {
+		return "", false, err
+	}
+	defer r.Close()
+
+	if maxBytes <= 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read skill body: %w", err)
+		}
+		return strings.TrimSpace(string(data)), false, nil
+	}
+
+	// Read one byte more than allowed to detect truncation
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxBytes)+1))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read skill body: %w", err)
+	}
+	if len(data) > maxBytes {
+		// Cut before a multi-byte character that does not fit completely
+		cut := maxBytes
+		for cut > 0 && maxBytes-cut < utf8.UTFMax && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		return strings.TrimSpace(string(data[:cut])), true, nil
+	}
+	return strings.TrimSpace(string(data)), false, nil
+}
+
+// readFrontmatter reads the frontmatter of a SKILL.md file line by line, stopping at the
+// closing delimiter, and returns it as YAML together with the offset at which the body
+// starts. Blank lines after the frontmatter are skipped, as ParseSkillPackage trims them.
+func readFrontmatter(path string) ([]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	var delimiter string
+	var frontmatter bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		offset += int64(len(line))
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case delimiter == "" && trimmed == "":
+			// Skip blank lines before the frontmatter
+		case delimiter == "" && (trimmed == "---" || trimmed == "+++"):
+			delimiter = trimmed
+		case delimiter == "":
+			return nil, 0, fmt.Errorf("no YAML frontmatter found or format is incorrect")
+		case trimmed == delimiter:
+			if err := skipBlankLines(r, &offset); err != nil {
+				return nil, 0, fmt.Errorf("failed to read SKILL.md: %w", err)
+			}
+			if delimiter == "+++" {
+				data, err := tomlToYAML(frontmatter.Bytes())
+				return data, offset, err
+			}
+			return frontmatter.Bytes(), offset, nil
+		default:
+			frontmatter.WriteString(line)
+		}
+
+		if err == io.EOF {
+			if delimiter == "+++" {
+				return nil, 0, fmt.Errorf("no TOML frontmatter found or format is incorrect")
+			}
+			return nil, 0, fmt.Errorf("no YAML frontmatter found or format is incorrect")
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read SKILL.md: %w", err)
+		}
+	}
+}
+
+// skipBlankLines advances r past whitespace-only lines, adding their length to offset.
+func skipBlankLines(r *bufio.Reader, offset *int64) error {
+	for {
+		peek, err := r.Peek(1)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if peek[0] != '\n' && peek[0] != '\r' && peek[0] != ' ' && peek[0] != '\t' {
+			return nil
+		}
+		if _, err := r.ReadByte(); err != nil {
+			return err
+		}
+		*offset++
+	}
+}
+
+// fileBodyOpener returns a function that opens path positioned at the start of the body.
+func fileBodyOpener(path string, offset int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open skill body: %w", err)
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to open skill body: %w", err)
+		}
+		return f, nil
+	}
+}
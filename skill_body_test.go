@@ -0,0 +1,135 @@
+package goskills
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSkillPackageLazy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"yaml", "---\nname: large-skill\ndescription: A large skill.\ntags: [big]\n---\n\n# Title\n\nStep one --- then step two.\n"},
+		{"toml", "+++\nname = \"large-skill\"\ndescription = \"A large skill.\"\ntags = [\"big\"]\n+++\n\n# Title\n\nStep one --- then step two.\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeSkillMD(t, t.TempDir(), "large-skill", tc.content)
+			eager, err := ParseSkillPackage(dir)
+			require.NoError(t, err)
+			lazy, err := ParseSkillPackageLazy(dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, eager.Meta, lazy.Meta)
+			assert.Empty(t, lazy.Body)
+
+			r, err := lazy.OpenBody()
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, eager.Body, strings.TrimSpace(string(data)))
+
+			body, truncated, err := lazy.ReadBody(0)
+			require.NoError(t, err)
+			assert.False(t, truncated)
+			assert.Equal(t, eager.Body, body)
+		})
+	}
+
+	t.Run("no frontmatter", func(t *testing.T) {
+		_, err := ParseSkillPackageLazy(writeSkillMD(t, t.TempDir(), "large-skill", "# Just a body\n"))
+		assert.ErrorContains(t, err, "no YAML frontmatter found")
+	})
+
+	t.Run("unterminated", func(t *testing.T) {
+		_, err := ParseSkillPackageLazy(writeSkillMD(t, t.TempDir(), "large-skill", "+++\nname = \"x\"\n"))
+		assert.ErrorContains(t, err, "no TOML frontmatter found")
+	})
+}
+
+func TestSkillPackage_ReadBody(t *testing.T) {
+	skill := &SkillPackage{Body: "0123456789"}
+
+	body, truncated, err := skill.ReadBody(4)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, "0123", body)
+
+	body, truncated, err = skill.ReadBody(10)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, "0123456789", body)
+
+	// Multi-byte characters that do not fit completely are dropped
+	skill = &SkillPackage{Body: "技能说明"} // Three bytes per character
+	for maxBytes, want := range map[int]string{3: "技", 4: "技", 5: "技", 6: "技能", 2: ""} {
+		body, truncated, err = skill.ReadBody(maxBytes)
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		assert.Equal(t, want, body, "maxBytes %d", maxBytes)
+	}
+}
+
+func TestDiscoverSkills_Lazy(t *testing.T) {
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "large-skill", "", "Follow the large instructions.")
+
+	skills, err := (&Agent{}).discoverSkills(skillsDir)
+	require.NoError(t, err)
+	skill := skills["large-skill"]
+	assert.Equal(t, "The large-skill skill", skill.Meta.Description)
+	assert.Empty(t, skill.Body, "the body is not read while discovering skills")
+	body, _, err := skill.ReadBody(0)
+	require.NoError(t, err)
+	assert.Equal(t, "Follow the large instructions.", body)
+
+	combined, included := combineSkills(skillsDir, skills, 1000)
+	assert.Equal(t, []string{"large-skill"}, included)
+	assert.Contains(t, combined.Body, "Follow the large instructions.")
+}
+
+func TestExecuteSkillWithTools_MaxSkillBodyBytes(t *testing.T) {
+	dir := writeTestSkill(t, t.TempDir(), "large-skill", "", strings.Repeat("a", 100)+strings.Repeat("b", 100))
+	skill, err := ParseSkillPackageLazy(dir)
+	require.NoError(t, err)
+
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("done")}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", MaxSkillBodyBytes: 100}}
+	_, err = agent.executeSkillWithTools(context.Background(), "go", skill)
+	require.NoError(t, err)
+
+	system := mockClient.requests[0].Messages[0].Content
+	assert.True(t, strings.HasPrefix(system, strings.Repeat("a", 100)+"\n\n[Skill instructions truncated after 100 bytes.]"))
+	assert.NotContains(t, system, "bbb")
+}
+
+// largeSkillBody is roughly 1 MB of Markdown.
+var largeSkillBody = strings.Repeat("## Step\n\nFollow these instructions carefully and report the result.\n\n", 15000)
+
+func BenchmarkParseSkillPackage_Eager(b *testing.B) {
+	dir := writeTestSkill(b, b.TempDir(), "large-skill", "", largeSkillBody)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseSkillPackage(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseSkillPackage_Lazy(b *testing.B) {
+	dir := writeTestSkill(b, b.TempDir(), "large-skill", "", largeSkillBody)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseSkillPackageLazy(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/smallnest/goskills/log"
+	"github.com/smallnest/goskills/skillstats"
+)
+
+// DefaultSkillFallbackThreshold is the number of consecutive failed tool calls after
+// which NewAgent's agents select a different skill.
+const DefaultSkillFallbackThreshold = 3
+
+// maxFallbackErrorLength caps each error quoted back to the LLM during re-selection.
+const maxFallbackErrorLength = 500
+
+// shouldFallBack reports whether consecutiveErrors failed tool calls warrant selecting
+// another skill. Re-selection is impossible when the skill was chosen explicitly or all
+// skills are combined.
+func (a *Agent) shouldFallBack(consecutiveErrors int) bool {
+	threshold := a.cfg.SkillFallbackThreshold
+	return threshold > 0 && consecutiveErrors >= threshold &&
+		a.cfg.SkillName == "" && !a.cfg.AllSkillsMode
+}
+
+// reselectSkill asks the LLM for a replacement of the skills in failed, telling it about
+// the errors the current skill ran into. It returns nil if no other skill is available.
+func (a *Agent) reselectSkill(ctx context.Context, userPrompt string, failed map[string]bool, errorHistory []string) (*SkillPackage, error) {
+	skills, err := a.discoverSkills(a.cfg.SkillsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover skills: %w", err)
+	}
+	for name := range failed {
+		delete(skills, name)
+	}
+	if len(skills) == 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(userPrompt)
+	sb.WriteString("\n\nNote: the previous skill failed with these errors, so choose a different skill:\n")
+	for _, e := range errorHistory {
+		sb.WriteString("- " + e + "\n")
+	}
+
+	name, err := a.selectSkill(ctx, sb.String(), skills)
+	if err != nil {
+		return nil, &skerrors.SelectionError{Strategy: SelectionStrategyLLM, Err: err}
+	}
+	skill, ok := skills[name]
+	if !ok {
+		return nil, &skerrors.SkillNotFoundError{Name: name, Available: getAvailableSkillNames(skills)}
+	}
+	a.recordSkillStats(skillstats.Counters{Skill: name, Selected: 1})
+	return &skill, nil
+}
+
+// skillFallbackMessage tells the LLM, and anyone reading the conversation, why the
+// skill was switched.
+func skillFallbackMessage(from, to, userPrompt string, errorHistory []string) openai.ChatCompletionMessage {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("The skill '%s' failed %d times in a row, so the skill '%s' was selected instead. The errors were:\n", from, len(errorHistory), to))
+	for _, e := range errorHistory {
+		sb.WriteString("- " + e + "\n")
+	}
+	sb.WriteString("\nFollow the new skill's instructions to complete the original request:\n" + userPrompt)
+	return openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: sb.String(),
+	}
+}
+
+// summarizeToolError formats a failed tool call for the error history.
+func summarizeToolError(toolName string, err error) string {
+	// The tool name is already part of the summary
+	var toolErr *skerrors.ToolError
+	if errors.As(err, &toolErr) {
+		err = toolErr.Err
+	}
+	msg := strings.ReplaceAll(err.Error(), "\n", " ")
+	if len(msg) > maxFallbackErrorLength {
+		msg = msg[:maxFallbackErrorLength] + "..."
+	}
+	return fmt.Sprintf("%s: %s", toolName, msg)
+}
+
+// logSkillFallback reports a skill switch at verbose level.
+func (a *Agent) logSkillFallback(from, to string) {
+	if a.cfg.Verbose >= 1 {
+		log.Info("skill %s kept failing, switching to skill %s", from, to)
+	}
+}
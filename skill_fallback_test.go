@@ -0,0 +1,123 @@
+package goskills
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toolCallResponse returns an LLM response calling the named tool.
+func toolCallResponse(id, name, args string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{
+			Role: openai.ChatMessageRoleAssistant,
+			ToolCalls: []openai.ToolCall{{
+				ID:       id,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: name, Arguments: args},
+			}},
+		}}},
+	}
+}
+
+func TestContinueSkillWithTools_SkillFallback(t *testing.T) {
+	skillsDir := t.TempDir()
+	for _, name := range []string{"broken-skill", "working-skill"} {
+		writeTestSkill(t, skillsDir, name, "", fmt.Sprintf("Follow the %s instructions.", name))
+	}
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "missing_tool", "{}"),
+		toolCallResponse("call-2", "missing_tool", "{}"),
+		toolCallResponse("call-3", "missing_tool", "{}"),
+		textResponse("working-skill"), // re-selection
+		toolCallResponse("call-4", "run_shell_code", `{"code": "echo recovered"}`),
+		textResponse("done"),
+	}, nil)
+
+	agent := &Agent{
+		client: mockClient,
+		cfg: RunnerConfig{
+			Model:                  "test-model",
+			SkillsDir:              skillsDir,
+			AutoApproveTools:       true,
+			SkillFallbackThreshold: 3,
+		},
+	}
+	broken, err := ParseSkillPackage(filepath.Join(skillsDir, "broken-skill"))
+	require.NoError(t, err)
+
+	result, err := agent.executeSkillWithTools(context.Background(), "do the thing", broken)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, "working-skill", agent.activeSkill)
+	require.Len(t, mockClient.requests, 6)
+
+	// The failed skill is excluded from re-selection, which sees the errors
+	selection := mockClient.requests[3].Messages[1].Content
+	assert.Contains(t, selection, "the previous skill failed with these errors")
+	assert.Contains(t, selection, "- missing_tool: unknown tool\n")
+	assert.NotContains(t, selection, "broken-skill")
+
+	// The new skill's instructions and an explanation are added to the conversation
+	next := mockClient.requests[4].Messages
+	require.GreaterOrEqual(t, len(next), 2)
+	assert.Equal(t, openai.ChatMessageRoleSystem, next[len(next)-2].Role)
+	assert.Contains(t, next[len(next)-2].Content, "Follow the working-skill instructions.")
+	assert.Equal(t, openai.ChatMessageRoleUser, next[len(next)-1].Role)
+	assert.Contains(t, next[len(next)-1].Content, "The skill 'broken-skill' failed 3 times in a row, so the skill 'working-skill' was selected instead.")
+	assert.Contains(t, next[len(next)-1].Content, "do the thing")
+}
+
+func TestContinueSkillWithTools_SkillFallbackNeedsConsecutiveErrors(t *testing.T) {
+	skillsDir := t.TempDir()
+	for _, name := range []string{"flaky-skill", "other-skill"} {
+		writeTestSkill(t, skillsDir, name, "", fmt.Sprintf("Follow the %s instructions.", name))
+	}
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "missing_tool", "{}"),
+		toolCallResponse("call-2", "missing_tool", "{}"),
+		toolCallResponse("call-3", "run_shell_code", `{"code": "echo ok"}`),
+		toolCallResponse("call-4", "missing_tool", "{}"),
+		toolCallResponse("call-5", "missing_tool", "{}"),
+		textResponse("done"),
+	}, nil)
+
+	agent := &Agent{
+		client: mockClient,
+		cfg: RunnerConfig{
+			Model:                  "test-model",
+			SkillsDir:              skillsDir,
+			AutoApproveTools:       true,
+			SkillFallbackThreshold: 3,
+		},
+	}
+	flaky, err := ParseSkillPackage(filepath.Join(skillsDir, "flaky-skill"))
+	require.NoError(t, err)
+
+	result, err := agent.executeSkillWithTools(context.Background(), "do the thing", flaky)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, "flaky-skill", agent.activeSkill)
+	assert.Len(t, mockClient.requests, 6)
+}
+
+func TestShouldFallBack(t *testing.T) {
+	agent := &Agent{cfg: RunnerConfig{SkillFallbackThreshold: 3}}
+	assert.False(t, agent.shouldFallBack(2))
+	assert.True(t, agent.shouldFallBack(3))
+
+	agent.cfg.SkillName = "forced"
+	assert.False(t, agent.shouldFallBack(3))
+
+	agent = &Agent{cfg: RunnerConfig{SkillFallbackThreshold: -1}}
+	assert.False(t, agent.shouldFallBack(10))
+
+	newAgent, err := NewAgent(RunnerConfig{APIKey: "test-key"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSkillFallbackThreshold, newAgent.cfg.SkillFallbackThreshold)
+}
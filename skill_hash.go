@@ -0,0 +1,61 @@
+package goskills
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Hash computes a deterministic SHA-256 digest of the skill package for integrity
+// verification. It covers the frontmatter (re-encoded as YAML so formatting and key
+// order do not matter), the body, and the path and content of every script, reference,
+// asset and template file, visited in sorted path order. The digest is returned as a
+// lowercase hex string.
+func (s *SkillPackage) Hash() (string, error) {
+	meta, err := yaml.Marshal(s.Meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode skill metadata: %w", err)
+	}
+
+	h := sha256.New()
+	writeHashField(h, "meta", meta)
+	writeHashField(h, "body", []byte(s.Body))
+
+	var files []string
+	files = append(files, s.Resources.Scripts...)
+	files = append(files, s.Resources.References...)
+	files = append(files, s.Resources.Assets...)
+	files = append(files, s.Resources.Templates...)
+	for i, f := range files {
+		files[i] = filepath.ToSlash(f)
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(s.Path, filepath.FromSlash(f)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", f, err)
+		}
+		writeHashField(h, "path", []byte(f))
+		writeHashField(h, "file", content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeHashField writes a labelled, length-prefixed field so that distinct inputs can
+// never produce the same byte stream.
+func writeHashField(h hash.Hash, label string, data []byte) {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(len(data)))
+	h.Write([]byte(label))
+	h.Write(size[:])
+	h.Write(data)
+}
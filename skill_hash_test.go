@@ -0,0 +1,94 @@
+package goskills
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeHashSkill creates a skill with a script, a reference and an asset.
+func writeHashSkill(t *testing.T) string {
+	t.Helper()
+	dir := writeTestSkill(t, t.TempDir(), "hashed", "version: 1.0.0\n", "# Hashed\n\nDo the thing.\n")
+	for path, content := range map[string]string{
+		"scripts/run.sh":     "#!/bin/sh\necho run\n",
+		"references/api.md":  "# API\n",
+		"assets/logo.txt":    "logo",
+		"scripts/lib/mod.py": "x = 1\n",
+	} {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	return dir
+}
+
+func hashSkillDir(t *testing.T, dir string) string {
+	t.Helper()
+	pkg, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	hash, err := pkg.Hash()
+	require.NoError(t, err)
+	return hash
+}
+
+func TestSkillPackageHash_Stable(t *testing.T) {
+	dir := writeHashSkill(t)
+
+	first := hashSkillDir(t, dir)
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, hashSkillDir(t, dir))
+	assert.Equal(t, first, hashSkillDir(t, dir))
+}
+
+func TestSkillPackageHash_DetectsChanges(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(t *testing.T, dir string)
+	}{
+		{"script content changed", func(t *testing.T, dir string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("#!/bin/sh\nrm -rf /\n"), 0644))
+		}},
+		{"script added", func(t *testing.T, dir string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "extra.sh"), []byte("echo extra\n"), 0644))
+		}},
+		{"reference changed", func(t *testing.T, dir string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "references", "api.md"), []byte("# API v2\n"), 0644))
+		}},
+		{"asset added", func(t *testing.T, dir string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "icon.txt"), []byte("icon"), 0644))
+		}},
+		{"asset renamed", func(t *testing.T, dir string) {
+			require.NoError(t, os.Rename(filepath.Join(dir, "assets", "logo.txt"), filepath.Join(dir, "assets", "logo2.txt")))
+		}},
+		{"body changed", func(t *testing.T, dir string) {
+			content := "---\nname: hashed\ndescription: The hashed skill\nversion: 1.0.0\n---\n# Hashed\n\nDo another thing.\n"
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+		}},
+		{"frontmatter changed", func(t *testing.T, dir string) {
+			content := "---\nname: hashed\ndescription: The hashed skill\nversion: 1.0.1\n---\n# Hashed\n\nDo the thing.\n"
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeHashSkill(t)
+			before := hashSkillDir(t, dir)
+			tc.modify(t, dir)
+			assert.NotEqual(t, before, hashSkillDir(t, dir))
+		})
+	}
+}
+
+func TestSkillPackageHash_IgnoresFrontmatterFormatting(t *testing.T) {
+	dir := writeHashSkill(t)
+	before := hashSkillDir(t, dir)
+
+	content := "---\nversion: \"1.0.0\"\ndescription:   The hashed skill\nname: hashed\n---\n# Hashed\n\nDo the thing.\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644))
+	assert.Equal(t, before, hashSkillDir(t, dir))
+}
@@ -0,0 +1,44 @@
+package goskills
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SkillInfo is the metadata of the active skill returned by the skill_info tool.
+// Resource paths are relative to the skill directory.
+type SkillInfo struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Version      string   `json:"version"`
+	Scripts      []string `json:"scripts"`
+	References   []string `json:"references"`
+	Assets       []string `json:"assets"`
+	AllowedTools []string `json:"allowed_tools"`
+}
+
+// skillInfo returns the metadata of skill as JSON. Empty lists are encoded as [].
+func skillInfo(skill *SkillPackage) (string, error) {
+	info := SkillInfo{
+		Name:         skill.Meta.Name,
+		Description:  skill.Meta.Description,
+		Version:      skill.Meta.Version,
+		Scripts:      nonNil(skill.Resources.Scripts),
+		References:   nonNil(skill.Resources.References),
+		Assets:       nonNil(skill.Resources.Assets),
+		AllowedTools: nonNil(skill.Meta.AllowedTools),
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal skill info: %w", err)
+	}
+	return string(data), nil
+}
+
+// nonNil returns s, or an empty slice if s is nil.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
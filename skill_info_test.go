@@ -0,0 +1,74 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	skerrors "github.com/smallnest/goskills/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteSkillWithTools_SkillInfo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pdf-tools")
+	for _, sub := range []string{"scripts", "references", "assets"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: pdf-tools\ndescription: Works with PDF files.\nversion: 1.2.0\nallowed-tools: [read_file]\n---\nUse the scripts."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "extract.py"), []byte("print('hi')\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "references", "spec.md"), []byte("# Spec"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "logo.png"), []byte("png"), 0644))
+	skill, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "skill_info", "{}"),
+		textResponse("done"),
+	}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", AutoApproveTools: true}}
+	_, err = agent.executeSkillWithTools(context.Background(), "what skill is this?", skill)
+	require.NoError(t, err)
+	require.Len(t, mockClient.requests, 2)
+
+	// skill_info is offered although allowed-tools does not list it
+	var offered []string
+	for _, def := range mockClient.requests[0].Tools {
+		offered = append(offered, def.Function.Name)
+	}
+	assert.Contains(t, offered, "skill_info")
+
+	messages := mockClient.requests[1].Messages
+	var info map[string]any
+	require.NoError(t, json.Unmarshal([]byte(messages[len(messages)-1].Content), &info))
+	assert.Equal(t, map[string]any{
+		"name":          "pdf-tools",
+		"description":   "Works with PDF files.",
+		"version":       "1.2.0",
+		"scripts":       []any{"scripts/extract.py"},
+		"references":    []any{"references/spec.md"},
+		"assets":        []any{"assets/logo.png"},
+		"allowed_tools": []any{"read_file"},
+	}, info)
+}
+
+func TestSkillInfo_Empty(t *testing.T) {
+	output, err := skillInfo(&SkillPackage{Meta: SkillMeta{Name: "bare"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "bare", "description": "", "version": "", "scripts": [], "references": [], "assets": [], "allowed_tools": []}`, output)
+}
+
+func TestExecuteToolCall_SkillInfoWithoutSkill(t *testing.T) {
+	agent := &Agent{cfg: RunnerConfig{Model: "test-model"}}
+	_, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "skill_info", Arguments: "{}"},
+	}, nil, nil)
+
+	var toolErr *skerrors.ToolError
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, "skill_info", toolErr.ToolName)
+	assert.Contains(t, err.Error(), "no active skill")
+}
@@ -3,12 +3,15 @@ package goskills
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,17 +21,38 @@ type SkillPackage struct {
 	Meta      SkillMeta      `json:"meta"`
 	Body      string         `json:"body"` // Raw Markdown content of SKILL.md body
 	Resources SkillResources `json:"resources"`
+
+	openBody func() (io.ReadCloser, error) // Streams the body from disk; set by ParseSkillPackageLazy
 }
 
 // SkillMeta corresponds to the content of SKILL.md frontmatter
 type SkillMeta struct {
-	Name         string   `yaml:"name"`
-	Description  string   `yaml:"description"`
-	AllowedTools []string `yaml:"allowed-tools"`
-	Model        string   `yaml:"model,omitempty"`
-	Author       string   `yaml:"author,omitempty"`
-	Version      string   `yaml:"version,omitempty"`
-	License      string   `yaml:"license,omitempty"`
+	Name          string                  `yaml:"name"`
+	Description   string                  `yaml:"description"`
+	AllowedTools  []string                `yaml:"allowed-tools"`
+	Model         string                  `yaml:"model,omitempty"`
+	Author        string                  `yaml:"author,omitempty"`
+	Version       string                  `yaml:"version,omitempty"`
+	License       string                  `yaml:"license,omitempty"`
+	Tags          []string                `yaml:"tags,omitempty"`
+	ToolOverrides map[string]ToolOverride `yaml:"tool-overrides,omitempty"`
+	Tools         []InlineTool            `yaml:"tools,omitempty"`
+	RequiredVars  []string                `yaml:"required-vars,omitempty"` // Variables that Template must be given for {{skill_var:KEY}} placeholders
+	Performance   *SkillPerformance       `yaml:"performance,omitempty"`   // Declared typical execution cost; nil when not declared
+}
+
+// InlineTool is a tool declared directly in SKILL.md frontmatter. Command is a shell
+// command template whose {{.param}} placeholders are filled with the call's arguments.
+type InlineTool struct {
+	Name        string         `yaml:"name" json:"name"`
+	Description string         `yaml:"description" json:"description"`
+	Parameters  map[string]any `yaml:"parameters,omitempty" json:"parameters,omitempty"` // JSON schema of the arguments
+	Command     string         `yaml:"command" json:"command"`
+}
+
+// ToolOverride customizes how a tool is presented to the LLM for a single skill
+type ToolOverride struct {
+	Description string `yaml:"description,omitempty"`
 }
 
 // SkillResources lists the relevant resource files in the skill package
@@ -41,32 +65,63 @@ type SkillResources struct {
 
 // extractFrontmatterAndBody separates and parses the frontmatter and body of SKILL.md
 func extractFrontmatterAndBody(data []byte) (SkillMeta, string, error) {
-	marker := []byte("---")
 	var meta SkillMeta
-	var body string
 
-	// Check if content starts with frontmatter marker
-	content := string(data)
-	if !strings.HasPrefix(strings.TrimSpace(content), "---") {
-		return meta, "", fmt.Errorf("no YAML frontmatter found or format is incorrect")
-	}
-
-	parts := bytes.SplitN(data, marker, 3)
-	if len(parts) < 3 {
-		return meta, "", fmt.Errorf("no YAML frontmatter found or format is incorrect")
+	frontmatter, body, err := splitFrontmatter(data)
+	if err != nil {
+		return meta, "", err
 	}
 
 	// Parse frontmatter
-	if err := yaml.Unmarshal(parts[1], &meta); err != nil {
+	if err := yaml.Unmarshal(frontmatter, &meta); err != nil {
 		return meta, "", fmt.Errorf("failed to parse SKILL.md frontmatter: %w", err)
 	}
 
-	// Extract body
-	body = strings.TrimSpace(string(parts[2]))
-
 	return meta, body, nil
 }
 
+// splitFrontmatter separates SKILL.md into its frontmatter and body. The frontmatter is
+// YAML delimited by "---", or TOML delimited by "+++"; TOML is converted to YAML so that
+// both formats map onto SkillMeta through the same field names.
+func splitFrontmatter(data []byte) ([]byte, string, error) {
+	content := strings.TrimSpace(string(data))
+	if strings.HasPrefix(content, "+++") {
+		parts := bytes.SplitN(data, []byte("+++"), 3)
+		if len(parts) < 3 {
+			return nil, "", fmt.Errorf("no TOML frontmatter found or format is incorrect")
+		}
+		frontmatter, err := tomlToYAML(parts[1])
+		if err != nil {
+			return nil, "", err
+		}
+		return frontmatter, strings.TrimSpace(string(parts[2])), nil
+	}
+
+	// Check if content starts with frontmatter marker
+	if !strings.HasPrefix(content, "---") {
+		return nil, "", fmt.Errorf("no YAML frontmatter found or format is incorrect")
+	}
+
+	parts := bytes.SplitN(data, []byte("---"), 3)
+	if len(parts) < 3 {
+		return nil, "", fmt.Errorf("no YAML frontmatter found or format is incorrect")
+	}
+	return parts[1], strings.TrimSpace(string(parts[2])), nil
+}
+
+// tomlToYAML re-encodes TOML frontmatter as YAML.
+func tomlToYAML(frontmatter []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(frontmatter, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse SKILL.md TOML frontmatter: %w", err)
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert SKILL.md TOML frontmatter: %w", err)
+	}
+	return data, nil
+}
+
 // parseOpenAISkill parses an OpenAI skill.md file without frontmatter
 // The skill name comes from the directory name
 // The description is extracted from between the first # heading and the first ## heading
@@ -168,6 +223,12 @@ func inferAllowedTools(content, skillName string) []string {
 		tools = append(tools, "run_shell_code")
 	}
 
+	// Check for JavaScript/TypeScript needs
+	if strings.Contains(content, "javascript") || strings.Contains(content, "typescript") ||
+		strings.Contains(content, "node.js") || strings.Contains(content, "npm") {
+		tools = append(tools, "run_node_code", "run_node_script")
+	}
+
 	// Check for web/data fetching needs
 	if strings.Contains(content, "fetch") || strings.Contains(content, "search") ||
 		strings.Contains(content, "web") || strings.Contains(content, "api") {
@@ -223,6 +284,18 @@ func findResourceFiles(skillPath, resourceDir string) ([]string, error) {
 
 // ParseSkillPackage finely parses the Skill package in the given directory path
 func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
+	return parseSkillPackage(dirPath, false)
+}
+
+// ParseSkillPackageLazy parses the Skill package like ParseSkillPackage, but reads only
+// the frontmatter of SKILL.md: Body is left empty and OpenBody streams the body from
+// disk when it is needed. skill.md packages, whose metadata is derived from the body,
+// are always loaded eagerly.
+func ParseSkillPackageLazy(dirPath string) (*SkillPackage, error) {
+	return parseSkillPackage(dirPath, true)
+}
+
+func parseSkillPackage(dirPath string, lazy bool) (*SkillPackage, error) {
 	info, err := os.Stat(dirPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -238,6 +311,8 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 	var meta SkillMeta
 	var bodyStr string
 	var mdContent []byte
+	var frontmatter []byte
+	var openBody func() (io.ReadCloser, error)
 
 	// Check what files actually exist (to handle case-insensitive filesystems)
 	entries, err := os.ReadDir(dirPath)
@@ -262,13 +337,26 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 	if hasClaudeSkill {
 		// Claude skill format with frontmatter
 		skillMdPath := filepath.Join(dirPath, "SKILL.md")
-		mdContent, err = os.ReadFile(skillMdPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
-		}
-		meta, bodyStr, err = extractFrontmatterAndBody(mdContent)
-		if err != nil {
-			return nil, err
+		if lazy {
+			var bodyOffset int64
+			frontmatter, bodyOffset, err = readFrontmatter(skillMdPath)
+			if err != nil {
+				return nil, err
+			}
+			if err := yaml.Unmarshal(frontmatter, &meta); err != nil {
+				return nil, fmt.Errorf("failed to parse SKILL.md frontmatter: %w", err)
+			}
+			openBody = fileBodyOpener(skillMdPath, bodyOffset)
+		} else {
+			mdContent, err = os.ReadFile(skillMdPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+			}
+			meta, bodyStr, err = extractFrontmatterAndBody(mdContent)
+			if err != nil {
+				return nil, err
+			}
+			frontmatter, _, _ = splitFrontmatter(mdContent)
 		}
 	} else if hasOpenAISkill {
 		// OpenAI skill format without frontmatter
@@ -281,10 +369,19 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 		if err != nil {
 			return nil, err
 		}
+		// Validate the derived metadata since there is no frontmatter
+		frontmatter, err = yaml.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		return nil, fmt.Errorf("neither SKILL.md nor skill.md found in skill directory: %s", dirPath)
 	}
 
+	if err := validateFrontmatterSchema(dirPath, frontmatter); err != nil {
+		return nil, err
+	}
+
 	// 2. Find resource files
 	scripts, err := findResourceFiles(dirPath, "scripts")
 	if err != nil {
@@ -314,6 +411,20 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 			Assets:     assets,
 			Templates:  templates,
 		},
+		openBody: openBody,
+	}
+
+	if err := validateInlineTools(pkg); err != nil {
+		return nil, err
+	}
+	if err := validateToolOverrides(pkg); err != nil {
+		return nil, err
+	}
+	if err := validateRequiredVars(pkg); err != nil {
+		return nil, err
+	}
+	if err := validatePerformance(pkg); err != nil {
+		return nil, err
 	}
 
 	return pkg, nil
@@ -325,6 +436,16 @@ func ParseSkillPackage(dirPath string) (*SkillPackage, error) {
 // It returns a slice of successfully parsed SkillPackage objects.
 
 func ParseSkillPackages(rootDir string) ([]*SkillPackage, error) {
+	return parseSkillPackages(rootDir, false)
+}
+
+// ParseSkillPackagesLazy finds all skill packages like ParseSkillPackages, but parses
+// them with ParseSkillPackageLazy so their bodies stay on disk until they are needed.
+func ParseSkillPackagesLazy(rootDir string) ([]*SkillPackage, error) {
+	return parseSkillPackages(rootDir, true)
+}
+
+func parseSkillPackages(rootDir string, lazy bool) ([]*SkillPackage, error) {
 	skillDirs := make(map[string]struct{})
 
 	walkErr := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
@@ -346,7 +467,7 @@ func ParseSkillPackages(rootDir string) ([]*SkillPackage, error) {
 
 	var packages []*SkillPackage
 	for dir := range skillDirs {
-		pkg, err := ParseSkillPackage(dir)
+		pkg, err := parseSkillPackage(dir, lazy)
 		if err == nil {
 			packages = append(packages, pkg)
 		}
@@ -373,6 +494,12 @@ func SkillsToPrompt(skills map[string]SkillPackage) string {
 	builder.WriteString("Important:\n")
 	builder.WriteString("- Only use skills listed in <available_skills> below\n")
 	builder.WriteString("- Do not invoke a skill that is already running\n")
+	for _, skill := range skills {
+		if performanceHint(skill.Meta) != "" {
+			builder.WriteString("- <performance> gives a skill's typical cost; when several skills fit the task, weigh it and prefer the cheaper skill\n")
+			break
+		}
+	}
 	builder.WriteString("</skills_instructions>\n\n")
 
 	// Add available skills section
@@ -382,6 +509,12 @@ func SkillsToPrompt(skills map[string]SkillPackage) string {
 		builder.WriteString("<skill>\n")
 		builder.WriteString(fmt.Sprintf("<name>%s</name>\n", skill.Meta.Name))
 		builder.WriteString(fmt.Sprintf("<description>%s</description>\n", skill.Meta.Description))
+		if len(skill.Meta.Tags) > 0 {
+			builder.WriteString(fmt.Sprintf("<tags>%s</tags>\n", strings.Join(skill.Meta.Tags, ", ")))
+		}
+		if hint := performanceHint(skill.Meta); hint != "" {
+			builder.WriteString(fmt.Sprintf("<performance>%s</performance>\n", hint))
+		}
 		builder.WriteString("<location>plugin</location>\n")
 		builder.WriteString("</skill>\n\n")
 	}
@@ -390,3 +523,102 @@ func SkillsToPrompt(skills map[string]SkillPackage) string {
 
 	return builder.String()
 }
+
+// estimateTokens approximates the token count of s by counting whitespace-separated words.
+func estimateTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+// SkillsToCompactPrompt renders skills as one "name: description" line each, sorted by
+// name, truncating descriptions so the result stays within maxTokens as measured by a
+// whitespace-based estimate. A maxTokens of zero or less disables truncation.
+func SkillsToCompactPrompt(skills map[string]SkillPackage, maxTokens int) string {
+	return SkillsToCompactPromptForRequest(skills, maxTokens, "")
+}
+
+// SkillsToCompactPromptForRequest is like SkillsToCompactPrompt, but skills whose names
+// appear in userPrompt are listed first and keep their full descriptions for as long as
+// the budget allows, since they are likely to be relevant.
+func SkillsToCompactPromptForRequest(skills map[string]SkillPackage, maxTokens int, userPrompt string) string {
+	type entry struct {
+		name     string
+		words    []string
+		keep     int
+		relevant bool
+	}
+
+	lowerPrompt := strings.ToLower(userPrompt)
+	entries := make([]*entry, 0, len(skills))
+	for name, skill := range skills {
+		lowerName := strings.ToLower(name)
+		relevant := lowerPrompt != "" && (strings.Contains(lowerPrompt, lowerName) ||
+			strings.Contains(lowerPrompt, strings.NewReplacer("-", " ", "_", " ").Replace(lowerName)))
+		words := strings.Fields(skill.Meta.Description)
+		entries = append(entries, &entry{name: name, words: words, keep: len(words), relevant: relevant})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].relevant != entries[j].relevant {
+			return entries[i].relevant
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	total, nameCost := 0, 0
+	for _, e := range entries {
+		nameCost += estimateTokens(e.name)
+		total += estimateTokens(e.name) + len(e.words)
+	}
+
+	if maxTokens > 0 && total > maxTokens {
+		budget := maxTokens - nameCost
+		if budget < 0 {
+			// Not even the names fit: list as many names as possible, relevant ones first
+			var builder strings.Builder
+			used := 0
+			for _, e := range entries {
+				cost := estimateTokens(e.name)
+				if used+cost > maxTokens {
+					break
+				}
+				used += cost
+				builder.WriteString(e.name + ":\n")
+			}
+			return strings.TrimSuffix(builder.String(), "\n")
+		}
+
+		// Relevant skills keep their full description while the budget lasts
+		var others []*entry
+		for _, e := range entries {
+			if e.relevant {
+				e.keep = min(len(e.words), budget)
+				budget -= e.keep
+			} else {
+				others = append(others, e)
+			}
+		}
+
+		// Share what is left evenly among the remaining skills, giving unused
+		// allowance from short descriptions to longer ones
+		sort.SliceStable(others, func(i, j int) bool { return len(others[i].words) < len(others[j].words) })
+		for i, e := range others {
+			share := budget / (len(others) - i)
+			e.keep = min(len(e.words), share)
+			budget -= e.keep
+		}
+	}
+
+	var builder strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(e.name + ":")
+		if e.keep > 0 {
+			builder.WriteString(" " + strings.Join(e.words[:e.keep], " "))
+		}
+		if e.keep < len(e.words) {
+			builder.WriteString("...")
+		}
+	}
+	return builder.String()
+}
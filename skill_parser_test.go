@@ -356,4 +356,320 @@ func TestInferAllowedTools(t *testing.T) {
 	assert.Contains(t, tools, "web_fetch")
 	assert.Contains(t, tools, "tavily_search")
 	assert.Contains(t, tools, "wikipedia_search")
+
+	// Test JavaScript/TypeScript skill
+	tools = inferAllowedTools("build the bundle with npm and TypeScript", "bundler")
+	assert.Contains(t, tools, "run_node_code")
+	assert.Contains(t, tools, "run_node_script")
+	assert.NotContains(t, inferAllowedTools("convert PDF files", "pdf"), "run_node_code")
+}
+
+func TestParseSkillPackage_Tags(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "tagged-skill")
+	require.NoError(t, os.Mkdir(skillPath, 0755))
+
+	skillContent := `---
+name: tagged-skill
+description: A skill with tags.
+tags: [data, python, pdf]
+---
+Body.`
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644))
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data", "python", "pdf"}, pkg.Meta.Tags)
+}
+
+func TestParseSkillPackage_ToolOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "csv-analyst")
+	require.NoError(t, os.Mkdir(skillPath, 0755))
+
+	skillContent := `---
+name: csv-analyst
+description: Analyses CSV files.
+tool-overrides:
+  run_python_code:
+    description: Use run_python_code to analyse the CSV using pandas.
+---
+Body.`
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644))
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Use run_python_code to analyse the CSV using pandas.", pkg.Meta.ToolOverrides["run_python_code"].Description)
+
+	tools, _ := GenerateToolDefinitions(pkg)
+	for _, tool := range tools {
+		if tool.Function.Name == "run_python_code" {
+			assert.Equal(t, "Use run_python_code to analyse the CSV using pandas.", tool.Function.Description)
+		}
+	}
+
+	// Overrides for unknown tools are rejected
+	invalid := strings.Replace(skillContent, "run_python_code:", "run_cobol_code:", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(invalid), 0644))
+	_, err = ParseSkillPackage(skillPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "run_cobol_code")
+}
+
+func TestParseSkillPackage_InlineTools(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "greeter")
+	require.NoError(t, os.Mkdir(skillPath, 0755))
+
+	skillContent := `---
+name: greeter
+description: Greets people.
+tools:
+  - name: greet
+    description: Prints a greeting.
+    parameters:
+      type: object
+      properties:
+        name:
+          type: string
+          description: Who to greet.
+      required: [name]
+    command: echo "Hello, {{.name}}!"
+  - name: list_files
+    description: Lists the current directory.
+    command: ls
+---
+Body.`
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644))
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+	require.Len(t, pkg.Meta.Tools, 2)
+	greet := pkg.Meta.Tools[0]
+	assert.Equal(t, "greet", greet.Name)
+	assert.Equal(t, "Prints a greeting.", greet.Description)
+	assert.Equal(t, `echo "Hello, {{.name}}!"`, greet.Command)
+	assert.Equal(t, "object", greet.Parameters["type"])
+	assert.Contains(t, greet.Parameters["properties"], "name")
+	assert.Nil(t, pkg.Meta.Tools[1].Parameters)
+
+	testCases := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "duplicate names",
+			content: strings.Replace(skillContent, "name: list_files", "name: greet", 1),
+			errMsg:  "duplicate tool name 'greet'",
+		},
+		{
+			name:    "conflicts with base tool",
+			content: strings.Replace(skillContent, "name: list_files", "name: read_file", 1),
+			errMsg:  "conflicts with an existing tool",
+		},
+		{
+			name:    "missing command",
+			content: strings.Replace(skillContent, "    command: ls\n", "", 1),
+			errMsg:  "tool 'list_files' has no command",
+		},
+		{
+			name:    "invalid template",
+			content: strings.Replace(skillContent, "command: ls", "command: ls {{.dir", 1),
+			errMsg:  "invalid command template",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(tc.content), 0644))
+			_, err := ParseSkillPackage(skillPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestSkillsToPrompt_Tags(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"tagged": {
+			Meta: SkillMeta{
+				Name:        "tagged",
+				Description: "A tagged skill",
+				Tags:        []string{"data", "python"},
+			},
+		},
+		"plain": {
+			Meta: SkillMeta{
+				Name:        "plain",
+				Description: "A skill without tags",
+			},
+		},
+	}
+
+	prompt := SkillsToPrompt(skills)
+	assert.Contains(t, prompt, "<tags>data, python</tags>")
+	assert.Equal(t, 1, strings.Count(prompt, "<tags>"))
+}
+
+func TestSkillsToCompactPrompt(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"pdf":           {Meta: SkillMeta{Name: "pdf", Description: "Extract text and tables from PDF files, fill forms, and merge documents."}},
+		"xlsx":          {Meta: SkillMeta{Name: "xlsx", Description: "Create, edit and analyze spreadsheets with formulas and charts."}},
+		"slack-gif":     {Meta: SkillMeta{Name: "slack-gif", Description: "Create animated GIFs optimized for Slack."}},
+		"canvas-design": {Meta: SkillMeta{Name: "canvas-design", Description: "Create beautiful visual art in png and pdf documents using design philosophy."}},
+	}
+
+	// Without a budget every description is kept in full
+	full := SkillsToCompactPrompt(skills, 0)
+	assert.Contains(t, full, "pdf: Extract text and tables from PDF files, fill forms, and merge documents.")
+	assert.Equal(t, 4, strings.Count(full, "\n")+1)
+	assert.True(t, strings.HasPrefix(full, "canvas-design:"), "skills should be sorted by name")
+
+	for _, budget := range []int{40, 20, 10, 6} {
+		prompt := SkillsToCompactPrompt(skills, budget)
+		assert.LessOrEqual(t, estimateTokens(prompt), budget, "budget %d exceeded:\n%s", budget, prompt)
+		for name := range skills {
+			assert.Contains(t, prompt, name+":")
+		}
+	}
+
+	// A budget smaller than the names alone lists as many names as fit
+	tiny := SkillsToCompactPrompt(skills, 2)
+	assert.LessOrEqual(t, estimateTokens(tiny), 2)
+}
+
+func TestSkillsToCompactPromptForRequest_PrefersRelevantSkills(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"pdf":           {Meta: SkillMeta{Name: "pdf", Description: "Extract text and tables from PDF files, fill forms, and merge documents."}},
+		"xlsx":          {Meta: SkillMeta{Name: "xlsx", Description: "Create, edit and analyze spreadsheets with formulas and charts."}},
+		"slack-gif":     {Meta: SkillMeta{Name: "slack-gif", Description: "Create animated GIFs optimized for Slack."}},
+		"canvas-design": {Meta: SkillMeta{Name: "canvas-design", Description: "Create beautiful visual art in png and pdf documents using design philosophy."}},
+	}
+
+	budget := 20
+	prompt := SkillsToCompactPromptForRequest(skills, budget, "Use the canvas design skill to draw a poster")
+	assert.LessOrEqual(t, estimateTokens(prompt), budget)
+
+	lines := strings.Split(prompt, "\n")
+	assert.Equal(t, "canvas-design: Create beautiful visual art in png and pdf documents using design philosophy.", lines[0])
+	for _, line := range lines[1:] {
+		assert.True(t, strings.HasSuffix(line, "..."), "non-relevant skill should be truncated: %s", line)
+	}
+}
+
+func TestParseSkillPackage_Schema(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("testdata", "schema", "skill.schema.json"))
+	require.NoError(t, err)
+
+	writeSkill := func(t *testing.T, frontmatter string) string {
+		skillPath := filepath.Join(t.TempDir(), "schema-skill")
+		require.NoError(t, os.Mkdir(skillPath, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte("---\n"+frontmatter+"---\nBody."), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, SkillSchemaFile), schema, 0644))
+		return skillPath
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		pkg, err := ParseSkillPackage(writeSkill(t, "name: schema-skill\ndescription: Validated.\ntags: [a, b]\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "schema-skill", pkg.Meta.Name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseSkillPackage(writeSkill(t, "name: Schema Skill\ntags: [a, a]\nversion: 2\n"))
+		require.Error(t, err)
+
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Len(t, verr.Violations, 4)
+		assert.Contains(t, err.Error(), "/: missing properties: 'description'")
+		assert.Contains(t, err.Error(), "/name: does not match pattern")
+		assert.Contains(t, err.Error(), "/tags: items at index 0 and 1 are equal")
+		assert.Contains(t, err.Error(), "/version: expected string, but got number")
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		skillPath := writeSkill(t, "name: schema-skill\ndescription: Validated.\n")
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, SkillSchemaFile), []byte(`{"type": 5}`), 0644))
+		_, err := ParseSkillPackage(skillPath)
+		assert.ErrorContains(t, err, "invalid schema.json")
+	})
+}
+
+func TestParseSkillPackage_TOMLFrontmatter(t *testing.T) {
+	tmpDir := t.TempDir()
+	skillPath := filepath.Join(tmpDir, "toml-skill")
+	require.NoError(t, os.Mkdir(skillPath, 0755))
+
+	skillContent := `+++
+name = "toml-skill"
+description = "A skill with TOML frontmatter."
+allowed-tools = ["read_file", "write_file"]
+model = "gpt-4"
+author = "Gemini"
+version = "0.2.0"
+license = "MIT"
+tags = ["docs", "toml"]
+
+[tool-overrides.read_file]
+description = "Reads a TOML file."
+
+[[tools]]
+name = "greet"
+description = "Prints a greeting."
+command = 'echo "Hello, {{.name}}!"'
+
+[tools.parameters]
+type = "object"
+required = ["name"]
+
+[tools.parameters.properties.name]
+type = "string"
++++
+# TOML Skill
+
+Body with a --- rule inside.
+`
+	require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(skillContent), 0644))
+
+	pkg, err := ParseSkillPackage(skillPath)
+	require.NoError(t, err)
+	assert.Equal(t, SkillMeta{
+		Name:          "toml-skill",
+		Description:   "A skill with TOML frontmatter.",
+		AllowedTools:  []string{"read_file", "write_file"},
+		Model:         "gpt-4",
+		Author:        "Gemini",
+		Version:       "0.2.0",
+		License:       "MIT",
+		Tags:          []string{"docs", "toml"},
+		ToolOverrides: map[string]ToolOverride{"read_file": {Description: "Reads a TOML file."}},
+		Tools: []InlineTool{{
+			Name:        "greet",
+			Description: "Prints a greeting.",
+			Command:     `echo "Hello, {{.name}}!"`,
+			Parameters: map[string]any{
+				"type":       "object",
+				"required":   []any{"name"},
+				"properties": map[string]any{"name": map[string]any{"type": "string"}},
+			},
+		}},
+	}, pkg.Meta)
+	assert.Equal(t, "# TOML Skill\n\nBody with a --- rule inside.", pkg.Body)
+
+	t.Run("invalid TOML", func(t *testing.T) {
+		invalid := "+++\nname = \"toml-skill\"\ndescription = \n+++\nBody."
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte(invalid), 0644))
+		_, err := ParseSkillPackage(skillPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse SKILL.md TOML frontmatter")
+		assert.Contains(t, err.Error(), "line 3")
+	})
+
+	t.Run("unterminated", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(skillPath, "SKILL.md"), []byte("+++\nname = \"toml-skill\"\n"), 0644))
+		_, err := ParseSkillPackage(skillPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no TOML frontmatter found")
+	})
 }
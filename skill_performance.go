@@ -0,0 +1,80 @@
+package goskills
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SlowSkillDuration is the expected duration at or above which continueSkillWithTools
+// warns at Verbose >= 1 that a skill will be slow.
+const SlowSkillDuration = 30 * time.Second
+
+// SkillPerformance is a skill's declared typical execution cost, given in the
+// frontmatter's performance field. The LLM sees it when selecting a skill.
+type SkillPerformance struct {
+	ExpectedDuration string `yaml:"expected_duration,omitempty"` // Typical run time as a Go duration, e.g. "30s"
+	RequiresGPU      bool   `yaml:"requires_gpu,omitempty"`
+	TypicalTokens    int    `yaml:"typical_tokens,omitempty"` // Typical LLM tokens used by a run
+}
+
+// Duration returns ExpectedDuration parsed, or 0 when it is empty.
+func (p SkillPerformance) Duration() (time.Duration, error) {
+	if p.ExpectedDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(p.ExpectedDuration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid performance.expected_duration '%s': %w", p.ExpectedDuration, err)
+	}
+	return d, nil
+}
+
+// validatePerformance checks the frontmatter's performance field.
+func validatePerformance(skill *SkillPackage) error {
+	p := skill.Meta.Performance
+	if p == nil {
+		return nil
+	}
+	d, err := p.Duration()
+	if err != nil {
+		return err
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid performance.expected_duration '%s': must not be negative", p.ExpectedDuration)
+	}
+	if p.TypicalTokens < 0 {
+		return fmt.Errorf("invalid performance.typical_tokens %d: must not be negative", p.TypicalTokens)
+	}
+	return nil
+}
+
+// performanceHint describes the skill's declared cost for the selection prompt, or
+// returns "" when the skill declares none.
+func performanceHint(meta SkillMeta) string {
+	p := meta.Performance
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	if p.ExpectedDuration != "" {
+		parts = append(parts, "expected duration "+p.ExpectedDuration)
+	}
+	if p.RequiresGPU {
+		parts = append(parts, "requires a GPU")
+	}
+	if p.TypicalTokens > 0 {
+		parts = append(parts, fmt.Sprintf("about %d tokens", p.TypicalTokens))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isSlowSkill reports whether the skill declares an expected duration of at least
+// SlowSkillDuration, returning that duration.
+func isSlowSkill(skill *SkillPackage) (time.Duration, bool) {
+	if skill.Meta.Performance == nil {
+		return 0, false
+	}
+	d, err := skill.Meta.Performance.Duration()
+	return d, err == nil && d >= SlowSkillDuration
+}
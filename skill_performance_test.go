@@ -0,0 +1,79 @@
+package goskills
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSkillPackage_Performance(t *testing.T) {
+	dir := writeTestSkill(t, t.TempDir(), "train", `performance:
+  expected_duration: 2m
+  requires_gpu: true
+  typical_tokens: 5000
+`, "Train the model.")
+	skill, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &SkillPerformance{ExpectedDuration: "2m", RequiresGPU: true, TypicalTokens: 5000}, skill.Meta.Performance)
+	d, err := skill.Meta.Performance.Duration()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, d)
+
+	dir = writeTestSkill(t, t.TempDir(), "plain", "", "Body")
+	skill, err = ParseSkillPackage(dir)
+	require.NoError(t, err)
+	assert.Nil(t, skill.Meta.Performance)
+
+	for _, performance := range []string{"expected_duration: soon", "expected_duration: -1s", "typical_tokens: -5"} {
+		dir = writeTestSkill(t, t.TempDir(), "bad", "performance:\n  "+performance+"\n", "Body")
+		_, err = ParseSkillPackage(dir)
+		assert.ErrorContains(t, err, "invalid performance.", performance)
+	}
+}
+
+func TestSkillsToPrompt_PerformanceHint(t *testing.T) {
+	skills := map[string]SkillPackage{
+		"train": {Meta: SkillMeta{Name: "train", Description: "Trains models", Performance: &SkillPerformance{
+			ExpectedDuration: "30s", RequiresGPU: true, TypicalTokens: 5000,
+		}}},
+		"plain": {Meta: SkillMeta{Name: "plain", Description: "Answers questions"}},
+	}
+
+	prompt := SkillsToPrompt(skills)
+	assert.Contains(t, prompt, "<performance>expected duration 30s, requires a GPU, about 5000 tokens</performance>")
+	assert.Equal(t, 1, bytes.Count([]byte(prompt), []byte("</performance>")))
+	assert.Contains(t, prompt, "prefer the cheaper skill")
+
+	delete(skills, "train")
+	assert.NotContains(t, SkillsToPrompt(skills), "performance>")
+}
+
+func TestContinueSkillWithTools_SlowSkillWarning(t *testing.T) {
+	var logs bytes.Buffer
+	old := log.GetDefaultLogger()
+	log.SetDefaultLogger(log.NewCustomLogger(&logs, log.LogLevelInfo))
+	t.Cleanup(func() { log.SetDefaultLogger(old) })
+
+	run := func(expected string, verbose int) string {
+		logs.Reset()
+		skill := &SkillPackage{Meta: SkillMeta{Name: "train", Performance: &SkillPerformance{ExpectedDuration: expected}}}
+		agent := &Agent{
+			client: NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("done")}, nil),
+			cfg:    RunnerConfig{Model: "test-model", Verbose: verbose},
+		}
+		_, err := agent.continueSkillWithTools(context.Background(), "go", skill)
+		require.NoError(t, err)
+		return logs.String()
+	}
+
+	assert.Contains(t, run("2m", 1), "skill train is expected to take about 2m0s")
+	assert.Contains(t, run("30s", 1), "expected to take about 30s")
+	assert.NotContains(t, run("10s", 1), "expected to take")
+	assert.NotContains(t, run("2m", 0), "expected to take")
+}
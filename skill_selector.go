@@ -0,0 +1,330 @@
+package goskills
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Skill selection strategies for RunnerConfig.SelectionStrategy.
+const (
+	SelectionStrategyLLM        = "llm"        // Ask the LLM to pick a skill (default)
+	SelectionStrategyKeyword    = "keyword"    // BM25 keyword scoring, fully offline
+	SelectionStrategyEmbeddings = "embeddings" // Cosine similarity of embeddings
+)
+
+// SkillSelector picks the skill best suited to a prompt and returns its name.
+type SkillSelector interface {
+	Select(ctx context.Context, prompt string, skills map[string]SkillPackage) (string, error)
+}
+
+// newSkillSelector creates the selector for the agent's configured strategy.
+func newSkillSelector(a *Agent) (SkillSelector, error) {
+	if a.cfg.SkillSelector != nil {
+		return a.cfg.SkillSelector, nil
+	}
+	switch a.cfg.SelectionStrategy {
+	case "", SelectionStrategyLLM:
+		return llmSelector{agent: a}, nil
+	case SelectionStrategyKeyword:
+		return KeywordSelector{}, nil
+	case SelectionStrategyEmbeddings:
+		client, ok := a.client.(EmbeddingClient)
+		if !ok {
+			return nil, errors.New("the embeddings selection strategy requires a client that supports embeddings")
+		}
+		return NewEmbeddingSelector(client, openai.EmbeddingModel(a.cfg.EmbeddingModel), a.cfg.EmbeddingCachePath), nil
+	default:
+		return nil, fmt.Errorf("unknown selection strategy '%s' (expected %s, %s or %s)",
+			a.cfg.SelectionStrategy, SelectionStrategyLLM, SelectionStrategyKeyword, SelectionStrategyEmbeddings)
+	}
+}
+
+// selectionStrategy returns the configured selection strategy name.
+func (a *Agent) selectionStrategy() string {
+	if a.cfg.SkillSelector != nil {
+		return "custom"
+	}
+	if a.cfg.SelectionStrategy == "" {
+		return SelectionStrategyLLM
+	}
+	return a.cfg.SelectionStrategy
+}
+
+// llmSelector asks the agent's LLM to choose a skill.
+type llmSelector struct {
+	agent *Agent
+}
+
+func (s llmSelector) Select(ctx context.Context, prompt string, skills map[string]SkillPackage) (string, error) {
+	return s.agent.selectSkill(ctx, prompt, skills)
+}
+
+// BM25 parameters used by KeywordSelector.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// KeywordSelector scores skills against the prompt with BM25 over their names and
+// descriptions. Name terms count twice, so naming a skill in the prompt favours it.
+// It makes no network calls.
+type KeywordSelector struct{}
+
+// Select returns the highest-scoring skill, breaking ties by name. It fails if no
+// skill shares a term with the prompt.
+func (KeywordSelector) Select(ctx context.Context, prompt string, skills map[string]SkillPackage) (string, error) {
+	scores := KeywordScores(prompt, skills)
+
+	best, bestScore := "", 0.0
+	for name, score := range scores {
+		if score > bestScore || (score == bestScore && score > 0 && name < best) {
+			best, bestScore = name, score
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no skill matches the keywords in the prompt")
+	}
+	return best, nil
+}
+
+// KeywordScores returns the BM25 score of every skill for prompt.
+func KeywordScores(prompt string, skills map[string]SkillPackage) map[string]float64 {
+	docs := make(map[string]map[string]int, len(skills))
+	lengths := make(map[string]int, len(skills))
+	df := make(map[string]int)
+	totalLength := 0
+	for name, skill := range skills {
+		terms := tokenize(name)
+		terms = append(terms, terms...)
+		terms = append(terms, tokenize(skill.Meta.Description)...)
+
+		tf := make(map[string]int)
+		for _, term := range terms {
+			tf[term]++
+		}
+		for term := range tf {
+			df[term]++
+		}
+		docs[name] = tf
+		lengths[name] = len(terms)
+		totalLength += len(terms)
+	}
+
+	scores := make(map[string]float64, len(skills))
+	if len(skills) == 0 {
+		return scores
+	}
+	avgLength := float64(totalLength) / float64(len(skills))
+	n := float64(len(skills))
+
+	queryTerms := make(map[string]bool)
+	for _, term := range tokenize(prompt) {
+		queryTerms[term] = true
+	}
+
+	for name, tf := range docs {
+		score := 0.0
+		for term := range queryTerms {
+			f := float64(tf[term])
+			if f == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[term])+0.5)/(float64(df[term])+0.5))
+			norm := 1 - bm25B
+			if avgLength > 0 {
+				norm += bm25B * float64(lengths[name]) / avgLength
+			}
+			score += idf * f * (bm25K1 + 1) / (f + bm25K1*norm)
+		}
+		scores[name] = score
+	}
+	return scores
+}
+
+// stopWords are common English words ignored by keyword scoring.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "can": true, "do": true, "for": true, "from": true, "how": true, "i": true,
+	"in": true, "is": true, "it": true, "me": true, "my": true, "of": true, "on": true,
+	"or": true, "please": true, "the": true, "this": true, "to": true, "use": true,
+	"what": true, "with": true, "you": true, "your": true,
+}
+
+// tokenize lowercases s and splits it into alphanumeric terms, dropping stop words.
+// Hyphenated and underscored words are split into their parts.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if !stopWords[f] {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// EmbeddingClient is the part of the OpenAI client used to compute embeddings.
+type EmbeddingClient interface {
+	CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+// EmbeddingSelector picks the skill whose name and description embedding is most
+// similar to the prompt's. Skill embeddings are cached in memory and, when a cache
+// path is given, in a JSON file so later runs only embed the prompt.
+type EmbeddingSelector struct {
+	client    EmbeddingClient
+	model     openai.EmbeddingModel
+	cachePath string
+
+	mu     sync.Mutex
+	cache  map[string][]float32 // keyed by embeddingCacheKey
+	loaded bool
+}
+
+// NewEmbeddingSelector creates an EmbeddingSelector. An empty model defaults to
+// text-embedding-3-small; an empty cachePath keeps the cache in memory only.
+func NewEmbeddingSelector(client EmbeddingClient, model openai.EmbeddingModel, cachePath string) *EmbeddingSelector {
+	if model == "" {
+		model = openai.SmallEmbedding3
+	}
+	return &EmbeddingSelector{client: client, model: model, cachePath: cachePath, cache: make(map[string][]float32)}
+}
+
+func (s *EmbeddingSelector) Select(ctx context.Context, prompt string, skills map[string]SkillPackage) (string, error) {
+	if len(skills) == 0 {
+		return "", errors.New("no skills to select from")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadCache()
+
+	names := getAvailableSkillNames(skills)
+	sort.Strings(names)
+
+	// Embed the prompt together with any skills missing from the cache.
+	inputs := []string{prompt}
+	var missing []string
+	for _, name := range names {
+		text := skillEmbeddingText(name, skills[name])
+		if _, ok := s.cache[s.cacheKey(text)]; !ok {
+			inputs = append(inputs, text)
+			missing = append(missing, text)
+		}
+	}
+	vectors, err := s.embed(ctx, inputs)
+	if err != nil {
+		return "", err
+	}
+	for i, text := range missing {
+		s.cache[s.cacheKey(text)] = vectors[i+1]
+	}
+	if len(missing) > 0 {
+		s.saveCache()
+	}
+
+	best, bestScore := "", math.Inf(-1)
+	for _, name := range names {
+		score := CosineSimilarity(vectors[0], s.cache[s.cacheKey(skillEmbeddingText(name, skills[name]))])
+		if score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best, nil
+}
+
+func (s *EmbeddingSelector) embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	resp, err := s.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{Input: inputs, Model: s.model})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	vectors := make([][]float32, len(inputs))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("missing embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// cacheKey identifies the embedding of text under the selector's model.
+func (s *EmbeddingSelector) cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(string(s.model) + "\n" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCache reads the on-disk cache once. A missing or unreadable file leaves the
+// cache empty.
+func (s *EmbeddingSelector) loadCache() {
+	if s.loaded || s.cachePath == "" {
+		return
+	}
+	s.loaded = true
+	data, err := os.ReadFile(s.cachePath)
+	if err != nil {
+		return
+	}
+	var cached map[string][]float32
+	if json.Unmarshal(data, &cached) == nil {
+		for k, v := range cached {
+			s.cache[k] = v
+		}
+	}
+}
+
+// saveCache writes the cache to disk. Failures only cost a recomputation later.
+func (s *EmbeddingSelector) saveCache() {
+	if s.cachePath == "" {
+		return
+	}
+	data, err := json.Marshal(s.cache)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(s.cachePath), 0755) == nil {
+		_ = os.WriteFile(s.cachePath, data, 0644)
+	}
+}
+
+// skillEmbeddingText is the text embedded for a skill.
+func skillEmbeddingText(name string, skill SkillPackage) string {
+	return name + ": " + skill.Meta.Description
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 if the
+// vectors differ in length or either has zero magnitude.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,236 @@
+package goskills
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selectorTestSkills() map[string]SkillPackage {
+	return map[string]SkillPackage{
+		"pdf":              {Meta: SkillMeta{Name: "pdf", Description: "Extract text and tables from PDF files, fill forms, and merge documents."}},
+		"xlsx":             {Meta: SkillMeta{Name: "xlsx", Description: "Create, edit and analyze spreadsheets with formulas, charts and pivot tables."}},
+		"email":            {Meta: SkillMeta{Name: "email", Description: "Compose and send email messages with attachments."}},
+		"calculator-skill": {Meta: SkillMeta{Name: "calculator-skill", Description: "Evaluate mathematical expressions: arithmetic, trigonometry and logarithms."}},
+		"markitdown":       {Meta: SkillMeta{Name: "markitdown", Description: "Convert web pages and documents to Markdown."}},
+	}
+}
+
+func TestKeywordSelector(t *testing.T) {
+	skills := selectorTestSkills()
+	testCases := []struct {
+		prompt   string
+		expected string
+	}{
+		{"extract the tables from report.pdf", "pdf"},
+		{"Merge these PDF documents", "pdf"},
+		{"build a spreadsheet with a pivot table of sales", "xlsx"},
+		{"send an email to the team with the attachment", "email"},
+		{"what is the logarithm of 100? use trigonometry too", "calculator-skill"},
+		{"use the calculator to add 2 and 3", "calculator-skill"},
+		{"convert https://example.com to markdown", "markitdown"},
+		{"XLSX: add a chart", "xlsx"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.prompt, func(t *testing.T) {
+			selected, err := KeywordSelector{}.Select(context.Background(), tc.prompt, skills)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, selected)
+		})
+	}
+}
+
+func TestKeywordSelector_NoMatch(t *testing.T) {
+	_, err := KeywordSelector{}.Select(context.Background(), "what is the weather like?", selectorTestSkills())
+	assert.Error(t, err)
+
+	_, err = KeywordSelector{}.Select(context.Background(), "pdf", nil)
+	assert.Error(t, err)
+}
+
+func TestKeywordScores(t *testing.T) {
+	skills := selectorTestSkills()
+
+	scores := KeywordScores("pdf documents", skills)
+	require.Len(t, scores, len(skills))
+	assert.Greater(t, scores["pdf"], scores["markitdown"], "name match and rarer term should outrank a shared term")
+	assert.Greater(t, scores["markitdown"], 0.0)
+	assert.Equal(t, 0.0, scores["email"])
+
+	// Stop words alone never match.
+	for name, score := range KeywordScores("the and of to with", skills) {
+		assert.Equal(t, 0.0, score, name)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"calculator", "skill", "add", "2", "3"}, tokenize("Use the calculator-skill to add 2 and 3!"))
+	assert.Empty(t, tokenize("the of and"))
+	assert.Equal(t, []string{"web", "fetch"}, tokenize("web_fetch"))
+}
+
+// topicEmbeddingClient embeds text as a bag of known topic words.
+type topicEmbeddingClient struct {
+	inputs [][]string
+}
+
+var embeddingTopics = []string{"pdf", "spreadsheet", "email", "math", "markdown"}
+
+func (c *topicEmbeddingClient) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	req := conv.Convert()
+	inputs := req.Input.([]string)
+	c.inputs = append(c.inputs, inputs)
+
+	var resp openai.EmbeddingResponse
+	for i, input := range inputs {
+		lower := strings.ToLower(input)
+		vector := make([]float32, len(embeddingTopics)+1)
+		vector[len(embeddingTopics)] = 0.1
+		for j, topic := range embeddingTopics {
+			vector[j] = float32(strings.Count(lower, topic))
+		}
+		resp.Data = append(resp.Data, openai.Embedding{Index: i, Embedding: vector})
+	}
+	return resp, nil
+}
+
+func embeddingTestSkills() map[string]SkillPackage {
+	return map[string]SkillPackage{
+		"pdf":        {Meta: SkillMeta{Name: "pdf", Description: "Work with PDF files."}},
+		"xlsx":       {Meta: SkillMeta{Name: "xlsx", Description: "Edit spreadsheet files."}},
+		"calculator": {Meta: SkillMeta{Name: "calculator", Description: "Do math."}},
+	}
+}
+
+func TestEmbeddingSelector(t *testing.T) {
+	client := &topicEmbeddingClient{}
+	selector := NewEmbeddingSelector(client, "", "")
+
+	selected, err := selector.Select(context.Background(), "sum a spreadsheet column", embeddingTestSkills())
+	require.NoError(t, err)
+	assert.Equal(t, "xlsx", selected)
+	require.Len(t, client.inputs, 1)
+	assert.Len(t, client.inputs[0], 4, "prompt plus three skills")
+
+	// Skill embeddings are cached; only the prompt is embedded the second time.
+	selected, err = selector.Select(context.Background(), "solve this math problem", embeddingTestSkills())
+	require.NoError(t, err)
+	assert.Equal(t, "calculator", selected)
+	require.Len(t, client.inputs, 2)
+	assert.Equal(t, []string{"solve this math problem"}, client.inputs[1])
+}
+
+func TestEmbeddingSelector_DiskCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache", "embeddings.json")
+
+	first := &topicEmbeddingClient{}
+	_, err := NewEmbeddingSelector(first, "", cachePath).Select(context.Background(), "read a pdf", embeddingTestSkills())
+	require.NoError(t, err)
+
+	second := &topicEmbeddingClient{}
+	selected, err := NewEmbeddingSelector(second, "", cachePath).Select(context.Background(), "read a pdf", embeddingTestSkills())
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", selected)
+	require.Len(t, second.inputs, 1)
+	assert.Equal(t, []string{"read a pdf"}, second.inputs[0], "skill embeddings should come from the disk cache")
+
+	// A different model does not reuse the cached vectors.
+	third := &topicEmbeddingClient{}
+	_, err = NewEmbeddingSelector(third, openai.LargeEmbedding3, cachePath).Select(context.Background(), "read a pdf", embeddingTestSkills())
+	require.NoError(t, err)
+	assert.Len(t, third.inputs[0], 4)
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2, 3}, []float32{2, 4, 6}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, -1.0, CosineSimilarity([]float32{1, 1}, []float32{-1, -1}), 1e-9)
+	assert.InDelta(t, 0.7071, CosineSimilarity([]float32{1, 0}, []float32{1, 1}), 1e-4)
+	assert.Equal(t, 0.0, CosineSimilarity([]float32{1, 2}, []float32{1, 2, 3}))
+	assert.Equal(t, 0.0, CosineSimilarity([]float32{0, 0}, []float32{1, 1}))
+	assert.Equal(t, 0.0, CosineSimilarity(nil, nil))
+}
+
+// TestSelectAndPrepareSkill_KeywordStrategy tests that the keyword strategy selects offline
+func TestSelectAndPrepareSkill_KeywordStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestSkill(t, tmpDir, "pdf-reader", "", "Read PDF files.")
+	writeTestSkill(t, tmpDir, "csv-analyzer", "", "Analyze CSV files.")
+
+	client := NewMockOpenAIClient(nil, nil)
+	agent := &Agent{client: client, cfg: RunnerConfig{SkillsDir: tmpDir, SelectionStrategy: SelectionStrategyKeyword}}
+
+	skill, err := agent.selectAndPrepareSkill(context.Background(), "summarize this csv")
+	require.NoError(t, err)
+	assert.Equal(t, "csv-analyzer", skill.Meta.Name)
+	assert.Empty(t, client.requests, "keyword selection must not call the LLM")
+}
+
+func TestNewSkillSelector(t *testing.T) {
+	agent := &Agent{client: NewMockOpenAIClient(nil, nil)}
+
+	for strategy, expected := range map[string]any{"": llmSelector{}, "llm": llmSelector{}, "keyword": KeywordSelector{}} {
+		agent.cfg.SelectionStrategy = strategy
+		selector, err := newSkillSelector(agent)
+		require.NoError(t, err, strategy)
+		assert.IsType(t, expected, selector, strategy)
+	}
+
+	agent.cfg.SelectionStrategy = SelectionStrategyEmbeddings
+	_, err := newSkillSelector(agent)
+	assert.Error(t, err, "the mock client cannot create embeddings")
+
+	agent.cfg.SelectionStrategy = "random"
+	_, err = newSkillSelector(agent)
+	assert.ErrorContains(t, err, "unknown selection strategy")
+
+	custom := KeywordSelector{}
+	agent.cfg.SkillSelector = custom
+	selector, err := newSkillSelector(agent)
+	require.NoError(t, err, "a custom selector overrides the strategy")
+	assert.Equal(t, custom, selector)
+}
+
+func BenchmarkSelectors(b *testing.B) {
+	skills := selectorTestSkills()
+	prompt := "extract the tables from report.pdf and merge them"
+	ctx := context.Background()
+
+	b.Run("llm", func(b *testing.B) {
+		responses := make([]openai.ChatCompletionResponse, b.N)
+		for i := range responses {
+			responses[i] = openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "pdf"}}}}
+		}
+		selector := llmSelector{agent: &Agent{client: NewMockOpenAIClient(responses, nil)}}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := selector.Select(ctx, prompt, skills); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("keyword", func(b *testing.B) {
+		selector := KeywordSelector{}
+		for i := 0; i < b.N; i++ {
+			if _, err := selector.Select(ctx, prompt, skills); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("embeddings", func(b *testing.B) {
+		selector := NewEmbeddingSelector(&topicEmbeddingClient{}, "", "")
+		for i := 0; i < b.N; i++ {
+			if _, err := selector.Select(ctx, prompt, skills); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package goskills
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LargeSkillBodyTokens is the approximate body size, in tokens, above which
+// executeSkillWithTools warns at Verbose >= 1 that a skill is expensive to send.
+const LargeSkillBodyTokens = 8000
+
+// SkillSize summarizes how large a skill package is, for capacity planning.
+type SkillSize struct {
+	BodyTokens     int // Approximate number of tokens in the body (see approxTokens)
+	ReferenceBytes int // Combined size of the reference files
+	ScriptCount    int
+	TotalFiles     int // SKILL.md plus every script, reference, asset and template file
+}
+
+// SizeReport returns the size of the skill. Reference files that cannot be read count
+// as empty; the body of a lazily parsed package is read from SKILL.md.
+func (s SkillPackage) SizeReport() SkillSize {
+	body, _, _ := s.ReadBody(0)
+	size := SkillSize{
+		BodyTokens:  approxTokens(body),
+		ScriptCount: len(s.Resources.Scripts),
+		TotalFiles: 1 + len(s.Resources.Scripts) + len(s.Resources.References) +
+			len(s.Resources.Assets) + len(s.Resources.Templates),
+	}
+	for _, ref := range s.Resources.References {
+		if info, err := os.Stat(filepath.Join(s.Path, ref)); err == nil {
+			size.ReferenceBytes += int(info.Size())
+		}
+	}
+	return size
+}
+
+// approxTokens approximates the number of LLM tokens in s as one token per four bytes,
+// which is close for English prose.
+func approxTokens(s string) int {
+	return len(s) / 4
+}
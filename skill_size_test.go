@@ -0,0 +1,76 @@
+package goskills
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproxTokens(t *testing.T) {
+	// Token counts of the cl100k_base tokenizer used by GPT-4 class models
+	testCases := []struct {
+		text   string
+		tokens int
+	}{
+		{"The quick brown fox jumps over the lazy dog.", 10},
+		{strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100), 1000},
+		{"Use the extract script to read the text of every page, then summarize each section in one sentence.", 20},
+	}
+	for _, tc := range testCases {
+		got := approxTokens(tc.text)
+		assert.InEpsilon(t, tc.tokens, got, 0.2, "%q: approximated %d tokens, want about %d", tc.text, got, tc.tokens)
+	}
+}
+
+func TestSkillPackage_SizeReport(t *testing.T) {
+	dir := writeTestSkill(t, t.TempDir(), "large-skill", "", strings.Repeat("x", 4000))
+	for _, sub := range []string{"scripts", "references", "assets"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo hi\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.py"), []byte("print('hi')\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "references", "api.md"), make([]byte, 1500), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "references", "faq.md"), make([]byte, 500), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "logo.png"), []byte("png"), 0644))
+
+	want := SkillSize{BodyTokens: 1000, ReferenceBytes: 2000, ScriptCount: 2, TotalFiles: 6}
+	skill, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, want, skill.SizeReport())
+
+	// Lazily parsed packages report the same size
+	skill, err = ParseSkillPackageLazy(dir)
+	require.NoError(t, err)
+	assert.Equal(t, want, skill.SizeReport())
+}
+
+func TestExecuteSkillWithTools_LargeBodyWarning(t *testing.T) {
+	var logs bytes.Buffer
+	old := log.GetDefaultLogger()
+	log.SetDefaultLogger(log.NewCustomLogger(&logs, log.LogLevelInfo))
+	t.Cleanup(func() { log.SetDefaultLogger(old) })
+
+	run := func(bodyTokens, verbose int) string {
+		logs.Reset()
+		skill := &SkillPackage{Meta: SkillMeta{Name: "big"}, Body: strings.Repeat("abcd", bodyTokens)}
+		agent := &Agent{
+			client: NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("done")}, nil),
+			cfg:    RunnerConfig{Model: "test-model", Verbose: verbose},
+		}
+		_, err := agent.executeSkillWithTools(context.Background(), "go", skill)
+		require.NoError(t, err)
+		return logs.String()
+	}
+
+	assert.NotContains(t, run(LargeSkillBodyTokens, 1), "body is about")
+	assert.Contains(t, run(LargeSkillBodyTokens+1, 1), "skill big body is about 8001 tokens, more than 8000")
+	assert.NotContains(t, run(LargeSkillBodyTokens+1, 0), "body is about")
+}
@@ -0,0 +1,17 @@
+package goskills
+
+import (
+	"github.com/smallnest/goskills/log"
+	"github.com/smallnest/goskills/skillstats"
+)
+
+// recordSkillStats adds delta to the skill's usage counters when they are enabled.
+// Failures are logged rather than returned so that statistics never break a run.
+func (a *Agent) recordSkillStats(delta skillstats.Counters) {
+	if a.stats == nil || delta.Skill == "" {
+		return
+	}
+	if err := a.stats.Add(delta); err != nil {
+		log.Warn("failed to record skill stats: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package goskills
+
+import (
+	"context"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/skillstats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_RecordsSkillStats(t *testing.T) {
+	stats, err := skillstats.Open(":memory:")
+	require.NoError(t, err)
+	defer stats.Close()
+
+	skillsDir := t.TempDir()
+	writeTestSkill(t, skillsDir, "greeter", "", "Greet the user.")
+
+	failing := toolCallResponse("call-1", "missing_tool", "{}")
+	failing.Usage = openai.Usage{TotalTokens: 30}
+	answer := textResponse("done")
+	answer.Usage = openai.Usage{TotalTokens: 12}
+	agent := &Agent{
+		client: NewMockOpenAIClient([]openai.ChatCompletionResponse{failing, answer}, nil),
+		cfg: RunnerConfig{
+			Model:                  "test-model",
+			SkillsDir:              skillsDir,
+			SkillName:              "greeter",
+			AutoApproveTools:       true,
+			SkillFallbackThreshold: -1,
+		},
+		stats: stats,
+	}
+
+	_, err = agent.Run(context.Background(), "hello")
+	require.NoError(t, err)
+
+	counters, err := stats.Get("greeter")
+	require.NoError(t, err)
+	assert.Equal(t, 1, counters.Selected)
+	assert.Equal(t, 1, counters.Executions)
+	assert.Equal(t, 1, counters.Successes)
+	assert.Equal(t, 1, counters.ToolErrors)
+	assert.Equal(t, 42, counters.Tokens)
+
+	// A run that never produces an answer is an execution without success
+	agent.client = NewMockOpenAIClient(nil, assert.AnError)
+	_, err = agent.Run(context.Background(), "hello again")
+	require.Error(t, err)
+
+	counters, err = stats.Get("greeter")
+	require.NoError(t, err)
+	assert.Equal(t, 2, counters.Selected)
+	assert.Equal(t, 2, counters.Executions)
+	assert.Equal(t, 1, counters.Successes)
+}
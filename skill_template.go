@@ -0,0 +1,80 @@
+package goskills
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// skillVarPattern matches a {{skill_var:KEY}} placeholder in a skill body.
+var skillVarPattern = regexp.MustCompile(`\{\{\s*skill_var:([A-Za-z0-9_]+)\s*\}\}`)
+
+// skillVarNamePattern matches a valid skill variable name.
+var skillVarNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ValidateSkillVars checks that every skill variable name consists of letters, digits
+// and underscores, so it can appear in a {{skill_var:KEY}} placeholder.
+func ValidateSkillVars(vars map[string]string) error {
+	for name := range vars {
+		if !skillVarNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid skill variable name '%s' (expected letters, digits and underscores)", name)
+		}
+	}
+	return nil
+}
+
+// validateRequiredVars checks that the names in required-vars can be used in a
+// {{skill_var:KEY}} placeholder.
+func validateRequiredVars(skill *SkillPackage) error {
+	for _, name := range skill.Meta.RequiredVars {
+		if !skillVarNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name '%s' in required-vars (expected letters, digits and underscores)", name)
+		}
+	}
+	return nil
+}
+
+// Template returns a copy of the skill whose body has every {{skill_var:KEY}}
+// placeholder replaced with vars[KEY]. It fails when a variable listed in the
+// frontmatter's required-vars is missing from vars. Placeholders for other variables
+// not in vars are left as they are.
+func (s SkillPackage) Template(vars map[string]string) (SkillPackage, error) {
+	var missing []string
+	for _, name := range s.Meta.RequiredVars {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return SkillPackage{}, fmt.Errorf("missing required skill variables: %s", strings.Join(missing, ", "))
+	}
+
+	body, _, err := s.ReadBody(0)
+	if err != nil {
+		return SkillPackage{}, err
+	}
+	s.Body = skillVarPattern.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := skillVarPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+	s.openBody = nil
+	return s, nil
+}
+
+// templatedSkill applies cfg.SkillVars to skill. Skills without variables to fill
+// are returned as is, so their bodies can still be streamed.
+func (a *Agent) templatedSkill(skill *SkillPackage) (*SkillPackage, error) {
+	if len(a.cfg.SkillVars) == 0 && len(skill.Meta.RequiredVars) == 0 {
+		return skill, nil
+	}
+	templated, err := skill.Template(a.cfg.SkillVars)
+	if err != nil {
+		return nil, err
+	}
+	return &templated, nil
+}
@@ -0,0 +1,141 @@
+package goskills
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillPackage_Template(t *testing.T) {
+	testCases := []struct {
+		name    string
+		body    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "single placeholder",
+			body: "Translate the report into {{skill_var:LANGUAGE}}.",
+			vars: map[string]string{"LANGUAGE": "German"},
+			want: "Translate the report into German.",
+		},
+		{
+			name: "repeated placeholders and surrounding spaces",
+			body: "Connect to {{skill_var:DB_URL}}.\nNever write to {{ skill_var:DB_URL }}.",
+			vars: map[string]string{"DB_URL": "postgres://db"},
+			want: "Connect to postgres://db.\nNever write to postgres://db.",
+		},
+		{
+			name: "several variables",
+			body: "{{skill_var:A}}-{{skill_var:B}}-{{skill_var:A}}",
+			vars: map[string]string{"A": "1", "B": "2"},
+			want: "1-2-1",
+		},
+		{
+			name: "unknown variables are left alone",
+			body: "Use {{skill_var:LANGUAGE}} and {{skill_var:TONE}}.",
+			vars: map[string]string{"LANGUAGE": "French"},
+			want: "Use French and {{skill_var:TONE}}.",
+		},
+		{
+			name: "empty value",
+			body: "Prefix:{{skill_var:PREFIX}}",
+			vars: map[string]string{"PREFIX": ""},
+			want: "Prefix:",
+		},
+		{
+			name: "other template syntax is untouched",
+			body: "Use {{.GOSKILLS_ARGS.url}} and {{skill_var:X}}",
+			vars: map[string]string{"X": "y"},
+			want: "Use {{.GOSKILLS_ARGS.url}} and y",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			skill := SkillPackage{Body: tc.body, Meta: SkillMeta{Name: "test"}}
+			got, err := skill.Template(tc.vars)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.Body)
+			assert.Equal(t, tc.body, skill.Body, "the original package must not change")
+		})
+	}
+}
+
+func TestSkillPackage_TemplateRequiredVars(t *testing.T) {
+	skill := SkillPackage{
+		Body: "Answer in {{skill_var:LANGUAGE}} for {{skill_var:COMPANY}}.",
+		Meta: SkillMeta{Name: "test", RequiredVars: []string{"LANGUAGE", "COMPANY"}},
+	}
+
+	_, err := skill.Template(map[string]string{})
+	assert.EqualError(t, err, "missing required skill variables: COMPANY, LANGUAGE")
+
+	_, err = skill.Template(map[string]string{"LANGUAGE": "German"})
+	assert.EqualError(t, err, "missing required skill variables: COMPANY")
+
+	got, err := skill.Template(map[string]string{"LANGUAGE": "German", "COMPANY": "Acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "Answer in German for Acme.", got.Body)
+}
+
+func TestSkillPackage_TemplateLazyBody(t *testing.T) {
+	dir := writeTestSkill(t, t.TempDir(), "lazy", "required-vars: [LANGUAGE]\n", "Speak {{skill_var:LANGUAGE}}.\n")
+	skill, err := ParseSkillPackageLazy(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LANGUAGE"}, skill.Meta.RequiredVars)
+
+	got, err := skill.Template(map[string]string{"LANGUAGE": "Spanish"})
+	require.NoError(t, err)
+	body, _, err := got.ReadBody(0)
+	require.NoError(t, err)
+	assert.Equal(t, "Speak Spanish.", body)
+}
+
+func TestParseSkillPackage_RequiredVars(t *testing.T) {
+	dir := writeTestSkill(t, t.TempDir(), "vars", "required-vars:\n  - LANGUAGE\n  - DB_URL\n", "Body\n")
+	skill, err := ParseSkillPackage(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LANGUAGE", "DB_URL"}, skill.Meta.RequiredVars)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: vars\ndescription: Bad\nrequired-vars: [\"DB-URL\"]\n---\nBody\n"), 0644))
+	_, err = ParseSkillPackage(dir)
+	assert.ErrorContains(t, err, "invalid name 'DB-URL' in required-vars")
+}
+
+func TestValidateSkillVars(t *testing.T) {
+	assert.NoError(t, ValidateSkillVars(map[string]string{"LANGUAGE": "en", "db_url_2": "x"}))
+	assert.EqualError(t, ValidateSkillVars(map[string]string{"bad key": "x"}),
+		"invalid skill variable name 'bad key' (expected letters, digits and underscores)")
+}
+
+func TestExecuteSkillWithTools_SkillVars(t *testing.T) {
+	skill := &SkillPackage{
+		Path: "/skills/translator",
+		Body: "Translate into {{skill_var:LANGUAGE}}.",
+		Meta: SkillMeta{Name: "translator", RequiredVars: []string{"LANGUAGE"}},
+	}
+
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{textResponse("Hallo")}, nil)
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", SkillVars: map[string]string{"LANGUAGE": "German"}}}
+	_, err := agent.executeSkillWithTools(context.Background(), "hello", skill)
+	require.NoError(t, err)
+	require.Len(t, mockClient.requests, 1)
+	assert.True(t, strings.HasPrefix(mockClient.requests[0].Messages[0].Content, "Translate into German."))
+
+	agent = &Agent{client: NewMockOpenAIClient(nil, nil), cfg: RunnerConfig{Model: "test-model"}}
+	_, err = agent.executeSkillWithTools(context.Background(), "hello", skill)
+	assert.EqualError(t, err, "failed to load skill translator: missing required skill variables: LANGUAGE")
+}
+
+func TestNewAgent_InvalidSkillVars(t *testing.T) {
+	_, err := NewAgent(RunnerConfig{APIKey: "key", SkillVars: map[string]string{"bad-key": "x"}}, nil)
+	assert.ErrorContains(t, err, "invalid skill variable name 'bad-key'")
+}
@@ -0,0 +1,35 @@
+package goskills
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// CompareVersions compares two semantic versions such as "1.2.0" or "v1.2.0".
+// It returns -1 if a < b, 0 if a == b and +1 if a > b, or an error if either
+// version is not a valid semantic version.
+func CompareVersions(a, b string) (int, error) {
+	va, err := canonicalVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := canonicalVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return semver.Compare(va, vb), nil
+}
+
+// canonicalVersion adds the "v" prefix expected by golang.org/x/mod/semver and validates v.
+func canonicalVersion(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return "", fmt.Errorf("invalid semantic version '%s'", v)
+	}
+	return v, nil
+}
@@ -0,0 +1,50 @@
+package goskills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     string
+		expected int
+	}{
+		{"equal", "1.2.3", "1.2.3", 0},
+		{"equal with prefix", "v1.2.3", "1.2.3", 0},
+		{"patch lower", "1.2.3", "1.2.4", -1},
+		{"patch higher", "1.2.10", "1.2.9", 1},
+		{"minor lower", "1.2.3", "1.3.0", -1},
+		{"minor higher", "1.10.0", "1.9.9", 1},
+		{"major lower", "1.9.9", "2.0.0", -1},
+		{"major higher", "2.0.0", "1.99.99", 1},
+		{"short form", "1.2", "1.2.0", 0},
+		{"major only", "2", "1.5.0", 1},
+		{"prerelease lower than release", "1.0.0-beta", "1.0.0", -1},
+		{"prerelease ordering", "1.0.0-alpha", "1.0.0-beta", -1},
+		{"build metadata ignored", "1.0.0+build1", "1.0.0+build2", 0},
+		{"surrounding whitespace", " 1.0.0 ", "1.0.0", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := CompareVersions(tc.a, tc.b)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestCompareVersions_Invalid(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"", "1.0.0"},
+		{"1.0.0", "latest"},
+		{"1.0.0.0", "1.0.0"},
+		{"1.0.0", "v"},
+	} {
+		_, err := CompareVersions(pair[0], pair[1])
+		assert.Error(t, err, "expected error comparing %q and %q", pair[0], pair[1])
+	}
+}
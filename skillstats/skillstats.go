@@ -0,0 +1,123 @@
+// Package skillstats keeps per-skill usage counters in a SQLite database.
+package skillstats
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// Counters are the usage counters of a single skill.
+type Counters struct {
+	Skill      string
+	Selected   int           // Times the skill was selected for a prompt
+	Executions int           // Prompts the skill ran to completion or failure
+	Successes  int           // Executions that produced a final answer
+	ToolErrors int           // Tool calls that failed while the skill was active
+	Tokens     int           // Total tokens of the LLM calls made while the skill was active
+	Duration   time.Duration // Total duration of all executions
+}
+
+// AvgDuration returns the mean duration of an execution.
+func (c Counters) AvgDuration() time.Duration {
+	if c.Executions == 0 {
+		return 0
+	}
+	return c.Duration / time.Duration(c.Executions)
+}
+
+const schema = `CREATE TABLE IF NOT EXISTS skill_stats (
+	skill       TEXT PRIMARY KEY,
+	selected    INTEGER NOT NULL DEFAULT 0,
+	executions  INTEGER NOT NULL DEFAULT 0,
+	successes   INTEGER NOT NULL DEFAULT 0,
+	tool_errors INTEGER NOT NULL DEFAULT 0,
+	tokens      INTEGER NOT NULL DEFAULT 0,
+	duration_ms INTEGER NOT NULL DEFAULT 0
+)`
+
+// Store records skill counters in a SQLite database. It is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the default location of the database, ~/.goskills/stats.db.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".goskills", "stats.db"), nil
+}
+
+// Open opens the database at path, creating it and its directory if needed. Use
+// ":memory:" for a database that lives only as long as the Store.
+func Open(path string) (*Store, error) {
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create stats directory: %w", err)
+		}
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stats database: %w", err)
+	}
+	// A single connection serializes writes and keeps an in-memory database alive
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize stats database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add adds delta's counters to those of delta.Skill.
+func (s *Store) Add(delta Counters) error {
+	_, err := s.db.Exec(`INSERT INTO skill_stats (skill, selected, executions, successes, tool_errors, tokens, duration_ms)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(skill) DO UPDATE SET
+	selected = selected + excluded.selected,
+	executions = executions + excluded.executions,
+	successes = successes + excluded.successes,
+	tool_errors = tool_errors + excluded.tool_errors,
+	tokens = tokens + excluded.tokens,
+	duration_ms = duration_ms + excluded.duration_ms`,
+		delta.Skill, delta.Selected, delta.Executions, delta.Successes, delta.ToolErrors, delta.Tokens, delta.Duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to update stats for %s: %w", delta.Skill, err)
+	}
+	return nil
+}
+
+// Get returns the counters of skill; a skill without recorded usage has zero counters.
+func (s *Store) Get(skill string) (Counters, error) {
+	c := Counters{Skill: skill}
+	var durationMs int64
+	err := s.db.QueryRow(`SELECT selected, executions, successes, tool_errors, tokens, duration_ms FROM skill_stats WHERE skill = ?`, skill).
+		Scan(&c.Selected, &c.Executions, &c.Successes, &c.ToolErrors, &c.Tokens, &durationMs)
+	if err == sql.ErrNoRows {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read stats for %s: %w", skill, err)
+	}
+	c.Duration = time.Duration(durationMs) * time.Millisecond
+	return c, nil
+}
+
+// Reset clears the counters of skill.
+func (s *Store) Reset(skill string) error {
+	if _, err := s.db.Exec(`DELETE FROM skill_stats WHERE skill = ?`, skill); err != nil {
+		return fmt.Errorf("failed to reset stats for %s: %w", skill, err)
+	}
+	return nil
+}
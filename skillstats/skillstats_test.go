@@ -0,0 +1,60 @@
+package skillstats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store, err := Open(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	counters, err := store.Get("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, Counters{Skill: "pdf"}, counters)
+
+	require.NoError(t, store.Add(Counters{Skill: "pdf", Selected: 1}))
+	require.NoError(t, store.Add(Counters{Skill: "pdf", Selected: 1, ToolErrors: 2, Tokens: 100}))
+	require.NoError(t, store.Add(Counters{Skill: "pdf", Executions: 1, Successes: 1, Duration: 3 * time.Second}))
+	require.NoError(t, store.Add(Counters{Skill: "pdf", Executions: 1, Duration: time.Second}))
+	require.NoError(t, store.Add(Counters{Skill: "docx", Selected: 1}))
+
+	counters, err = store.Get("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, Counters{Skill: "pdf", Selected: 2, Executions: 2, Successes: 1, ToolErrors: 2, Tokens: 100, Duration: 4 * time.Second}, counters)
+	assert.Equal(t, 2*time.Second, counters.AvgDuration())
+
+	require.NoError(t, store.Reset("pdf"))
+	counters, err = store.Get("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, Counters{Skill: "pdf"}, counters)
+
+	// Other skills are untouched by the reset
+	counters, err = store.Get("docx")
+	require.NoError(t, err)
+	assert.Equal(t, 1, counters.Selected)
+}
+
+func TestStore_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "stats.db")
+	store, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Add(Counters{Skill: "pdf", Selected: 1}))
+	require.NoError(t, store.Close())
+
+	store, err = Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+	counters, err := store.Get("pdf")
+	require.NoError(t, err)
+	assert.Equal(t, 1, counters.Selected)
+}
+
+func TestCounters_AvgDuration(t *testing.T) {
+	assert.Zero(t, Counters{}.AvgDuration())
+}
@@ -1,6 +1,8 @@
 package tool
 
 import (
+	"encoding/json"
+
 	openai "github.com/sashabaranov/go-openai"
 )
 
@@ -97,6 +99,51 @@ func GetBaseTools() []openai.Tool {
 				},
 			},
 		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "run_node_code",
+				Description: "Executes a JavaScript code snippet with Node.js and returns its combined stdout and stderr.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"code": map[string]any{
+							"type":        "string",
+							"description": "The JavaScript code snippet to execute.",
+						},
+						"args": map[string]any{
+							"type":        "object",
+							"description": "A map of key-value pairs to pass to the code.",
+						},
+					},
+					"required": []string{"code"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "run_node_script",
+				Description: "Executes a JavaScript script with Node.js, or a TypeScript (.ts) script with ts-node, and returns its combined stdout and stderr.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"scriptPath": map[string]any{
+							"type":        "string",
+							"description": "The path to the JavaScript or TypeScript script to execute.",
+						},
+						"args": map[string]any{
+							"type":        "array",
+							"description": "A list of string arguments to pass to the script.",
+							"items": map[string]any{
+								"type": "string",
+							},
+						},
+					},
+					"required": []string{"scriptPath"},
+				},
+			},
+		},
 		{
 			Type: openai.ToolTypeFunction,
 			Function: &openai.FunctionDefinition{
@@ -114,6 +161,24 @@ func GetBaseTools() []openai.Tool {
 				},
 			},
 		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "batch_read_files",
+				Description: "Reads several files in one call and returns a JSON object mapping each path to its content. Files that cannot be read map to an object with an \"error\" key.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"file_paths": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"description": "The paths of the files to read.",
+						},
+					},
+					"required": []string{"file_paths"},
+				},
+			},
+		},
 		{
 			Type: openai.ToolTypeFunction,
 			Function: &openai.FunctionDefinition{
@@ -135,6 +200,165 @@ func GetBaseTools() []openai.Tool {
 				},
 			},
 		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "search_replace_file",
+				Description: "Replaces occurrences of a string or regular expression in a file and returns the number of replacements made. Prefer this over write_file for targeted edits.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"file_path": map[string]any{
+							"type":        "string",
+							"description": "The path to the file to edit.",
+						},
+						"search": map[string]any{
+							"type":        "string",
+							"description": "The text to replace, or a regular expression when use_regex is true.",
+						},
+						"replacement": map[string]any{
+							"type":        "string",
+							"description": "The text to replace each occurrence with. With use_regex, $1 or ${name} insert submatches.",
+						},
+						"count": map[string]any{
+							"type":        "integer",
+							"description": "Replace only the first count occurrences. Defaults to -1, which replaces all of them.",
+						},
+						"use_regex": map[string]any{
+							"type":        "boolean",
+							"description": "Treat search as a Go regular expression. Defaults to false.",
+						},
+					},
+					"required": []string{"file_path", "search", "replacement"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "compare_files",
+				Description: "Compares two text files and returns a unified diff, or \"Files are identical.\" when they do not differ. Useful for verifying changes made to a file.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"file_a": map[string]any{
+							"type":        "string",
+							"description": "The path of the original file.",
+						},
+						"file_b": map[string]any{
+							"type":        "string",
+							"description": "The path of the changed file.",
+						},
+						"context_lines": map[string]any{
+							"type":        "integer",
+							"description": "Number of unchanged lines to show around each change. Defaults to 3.",
+						},
+					},
+					"required": []string{"file_a", "file_b"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "code_search",
+				Description: "Searches the files in a directory, recursively, for lines matching a regular expression, like grep. Returns matches as file:line: text, at most 500.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"directory": map[string]any{
+							"type":        "string",
+							"description": "The directory to search.",
+						},
+						"pattern": map[string]any{
+							"type":        "string",
+							"description": "The regular expression (Go RE2 syntax) to search for.",
+						},
+						"file_pattern": map[string]any{
+							"type":        "string",
+							"description": "Only search files whose name matches this glob, e.g. '*.go'. Defaults to '*'.",
+						},
+						"case_sensitive": map[string]any{
+							"type":        "boolean",
+							"description": "Match case exactly. Defaults to false.",
+						},
+					},
+					"required": []string{"directory", "pattern"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "parse_structured_data",
+				Description: "Parses a CSV, JSON, YAML or TOML file into a table and returns it as Markdown. The format is detected from the file extension unless given.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"file_path": map[string]any{
+							"type":        "string",
+							"description": "The path to the file to parse.",
+						},
+						"format": map[string]any{
+							"type":        "string",
+							"description": "Override the detected format.",
+							"enum":        []string{"csv", "json", "yaml", "toml"},
+						},
+					},
+					"required": []string{"file_path"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "copy_file",
+				Description: "Copies a file to a new location, creating intermediate directories as needed. Fails if the destination exists unless overwrite is true.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"source": map[string]any{
+							"type":        "string",
+							"description": "The path of the file to copy.",
+						},
+						"destination": map[string]any{
+							"type":        "string",
+							"description": "The path to copy the file to.",
+						},
+						"overwrite": map[string]any{
+							"type":        "boolean",
+							"description": "Replace the destination if it already exists. Defaults to false.",
+						},
+					},
+					"required": []string{"source", "destination"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "move_file",
+				Description: "Moves (renames) a file to a new location, creating intermediate directories as needed. Fails if the destination exists unless overwrite is true.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"source": map[string]any{
+							"type":        "string",
+							"description": "The path of the file to move.",
+						},
+						"destination": map[string]any{
+							"type":        "string",
+							"description": "The path to move the file to.",
+						},
+						"overwrite": map[string]any{
+							"type":        "boolean",
+							"description": "Replace the destination if it already exists. Defaults to false.",
+						},
+					},
+					"required": []string{"source", "destination"},
+				},
+			},
+		},
 		{
 			Type: openai.ToolTypeFunction,
 			Function: &openai.FunctionDefinition{
@@ -169,6 +393,218 @@ func GetBaseTools() []openai.Tool {
 				},
 			},
 		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "web_search",
+				Description: "Searches the web with DuckDuckGo, Tavily and Wikipedia at once and returns one deduplicated, ranked list of results. Prefer this over calling the individual search tools.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"query": map[string]any{
+							"type":        "string",
+							"description": "The search query.",
+						},
+					},
+					"required": []string{"query"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "read_url_raw",
+				Description: "Fetches a URL and returns its raw bytes without any HTML processing, as JSON with the base64-encoded body in content_base64 and, when the body is valid UTF-8, the text in content_text. Use this for binary files and non-HTML responses.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"url": map[string]any{
+							"type":        "string",
+							"description": "The full URL to fetch, including the protocol (e.g., 'https://example.com/data.bin').",
+						},
+						"max_bytes": map[string]any{
+							"type":        "integer",
+							"description": "Maximum number of bytes to return. Defaults to 1048576 (1 MiB).",
+						},
+					},
+					"required": []string{"url"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "get_env",
+				Description: "Returns the values of the given environment variables as a JSON object. Variables that are not allowed are returned as \"<redacted>\".",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"keys": map[string]any{
+							"type":        "array",
+							"items":       map[string]any{"type": "string"},
+							"description": "The names of the environment variables to read, e.g. [\"HOME\", \"PWD\"].",
+						},
+					},
+					"required": []string{"keys"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "git_log",
+				Description: "Lists the most recent commits of a git repository with their hash, author, date and message. Only allowed repository paths can be read.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"repo_path": map[string]any{
+							"type":        "string",
+							"description": "Path of the repository, or of a directory inside it.",
+						},
+						"branch": map[string]any{
+							"type":        "string",
+							"description": "Branch, tag or commit to start from (default: HEAD).",
+						},
+						"max_commits": map[string]any{
+							"type":        "integer",
+							"description": "Maximum number of commits to return (default: 20).",
+						},
+					},
+					"required": []string{"repo_path"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "git_diff",
+				Description: "Returns the unified diff between two commits of a git repository. Only allowed repository paths can be read.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"repo_path": map[string]any{
+							"type":        "string",
+							"description": "Path of the repository, or of a directory inside it.",
+						},
+						"from_ref": map[string]any{
+							"type":        "string",
+							"description": "Branch, tag or commit to diff from.",
+						},
+						"to_ref": map[string]any{
+							"type":        "string",
+							"description": "Branch, tag or commit to diff to (default: HEAD).",
+						},
+					},
+					"required": []string{"repo_path", "from_ref"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "memory_set",
+				Description: "Remembers a fact across runs, e.g. the user's name or preferences. Stored facts are shown under \"Current memory\" in later runs. An empty value forgets the key.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key": map[string]any{
+							"type":        "string",
+							"description": "A short name for the fact, e.g. 'preferred_language'.",
+						},
+						"value": map[string]any{
+							"type":        "string",
+							"description": "The fact to remember.",
+						},
+					},
+					"required": []string{"key", "value"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "memory_get",
+				Description: "Returns a fact remembered with memory_set.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key": map[string]any{
+							"type":        "string",
+							"description": "The name of the fact.",
+						},
+					},
+					"required": []string{"key"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "execute_sql",
+				Description: "Runs a SQL query against a SQLite (or other configured) database and returns the result as a Markdown table. SELECT queries return at most 1000 rows.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"driver": map[string]any{
+							"type":        "string",
+							"description": "The database/sql driver name. Defaults to 'sqlite'.",
+						},
+						"dsn": map[string]any{
+							"type":        "string",
+							"description": "The data source name, e.g. a SQLite file path or 'file:data.db?mode=ro' to open it read-only.",
+						},
+						"query": map[string]any{
+							"type":        "string",
+							"description": "The SQL statement to run.",
+						},
+					},
+					"required": []string{"dsn", "query"},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        ListToolsName,
+				Description: "Returns a JSON array with the name and description of every tool currently available. Use it to check which tools exist before calling one you are unsure about.",
+				Parameters: map[string]any{
+					"type":       "object",
+					"properties": map[string]any{},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        SkillInfoName,
+				Description: "Returns metadata about the active skill as a JSON object: its name, description, version, the relative paths of its scripts, references and assets, and its allowed tools.",
+				Parameters: map[string]any{
+					"type":       "object",
+					"properties": map[string]any{},
+				},
+			},
+		},
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        ConstructToolCallName,
+				Description: "Calls another tool whose arguments are hard to write as JSON directly. Describe the arguments in plain language; they are turned into valid JSON for that tool's parameters and the tool is run, returning its output.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"tool_name": map[string]any{
+							"type":        "string",
+							"description": "The name of the tool to call.",
+						},
+						"params_description": map[string]any{
+							"type":        "string",
+							"description": "A description of the argument values to pass, including any computed values.",
+						},
+					},
+					"required": []string{"tool_name", "params_description"},
+				},
+			},
+		},
 		// {
 		// 	Type: openai.ToolTypeFunction,
 		// 	Function: &openai.FunctionDefinition{
@@ -188,3 +624,130 @@ func GetBaseTools() []openai.Tool {
 		// },
 	}
 }
+
+// GetBrowserTools returns the tools that require a headless Chrome/Chromium browser.
+// They are only offered to the LLM when browser tools are enabled.
+func GetBrowserTools() []openai.Tool {
+	return []openai.Tool{
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "web_screenshot",
+				Description: "Renders a web page in a headless browser and returns a screenshot of it as a base64-encoded PNG string.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"url": map[string]any{
+							"type":        "string",
+							"description": "The full URL of the page to capture, including the protocol (e.g., 'https://example.com').",
+						},
+						"width": map[string]any{
+							"type":        "integer",
+							"description": "The viewport width in pixels. Defaults to 1280.",
+						},
+						"height": map[string]any{
+							"type":        "integer",
+							"description": "The viewport height in pixels. Defaults to 800.",
+						},
+						"wait_ms": map[string]any{
+							"type":        "integer",
+							"description": "Milliseconds to wait after the page loads so JavaScript can settle. Defaults to 500, at most 10000.",
+						},
+					},
+					"required": []string{"url"},
+				},
+			},
+		},
+	}
+}
+
+// GetDockerTools returns the tools that run commands in Docker containers. They are only
+// offered to the LLM when Docker is enabled.
+func GetDockerTools() []openai.Tool {
+	return []openai.Tool{
+		{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        "run_docker_command",
+				Description: "Runs a shell command in a new, isolated Docker container and returns its combined stdout and stderr. Only allowed images can be used; the container is removed afterwards.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"image": map[string]any{
+							"type":        "string",
+							"description": "The Docker image to run, e.g. 'python:3.12-slim'.",
+						},
+						"command": map[string]any{
+							"type":        "string",
+							"description": "The command to run with sh -c in the container.",
+						},
+						"volumes": map[string]any{
+							"type":        "array",
+							"description": "Host paths to mount into the container. Only the skill directory and paths allowed by the user can be mounted.",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"source": map[string]any{
+										"type":        "string",
+										"description": "The path on the host.",
+									},
+									"target": map[string]any{
+										"type":        "string",
+										"description": "The absolute path in the container.",
+									},
+									"writable": map[string]any{
+										"type":        "boolean",
+										"description": "Mount with write access. Defaults to false (read-only).",
+									},
+								},
+								"required": []string{"source", "target"},
+							},
+						},
+						"env": map[string]any{
+							"type":        "object",
+							"description": "Environment variables to set in the container.",
+						},
+					},
+					"required": []string{"image", "command"},
+				},
+			},
+		},
+	}
+}
+
+// ListToolsName is the name of the introspection tool. It is always offered to the LLM,
+// even when a skill restricts its tools with allowed-tools.
+const ListToolsName = "list_tools"
+
+// SkillInfoName is the name of the tool that describes the active skill. Like list_tools
+// it is always offered; the runner answers it from the active skill package.
+const SkillInfoName = "skill_info"
+
+// ConstructToolCallName is the name of the meta-tool that builds another tool's JSON
+// arguments from a description with a separate LLM call; the runner then runs that tool.
+const ConstructToolCallName = "construct_tool_call"
+
+// ToolSummary is the name and description of a tool as returned by list_tools.
+type ToolSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ListTools returns the names and descriptions of tools as a JSON array, in order.
+// Tools offered more than once are listed once.
+func ListTools(tools []openai.Tool) (string, error) {
+	summaries := make([]ToolSummary, 0, len(tools))
+	seen := make(map[string]bool)
+	for _, t := range tools {
+		if t.Function == nil || seen[t.Function.Name] {
+			continue
+		}
+		seen[t.Function.Name] = true
+		summaries = append(summaries, ToolSummary{Name: t.Function.Name, Description: t.Function.Description})
+	}
+	data, err := json.Marshal(summaries)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -12,7 +12,7 @@ func TestGetBaseTools(t *testing.T) {
 	tools := GetBaseTools()
 
 	// Test that we get the expected number of tools
-	expectedCount := 8 // Based on the current implementation
+	expectedCount := 28 // Based on the current implementation
 	if len(tools) != expectedCount {
 		t.Errorf("GetBaseTools() returned %d tools, expected %d", len(tools), expectedCount)
 	}
@@ -60,8 +60,8 @@ func TestGetBaseTools(t *testing.T) {
 			continue
 		}
 
-		// Each tool should have at least one property
-		if len(properties) == 0 {
+		// Each tool should have at least one property, except the parameterless list_tools and skill_info
+		if len(properties) == 0 && tool.Function.Name != ListToolsName && tool.Function.Name != SkillInfoName {
 			t.Errorf("Tool %d has no properties defined", i)
 		}
 	}
@@ -80,10 +80,26 @@ func TestGetBaseToolsSpecificTools(t *testing.T) {
 		"run_shell_script",
 		"run_python_code",
 		"run_python_script",
+		"run_node_code",
+		"run_node_script",
 		"read_file",
+		"batch_read_files",
 		"write_file",
+		"parse_structured_data",
+		"copy_file",
+		"move_file",
 		"wikipedia_search",
 		"tavily_search",
+		"web_search",
+		"execute_sql",
+		"get_env",
+		"git_log",
+		"git_diff",
+		"memory_set",
+		"memory_get",
+		"list_tools",
+		"skill_info",
+		"read_url_raw",
 	}
 
 	for _, expectedTool := range expectedTools {
@@ -127,18 +143,48 @@ func TestToolDefinitionsStructure(t *testing.T) {
 			expectedParams: []string{"scriptPath", "args"},
 			requiredParams: []string{"scriptPath"},
 		},
+		{
+			name:           "run_node_code",
+			expectedDesc:   "Executes a JavaScript code snippet with Node.js and returns its combined stdout and stderr.",
+			expectedParams: []string{"code", "args"},
+			requiredParams: []string{"code"},
+		},
+		{
+			name:           "run_node_script",
+			expectedDesc:   "Executes a JavaScript script with Node.js, or a TypeScript (.ts) script with ts-node, and returns its combined stdout and stderr.",
+			expectedParams: []string{"scriptPath", "args"},
+			requiredParams: []string{"scriptPath"},
+		},
 		{
 			name:           "read_file",
 			expectedDesc:   "Reads the content of a file and returns it as a string.",
 			expectedParams: []string{"filePath"},
 			requiredParams: []string{"filePath"},
 		},
+		{
+			name:           "batch_read_files",
+			expectedDesc:   "Reads several files in one call and returns a JSON object mapping each path to its content. Files that cannot be read map to an object with an \"error\" key.",
+			expectedParams: []string{"file_paths"},
+			requiredParams: []string{"file_paths"},
+		},
 		{
 			name:           "write_file",
 			expectedDesc:   "Writes the given content to a file. If the file does not exist, it will be created. If it exists, its content will be truncated.",
 			expectedParams: []string{"filePath", "content"},
 			requiredParams: []string{"filePath", "content"},
 		},
+		{
+			name:           "copy_file",
+			expectedDesc:   "Copies a file to a new location, creating intermediate directories as needed. Fails if the destination exists unless overwrite is true.",
+			expectedParams: []string{"source", "destination", "overwrite"},
+			requiredParams: []string{"source", "destination"},
+		},
+		{
+			name:           "move_file",
+			expectedDesc:   "Moves (renames) a file to a new location, creating intermediate directories as needed. Fails if the destination exists unless overwrite is true.",
+			expectedParams: []string{"source", "destination", "overwrite"},
+			requiredParams: []string{"source", "destination"},
+		},
 		{
 			name:           "wikipedia_search",
 			expectedDesc:   "Performs a search on Wikipedia for the given query and returns a summary of the relevant entry.",
@@ -359,8 +405,54 @@ func TestToolsJSONSerialization(t *testing.T) {
 	}
 }
 
+func TestGetBrowserTools(t *testing.T) {
+	tools := GetBrowserTools()
+	if len(tools) != 1 {
+		t.Fatalf("GetBrowserTools() returned %d tools, expected 1", len(tools))
+	}
+
+	fn := tools[0].Function
+	if fn.Name != "web_screenshot" {
+		t.Errorf("GetBrowserTools() tool name = %s, expected web_screenshot", fn.Name)
+	}
+
+	params := fn.Parameters.(map[string]any)
+	properties := params["properties"].(map[string]any)
+	for _, name := range []string{"url", "width", "height", "wait_ms"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("web_screenshot missing parameter %s", name)
+		}
+	}
+	if !reflect.DeepEqual(params["required"], []string{"url"}) {
+		t.Errorf("web_screenshot required parameters = %v, expected [url]", params["required"])
+	}
+}
+
 func BenchmarkGetBaseTools(b *testing.B) {
 	for b.Loop() {
 		_ = GetBaseTools()
 	}
 }
+
+func TestListTools(t *testing.T) {
+	tools := append(GetBaseTools(), GetBaseTools()[0])
+	output, err := ListTools(tools)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+
+	var summaries []ToolSummary
+	if err := json.Unmarshal([]byte(output), &summaries); err != nil {
+		t.Fatalf("ListTools() output is not a JSON array of tools: %v", err)
+	}
+	// The duplicated tool is listed once
+	if len(summaries) != len(GetBaseTools()) {
+		t.Fatalf("ListTools() returned %d tools, expected %d", len(summaries), len(GetBaseTools()))
+	}
+	for i, tool := range GetBaseTools() {
+		want := ToolSummary{Name: tool.Function.Name, Description: tool.Function.Description}
+		if summaries[i] != want {
+			t.Errorf("ListTools()[%d] = %+v, expected %+v", i, summaries[i], want)
+		}
+	}
+}
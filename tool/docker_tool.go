@@ -0,0 +1,137 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mount is a host directory or file bind-mounted into a container.
+type Mount struct {
+	Source   string `json:"source"`   // Path on the host; relative paths are resolved against the working directory
+	Target   string `json:"target"`   // Absolute path in the container
+	Writable bool   `json:"writable"` // Mount with write access; mounts are read-only by default
+}
+
+// CheckDockerImage returns an error unless image is allowed. An allowed entry without a
+// tag or digest, such as "python", allows every tag of that image; "python:3.12" only
+// allows that tag.
+func CheckDockerImage(image string, allowed []string) error {
+	if len(allowed) == 0 {
+		return errors.New("no Docker images are allowed (see --allow-docker-image)")
+	}
+	repo := dockerImageRepository(image)
+	for _, a := range allowed {
+		if image == a || (a == dockerImageRepository(a) && repo == a) {
+			return nil
+		}
+	}
+	return fmt.Errorf("docker image '%s' is not in the allowed images %v", image, allowed)
+}
+
+// CheckDockerVolumes returns an error unless the source of every volume is one of the
+// allowed directories or lies inside one. Symlinks are resolved before comparing, so
+// sources must exist.
+func CheckDockerVolumes(volumes []Mount, allowed []string) error {
+	for _, m := range volumes {
+		source, err := resolvePath(m.Source)
+		if err != nil {
+			return err
+		}
+		if !withinAny(source, allowed) {
+			return fmt.Errorf("volume source '%s' is not in the skill directory or the allowed volume paths (see --allow-docker-volume)", m.Source)
+		}
+	}
+	return nil
+}
+
+// dockerImageRepository returns image without its tag or digest.
+func dockerImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// DockerRunArgs returns the arguments of the 'docker run' invocation that runs command
+// with sh in a new container of image named name, which is removed when it exits.
+func DockerRunArgs(name, image, command string, volumes []Mount, env map[string]string) ([]string, error) {
+	args := []string{"run", "--rm", "--name", name}
+	for _, m := range volumes {
+		if m.Source == "" || !strings.HasPrefix(m.Target, "/") {
+			return nil, fmt.Errorf("invalid volume %q:%q (need a source and an absolute target)", m.Source, m.Target)
+		}
+		source, err := filepath.Abs(m.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve volume source '%s': %w", m.Source, err)
+		}
+		volume := source + ":" + m.Target
+		if !m.Writable {
+			volume += ":ro"
+		}
+		args = append(args, "-v", volume)
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+env[k])
+	}
+	return append(args, image, "sh", "-c", command), nil
+}
+
+// RunDockerCommand runs command with sh in a new container of image using the docker
+// CLI and returns its combined stdout and stderr. The container is removed when it
+// exits. When timeout is positive the container is killed after that long and the
+// returned error wraps context.DeadlineExceeded.
+func RunDockerCommand(image, command string, volumes []Mount, env map[string]string, timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return RunDockerCommandContext(ctx, image, command, volumes, env)
+}
+
+// RunDockerCommandContext is like RunDockerCommand but kills the container when ctx
+// is done.
+func RunDockerCommandContext(ctx context.Context, image, command string, volumes []Mount, env map[string]string) (string, error) {
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	name := "goskills-" + hex.EncodeToString(suffix)
+	args, err := DockerRunArgs(name, image, command, volumes, env)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.WaitDelay = processWaitDelay
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		// Stopping the docker CLI leaves the container running
+		exec.Command("docker", "kill", name).Run()
+		return "", fmt.Errorf("docker command in image '%s' was stopped: %w\nStdout: %s\nStderr: %s", image, ctx.Err(), stdout.String(), stderr.String())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run docker command in image '%s': %w\nStdout: %s\nStderr: %s", image, err, stdout.String(), stderr.String())
+	}
+	return stdout.String() + stderr.String(), nil
+}
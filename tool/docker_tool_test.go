@@ -0,0 +1,210 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDocker puts a docker executable on PATH that appends its arguments to the
+// returned file, one invocation per line, and otherwise acts on the sh -c command:
+// "exit 3" fails, "sleep" hangs and anything else prints a greeting.
+func fakeDocker(t *testing.T) string {
+	t.Helper()
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "args.txt")
+	script := `#!/bin/sh
+printf '%s\n' "$*" >> "` + argsFile + `"
+[ "$1" = kill ] && exit 0
+case "$*" in
+*"exit 3"*) echo boom >&2; exit 3 ;;
+*sleep*) sleep 5 ;;
+esac
+echo "hello from container"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake docker: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func readInvocations(t *testing.T, argsFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("Failed to read docker arguments: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestCheckDockerImage(t *testing.T) {
+	allowed := []string{"python", "alpine:3.20", "localhost:5000/tools"}
+	tests := []struct {
+		image string
+		ok    bool
+	}{
+		{"python", true},
+		{"python:3.12-slim", true},
+		{"python@sha256:abc", true},
+		{"alpine:3.20", true},
+		{"alpine", false},
+		{"alpine:latest", false},
+		{"localhost:5000/tools:1.0", true},
+		{"localhost:5000/other", false},
+		{"pythonista", false},
+		{"evil/python", false},
+	}
+	for _, tt := range tests {
+		err := CheckDockerImage(tt.image, allowed)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckDockerImage(%q) error = %v, want allowed %v", tt.image, err, tt.ok)
+		}
+	}
+
+	if err := CheckDockerImage("python", nil); err == nil || !strings.Contains(err.Error(), "no Docker images are allowed") {
+		t.Errorf("CheckDockerImage() without allowed images error = %v", err)
+	}
+}
+
+func TestDockerRunArgs(t *testing.T) {
+	args, err := DockerRunArgs("box", "python:3.12", "python /work/run.py", []Mount{
+		{Source: "/data/in", Target: "/work", Writable: true},
+		{Source: "/data/cache", Target: "/cache"},
+	}, map[string]string{"MODE": "fast", "DEBUG": "1"})
+	if err != nil {
+		t.Fatalf("DockerRunArgs() error = %v", err)
+	}
+	want := []string{
+		"run", "--rm", "--name", "box",
+		"-v", "/data/in:/work",
+		"-v", "/data/cache:/cache:ro",
+		"-e", "DEBUG=1",
+		"-e", "MODE=fast",
+		"python:3.12", "sh", "-c", "python /work/run.py",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("DockerRunArgs() = %q, want %q", args, want)
+	}
+
+	// Relative sources are made absolute; mounts are read-only by default
+	args, err = DockerRunArgs("box", "alpine", "ls", []Mount{{Source: "data", Target: "/data"}}, nil)
+	if err != nil {
+		t.Fatalf("DockerRunArgs() error = %v", err)
+	}
+	wd, _ := os.Getwd()
+	if got, want := args[5], filepath.Join(wd, "data")+":/data:ro"; got != want {
+		t.Errorf("DockerRunArgs() volume = %q, want %q", got, want)
+	}
+
+	for _, m := range []Mount{{Source: "", Target: "/data"}, {Source: "/data", Target: "data"}} {
+		if _, err := DockerRunArgs("box", "alpine", "ls", []Mount{m}, nil); err == nil {
+			t.Errorf("DockerRunArgs() with volume %+v expected error, got nil", m)
+		}
+	}
+}
+
+func TestCheckDockerVolumes(t *testing.T) {
+	skillDir := t.TempDir()
+	dataDir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(skillDir, "scripts"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	link := filepath.Join(skillDir, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	allowed := []string{skillDir, dataDir}
+
+	for _, source := range []string{skillDir, filepath.Join(skillDir, "scripts"), dataDir} {
+		if err := CheckDockerVolumes([]Mount{{Source: source, Target: "/mnt"}}, allowed); err != nil {
+			t.Errorf("CheckDockerVolumes(%q) error = %v", source, err)
+		}
+	}
+	for _, source := range []string{outside, link, filepath.Join(skillDir, ".."), "/", filepath.Join(skillDir, "missing")} {
+		if err := CheckDockerVolumes([]Mount{{Source: source, Target: "/mnt"}}, allowed); err == nil {
+			t.Errorf("CheckDockerVolumes(%q) expected error, got nil", source)
+		}
+	}
+	if err := CheckDockerVolumes(nil, nil); err != nil {
+		t.Errorf("CheckDockerVolumes() without volumes error = %v", err)
+	}
+}
+
+func TestRunDockerCommand(t *testing.T) {
+	argsFile := fakeDocker(t)
+	mountDir := t.TempDir()
+
+	output, err := RunDockerCommand("alpine", "echo hi", []Mount{{Source: mountDir, Target: "/mnt"}},
+		map[string]string{"GREETING": "hi"}, time.Minute)
+	if err != nil {
+		t.Fatalf("RunDockerCommand() error = %v", err)
+	}
+	if output != "hello from container\n" {
+		t.Errorf("RunDockerCommand() = %q, want %q", output, "hello from container\n")
+	}
+	invocations := readInvocations(t, argsFile)
+	if len(invocations) != 1 {
+		t.Fatalf("docker was run %d times, want 1: %q", len(invocations), invocations)
+	}
+	fields := strings.Fields(invocations[0])
+	if len(fields) < 4 || fields[0] != "run" || fields[1] != "--rm" || !strings.HasPrefix(fields[3], "goskills-") {
+		t.Errorf("docker arguments = %q, want 'run --rm --name goskills-...'", invocations[0])
+	}
+	if want := "-v " + mountDir + ":/mnt:ro -e GREETING=hi alpine sh -c echo hi"; !strings.HasSuffix(invocations[0], want) {
+		t.Errorf("docker arguments = %q, want suffix %q", invocations[0], want)
+	}
+
+	_, err = RunDockerCommand("alpine", "exit 3", nil, nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("RunDockerCommand() with failing command error = %v, want stderr in error", err)
+	}
+}
+
+func TestRunDockerCommandTimeout(t *testing.T) {
+	argsFile := fakeDocker(t)
+
+	start := time.Now()
+	_, err := RunDockerCommand("alpine", "sleep 60", nil, nil, 100*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RunDockerCommand() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("RunDockerCommand() returned after %s, want shortly after the timeout", elapsed)
+	}
+
+	// The container is killed by name
+	invocations := readInvocations(t, argsFile)
+	if len(invocations) != 2 {
+		t.Fatalf("docker was run %d times, want 2: %q", len(invocations), invocations)
+	}
+	name := strings.Fields(invocations[0])[3]
+	if invocations[1] != "kill "+name {
+		t.Errorf("second docker invocation = %q, want %q", invocations[1], "kill "+name)
+	}
+}
+
+func TestRunDockerCommandContext(t *testing.T) {
+	argsFile := fakeDocker(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := RunDockerCommandContext(ctx, "alpine", "sleep 60", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunDockerCommandContext() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("RunDockerCommandContext() returned after %s, want shortly after cancellation", elapsed)
+	}
+	invocations := readInvocations(t, argsFile)
+	if len(invocations) != 2 || !strings.HasPrefix(invocations[1], "kill goskills-") {
+		t.Errorf("docker invocations = %q, want the container to be killed", invocations)
+	}
+}
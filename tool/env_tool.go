@@ -0,0 +1,35 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DefaultAllowedEnvVars are the environment variables exposed by GetEnv when no allowlist is configured.
+var DefaultAllowedEnvVars = []string{"HOME", "USER", "PWD", "PATH"}
+
+// RedactedEnvValue is returned for environment variables that are not in the allowlist.
+const RedactedEnvValue = "<redacted>"
+
+// GetEnv returns the values of the requested environment variables as a JSON object.
+// Keys that are not in allowed are reported as RedactedEnvValue; unset variables map to "".
+func GetEnv(keys []string, allowed []string) (string, error) {
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if !slices.Contains(allowed, key) {
+			values[key] = RedactedEnvValue
+			continue
+		}
+		values[key] = os.Getenv(key)
+	}
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(values); err != nil {
+		return "", fmt.Errorf("failed to marshal environment variables: %w", err)
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
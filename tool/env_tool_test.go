@@ -0,0 +1,44 @@
+package tool
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetEnv(t *testing.T) {
+	t.Setenv("GOSKILLS_TEST_VISIBLE", "visible")
+	t.Setenv("GOSKILLS_TEST_SECRET", "secret")
+
+	output, err := GetEnv([]string{"GOSKILLS_TEST_VISIBLE", "GOSKILLS_TEST_SECRET", "GOSKILLS_TEST_UNSET"},
+		[]string{"GOSKILLS_TEST_VISIBLE", "GOSKILLS_TEST_UNSET"})
+	if err != nil {
+		t.Fatalf("GetEnv() error = %v", err)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(output), &values); err != nil {
+		t.Fatalf("GetEnv() returned invalid JSON %q: %v", output, err)
+	}
+	want := map[string]string{
+		"GOSKILLS_TEST_VISIBLE": "visible",
+		"GOSKILLS_TEST_SECRET":  RedactedEnvValue,
+		"GOSKILLS_TEST_UNSET":   "",
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("GetEnv()[%s] = %q, want %q", key, values[key], value)
+		}
+	}
+}
+
+func TestGetEnv_DefaultAllowlist(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+
+	output, err := GetEnv([]string{"HOME", "OPENAI_API_KEY"}, DefaultAllowedEnvVars)
+	if err != nil {
+		t.Fatalf("GetEnv() error = %v", err)
+	}
+	if want := `{"HOME":"/home/test","OPENAI_API_KEY":"<redacted>"}`; output != want {
+		t.Errorf("GetEnv() = %s, want %s", output, want)
+	}
+}
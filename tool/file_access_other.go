@@ -0,0 +1,17 @@
+//go:build !unix
+
+package tool
+
+import "os"
+
+const (
+	readAccess   = 04
+	writeAccess  = 02
+	searchAccess = 01
+)
+
+// hasAccess reports whether the owner permission bits of perm allow mode.
+// Platforms without access(2) fall back to the mode bits alone.
+func hasAccess(_ string, perm os.FileMode, mode uint32) bool {
+	return uint32(perm.Perm()>>6)&mode == mode
+}
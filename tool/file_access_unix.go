@@ -0,0 +1,21 @@
+//go:build unix
+
+package tool
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	readAccess   = unix.R_OK
+	writeAccess  = unix.W_OK
+	searchAccess = unix.X_OK
+)
+
+// hasAccess reports whether the current process may access path with mode,
+// taking ownership, group membership and privileges into account.
+func hasAccess(path string, _ os.FileMode, mode uint32) bool {
+	return unix.Access(path, mode) == nil
+}
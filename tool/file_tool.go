@@ -1,12 +1,96 @@
 package tool
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"gopkg.in/yaml.v3"
 )
 
+// rename is os.Rename, replaceable in tests to simulate cross-device moves.
+var rename = os.Rename
+
+// PermissionError reports that a file operation was refused because the current
+// process may not access the file.
+type PermissionError struct {
+	Path               string
+	RequiredPermission string // "read" or "write"
+	ActualPermissions  os.FileMode
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("permission denied: '%s' is not %s (permissions %s)", e.Path, permissionAdjective(e.RequiredPermission), e.ActualPermissions.Perm())
+}
+
+// Is makes errors.Is(err, fs.ErrPermission) match a PermissionError.
+func (e *PermissionError) Is(target error) bool {
+	return target == fs.ErrPermission
+}
+
+func permissionAdjective(permission string) string {
+	if permission == "write" {
+		return "writable"
+	}
+	return "readable"
+}
+
+// CheckReadPermission returns a *PermissionError when path exists but the
+// current process may not read it. Other stat failures, such as a missing file,
+// are left for the read itself to report.
+func CheckReadPermission(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !hasAccess(path, info.Mode(), readAccess) {
+		return &PermissionError{Path: path, RequiredPermission: "read", ActualPermissions: info.Mode().Perm()}
+	}
+	return nil
+}
+
+// CheckWritePermission returns a *PermissionError when path exists and the
+// current process may not write it, or when path does not exist yet and its
+// parent directory cannot be written to.
+func CheckWritePermission(path string) error {
+	info, err := os.Stat(path)
+	if err == nil {
+		if !hasAccess(path, info.Mode(), writeAccess) {
+			return &PermissionError{Path: path, RequiredPermission: "write", ActualPermissions: info.Mode().Perm()}
+		}
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+	if !hasAccess(dir, dirInfo.Mode(), writeAccess|searchAccess) {
+		return &PermissionError{Path: dir, RequiredPermission: "write", ActualPermissions: dirInfo.Mode().Perm()}
+	}
+	return nil
+}
+
 // ReadFile reads the content of a file and returns it as a string.
 func ReadFile(filePath string) (string, error) {
+	if err := CheckReadPermission(filePath); err != nil {
+		return "", err
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file '%s': %w", filePath, err)
@@ -14,12 +98,577 @@ func ReadFile(filePath string) (string, error) {
 	return string(content), nil
 }
 
+// MaxBatchReadFiles is the maximum number of files a single BatchReadFiles call reads.
+const MaxBatchReadFiles = 50
+
+// BatchReadFiles reads several files at once and returns a map from each requested path
+// to its content. A file that cannot be read does not fail the call; its value is a map
+// with an "error" key instead. Paths containing ".." are rejected this way as well.
+func BatchReadFiles(paths []string) (map[string]any, error) {
+	return BatchReadFilesIn("", paths)
+}
+
+// BatchReadFilesIn is like BatchReadFiles, but a relative path is read from baseDir when
+// the file exists there, as the read_file tool does for skill files. The keys of the
+// result are the paths as given.
+func BatchReadFilesIn(baseDir string, paths []string) (map[string]any, error) {
+	if len(paths) > MaxBatchReadFiles {
+		return nil, fmt.Errorf("too many files: %d requested, at most %d can be read at once", len(paths), MaxBatchReadFiles)
+	}
+	results := make(map[string]any, len(paths))
+	for _, path := range paths {
+		if hasParentReference(path) {
+			results[path] = map[string]string{"error": fmt.Sprintf("path '%s' must not contain '..'", path)}
+			continue
+		}
+		resolved := path
+		if !filepath.IsAbs(path) && baseDir != "" {
+			if _, err := os.Stat(filepath.Join(baseDir, path)); err == nil {
+				resolved = filepath.Join(baseDir, path)
+			}
+		}
+		content, err := ReadFile(resolved)
+		if err != nil {
+			results[path] = map[string]string{"error": err.Error()}
+			continue
+		}
+		results[path] = content
+	}
+	return results, nil
+}
+
+// hasParentReference reports whether path has a ".." element.
+func hasParentReference(path string) bool {
+	for _, elem := range strings.FieldsFunc(filepath.ToSlash(path), func(r rune) bool { return r == '/' }) {
+		if elem == ".." {
+			return true
+		}
+	}
+	return false
+}
+
 // WriteFile writes the given content to a file.
 // If the file does not exist, it will be created. If it exists, its content will be truncated.
 func WriteFile(filePath string, content string) error {
+	if err := CheckWritePermission(filePath); err != nil {
+		return err
+	}
 	err := os.WriteFile(filePath, []byte(content), 0644) // 0644 is standard file permissions
 	if err != nil {
 		return fmt.Errorf("failed to write to file '%s': %w", filePath, err)
 	}
 	return nil
 }
+
+// SearchReplaceFile replaces occurrences of search in the file with replacement and
+// returns the number of replacements made. A count of -1 replaces every occurrence and
+// a positive count replaces the first count occurrences. The file is only rewritten
+// when something was replaced, and keeps its permissions.
+func SearchReplaceFile(filePath, search, replacement string, count int) (int, error) {
+	if search == "" {
+		return 0, errors.New("search string must not be empty")
+	}
+	return replaceInFile(filePath, count, func(content string, n int) (string, int) {
+		found := strings.Count(content, search)
+		if n >= 0 {
+			found = min(found, n)
+		}
+		return strings.Replace(content, search, replacement, n), found
+	})
+}
+
+// SearchReplaceFileRegexp is like SearchReplaceFile, but search is a regular expression
+// and replacement may refer to its submatches as $1 or ${name}, as in
+// regexp.Regexp.Expand.
+func SearchReplaceFileRegexp(filePath, pattern, replacement string, count int) (int, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("invalid search pattern: %w", err)
+	}
+	return replaceInFile(filePath, count, func(content string, n int) (string, int) {
+		matches := re.FindAllStringSubmatchIndex(content, n)
+		var sb strings.Builder
+		last := 0
+		for _, m := range matches {
+			sb.WriteString(content[last:m[0]])
+			sb.Write(re.ExpandString(nil, replacement, content, m))
+			last = m[1]
+		}
+		sb.WriteString(content[last:])
+		return sb.String(), len(matches)
+	})
+}
+
+// replaceInFile validates count, applies replace to the file's content and writes the
+// result back when replace made replacements.
+func replaceInFile(filePath string, count int, replace func(content string, n int) (string, int)) (int, error) {
+	if count == 0 || count < -1 {
+		return 0, fmt.Errorf("count must be -1 (replace all) or positive, got %d", count)
+	}
+	if err := CheckReadPermission(filePath); err != nil {
+		return 0, err
+	}
+	if err := CheckWritePermission(filePath); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+	updated, replaced := replace(string(content), count)
+	if replaced == 0 {
+		return 0, nil
+	}
+	if err := os.WriteFile(filePath, []byte(updated), info.Mode().Perm()); err != nil {
+		return 0, fmt.Errorf("failed to write to file '%s': %w", filePath, err)
+	}
+	return replaced, nil
+}
+
+// CopyFile copies the file at src to dst, creating any missing parent directories of dst.
+// It refuses to replace an existing destination.
+func CopyFile(src, dst string) error {
+	return CopyFileWithOverwrite(src, dst, false)
+}
+
+// CopyFileWithOverwrite copies the file at src to dst, creating any missing parent directories of dst.
+// An existing destination is only replaced when overwrite is true.
+func CopyFileWithOverwrite(src, dst string, overwrite bool) error {
+	info, err := prepareDestination(src, dst, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to copy '%s' to '%s': %w", src, dst, err)
+	}
+	if err := copyFileContents(src, dst, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to copy '%s' to '%s': %w", src, dst, err)
+	}
+	return nil
+}
+
+// MoveFile moves the file at src to dst, creating any missing parent directories of dst.
+// It refuses to replace an existing destination.
+func MoveFile(src, dst string) error {
+	return MoveFileWithOverwrite(src, dst, false)
+}
+
+// MoveFileWithOverwrite moves the file at src to dst, creating any missing parent directories of dst.
+// An existing destination is only replaced when overwrite is true. When src and dst are on
+// different devices the file is copied and the source removed afterwards.
+func MoveFileWithOverwrite(src, dst string, overwrite bool) error {
+	info, err := prepareDestination(src, dst, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to move '%s' to '%s': %w", src, dst, err)
+	}
+
+	err = rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("failed to move '%s' to '%s': %w", src, dst, err)
+	}
+
+	// Rename cannot cross filesystem boundaries, fall back to copy and delete
+	if err := copyFileContents(src, dst, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to move '%s' to '%s': %w", src, dst, err)
+	}
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("copied '%s' to '%s' but failed to remove the source: %w", src, dst, err)
+	}
+	return nil
+}
+
+// prepareDestination validates src, enforces the overwrite policy for dst and creates
+// the parent directories of dst. It returns the file info of src.
+func prepareDestination(src, dst string, overwrite bool) (os.FileInfo, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("source '%s' is a directory", src)
+	}
+
+	if dstInfo, err := os.Stat(dst); err == nil {
+		if dstInfo.IsDir() {
+			return nil, fmt.Errorf("destination '%s' is a directory", dst)
+		}
+		if !overwrite {
+			return nil, fmt.Errorf("destination '%s' already exists (set overwrite to true to replace it)", dst)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	return info, nil
+}
+
+// copyFileContents copies the bytes of src into dst, truncating dst if it exists.
+func copyFileContents(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// MaxCodeSearchMatches caps the number of matches a CodeSearch call returns.
+const MaxCodeSearchMatches = 500
+
+// SearchMatch is a line matched by CodeSearch. File is relative to the searched directory.
+type SearchMatch struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"line_number"`
+	Line       string `json:"line"`
+}
+
+// CodeSearch searches the files under dir whose base name matches fileGlob ("*" or empty
+// for all files) for lines matching the regular expression pattern. Hidden directories
+// such as .git and binary files are skipped. At most MaxCodeSearchMatches matches are
+// returned, in file and line order.
+func CodeSearch(dir, pattern string, fileGlob string, caseSensitive bool) ([]SearchMatch, error) {
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern: %w", err)
+	}
+	if fileGlob == "" {
+		fileGlob = "*"
+	}
+	if _, err := filepath.Match(fileGlob, ""); err != nil {
+		return nil, fmt.Errorf("invalid file pattern '%s': %w", fileGlob, err)
+	}
+
+	var matches []SearchMatch
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := filepath.Match(fileGlob, d.Name()); !ok || !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			return nil // binary file
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if !re.MatchString(line) {
+				continue
+			}
+			matches = append(matches, SearchMatch{File: rel, LineNumber: i + 1, Line: line})
+			if len(matches) == MaxCodeSearchMatches {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search '%s': %w", dir, err)
+	}
+	return matches, nil
+}
+
+// DefaultDiffContextLines is the number of unchanged lines CompareFiles shows around each change.
+const DefaultDiffContextLines = 3
+
+// CompareFiles returns a unified diff of the text files at pathA and pathB with
+// DefaultDiffContextLines lines of context, or "Files are identical." when they match.
+func CompareFiles(pathA, pathB string) (string, error) {
+	return CompareFilesWithContext(pathA, pathB, DefaultDiffContextLines)
+}
+
+// CompareFilesWithContext is like CompareFiles but shows contextLines unchanged lines
+// around each change.
+func CompareFilesWithContext(pathA, pathB string, contextLines int) (string, error) {
+	if contextLines < 0 {
+		return "", fmt.Errorf("context_lines must not be negative, got %d", contextLines)
+	}
+	a, err := ReadFile(pathA)
+	if err != nil {
+		return "", err
+	}
+	b, err := ReadFile(pathB)
+	if err != nil {
+		return "", err
+	}
+	if a == b {
+		return "Files are identical.", nil
+	}
+	return unifiedDiff(pathA, pathB, lineDiff(a, b), contextLines), nil
+}
+
+// diffLine is one line of a line-level diff. op is ' ', '-' or '+'; text keeps
+// its trailing newline, if any.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+func lineDiff(a, b string) []diffLine {
+	dmp := diffmatchpatch.New()
+	charsA, charsB, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(charsA, charsB, false), lines)
+
+	var result []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line != "" {
+				result = append(result, diffLine{op: op, text: line})
+			}
+		}
+	}
+	return result
+}
+
+// unifiedDiff renders lines as unified diff hunks, merging changes that are at most
+// 2*contextLines unchanged lines apart into one hunk.
+func unifiedDiff(nameA, nameB string, lines []diffLine, contextLines int) string {
+	// posA[i] and posB[i] count the lines of each file that precede lines[i].
+	posA := make([]int, len(lines)+1)
+	posB := make([]int, len(lines)+1)
+	for i, l := range lines {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if l.op != '+' {
+			posA[i+1]++
+		}
+		if l.op != '-' {
+			posB[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while the next change is close enough.
+		last := i
+		for j := i + 1; j < len(lines) && j-last-1 <= 2*contextLines; j++ {
+			if lines[j].op != ' ' {
+				last = j
+			}
+		}
+		start := max(0, i-contextLines)
+		end := min(len(lines), last+contextLines+1)
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(posA[start], posA[end]-posA[start]),
+			hunkRange(posB[start], posB[end]-posB[start]))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats the range of a hunk header. before is the number of lines
+// preceding the hunk; an empty range refers to the line before it, as diff does.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, count)
+	}
+}
+
+// TableData is structured data flattened into a table of strings.
+type TableData struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// String renders the table as Markdown.
+func (t TableData) String() string {
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(escapeCells(t.Headers), " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(t.Headers)) + "\n")
+	for _, row := range t.Rows {
+		sb.WriteString("| " + strings.Join(escapeCells(row), " | ") + " |\n")
+	}
+	if len(t.Rows) == 0 {
+		sb.WriteString("\n(no rows)\n")
+	}
+	return sb.String()
+}
+
+// ParseStructuredData parses a CSV, JSON, YAML or TOML file into a table, detecting the
+// format from the file extension.
+func ParseStructuredData(filePath string) (TableData, error) {
+	return ParseStructuredDataWithFormat(filePath, "")
+}
+
+// ParseStructuredDataWithFormat parses a file into a table using the given format
+// ("csv", "json", "yaml" or "toml"). An empty format is detected from the file extension.
+//
+// The first CSV record holds the headers. For the other formats a list of objects becomes
+// one row per object with the union of their keys as sorted headers, a single object
+// becomes one row, and a top-level object whose only key holds a list (such as a TOML
+// array of tables) is unwrapped. Nested values are rendered as JSON.
+func ParseStructuredDataWithFormat(filePath, format string) (TableData, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), ".")
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return TableData{}, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+
+	var data any
+	switch strings.ToLower(format) {
+	case "csv":
+		records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+		if err != nil {
+			return TableData{}, fmt.Errorf("failed to parse CSV file '%s': %w", filePath, err)
+		}
+		if len(records) == 0 {
+			return TableData{}, nil
+		}
+		return TableData{Headers: records[0], Rows: records[1:]}, nil
+	case "json":
+		err = json.Unmarshal(content, &data)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(content, &data)
+	case "toml":
+		var table map[string]any
+		_, err = toml.Decode(string(content), &table)
+		data = table
+	default:
+		return TableData{}, fmt.Errorf("unsupported format '%s' for file '%s' (expected csv, json, yaml or toml)", format, filePath)
+	}
+	if err != nil {
+		return TableData{}, fmt.Errorf("failed to parse %s file '%s': %w", strings.ToUpper(format), filePath, err)
+	}
+	return valueToTable(data), nil
+}
+
+// valueToTable flattens a decoded JSON, YAML or TOML document into a table.
+func valueToTable(data any) TableData {
+	if m, ok := data.(map[string]any); ok && len(m) == 1 {
+		for _, v := range m {
+			if list := toList(v); list != nil {
+				data = list
+			}
+		}
+	}
+
+	records := toList(data)
+	if records == nil {
+		records = []any{data}
+	}
+
+	seen := make(map[string]bool)
+	var headers []string
+	for _, record := range records {
+		if m, ok := record.(map[string]any); ok {
+			for k := range m {
+				if !seen[k] {
+					seen[k] = true
+					headers = append(headers, k)
+				}
+			}
+		} else if !seen["value"] {
+			seen["value"] = true
+			headers = append(headers, "value")
+		}
+	}
+	sort.Strings(headers)
+
+	table := TableData{Headers: headers}
+	for _, record := range records {
+		row := make([]string, len(headers))
+		for i, h := range headers {
+			if m, ok := record.(map[string]any); ok {
+				if v, ok := m[h]; ok {
+					row[i] = formatCell(v)
+				}
+			} else if h == "value" {
+				row[i] = formatCell(record)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// toList returns v as a list, or nil if it is not one. TOML arrays of tables decode
+// as []map[string]any and are converted.
+func toList(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case []map[string]any:
+		list := make([]any, len(v))
+		for i, m := range v {
+			list[i] = m
+		}
+		return list
+	}
+	return nil
+}
+
+// formatCell renders a scalar as text and anything else as JSON.
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any, []map[string]any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -1,8 +1,14 @@
 package tool
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"testing/fstest"
 )
@@ -37,6 +43,73 @@ func TestReadFile(t *testing.T) {
 	}
 }
 
+func TestBatchReadFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "first.txt")
+	second := filepath.Join(tmpDir, "second.txt")
+	missing := filepath.Join(tmpDir, "missing.txt")
+	traversal := tmpDir + "/../" + filepath.Base(tmpDir) + "/first.txt"
+	if err := os.WriteFile(first, []byte("one"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(second, []byte("two"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := BatchReadFiles([]string{first, second, missing, traversal})
+	if err != nil {
+		t.Fatalf("BatchReadFiles() error = %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("BatchReadFiles() returned %d results, want 4", len(results))
+	}
+	if results[first] != "one" || results[second] != "two" {
+		t.Errorf("BatchReadFiles() contents = %v, want one and two", results)
+	}
+	for _, path := range []string{missing, traversal} {
+		failure, ok := results[path].(map[string]string)
+		if !ok || failure["error"] == "" {
+			t.Errorf("BatchReadFiles()[%q] = %v, want an error entry", path, results[path])
+		}
+	}
+	if failure, _ := results[traversal].(map[string]string); !strings.Contains(failure["error"], "must not contain '..'") {
+		t.Errorf("BatchReadFiles() traversal error = %q", failure["error"])
+	}
+
+	results, err = BatchReadFiles(nil)
+	if err != nil {
+		t.Fatalf("BatchReadFiles(nil) error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("BatchReadFiles(nil) = %v, want empty map", results)
+	}
+
+	if _, err := BatchReadFiles(make([]string, MaxBatchReadFiles+1)); err == nil {
+		t.Error("BatchReadFiles() expected error for too many files, got nil")
+	}
+}
+
+func TestBatchReadFilesIn(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(baseDir, "references"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "references", "api.md"), []byte("# API"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	results, err := BatchReadFilesIn(baseDir, []string{"references/api.md", "references/../../secret.txt"})
+	if err != nil {
+		t.Fatalf("BatchReadFilesIn() error = %v", err)
+	}
+	if results["references/api.md"] != "# API" {
+		t.Errorf("BatchReadFilesIn() = %v, want the file read from the base directory", results["references/api.md"])
+	}
+	if _, ok := results["references/../../secret.txt"].(map[string]string); !ok {
+		t.Errorf("BatchReadFilesIn() did not reject a path escaping the base directory")
+	}
+}
+
 func TestWriteFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "write_test.txt")
@@ -84,6 +157,255 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestCheckReadPermission(t *testing.T) {
+	tmpDir := t.TempDir()
+	readable := filepath.Join(tmpDir, "readable.txt")
+	unreadable := filepath.Join(tmpDir, "unreadable.txt")
+	if err := os.WriteFile(readable, []byte("ok"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(unreadable, []byte("secret"), 0200); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if err := CheckReadPermission(readable); err != nil {
+		t.Errorf("CheckReadPermission() on readable file error = %v", err)
+	}
+	if err := CheckReadPermission(filepath.Join(tmpDir, "missing.txt")); err != nil {
+		t.Errorf("CheckReadPermission() on missing file error = %v, want nil", err)
+	}
+	if os.Geteuid() == 0 {
+		// root may read any file regardless of its permission bits
+		if err := CheckReadPermission(unreadable); err != nil {
+			t.Errorf("CheckReadPermission() as root error = %v, want nil", err)
+		}
+		return
+	}
+
+	_, err := ReadFile(unreadable)
+	var permErr *PermissionError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("ReadFile() error = %v, want *PermissionError", err)
+	}
+	if permErr.Path != unreadable || permErr.RequiredPermission != "read" || permErr.ActualPermissions != 0200 {
+		t.Errorf("PermissionError = %+v", permErr)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Error("PermissionError should match fs.ErrPermission")
+	}
+	want := "permission denied: '" + unreadable + "' is not readable (permissions --w-------)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestCheckWritePermission(t *testing.T) {
+	tmpDir := t.TempDir()
+	readOnly := filepath.Join(tmpDir, "readonly.txt")
+	if err := os.WriteFile(readOnly, []byte("fixed"), 0444); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := CheckWritePermission(filepath.Join(tmpDir, "new.txt")); err != nil {
+		t.Errorf("CheckWritePermission() on new file in writable dir error = %v", err)
+	}
+	if os.Geteuid() == 0 {
+		// root may write any file regardless of its permission bits
+		if err := CheckWritePermission(readOnly); err != nil {
+			t.Errorf("CheckWritePermission() as root error = %v, want nil", err)
+		}
+		return
+	}
+
+	err := WriteFile(readOnly, "changed")
+	var permErr *PermissionError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("WriteFile() error = %v, want *PermissionError", err)
+	}
+	if permErr.RequiredPermission != "write" || permErr.ActualPermissions != 0444 {
+		t.Errorf("PermissionError = %+v", permErr)
+	}
+	if !strings.Contains(err.Error(), "is not writable (permissions -r--r--r--)") {
+		t.Errorf("Error() = %q, want a description of the missing write permission", err.Error())
+	}
+
+	lockedDir := filepath.Join(tmpDir, "locked")
+	if err := os.Mkdir(lockedDir, 0555); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(lockedDir, 0755) })
+	err = CheckWritePermission(filepath.Join(lockedDir, "new.txt"))
+	if !errors.As(err, &permErr) {
+		t.Fatalf("CheckWritePermission() error = %v, want *PermissionError", err)
+	}
+	if permErr.Path != lockedDir {
+		t.Errorf("PermissionError.Path = %q, want %q", permErr.Path, lockedDir)
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		return path
+	}
+	lines := func(from, to int, changed map[int]string) string {
+		var sb strings.Builder
+		for i := from; i <= to; i++ {
+			if s, ok := changed[i]; ok {
+				sb.WriteString(s + "\n")
+			} else {
+				sb.WriteString("line" + strconv.Itoa(i) + "\n")
+			}
+		}
+		return sb.String()
+	}
+
+	original := write("original.txt", lines(1, 20, nil))
+
+	// Identical files
+	got, err := CompareFiles(original, write("copy.txt", lines(1, 20, nil)))
+	if err != nil {
+		t.Fatalf("CompareFiles() error = %v", err)
+	}
+	if got != "Files are identical." {
+		t.Errorf("CompareFiles() = %q, want %q", got, "Files are identical.")
+	}
+
+	// Single-line change
+	single := write("single.txt", lines(1, 20, map[int]string{10: "changed"}))
+	got, err = CompareFiles(original, single)
+	if err != nil {
+		t.Fatalf("CompareFiles() error = %v", err)
+	}
+	want := "--- " + original + "\n+++ " + single + "\n" +
+		"@@ -7,7 +7,7 @@\n line7\n line8\n line9\n-line10\n+changed\n line11\n line12\n line13\n"
+	if got != want {
+		t.Errorf("CompareFiles() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Changes far apart produce separate hunks
+	multi := write("multi.txt", lines(1, 20, map[int]string{2: "second", 18: "eighteenth"}))
+	got, err = CompareFiles(original, multi)
+	if err != nil {
+		t.Fatalf("CompareFiles() error = %v", err)
+	}
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Errorf("CompareFiles() produced %d hunks, want 2:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@\n line1\n-line2\n+second\n") || !strings.Contains(got, "@@ -15,6 +15,6 @@\n") {
+		t.Errorf("CompareFiles() hunks have unexpected ranges:\n%s", got)
+	}
+
+	// A larger context merges them into one
+	got, err = CompareFilesWithContext(original, multi, 8)
+	if err != nil {
+		t.Fatalf("CompareFilesWithContext() error = %v", err)
+	}
+	if n := strings.Count(got, "@@ -"); n != 1 {
+		t.Errorf("CompareFilesWithContext() produced %d hunks, want 1:\n%s", n, got)
+	}
+
+	// Appended lines and a missing trailing newline
+	got, err = CompareFilesWithContext(write("short.txt", "a\nb"), write("long.txt", "a\nb\nc\n"), 0)
+	if err != nil {
+		t.Fatalf("CompareFilesWithContext() error = %v", err)
+	}
+	if !strings.Contains(got, "@@ -2 +2,2 @@\n-b\n\\ No newline at end of file\n+b\n+c\n") {
+		t.Errorf("CompareFilesWithContext() =\n%s", got)
+	}
+
+	// Non-existent file
+	if _, err := CompareFiles(original, filepath.Join(tmpDir, "missing.txt")); err == nil {
+		t.Error("CompareFiles() expected error for nonexistent file, got nil")
+	}
+	if _, err := CompareFilesWithContext(original, single, -1); err == nil {
+		t.Error("CompareFilesWithContext() expected error for negative context, got nil")
+	}
+}
+
+func TestCodeSearch(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.go":          "package main\n\nfunc main() {\n\tRun()\n}\n",
+		"pkg/run.go":       "package pkg\n\n// Run starts the server\nfunc Run() {}\n",
+		"README.md":        "Call run() to start.\n",
+		".git/config":      "func Run in git metadata\n",
+		"pkg/blob.bin":     "func Run\x00binary",
+		"pkg/windows.go":   "func Run() {}\r\n",
+		"pkg/unrelated.go": "package pkg\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// Case-insensitive search across all files, skipping hidden directories and binary files
+	matches, err := CodeSearch(tmpDir, `run\(`, "", false)
+	if err != nil {
+		t.Fatalf("CodeSearch() error = %v", err)
+	}
+	want := []SearchMatch{
+		{File: "README.md", LineNumber: 1, Line: "Call run() to start."},
+		{File: "main.go", LineNumber: 4, Line: "\tRun()"},
+		{File: filepath.Join("pkg", "run.go"), LineNumber: 4, Line: "func Run() {}"},
+		{File: filepath.Join("pkg", "windows.go"), LineNumber: 1, Line: "func Run() {}"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("CodeSearch() = %+v, want %+v", matches, want)
+	}
+
+	// Case-sensitive search limited to Go files
+	matches, err = CodeSearch(tmpDir, `^func Run`, "*.go", true)
+	if err != nil {
+		t.Fatalf("CodeSearch() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("CodeSearch() returned %d matches, want 2: %+v", len(matches), matches)
+	}
+	matches, err = CodeSearch(tmpDir, `call run`, "*.md", true)
+	if err != nil {
+		t.Fatalf("CodeSearch() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("CodeSearch() case-sensitive = %+v, want no matches", matches)
+	}
+
+	// Invalid input
+	if _, err := CodeSearch(tmpDir, `(`, "", false); err == nil {
+		t.Error("CodeSearch() expected error for invalid pattern, got nil")
+	}
+	if _, err := CodeSearch(tmpDir, `x`, "[", false); err == nil {
+		t.Error("CodeSearch() expected error for invalid file pattern, got nil")
+	}
+	if _, err := CodeSearch(filepath.Join(tmpDir, "missing"), `x`, "", false); err == nil {
+		t.Error("CodeSearch() expected error for nonexistent directory, got nil")
+	}
+}
+
+func TestCodeSearchLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := strings.Repeat("match\n", MaxCodeSearchMatches+10)
+	if err := os.WriteFile(filepath.Join(tmpDir, "many.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	matches, err := CodeSearch(tmpDir, "match", "*", false)
+	if err != nil {
+		t.Fatalf("CodeSearch() error = %v", err)
+	}
+	if len(matches) != MaxCodeSearchMatches {
+		t.Errorf("CodeSearch() returned %d matches, want %d", len(matches), MaxCodeSearchMatches)
+	}
+}
+
 // Test using in-memory file system for faster testing
 func TestFileOperationsWithMemFS(t *testing.T) {
 	memFS := fstest.MapFS{
@@ -111,3 +433,284 @@ func TestFileOperationsWithMemFS(t *testing.T) {
 		t.Errorf("memFS content = %q, want %q", string(content[:n]), expected)
 	}
 }
+
+func TestSearchReplaceFile(t *testing.T) {
+	const original = "foo bar foo baz foo\nid=12 id=345\n"
+	tests := []struct {
+		name        string
+		search      string
+		replacement string
+		count       int
+		useRegex    bool
+		want        string
+		wantCount   int
+		wantErr     string
+	}{
+		{name: "all literal", search: "foo", replacement: "qux", count: -1, want: "qux bar qux baz qux\nid=12 id=345\n", wantCount: 3},
+		{name: "first literal", search: "foo", replacement: "qux", count: 1, want: "qux bar foo baz foo\nid=12 id=345\n", wantCount: 1},
+		{name: "count above occurrences", search: "foo", replacement: "qux", count: 10, want: "qux bar qux baz qux\nid=12 id=345\n", wantCount: 3},
+		{name: "literal with regex characters", search: "id=1.", replacement: "x", count: -1, want: original, wantCount: 0},
+		{name: "no match", search: "missing", replacement: "x", count: -1, want: original, wantCount: 0},
+		{name: "all regex", search: `id=(\d+)`, replacement: "n=$1", count: -1, useRegex: true, want: "foo bar foo baz foo\nn=12 n=345\n", wantCount: 2},
+		{name: "first regex", search: `id=(?P<num>\d+)`, replacement: "n=${num}0", count: 1, useRegex: true, want: "foo bar foo baz foo\nn=120 id=345\n", wantCount: 1},
+		{name: "regex no match", search: `^bar`, replacement: "x", count: -1, useRegex: true, want: original, wantCount: 0},
+		{name: "invalid regex", search: "(", replacement: "x", count: -1, useRegex: true, want: original, wantErr: "invalid search pattern"},
+		{name: "empty search", search: "", replacement: "x", count: -1, want: original, wantErr: "must not be empty"},
+		{name: "zero count", search: "foo", replacement: "x", count: 0, want: original, wantErr: "count must be -1"},
+		{name: "negative count", search: "foo", replacement: "x", count: -2, useRegex: true, want: original, wantErr: "count must be -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file.txt")
+			if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			var count int
+			var err error
+			if tt.useRegex {
+				count, err = SearchReplaceFileRegexp(path, tt.search, tt.replacement, tt.count)
+			} else {
+				count, err = SearchReplaceFile(path, tt.search, tt.replacement, tt.count)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("replacements = %d, want %d", count, tt.wantCount)
+			}
+			content, _ := os.ReadFile(path)
+			if string(content) != tt.want {
+				t.Errorf("content = %q, want %q", string(content), tt.want)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+				t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0600))
+			}
+		})
+	}
+
+	if _, err := SearchReplaceFile(filepath.Join(t.TempDir(), "missing.txt"), "a", "b", -1); err == nil {
+		t.Error("SearchReplaceFile() with missing file expected error, got nil")
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.txt")
+	if err := os.WriteFile(src, []byte("copy me"), 0600); err != nil {
+		t.Fatalf("Failed to create source file: %v", err)
+	}
+
+	// Test case 1: Copy into a directory that does not exist yet
+	dst := filepath.Join(tmpDir, "nested", "deeper", "dst.txt")
+	if err := CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	content, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("Failed to read copied file: %v", err)
+	}
+	if string(content) != "copy me" {
+		t.Errorf("CopyFile() content = %q, want %q", string(content), "copy me")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("CopyFile() should keep the source file, got %v", err)
+	}
+	if info, _ := os.Stat(dst); info.Mode().Perm() != 0600 {
+		t.Errorf("CopyFile() mode = %v, want %v", info.Mode().Perm(), os.FileMode(0600))
+	}
+
+	// Test case 2: Existing destination is protected
+	if err := os.WriteFile(src, []byte("new content"), 0600); err != nil {
+		t.Fatalf("Failed to update source file: %v", err)
+	}
+	err = CopyFile(src, dst)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("CopyFile() onto existing file expected 'already exists' error, got %v", err)
+	}
+	content, _ = os.ReadFile(dst)
+	if string(content) != "copy me" {
+		t.Errorf("CopyFile() must not modify a protected destination, got %q", string(content))
+	}
+
+	// Test case 3: Overwrite explicitly allowed
+	if err := CopyFileWithOverwrite(src, dst, true); err != nil {
+		t.Fatalf("CopyFileWithOverwrite() error = %v", err)
+	}
+	content, _ = os.ReadFile(dst)
+	if string(content) != "new content" {
+		t.Errorf("CopyFileWithOverwrite() content = %q, want %q", string(content), "new content")
+	}
+
+	// Test case 4: Missing source and directory source
+	if err := CopyFile(filepath.Join(tmpDir, "missing.txt"), filepath.Join(tmpDir, "out.txt")); err == nil {
+		t.Error("CopyFile() with missing source expected error, got nil")
+	}
+	if err := CopyFile(tmpDir, filepath.Join(tmpDir, "out.txt")); err == nil {
+		t.Error("CopyFile() with directory source expected error, got nil")
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.txt")
+	if err := os.WriteFile(src, []byte("move me"), 0644); err != nil {
+		t.Fatalf("Failed to create source file: %v", err)
+	}
+
+	// Test case 1: Move into a directory that does not exist yet
+	dst := filepath.Join(tmpDir, "a", "b", "dst.txt")
+	if err := MoveFile(src, dst); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("MoveFile() should remove the source file, stat error = %v", err)
+	}
+	content, err := os.ReadFile(dst)
+	if err != nil || string(content) != "move me" {
+		t.Errorf("MoveFile() destination content = %q, err = %v", string(content), err)
+	}
+
+	// Test case 2: Existing destination is protected
+	other := filepath.Join(tmpDir, "other.txt")
+	if err := os.WriteFile(other, []byte("other"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	err = MoveFile(other, dst)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("MoveFile() onto existing file expected 'already exists' error, got %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("MoveFile() must keep the source when refusing to overwrite, got %v", err)
+	}
+
+	// Test case 3: Overwrite explicitly allowed
+	if err := MoveFileWithOverwrite(other, dst, true); err != nil {
+		t.Fatalf("MoveFileWithOverwrite() error = %v", err)
+	}
+	content, _ = os.ReadFile(dst)
+	if string(content) != "other" {
+		t.Errorf("MoveFileWithOverwrite() content = %q, want %q", string(content), "other")
+	}
+}
+
+func TestMoveFileCrossDevice(t *testing.T) {
+	// Simulate a rename across filesystems, which the kernel rejects with EXDEV
+	originalRename := rename
+	defer func() { rename = originalRename }()
+	rename = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.txt")
+	if err := os.WriteFile(src, []byte("cross device"), 0644); err != nil {
+		t.Fatalf("Failed to create source file: %v", err)
+	}
+
+	dst := filepath.Join(tmpDir, "other-device", "dst.txt")
+	if err := MoveFile(src, dst); err != nil {
+		t.Fatalf("MoveFile() across devices error = %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("MoveFile() across devices should remove the source, stat error = %v", err)
+	}
+	content, err := os.ReadFile(dst)
+	if err != nil || string(content) != "cross device" {
+		t.Errorf("MoveFile() across devices content = %q, err = %v", string(content), err)
+	}
+}
+
+func TestParseStructuredData(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		headers []string
+		rows    [][]string
+	}{
+		{
+			name:    "products.csv",
+			content: "name,price\nwidget,1.50\n\"gadget, large\",20\n",
+			headers: []string{"name", "price"},
+			rows:    [][]string{{"widget", "1.50"}, {"gadget, large", "20"}},
+		},
+		{
+			name:    "products.json",
+			content: `[{"name": "widget", "price": 1.5}, {"name": "gadget", "tags": ["a", "b"]}]`,
+			headers: []string{"name", "price", "tags"},
+			rows:    [][]string{{"widget", "1.5", ""}, {"gadget", "", `["a","b"]`}},
+		},
+		{
+			name:    "products.yaml",
+			content: "products:\n  - name: widget\n    price: 1.5\n  - name: gadget\n    price: 20\n",
+			headers: []string{"name", "price"},
+			rows:    [][]string{{"widget", "1.5"}, {"gadget", "20"}},
+		},
+		{
+			name:    "products.toml",
+			content: "[[products]]\nname = \"widget\"\nprice = 1.5\n\n[[products]]\nname = \"gadget\"\nprice = 20\n",
+			headers: []string{"name", "price"},
+			rows:    [][]string{{"widget", "1.5"}, {"gadget", "20"}},
+		},
+		{
+			name:    "config.toml",
+			content: "title = \"demo\"\nport = 8080\n",
+			headers: []string{"port", "title"},
+			rows:    [][]string{{"8080", "demo"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			table, err := ParseStructuredData(path)
+			if err != nil {
+				t.Fatalf("ParseStructuredData() error = %v", err)
+			}
+			if !reflect.DeepEqual(table.Headers, tt.headers) {
+				t.Errorf("Headers = %v, want %v", table.Headers, tt.headers)
+			}
+			if !reflect.DeepEqual(table.Rows, tt.rows) {
+				t.Errorf("Rows = %v, want %v", table.Rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestParseStructuredDataWithFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "data.txt")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	if _, err := ParseStructuredData(path); err == nil {
+		t.Error("Expected error for unknown extension")
+	}
+
+	table, err := ParseStructuredDataWithFormat(path, "csv")
+	if err != nil {
+		t.Fatalf("ParseStructuredDataWithFormat() error = %v", err)
+	}
+	expected := "| a | b |\n| --- | --- |\n| 1 | 2 |\n"
+	if table.String() != expected {
+		t.Errorf("String() = %q, want %q", table.String(), expected)
+	}
+
+	if _, err := ParseStructuredDataWithFormat(path, "json"); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if _, err := ParseStructuredData(filepath.Join(tmpDir, "missing.csv")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,173 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultGitLogCommits is the number of commits GitLog returns when maxCommits is not positive.
+const DefaultGitLogCommits = 20
+
+// CheckRepoPath returns an error unless repoPath is one of the allowed directories or
+// lies inside one. Symlinks are resolved before comparing.
+func CheckRepoPath(repoPath string, allowed []string) error {
+	if len(allowed) == 0 {
+		return errors.New("git tools are disabled because no repository paths are allowed (see --allow-repo)")
+	}
+	path, err := resolvePath(repoPath)
+	if err != nil {
+		return err
+	}
+	if withinAny(path, allowed) {
+		return nil
+	}
+	return fmt.Errorf("repository path '%s' is not in the allowed repository paths", repoPath)
+}
+
+// withinAny reports whether the resolved path is one of dirs or lies inside one.
+// Directories that cannot be resolved are skipped.
+func withinAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		resolvedDir, err := resolvePath(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(resolvedDir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the absolute path of path with symlinks resolved.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path '%s': %w", path, err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path '%s': %w", path, err)
+	}
+	return resolved, nil
+}
+
+// GitLog returns up to maxCommits commits reachable from branch, newest first, formatted
+// like 'git log'. branch may be any revision, e.g. a branch, tag or hash; HEAD is used
+// when it is empty.
+func GitLog(repoPath, branch string, maxCommits int) (string, error) {
+	return GitLogContext(context.Background(), repoPath, branch, maxCommits)
+}
+
+// GitLogContext is like GitLog but stops walking the history when ctx is done.
+func GitLogContext(ctx context.Context, repoPath, branch string, maxCommits int) (string, error) {
+	if maxCommits <= 0 {
+		maxCommits = DefaultGitLogCommits
+	}
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return "", err
+	}
+	hash, err := resolveRevision(repo, branch)
+	if err != nil {
+		return "", err
+	}
+	iter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return "", fmt.Errorf("failed to read git log: %w", err)
+	}
+	defer iter.Close()
+
+	var sb strings.Builder
+	for range maxCommits {
+		if err := ctx.Err(); err != nil {
+			return "", fmt.Errorf("git log was stopped: %w", err)
+		}
+		commit, err := iter.Next()
+		if err != nil {
+			break
+		}
+		fmt.Fprintf(&sb, "commit %s\nAuthor: %s <%s>\nDate:   %s\n\n", commit.Hash, commit.Author.Name, commit.Author.Email,
+			commit.Author.When.Format("Mon Jan 2 15:04:05 2006 -0700"))
+		for _, line := range strings.Split(strings.TrimRight(commit.Message, "\n"), "\n") {
+			sb.WriteString("    " + line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// GitDiff returns the unified diff between the commits fromRef and toRef. toRef
+// defaults to HEAD.
+func GitDiff(repoPath, fromRef, toRef string) (string, error) {
+	return GitDiffContext(context.Background(), repoPath, fromRef, toRef)
+}
+
+// GitDiffContext is like GitDiff but stops computing the diff when ctx is done.
+func GitDiffContext(ctx context.Context, repoPath, fromRef, toRef string) (string, error) {
+	if fromRef == "" {
+		return "", errors.New("fromRef is required")
+	}
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return "", err
+	}
+	from, err := resolveCommit(repo, fromRef)
+	if err != nil {
+		return "", err
+	}
+	to, err := resolveCommit(repo, toRef)
+	if err != nil {
+		return "", err
+	}
+	patch, err := from.PatchContext(ctx, to)
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("git diff was stopped: %w", ctx.Err())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s and %s: %w", fromRef, toRef, err)
+	}
+	return patch.String(), nil
+}
+
+// openRepo opens the repository containing repoPath.
+func openRepo(repoPath string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository '%s': %w", repoPath, err)
+	}
+	return repo, nil
+}
+
+// resolveRevision resolves rev, or HEAD when it is empty, to a commit hash.
+func resolveRevision(repo *git.Repository, rev string) (plumbing.Hash, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve revision '%s': %w", rev, err)
+	}
+	return *hash, nil
+}
+
+// resolveCommit resolves rev, or HEAD when it is empty, to a commit.
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := resolveRevision(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit '%s': %w", rev, err)
+	}
+	return commit, nil
+}
@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// initTestRepo creates a repository with two commits: one adding README.md and one
+// changing it. It returns the repository path and the hash of the first commit.
+func initTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to open worktree: %v", err)
+	}
+
+	var hashes []string
+	for i, content := range []string{"hello\n", "hello\nworld\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("README.md"); err != nil {
+			t.Fatalf("Failed to stage file: %v", err)
+		}
+		hash, err := worktree.Commit([]string{"Add README", "Add world to README\n\nLonger explanation."}[i], &git.CommitOptions{
+			Author: &object.Signature{Name: "Ada", Email: "ada@example.com", When: time.Date(2024, 5, 1+i, 12, 0, 0, 0, time.UTC)},
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		hashes = append(hashes, hash.String())
+	}
+	return dir, hashes[0]
+}
+
+func TestGitLog(t *testing.T) {
+	dir, first := initTestRepo(t)
+
+	result, err := GitLog(dir, "", 0)
+	if err != nil {
+		t.Fatalf("GitLog() error = %v", err)
+	}
+	for _, want := range []string{
+		"Author: Ada <ada@example.com>\nDate:   Thu May 2 12:00:00 2024 +0000\n\n    Add world to README\n    \n    Longer explanation.\n",
+		"commit " + first + "\n",
+		"    Add README\n",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GitLog() = %q, want it to contain %q", result, want)
+		}
+	}
+	if strings.Index(result, "Add world") > strings.Index(result, "Add README\n") {
+		t.Errorf("GitLog() did not list the newest commit first: %q", result)
+	}
+
+	result, err = GitLog(dir, "master", 1)
+	if err != nil {
+		t.Fatalf("GitLog() error = %v", err)
+	}
+	if strings.Count(result, "commit ") != 1 {
+		t.Errorf("GitLog() with maxCommits 1 returned %q", result)
+	}
+
+	result, err = GitLog(dir, first, 10)
+	if err != nil {
+		t.Fatalf("GitLog() error = %v", err)
+	}
+	if strings.Contains(result, "Add world") {
+		t.Errorf("GitLog() from the first commit included a later commit: %q", result)
+	}
+
+	if _, err := GitLog(dir, "no-such-branch", 1); err == nil {
+		t.Error("GitLog() expected error for unknown branch, got nil")
+	}
+	if _, err := GitLog(t.TempDir(), "", 1); err == nil {
+		t.Error("GitLog() expected error outside a repository, got nil")
+	}
+}
+
+func TestGitDiff(t *testing.T) {
+	dir, first := initTestRepo(t)
+
+	result, err := GitDiff(dir, first, "")
+	if err != nil {
+		t.Fatalf("GitDiff() error = %v", err)
+	}
+	for _, want := range []string{"--- a/README.md\n", "+++ b/README.md\n", " hello\n", "+world\n"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GitDiff() = %q, want it to contain %q", result, want)
+		}
+	}
+
+	result, err = GitDiff(dir, "HEAD", "HEAD")
+	if err != nil {
+		t.Fatalf("GitDiff() error = %v", err)
+	}
+	if result != "" {
+		t.Errorf("GitDiff() of a commit with itself = %q, want empty", result)
+	}
+
+	if _, err := GitDiff(dir, "", "HEAD"); err == nil {
+		t.Error("GitDiff() expected error without fromRef, got nil")
+	}
+}
+
+func TestGitContext_Cancelled(t *testing.T) {
+	dir, first := initTestRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := GitLogContext(ctx, dir, "", 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("GitLogContext() error = %v, want context.Canceled", err)
+	}
+	if _, err := GitDiffContext(ctx, dir, first, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("GitDiffContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCheckRepoPath(t *testing.T) {
+	allowed := t.TempDir()
+	if err := os.Mkdir(filepath.Join(allowed, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	outside := t.TempDir()
+	link := filepath.Join(allowed, "link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	for _, path := range []string{allowed, filepath.Join(allowed, "sub"), filepath.Join(allowed, "sub", "..")} {
+		if err := CheckRepoPath(path, []string{allowed}); err != nil {
+			t.Errorf("CheckRepoPath(%q) error = %v", path, err)
+		}
+	}
+	for _, path := range []string{outside, filepath.Join(allowed, ".."), link, filepath.Join(allowed, "missing")} {
+		if err := CheckRepoPath(path, []string{allowed}); err == nil {
+			t.Errorf("CheckRepoPath(%q) = nil, want error", path)
+		}
+	}
+	if err := CheckRepoPath(allowed, nil); err == nil || !strings.Contains(err.Error(), "--allow-repo") {
+		t.Errorf("CheckRepoPath() without allowed paths error = %v", err)
+	}
+}
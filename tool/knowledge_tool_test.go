@@ -1,6 +1,7 @@
 package tool
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -265,6 +266,14 @@ func TestWikipediaSearchErrorHandling(t *testing.T) {
 	t.Logf("Would test malformed JSON against server at %s", malformedServer.URL)
 }
 
+func TestWikipediaSearchContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WikipediaSearchContext(ctx, "Go (programming language)"); err == nil {
+		t.Fatal("WikipediaSearchContext() with a cancelled context expected error, got nil")
+	}
+}
+
 func TestWikipediaSearchRealQueries(t *testing.T) {
 	// These tests would make real API calls to Wikipedia
 	// They should be run manually or as integration tests, not in unit tests
@@ -0,0 +1,122 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a set of key-value facts persisted as a JSON object in a file, so the
+// LLM can remember things between runs.
+type MemoryStore struct {
+	path string
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// DefaultMemoryStorePath returns ~/.goskills/memory.json.
+func DefaultMemoryStorePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".goskills", "memory.json"), nil
+}
+
+// NewMemoryStore loads the store saved at path. A missing file gives an empty store.
+func NewMemoryStore(path string) (*MemoryStore, error) {
+	s := &MemoryStore{path: path, data: make(map[string]string)}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory store '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(content, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse memory store '%s': %w", path, err)
+	}
+	if s.data == nil {
+		s.data = make(map[string]string)
+	}
+	return s, nil
+}
+
+// Get returns the value stored for key.
+func (s *MemoryStore) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[key]
+	return value, ok
+}
+
+// Set stores value for key and saves the store. An empty value deletes the key.
+func (s *MemoryStore) Set(key, value string) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("memory key is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value == "" {
+		delete(s.data, key)
+	} else {
+		s.data[key] = value
+	}
+	return s.save()
+}
+
+// Len returns the number of stored facts.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.data)
+}
+
+// String lists the stored facts as "- key: value" lines, sorted by key.
+func (s *MemoryStore) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", k, s.data[k]))
+	}
+	return sb.String()
+}
+
+// save writes the store to a temporary file and renames it into place.
+func (s *MemoryStore) save() error {
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal memory store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create memory store directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".memory-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save memory store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save memory store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save memory store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save memory store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "memory.json")
+
+	store, err := NewMemoryStore(path)
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+	if store.Len() != 0 {
+		t.Errorf("Expected an empty store, got %d facts", store.Len())
+	}
+	if _, ok := store.Get("name"); ok {
+		t.Error("Expected missing key")
+	}
+
+	if err := store.Set("name", "Ada"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("editor", "vim"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("", "value"); err == nil {
+		t.Error("Expected error for empty key")
+	}
+
+	// A new store simulates the next process reading the same file
+	reloaded, err := NewMemoryStore(path)
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+	if value, ok := reloaded.Get("name"); !ok || value != "Ada" {
+		t.Errorf("Get(name) = %q, %v, want Ada, true", value, ok)
+	}
+	if got, want := reloaded.String(), "- editor: vim\n- name: Ada\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// An empty value deletes the key
+	if err := reloaded.Set("editor", ""); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	reloaded, err = NewMemoryStore(path)
+	if err != nil {
+		t.Fatalf("NewMemoryStore() error = %v", err)
+	}
+	if _, ok := reloaded.Get("editor"); ok {
+		t.Error("Expected editor to be deleted")
+	}
+	if reloaded.Len() != 1 {
+		t.Errorf("Expected 1 fact, got %d", reloaded.Len())
+	}
+}
+
+func TestMemoryStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if _, err := NewMemoryStore(path); err == nil {
+		t.Error("Expected error for invalid memory file")
+	}
+}
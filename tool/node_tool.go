@@ -0,0 +1,80 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+// NodeTool runs JavaScript code snippets.
+type NodeTool struct {
+	// SkillArgs are exposed to the code as {{.GOSKILLS_ARGS.key}} and as
+	// GOSKILLS_ARG_<KEY> environment variables.
+	SkillArgs map[string]string
+}
+
+// Run executes code, a template filled with args, with node. The script is killed when
+// ctx is done.
+func (t *NodeTool) Run(ctx context.Context, args map[string]any, code string) (string, error) {
+	tmpl, err := template.New("node").Parse(code)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse node template: %w", err)
+	}
+
+	var script bytes.Buffer
+	err = tmpl.Execute(&script, withSkillArgs(args, t.SkillArgs))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute node template: %w", err)
+	}
+
+	nodeExe, err := exec.LookPath("node")
+	if err != nil {
+		return "", fmt.Errorf("failed to find node in PATH: %w", err)
+	}
+
+	return runNode(ctx, nodeExe, []string{"-e", script.String()}, "<inline>", SkillArgsEnv(t.SkillArgs))
+}
+
+// RunNodeScript executes a JavaScript or TypeScript script and returns its combined stdout and stderr.
+// Scripts ending in .ts are run with 'ts-node', all others with 'node'.
+func RunNodeScript(scriptPath string, args []string) (string, error) {
+	return RunNodeScriptContext(context.Background(), scriptPath, args, nil)
+}
+
+// RunNodeScriptContext is like RunNodeScript but adds env, a list of KEY=value entries,
+// to the script's environment and kills the script when ctx is done.
+func RunNodeScriptContext(ctx context.Context, scriptPath string, args []string, env []string) (string, error) {
+	interpreter := "node"
+	if filepath.Ext(scriptPath) == ".ts" {
+		interpreter = "ts-node"
+	}
+
+	exe, err := exec.LookPath(interpreter)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s in PATH: %w", interpreter, err)
+	}
+
+	return runNode(ctx, exe, append([]string{scriptPath}, args...), scriptPath, env)
+}
+
+func runNode(ctx context.Context, exe string, cmdArgs []string, scriptPath string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, exe, cmdArgs...)
+	cmd.Env = commandEnv(env)
+	cmd.WaitDelay = processWaitDelay
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("node script '%s' was stopped: %w\nStdout: %s\nStderr: %s", scriptPath, ctx.Err(), stdout.String(), stderr.String())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run node script '%s' with '%s': %w\nStdout: %s\nStderr: %s", scriptPath, exe, err, stdout.String(), stderr.String())
+	}
+
+	return stdout.String() + stderr.String(), nil
+}
@@ -0,0 +1,150 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func requireNode(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+}
+
+func TestNodeTool_Run(t *testing.T) {
+	requireNode(t)
+	nodeTool := &NodeTool{}
+
+	// Test case 1: Simple JavaScript code
+	result, err := nodeTool.Run(context.Background(), map[string]any{}, "console.log('Hello from Node!')")
+	if err != nil {
+		t.Errorf("NodeTool.Run() error = %v", err)
+		return
+	}
+
+	expected := "Hello from Node!\n"
+	if result != expected {
+		t.Errorf("NodeTool.Run() = %q, want %q", result, expected)
+	}
+
+	// Test case 2: JavaScript code with template arguments
+	args := map[string]any{
+		"name":  "GoTest",
+		"value": 42,
+	}
+	result, err = nodeTool.Run(context.Background(), args, "console.log(`Name: {{.name}}, Value: {{.value}}`)")
+	if err != nil {
+		t.Errorf("NodeTool.Run() with args error = %v", err)
+		return
+	}
+
+	expected = "Name: GoTest, Value: 42\n"
+	if result != expected {
+		t.Errorf("NodeTool.Run() with args = %q, want %q", result, expected)
+	}
+
+	// Test case 3: JavaScript code with syntax error
+	_, err = nodeTool.Run(context.Background(), map[string]any{}, "console.log('unclosed string")
+	if err == nil {
+		t.Error("NodeTool.Run() with syntax error expected error, got nil")
+	}
+
+	// Test case 4: JavaScript code that writes to stderr
+	result, err = nodeTool.Run(context.Background(), map[string]any{}, `console.log("This goes to stdout");
+console.error("This goes to stderr");`)
+	if err != nil {
+		t.Errorf("NodeTool.Run() with stderr output error = %v", err)
+		return
+	}
+
+	if !containsString(result, "This goes to stdout") || !containsString(result, "This goes to stderr") {
+		t.Errorf("NodeTool.Run() result should contain both stdout and stderr, got %q", result)
+	}
+}
+
+func TestNodeTool_RunTimeout(t *testing.T) {
+	requireNode(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := (&NodeTool{}).Run(ctx, nil, "setTimeout(() => {}, 5000)")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("NodeTool.Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("NodeTool.Run() returned after %s, want shortly after the timeout", elapsed)
+	}
+}
+
+func TestRunNodeScript(t *testing.T) {
+	requireNode(t)
+	tmpDir := t.TempDir()
+
+	scriptContent := `console.log("Script started");
+console.log("Arguments received: " + process.argv.slice(2).join(","));
+console.error("Script ended");
+`
+	scriptPath := filepath.Join(tmpDir, "test_script.js")
+	if err := os.WriteFile(scriptPath, []byte(scriptContent), 0644); err != nil {
+		t.Fatalf("Failed to create test script: %v", err)
+	}
+
+	// Test case 1: Run script with arguments, capturing stderr
+	result, err := RunNodeScript(scriptPath, []string{"arg1", "arg2"})
+	if err != nil {
+		t.Errorf("RunNodeScript() error = %v", err)
+		return
+	}
+
+	if !containsString(result, "Arguments received: arg1,arg2") || !containsString(result, "Script ended") {
+		t.Errorf("RunNodeScript() result = %q, missing expected output", result)
+	}
+
+	// Test case 2: Non-existent script
+	if _, err := RunNodeScript(filepath.Join(tmpDir, "missing.js"), nil); err == nil {
+		t.Error("RunNodeScript() with non-existent script expected error, got nil")
+	}
+
+	// Test case 3: Script that exits with an error
+	failingPath := filepath.Join(tmpDir, "fail.js")
+	if err := os.WriteFile(failingPath, []byte("console.error('boom'); process.exit(1);"), 0644); err != nil {
+		t.Fatalf("Failed to create failing script: %v", err)
+	}
+	_, err = RunNodeScript(failingPath, nil)
+	if err == nil {
+		t.Error("RunNodeScript() with failing script expected error, got nil")
+	} else if !containsString(err.Error(), "boom") {
+		t.Errorf("RunNodeScript() error should include stderr, got %v", err)
+	}
+}
+
+func TestRunNodeScript_MissingInterpreter(t *testing.T) {
+	tmpDir := t.TempDir()
+	scriptPath := filepath.Join(tmpDir, "script.js")
+	if err := os.WriteFile(scriptPath, []byte("console.log('hi')"), 0644); err != nil {
+		t.Fatalf("Failed to create test script: %v", err)
+	}
+	tsPath := filepath.Join(tmpDir, "script.ts")
+	if err := os.WriteFile(tsPath, []byte("console.log('hi')"), 0644); err != nil {
+		t.Fatalf("Failed to create test script: %v", err)
+	}
+
+	t.Setenv("PATH", "")
+
+	if _, err := RunNodeScript(scriptPath, nil); err == nil || !containsString(err.Error(), "node") {
+		t.Errorf("RunNodeScript() without node expected interpreter error, got %v", err)
+	}
+	if _, err := RunNodeScript(tsPath, nil); err == nil || !containsString(err.Error(), "ts-node") {
+		t.Errorf("RunNodeScript() without ts-node expected interpreter error, got %v", err)
+	}
+	if _, err := (&NodeTool{}).Run(context.Background(), nil, "console.log('hi')"); err == nil {
+		t.Error("NodeTool.Run() without node expected error, got nil")
+	}
+}
@@ -2,24 +2,36 @@ package tool
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"os"
 	"os/exec"
 	"text/template"
 )
 
+// PythonTool runs Python code snippets.
 type PythonTool struct {
+	// Preferred is the Python interpreter to use, such as "python2" or a full path.
+	// When empty the interpreter is chosen from the code with DetectPythonVersion.
+	Preferred string
+	// SkillArgs are exposed to the code as {{.GOSKILLS_ARGS.key}} and as
+	// GOSKILLS_ARG_<KEY> environment variables.
+	SkillArgs map[string]string
 }
 
-func (t *PythonTool) Run(args map[string]any, code string) (string, error) {
+// Run executes code, a template filled with args, with Python. The script is killed
+// when ctx is done.
+func (t *PythonTool) Run(ctx context.Context, args map[string]any, code string) (string, error) {
 	tmpl, err := template.New("python").Parse(code)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse python template: %w", err)
 	}
 
 	var script bytes.Buffer
-	err = tmpl.Execute(&script, args)
+	err = tmpl.Execute(&script, withSkillArgs(args, t.SkillArgs))
 	if err != nil {
 		return "", fmt.Errorf("failed to execute python template: %w", err)
 	}
@@ -37,27 +49,91 @@ func (t *PythonTool) Run(args map[string]any, code string) (string, error) {
 		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 
-	return RunPythonScript(tmpfile.Name(), nil)
+	return RunPythonScriptContext(ctx, tmpfile.Name(), nil, t.Preferred, SkillArgsEnv(t.SkillArgs))
+}
+
+var (
+	pythonMagicComment = regexp.MustCompile(`(?m)^#\s*goskills:\s*python([23])\s*$`)
+	python3Markers     = regexp.MustCompile(`(?m)\bprint\s*\(.*\bfile\s*=|\bnonlocal\b|^\s*async\s+def\b|\bf"|\bf'`)
+	python2Markers     = regexp.MustCompile(`(?m)^\s*print(?:\s+[\w"']|\s*["']|\s*>>)|^\s*except\s+[\w.]+\s*,\s*\w+\s*:|^\s*exec\s+["'\w]|\braw_input\s*\(|\bxrange\s*\(|\.has_key\s*\(`)
+)
+
+// DetectPythonVersion guesses whether code is written for Python 2 or Python 3 and
+// returns 2 or 3. A "# goskills: python2" or "# goskills: python3" comment always wins;
+// otherwise Python 2 is only reported when the code uses Python 2 only syntax, such as
+// the print statement, and no Python 3 only syntax.
+func DetectPythonVersion(code string) int {
+	if m := pythonMagicComment.FindStringSubmatch(code); m != nil {
+		if m[1] == "2" {
+			return 2
+		}
+		return 3
+	}
+	if python3Markers.MatchString(code) {
+		return 3
+	}
+	if python2Markers.MatchString(code) {
+		return 2
+	}
+	return 3
+}
+
+// SelectPythonInterpreter returns the path of the interpreter to run code with. An
+// explicit preferred interpreter always wins. Otherwise Python 2 code prefers 'python2'
+// and Python 3 code prefers 'python3', both falling back to 'python'.
+func SelectPythonInterpreter(code, preferred string) (string, error) {
+	if preferred != "" {
+		exe, err := exec.LookPath(preferred)
+		if err != nil {
+			return "", fmt.Errorf("failed to find preferred python '%s' in PATH: %w", preferred, err)
+		}
+		return exe, nil
+	}
+
+	candidates := []string{"python3", "python"}
+	if DetectPythonVersion(code) == 2 {
+		candidates = []string{"python2", "python"}
+	}
+	for _, name := range candidates {
+		if exe, err := exec.LookPath(name); err == nil {
+			return exe, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find %s in PATH", strings.Join(candidates, " or "))
 }
 
 // RunPythonScript executes a Python script and returns its combined stdout and stderr.
-// It tries to use 'python3' first, then falls back to 'python'.
+// The interpreter is chosen by SelectPythonInterpreter from the script's content.
 func RunPythonScript(scriptPath string, args []string) (string, error) {
-	pythonExe, err := exec.LookPath("python3")
-	if err != nil {
-		pythonExe, err = exec.LookPath("python")
-		if err != nil {
-			return "", fmt.Errorf("failed to find python3 or python in PATH: %w", err)
+	return RunPythonScriptContext(context.Background(), scriptPath, args, "", nil)
+}
+
+// RunPythonScriptContext is like RunPythonScript but runs the script with the preferred
+// interpreter when one is given and adds env, a list of KEY=value entries, to its
+// environment. The script is killed when ctx is done, returning an error that wraps ctx.Err().
+func RunPythonScriptContext(ctx context.Context, scriptPath string, args []string, preferred string, env []string) (string, error) {
+	var code string
+	if preferred == "" {
+		if content, err := os.ReadFile(scriptPath); err == nil {
+			code = string(content)
 		}
 	}
+	pythonExe, err := SelectPythonInterpreter(code, preferred)
+	if err != nil {
+		return "", err
+	}
 
-	cmd := exec.Command(pythonExe, append([]string{scriptPath}, args...)...)
-	cmd.Env = os.Environ()
+	cmd := exec.CommandContext(ctx, pythonExe, append([]string{scriptPath}, args...)...)
+	cmd.Env = commandEnv(env)
+	cmd.WaitDelay = processWaitDelay
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err = cmd.Run()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("python script '%s' was stopped: %w\nStdout: %s\nStderr: %s", scriptPath, ctx.Err(), stdout.String(), stderr.String())
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to run python script '%s' with '%s': %w\nStdout: %s\nStderr: %s", scriptPath, pythonExe, err, stdout.String(), stderr.String())
 	}
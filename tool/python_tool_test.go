@@ -1,6 +1,7 @@
 package tool
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,7 +14,7 @@ func TestPythonTool_Run(t *testing.T) {
 	args := map[string]any{}
 	code := "print('Hello from Python!')"
 
-	result, err := pythonTool.Run(args, code)
+	result, err := pythonTool.Run(context.Background(), args, code)
 	if err != nil {
 		t.Errorf("PythonTool.Run() error = %v", err)
 		return
@@ -31,7 +32,7 @@ func TestPythonTool_Run(t *testing.T) {
 	}
 	code = `print("Name: {{.name}}, Value: {{.value}}")`
 
-	result, err = pythonTool.Run(args, code)
+	result, err = pythonTool.Run(context.Background(), args, code)
 	if err != nil {
 		t.Errorf("PythonTool.Run() with args error = %v", err)
 		return
@@ -46,7 +47,7 @@ func TestPythonTool_Run(t *testing.T) {
 	args = map[string]any{}
 	code = "print('unclosed string"
 
-	_, err = pythonTool.Run(args, code)
+	_, err = pythonTool.Run(context.Background(), args, code)
 	if err == nil {
 		t.Error("PythonTool.Run() with syntax error expected error, got nil")
 	}
@@ -57,7 +58,7 @@ func TestPythonTool_Run(t *testing.T) {
 print("This goes to stdout")
 print("This goes to stderr", file=sys.stderr)`
 
-	result, err = pythonTool.Run(args, code)
+	result, err = pythonTool.Run(context.Background(), args, code)
 	if err != nil {
 		t.Errorf("PythonTool.Run() with stderr output error = %v", err)
 		return
@@ -182,7 +183,7 @@ print(json.dumps(data))`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := pythonTool.Run(tt.args, tt.code)
+			result, err := pythonTool.Run(context.Background(), tt.args, tt.code)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PythonTool.Run() error = %v, wantErr %v", err, tt.wantErr)
@@ -221,9 +222,130 @@ func BenchmarkPythonTool_Run(b *testing.B) {
 	code := "print('Benchmark test')"
 
 	for b.Loop() {
-		_, err := pythonTool.Run(args, code)
+		_, err := pythonTool.Run(context.Background(), args, code)
 		if err != nil {
 			b.Fatalf("PythonTool.Run() error = %v", err)
 		}
 	}
 }
+
+func TestDetectPythonVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want int
+	}{
+		{"print function", "print('hello')", 3},
+		{"print statement", "print \"hello\"", 2},
+		{"print statement with name", "x = 1\nprint x", 2},
+		{"print chevron", "import sys\nprint >>sys.stderr, 'oops'", 2},
+		{"old except syntax", "try:\n    pass\nexcept ValueError, e:\n    pass", 2},
+		{"raw_input", "name = raw_input('name? ')", 2},
+		{"print with file keyword", "import sys\nprint('oops', file=sys.stderr)", 3},
+		{"python3 marker wins", "print('a', file=sys.stderr)\nfor i in xrange(3): pass", 3},
+		{"printer variable", "printer = 1\nprint(printer)", 3},
+		{"magic comment python2", "# goskills: python2\nprint('hello')", 2},
+		{"magic comment python3", "# goskills: python3\nprint \"hello\"", 3},
+		{"empty", "", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPythonVersion(tt.code); got != tt.want {
+				t.Errorf("DetectPythonVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// installFakePythons creates executables named after each interpreter in a temp
+// directory and makes it the only entry in PATH.
+func installFakePythons(t *testing.T, names ...string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	for _, name := range names {
+		script := "#!/bin/sh\necho " + name + "\n"
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+			t.Fatalf("failed to create fake %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+	return binDir
+}
+
+func TestSelectPythonInterpreter(t *testing.T) {
+	py2Code := "print \"hello\""
+	py3Code := "print('hello')"
+
+	tests := []struct {
+		name      string
+		installed []string
+		code      string
+		preferred string
+		want      string
+		wantErr   bool
+	}{
+		{"python3 code", []string{"python", "python2", "python3"}, py3Code, "", "python3", false},
+		{"python2 code", []string{"python", "python2", "python3"}, py2Code, "", "python2", false},
+		{"python2 code falls back to python", []string{"python", "python3"}, py2Code, "", "python", false},
+		{"python3 code falls back to python", []string{"python", "python2"}, py3Code, "", "python", false},
+		{"override wins for python3 code", []string{"python", "python2", "python3"}, py3Code, "python2", "python2", false},
+		{"override wins for python2 code", []string{"python", "python2", "python3"}, py2Code, "python3", "python3", false},
+		{"missing override", []string{"python3"}, py3Code, "python2", "", true},
+		{"no interpreter", nil, py2Code, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binDir := installFakePythons(t, tt.installed...)
+			got, err := SelectPythonInterpreter(tt.code, tt.preferred)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectPythonInterpreter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want := filepath.Join(binDir, tt.want); got != want {
+				t.Errorf("SelectPythonInterpreter() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRunPythonScript_SelectsInterpreter(t *testing.T) {
+	installFakePythons(t, "python2", "python3")
+
+	scriptPath := filepath.Join(t.TempDir(), "legacy.py")
+	if err := os.WriteFile(scriptPath, []byte("print \"hello\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	result, err := RunPythonScript(scriptPath, nil)
+	if err != nil {
+		t.Fatalf("RunPythonScript() error = %v", err)
+	}
+	if result != "python2\n" {
+		t.Errorf("RunPythonScript() ran %q, want python2", result)
+	}
+
+	result, err = RunPythonScriptContext(context.Background(), scriptPath, nil, "python3", nil)
+	if err != nil {
+		t.Fatalf("RunPythonScriptContext() error = %v", err)
+	}
+	if result != "python3\n" {
+		t.Errorf("RunPythonScriptContext() ran %q, want python3", result)
+	}
+}
+
+func TestPythonTool_SkillArgs(t *testing.T) {
+	pythonTool := &PythonTool{SkillArgs: map[string]string{"name": "Ada"}}
+
+	code := "import os\nprint(os.environ['GOSKILLS_ARG_NAME'], '{{.GOSKILLS_ARGS.name}}', '{{.greeting}}')"
+	result, err := pythonTool.Run(context.Background(), map[string]any{"greeting": "hi"}, code)
+	if err != nil {
+		t.Fatalf("PythonTool.Run() error = %v", err)
+	}
+	if want := "Ada Ada hi\n"; result != want {
+		t.Errorf("PythonTool.Run() = %q, want %q", result, want)
+	}
+}
@@ -0,0 +1,295 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Search backends supported by AggregateSearch.
+const (
+	SearchSourceDuckDuckGo = "duckduckgo"
+	SearchSourceTavily     = "tavily"
+	SearchSourceWikipedia  = "wikipedia"
+)
+
+// DefaultSearchSources are the backends AggregateSearch queries when none are given.
+var DefaultSearchSources = []string{SearchSourceDuckDuckGo, SearchSourceTavily, SearchSourceWikipedia}
+
+// Backend endpoints, replaceable in tests.
+var (
+	duckDuckGoURL      = "https://api.duckduckgo.com/"
+	tavilySearchURL    = "https://api.tavily.com/search"
+	wikipediaSearchURL = "https://en.wikipedia.org/w/api.php"
+)
+
+// maxResultsPerSource caps the results taken from each backend.
+const maxResultsPerSource = 10
+
+// SearchResult is a single hit returned by a search backend.
+type SearchResult struct {
+	Title   string
+	URL     string
+	Snippet string
+	Sources []string // Backends that returned this URL
+}
+
+// searchBackends maps each source name to the function querying it.
+var searchBackends = map[string]func(ctx context.Context, query string) ([]SearchResult, error){
+	SearchSourceDuckDuckGo: searchDuckDuckGo,
+	SearchSourceTavily:     searchTavily,
+	SearchSourceWikipedia:  searchWikipedia,
+}
+
+// AggregateSearch queries the given search backends in parallel and returns their results
+// merged into one ranked list. Results with the same URL are reported once; a result
+// ranks higher the more backends return it and the higher they rank it. An empty sources
+// list uses DefaultSearchSources. Tavily is skipped when TAVILY_API_KEY is not set, unless
+// it is the only source.
+// Backends that fail are listed after the results; an error is only returned if all fail.
+func AggregateSearch(query string, sources []string) (string, error) {
+	return AggregateSearchContext(context.Background(), query, sources)
+}
+
+// AggregateSearchContext is like AggregateSearch but abandons the backend requests when
+// ctx is done.
+func AggregateSearchContext(ctx context.Context, query string, sources []string) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", errors.New("query is required")
+	}
+	if len(sources) == 0 {
+		sources = DefaultSearchSources
+	}
+
+	var active []string
+	for _, source := range sources {
+		if _, ok := searchBackends[source]; !ok {
+			return "", fmt.Errorf("unknown search source '%s' (expected duckduckgo, tavily or wikipedia)", source)
+		}
+		if source == SearchSourceTavily && os.Getenv("TAVILY_API_KEY") == "" && len(sources) > 1 {
+			continue
+		}
+		active = append(active, source)
+	}
+
+	results := make([][]SearchResult, len(active))
+	errs := make([]error, len(active))
+	var wg sync.WaitGroup
+	for i, source := range active {
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			results[i], errs[i] = searchBackends[source](ctx, query)
+		}(i, source)
+	}
+	wg.Wait()
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", active[i], err))
+		}
+	}
+	if len(failures) == len(active) {
+		return "", fmt.Errorf("all search sources failed: %s", strings.Join(failures, "; "))
+	}
+
+	merged := mergeSearchResults(active, results)
+
+	var sb strings.Builder
+	for _, r := range merged {
+		sb.WriteString(fmt.Sprintf("Title: %s\nURL: %s\nSources: %s\nContent: %s\n\n", r.Title, r.URL, strings.Join(r.Sources, ", "), r.Snippet))
+	}
+	if len(merged) == 0 {
+		sb.WriteString("No results found.\n")
+	}
+	if len(failures) > 0 {
+		sb.WriteString("\nFailed sources:\n")
+		for _, f := range failures {
+			sb.WriteString("- " + f + "\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// mergeSearchResults deduplicates results by URL and ranks them by the sum of their
+// reciprocal ranks across backends. Ties keep the order in which URLs were first seen.
+func mergeSearchResults(sources []string, results [][]SearchResult) []SearchResult {
+	type entry struct {
+		result SearchResult
+		score  float64
+		order  int
+	}
+	byURL := make(map[string]*entry)
+	var entries []*entry
+	for i, list := range results {
+		for rank, r := range list {
+			key := normalizeResultURL(r.URL)
+			if key == "" {
+				continue
+			}
+			e, ok := byURL[key]
+			if !ok {
+				e = &entry{result: SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Snippet}, order: len(entries)}
+				byURL[key] = e
+				entries = append(entries, e)
+			}
+			if e.result.Snippet == "" {
+				e.result.Snippet = r.Snippet
+			}
+			e.result.Sources = append(e.result.Sources, sources[i])
+			e.score += 1 / float64(rank+1)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].score != entries[j].score {
+			return entries[i].score > entries[j].score
+		}
+		return entries[i].order < entries[j].order
+	})
+	merged := make([]SearchResult, len(entries))
+	for i, e := range entries {
+		merged[i] = e.result
+	}
+	return merged
+}
+
+// normalizeResultURL reduces a URL to a key that is equal for trivially different
+// spellings: scheme, "www.", case of the host, trailing slashes and fragments are ignored.
+func normalizeResultURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	return host + strings.TrimRight(u.Path, "/") + "?" + u.RawQuery
+}
+
+// searchDuckDuckGo queries the DuckDuckGo Instant Answer API.
+func searchDuckDuckGo(ctx context.Context, query string) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("no_html", "1")
+	params.Set("skip_disambig", "1")
+
+	var resp struct {
+		Heading       string `json:"Heading"`
+		AbstractText  string `json:"AbstractText"`
+		AbstractURL   string `json:"AbstractURL"`
+		RelatedTopics []struct {
+			FirstURL string `json:"FirstURL"`
+			Text     string `json:"Text"`
+			Topics   []struct {
+				FirstURL string `json:"FirstURL"`
+				Text     string `json:"Text"`
+			} `json:"Topics"`
+		} `json:"RelatedTopics"`
+	}
+	if err := getSearchJSON(ctx, duckDuckGoURL+"?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	if resp.AbstractURL != "" {
+		results = append(results, SearchResult{Title: resp.Heading, URL: resp.AbstractURL, Snippet: resp.AbstractText})
+	}
+	add := func(firstURL, text string) {
+		if firstURL == "" || len(results) >= maxResultsPerSource {
+			return
+		}
+		title, _, _ := strings.Cut(text, " - ")
+		results = append(results, SearchResult{Title: title, URL: firstURL, Snippet: text})
+	}
+	for _, topic := range resp.RelatedTopics {
+		add(topic.FirstURL, topic.Text)
+		for _, sub := range topic.Topics {
+			add(sub.FirstURL, sub.Text)
+		}
+	}
+	return results, nil
+}
+
+// searchTavily queries the Tavily API.
+func searchTavily(ctx context.Context, query string) ([]SearchResult, error) {
+	resp, err := tavilyQuery(ctx, query, maxResultsPerSource, tavilySearchURL)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SearchResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return results, nil
+}
+
+// htmlTagPattern matches the markup Wikipedia puts into search snippets.
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// searchWikipedia runs a full-text search on Wikipedia.
+func searchWikipedia(ctx context.Context, query string) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("action", "query")
+	params.Set("format", "json")
+	params.Set("list", "search")
+	params.Set("srsearch", query)
+	params.Set("srlimit", fmt.Sprint(maxResultsPerSource))
+
+	var resp struct {
+		Query struct {
+			Search []struct {
+				Title   string `json:"title"`
+				Snippet string `json:"snippet"`
+			} `json:"search"`
+		} `json:"query"`
+	}
+	if err := getSearchJSON(ctx, wikipediaSearchURL+"?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, r := range resp.Query.Search {
+		results = append(results, SearchResult{
+			Title:   r.Title,
+			URL:     "https://en.wikipedia.org/wiki/" + url.PathEscape(strings.ReplaceAll(r.Title, " ", "_")),
+			Snippet: htmlTagPattern.ReplaceAllString(r.Snippet, ""),
+		})
+	}
+	return results, nil
+}
+
+// getSearchJSON fetches searchURL and decodes its JSON body into v.
+func getSearchJSON(ctx context.Context, searchURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "goskills")
+
+	client := http.Client{
+		Timeout: 15 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
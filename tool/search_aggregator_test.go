@@ -0,0 +1,150 @@
+package tool
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// searchBarrier blocks every backend request until n requests are in flight, proving the
+// backends are queried in parallel. Sequential requests time out and fail the barrier.
+type searchBarrier struct {
+	n       int
+	mu      sync.Mutex
+	arrived int
+	ready   chan struct{}
+	timeout bool
+}
+
+func newSearchBarrier(n int) *searchBarrier {
+	return &searchBarrier{n: n, ready: make(chan struct{})}
+}
+
+func (b *searchBarrier) wait() {
+	b.mu.Lock()
+	b.arrived++
+	if b.arrived == b.n {
+		close(b.ready)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.ready:
+	case <-time.After(2 * time.Second):
+		b.mu.Lock()
+		b.timeout = true
+		b.mu.Unlock()
+	}
+}
+
+// startSearchBackends points all three backends at mock servers that wait on barrier.
+func startSearchBackends(t *testing.T, barrier *searchBarrier) {
+	t.Helper()
+	serve := func(body any) string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			barrier.wait()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(body)
+		}))
+		t.Cleanup(server.Close)
+		return server.URL
+	}
+
+	oldDDG, oldTavily, oldWiki := duckDuckGoURL, tavilySearchURL, wikipediaSearchURL
+	t.Cleanup(func() { duckDuckGoURL, tavilySearchURL, wikipediaSearchURL = oldDDG, oldTavily, oldWiki })
+
+	duckDuckGoURL = serve(map[string]any{
+		"Heading":      "Go (programming language)",
+		"AbstractText": "Go is a statically typed language.",
+		"AbstractURL":  "https://en.wikipedia.org/wiki/Go_(programming_language)",
+		"RelatedTopics": []map[string]any{
+			{"FirstURL": "https://duckduckgo.com/Gopher", "Text": "Gopher - The Go mascot."},
+			{"Topics": []map[string]any{{"FirstURL": "https://go.dev/", "Text": "Go website - The home of Go."}}},
+		},
+	})
+	tavilySearchURL = serve(map[string]any{
+		"results": []map[string]any{
+			{"title": "The Go Programming Language", "url": "https://go.dev", "content": "Build simple, secure, scalable systems with Go."},
+			{"title": "Go blog", "url": "https://go.dev/blog/", "content": "The Go blog."},
+		},
+	})
+	wikipediaSearchURL = serve(map[string]any{
+		"query": map[string]any{
+			"search": []map[string]any{
+				{"title": "Go (programming language)", "snippet": `<span class="searchmatch">Go</span> is a language`},
+				{"title": "Gopher", "snippet": `A <span class="searchmatch">gopher</span> is a rodent`},
+			},
+		},
+	})
+}
+
+func TestAggregateSearch(t *testing.T) {
+	t.Setenv("TAVILY_API_KEY", "test-key")
+	barrier := newSearchBarrier(3)
+	startSearchBackends(t, barrier)
+
+	result, err := AggregateSearch("golang", nil)
+	if err != nil {
+		t.Fatalf("AggregateSearch() error = %v", err)
+	}
+	if barrier.timeout {
+		t.Error("Expected all backends to be queried in parallel")
+	}
+
+	// The Wikipedia article is returned first by two backends and ranks first
+	entries := strings.Split(strings.TrimSpace(result), "\n\n")
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 deduplicated results, got %d:\n%s", len(entries), result)
+	}
+	expected := []string{
+		"URL: https://en.wikipedia.org/wiki/Go_(programming_language)\nSources: duckduckgo, wikipedia",
+		"URL: https://go.dev/\nSources: duckduckgo, tavily",
+		"URL: https://duckduckgo.com/Gopher\nSources: duckduckgo",
+		"URL: https://go.dev/blog/\nSources: tavily",
+		"URL: https://en.wikipedia.org/wiki/Gopher\nSources: wikipedia\nContent: A gopher is a rodent",
+	}
+	for i, want := range expected {
+		if !strings.Contains(entries[i], want) {
+			t.Errorf("Result %d = %q, want it to contain %q", i, entries[i], want)
+		}
+	}
+}
+
+func TestAggregateSearch_Sources(t *testing.T) {
+	t.Setenv("TAVILY_API_KEY", "")
+	barrier := newSearchBarrier(2)
+	startSearchBackends(t, barrier)
+
+	// Tavily is skipped without an API key
+	result, err := AggregateSearch("golang", DefaultSearchSources)
+	if err != nil {
+		t.Fatalf("AggregateSearch() error = %v", err)
+	}
+	if strings.Contains(result, "tavily") {
+		t.Errorf("Expected Tavily to be skipped, got:\n%s", result)
+	}
+
+	// A failing backend is reported without failing the search
+	wikipediaSearchURL = "http://127.0.0.1:0"
+	result, err = AggregateSearch("golang", []string{SearchSourceDuckDuckGo, SearchSourceWikipedia})
+	if err != nil {
+		t.Fatalf("AggregateSearch() error = %v", err)
+	}
+	if !strings.Contains(result, "Failed sources:\n- wikipedia:") {
+		t.Errorf("Expected the Wikipedia failure to be reported, got:\n%s", result)
+	}
+
+	if _, err := AggregateSearch("golang", []string{SearchSourceWikipedia}); err == nil {
+		t.Error("Expected error when all sources fail")
+	}
+	if _, err := AggregateSearch("golang", []string{"altavista"}); err == nil {
+		t.Error("Expected error for unknown source")
+	}
+	if _, err := AggregateSearch(" ", nil); err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
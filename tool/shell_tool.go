@@ -2,23 +2,35 @@ package tool
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"text/template"
+	"time"
 )
 
+// processWaitDelay is how long the output of a process stopped by its context is
+// still read, so processes it started cannot keep the tool waiting.
+const processWaitDelay = time.Second
+
+// ShellTool runs shell code snippets.
 type ShellTool struct {
+	// SkillArgs are exposed to the code as {{.GOSKILLS_ARGS.key}} and as
+	// GOSKILLS_ARG_<KEY> environment variables.
+	SkillArgs map[string]string
 }
 
-func (t *ShellTool) Run(args map[string]any, code string) (string, error) {
+// Run executes code, a template filled with args, with bash. The script is killed when
+// ctx is done.
+func (t *ShellTool) Run(ctx context.Context, args map[string]any, code string) (string, error) {
 	tmpl, err := template.New("shell").Parse(code)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse shell template: %w", err)
 	}
 
 	var script bytes.Buffer
-	err = tmpl.Execute(&script, args)
+	err = tmpl.Execute(&script, withSkillArgs(args, t.SkillArgs))
 	if err != nil {
 		return "", fmt.Errorf("failed to execute shell template: %w", err)
 	}
@@ -36,18 +48,30 @@ func (t *ShellTool) Run(args map[string]any, code string) (string, error) {
 		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 
-	return RunShellScript(tmpfile.Name(), nil)
+	return RunShellScriptContext(ctx, tmpfile.Name(), nil, SkillArgsEnv(t.SkillArgs))
 }
 
 // RunShellScript executes a shell script and returns its combined stdout and stderr.
 func RunShellScript(scriptPath string, args []string) (string, error) {
-	cmd := exec.Command("bash", append([]string{scriptPath}, args...)...)
+	return RunShellScriptContext(context.Background(), scriptPath, args, nil)
+}
+
+// RunShellScriptContext is like RunShellScript but adds env, a list of KEY=value entries,
+// to the script's environment and kills the script when ctx is done, returning an error
+// that wraps ctx.Err().
+func RunShellScriptContext(ctx context.Context, scriptPath string, args []string, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "bash", append([]string{scriptPath}, args...)...)
+	cmd.Env = commandEnv(env)
+	cmd.WaitDelay = processWaitDelay
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("shell script '%s' was stopped: %w\nStdout: %s\nStderr: %s", scriptPath, ctx.Err(), stdout.String(), stderr.String())
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to run shell script '%s': %w\nStdout: %s\nStderr: %s", scriptPath, err, stdout.String(), stderr.String())
 	}
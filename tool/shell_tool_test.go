@@ -1,9 +1,12 @@
 package tool
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestShellTool_Run(t *testing.T) {
@@ -13,7 +16,7 @@ func TestShellTool_Run(t *testing.T) {
 	args := map[string]any{}
 	code := "echo 'Hello World'"
 
-	result, err := shellTool.Run(args, code)
+	result, err := shellTool.Run(context.Background(), args, code)
 	if err != nil {
 		t.Errorf("ShellTool.Run() error = %v", err)
 		return
@@ -31,7 +34,7 @@ func TestShellTool_Run(t *testing.T) {
 	}
 	code = `echo "Hello {{.name}}! Count is {{.count}}"`
 
-	result, err = shellTool.Run(args, code)
+	result, err = shellTool.Run(context.Background(), args, code)
 	if err != nil {
 		t.Errorf("ShellTool.Run() with args error = %v", err)
 		return
@@ -46,7 +49,7 @@ func TestShellTool_Run(t *testing.T) {
 	args = map[string]any{}
 	code = "echo {{.invalid.property}}"
 
-	_, err = shellTool.Run(args, code)
+	_, err = shellTool.Run(context.Background(), args, code)
 	if err == nil {
 		t.Error("ShellTool.Run() with invalid template expected error, got nil")
 	}
@@ -165,7 +168,7 @@ func TestShellToolWithComplexCommands(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := shellTool.Run(tt.args, tt.code)
+			result, err := shellTool.Run(context.Background(), tt.args, tt.code)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ShellTool.Run() error = %v, wantErr %v", err, tt.wantErr)
@@ -186,9 +189,67 @@ func BenchmarkShellTool_Run(b *testing.B) {
 	code := "echo 'Benchmark test'"
 
 	for b.Loop() {
-		_, err := shellTool.Run(args, code)
+		_, err := shellTool.Run(context.Background(), args, code)
 		if err != nil {
 			b.Fatalf("ShellTool.Run() error = %v", err)
 		}
 	}
 }
+
+func TestShellTool_RunTimeout(t *testing.T) {
+	shellTool := &ShellTool{}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := shellTool.Run(ctx, nil, "echo started; sleep 5")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ShellTool.Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("ShellTool.Run() returned after %s, want shortly after the timeout", elapsed)
+	}
+}
+
+func TestShellTool_SkillArgs(t *testing.T) {
+	shellTool := &ShellTool{SkillArgs: map[string]string{"url": "https://example.com", "max_pages": "3"}}
+
+	result, err := shellTool.Run(context.Background(), nil, `echo "$GOSKILLS_ARG_URL $GOSKILLS_ARG_MAX_PAGES {{.GOSKILLS_ARGS.url}}"`)
+	if err != nil {
+		t.Fatalf("ShellTool.Run() error = %v", err)
+	}
+	if want := "https://example.com 3 https://example.com\n"; result != want {
+		t.Errorf("ShellTool.Run() = %q, want %q", result, want)
+	}
+
+	scriptPath := filepath.Join(t.TempDir(), "args.sh")
+	if err := os.WriteFile(scriptPath, []byte("echo \"$GOSKILLS_ARG_URL\"\n"), 0755); err != nil {
+		t.Fatalf("Failed to create test script: %v", err)
+	}
+	result, err = RunShellScriptContext(context.Background(), scriptPath, nil, SkillArgsEnv(shellTool.SkillArgs))
+	if err != nil {
+		t.Fatalf("RunShellScriptContext() error = %v", err)
+	}
+	if result != "https://example.com\n" {
+		t.Errorf("RunShellScriptContext() = %q, want %q", result, "https://example.com\n")
+	}
+}
+
+func TestSkillArgsEnv(t *testing.T) {
+	env := SkillArgsEnv(map[string]string{"url": "u", "Max_Pages": "3"})
+	want := []string{"GOSKILLS_ARG_MAX_PAGES=3", "GOSKILLS_ARG_URL=u"}
+	if len(env) != len(want) || env[0] != want[0] || env[1] != want[1] {
+		t.Errorf("SkillArgsEnv() = %v, want %v", env, want)
+	}
+}
+
+func TestValidateSkillArgs(t *testing.T) {
+	if err := ValidateSkillArgs(map[string]string{"url": "x", "max_pages2": "y"}); err != nil {
+		t.Errorf("ValidateSkillArgs() error = %v", err)
+	}
+	for _, key := range []string{"", "bad-key", "a b", "ключ"} {
+		if err := ValidateSkillArgs(map[string]string{key: "x"}); err == nil {
+			t.Errorf("ValidateSkillArgs(%q) = nil, want error", key)
+		}
+	}
+}
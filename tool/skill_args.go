@@ -0,0 +1,64 @@
+package tool
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SkillArgsKey is the template variable that holds the skill arguments given with
+// 'goskills run --skill-arg', so code can use {{.GOSKILLS_ARGS.key}}.
+const SkillArgsKey = "GOSKILLS_ARGS"
+
+// SkillArgEnvPrefix prefixes the environment variables that pass skill arguments to
+// scripts: the argument url is available as GOSKILLS_ARG_URL.
+const SkillArgEnvPrefix = "GOSKILLS_ARG_"
+
+var skillArgKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ValidateSkillArgs checks that every skill argument key consists of letters, digits
+// and underscores, so it can be used in an environment variable name and a template.
+func ValidateSkillArgs(args map[string]string) error {
+	for key := range args {
+		if !skillArgKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid skill argument key '%s' (expected letters, digits and underscores)", key)
+		}
+	}
+	return nil
+}
+
+// SkillArgsEnv returns the skill arguments as GOSKILLS_ARG_<KEY>=value environment
+// entries, sorted by key.
+func SkillArgsEnv(args map[string]string) []string {
+	env := make([]string, 0, len(args))
+	for key, value := range args {
+		env = append(env, SkillArgEnvPrefix+strings.ToUpper(key)+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// withSkillArgs returns the template data for code: args plus the skill arguments
+// under SkillArgsKey. args itself is not modified.
+func withSkillArgs(args map[string]any, skillArgs map[string]string) map[string]any {
+	if len(skillArgs) == 0 {
+		return args
+	}
+	data := make(map[string]any, len(args)+1)
+	for k, v := range args {
+		data[k] = v
+	}
+	data[SkillArgsKey] = skillArgs
+	return data
+}
+
+// commandEnv returns the environment for a subprocess: the current environment plus
+// extra, or nil to inherit the current environment unchanged.
+func commandEnv(extra []string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
@@ -0,0 +1,158 @@
+package tool
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// DefaultSQLDriver is the database/sql driver used when none is given.
+const DefaultSQLDriver = "sqlite"
+
+// maxSQLRows caps the number of rows returned by a SELECT query.
+const maxSQLRows = 1000
+
+// ddlKeywords are the leading keywords of statements that change a database schema.
+var ddlKeywords = []string{"CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME"}
+
+// ExecuteSQL runs query against the database identified by driver and dsn and
+// returns the result as a Markdown table. SELECT (and WITH) queries are limited
+// to 1000 rows; other statements report the number of affected rows. DDL
+// statements are rejected when the DSN opens the database read-only.
+// Additional drivers (e.g. DuckDB) can be used by importing them for their side effects.
+func ExecuteSQL(driver, dsn, query string) (string, error) {
+	return ExecuteSQLContext(context.Background(), driver, dsn, query)
+}
+
+// ExecuteSQLContext is like ExecuteSQL but cancels the statement when ctx is done.
+func ExecuteSQLContext(ctx context.Context, driver, dsn, query string) (string, error) {
+	// "sqlite3" is the name most SQLite drivers register, so it is accepted too
+	if driver == "" || driver == "sqlite3" {
+		driver = DefaultSQLDriver
+	}
+	query = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(query), ";"))
+	if query == "" {
+		return "", errors.New("query is empty")
+	}
+	keyword := strings.ToUpper(firstWord(query))
+	if isReadOnlyDSN(dsn) {
+		for _, ddl := range ddlKeywords {
+			if keyword == ddl {
+				return "", fmt.Errorf("%s statements are not allowed on a read-only database", keyword)
+			}
+		}
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s database: %w", driver, err)
+	}
+	defer db.Close()
+
+	if keyword != "SELECT" && keyword != "WITH" {
+		result, err := db.ExecContext(ctx, query)
+		if err != nil {
+			return "", fmt.Errorf("failed to execute statement: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return "Statement executed successfully.", nil
+		}
+		return fmt.Sprintf("Statement executed successfully. %d row(s) affected.", affected), nil
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM (%s) LIMIT %d", query, maxSQLRows))
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return rowsToMarkdown(rows)
+}
+
+// rowsToMarkdown renders rows as a Markdown table.
+func rowsToMarkdown(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(escapeCells(columns), " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", fmt.Errorf("failed to scan row: %w", err)
+		}
+		cells := make([]string, len(values))
+		for i, v := range values {
+			switch v := v.(type) {
+			case nil:
+				cells[i] = "NULL"
+			case []byte:
+				cells[i] = string(v)
+			default:
+				cells[i] = fmt.Sprint(v)
+			}
+		}
+		sb.WriteString("| " + strings.Join(escapeCells(cells), " | ") + " |\n")
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read rows: %w", err)
+	}
+	if count == 0 {
+		sb.WriteString("\n(no rows)\n")
+	}
+	return sb.String(), nil
+}
+
+// escapeCells makes values safe to place inside a Markdown table cell.
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		c = strings.ReplaceAll(c, "|", `\|`)
+		escaped[i] = strings.ReplaceAll(c, "\n", " ")
+	}
+	return escaped
+}
+
+// firstWord returns the first whitespace-delimited word of s, ignoring a leading parenthesis.
+func firstWord(s string) string {
+	s = strings.TrimLeft(s, "( \t\r\n")
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return strings.TrimRight(fields[0], "(")
+	}
+	return ""
+}
+
+// isReadOnlyDSN reports whether dsn asks the driver to open the database read-only.
+func isReadOnlyDSN(dsn string) bool {
+	_, rawQuery, ok := strings.Cut(dsn, "?")
+	if !ok {
+		return false
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return false
+	}
+	switch {
+	case params.Get("mode") == "ro",
+		params.Get("immutable") == "1",
+		params.Get("_query_only") == "1", params.Get("_query_only") == "true",
+		params.Get("access_mode") == "read_only", params.Get("access_mode") == "READ_ONLY":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package tool
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// openSharedMemoryDB opens a named in-memory SQLite database and keeps it alive for the
+// duration of the test so ExecuteSQL calls with the same DSN see the same data.
+func openSharedMemoryDB(t *testing.T, name string) string {
+	t.Helper()
+	dsn := "file:" + name + "?mode=memory&cache=shared"
+	db, err := sql.Open(DefaultSQLDriver, dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return dsn
+}
+
+func TestExecuteSQL(t *testing.T) {
+	dsn := openSharedMemoryDB(t, "execute_sql")
+
+	output, err := ExecuteSQL("", dsn, "CREATE TABLE users (id INTEGER, name TEXT, email TEXT)")
+	if err != nil {
+		t.Fatalf("ExecuteSQL() create error = %v", err)
+	}
+	if !strings.Contains(output, "executed successfully") {
+		t.Errorf("ExecuteSQL() create output = %q", output)
+	}
+
+	// "sqlite3" is accepted as an alias of the default driver
+	output, err = ExecuteSQL("sqlite3", dsn, "INSERT INTO users VALUES (1, 'alice', 'a|b'), (2, 'bob', NULL);")
+	if err != nil {
+		t.Fatalf("ExecuteSQL() insert error = %v", err)
+	}
+	if !strings.Contains(output, "2 row(s) affected") {
+		t.Errorf("ExecuteSQL() insert output = %q", output)
+	}
+
+	output, err = ExecuteSQL("sqlite", dsn, "SELECT id, name, email FROM users ORDER BY id")
+	if err != nil {
+		t.Fatalf("ExecuteSQL() select error = %v", err)
+	}
+	want := "| id | name | email |\n| --- | --- | --- |\n| 1 | alice | a\\|b |\n| 2 | bob | NULL |\n"
+	if output != want {
+		t.Errorf("ExecuteSQL() select output = %q, want %q", output, want)
+	}
+
+	output, err = ExecuteSQL("sqlite", dsn, "SELECT * FROM users WHERE id > 10")
+	if err != nil {
+		t.Fatalf("ExecuteSQL() empty select error = %v", err)
+	}
+	if !strings.Contains(output, "(no rows)") {
+		t.Errorf("ExecuteSQL() empty select output = %q", output)
+	}
+}
+
+func TestExecuteSQL_RowLimit(t *testing.T) {
+	query := "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1500) SELECT i FROM n"
+	output, err := ExecuteSQL("sqlite", ":memory:", query)
+	if err != nil {
+		t.Fatalf("ExecuteSQL() error = %v", err)
+	}
+	// Header and separator lines plus the limited rows.
+	if lines := strings.Count(output, "\n"); lines != maxSQLRows+2 {
+		t.Errorf("ExecuteSQL() returned %d lines, want %d", lines, maxSQLRows+2)
+	}
+}
+
+func TestExecuteSQL_ReadOnlyBlocksDDL(t *testing.T) {
+	for _, query := range []string{"DROP TABLE users", "create table t (id int)", "ALTER TABLE users ADD COLUMN age INT"} {
+		if _, err := ExecuteSQL("sqlite", "file:data.db?mode=ro", query); err == nil || !strings.Contains(err.Error(), "read-only") {
+			t.Errorf("ExecuteSQL(%q) error = %v, want read-only error", query, err)
+		}
+	}
+
+	if _, err := ExecuteSQL("sqlite", "file:readonly?mode=memory&immutable=1", "SELECT 1"); err != nil {
+		t.Errorf("ExecuteSQL() select on read-only database error = %v", err)
+	}
+}
+
+func TestExecuteSQL_Errors(t *testing.T) {
+	if _, err := ExecuteSQL("sqlite", ":memory:", "  ;"); err == nil {
+		t.Error("ExecuteSQL() expected error for empty query")
+	}
+	if _, err := ExecuteSQL("nosuchdriver", ":memory:", "SELECT 1"); err == nil {
+		t.Error("ExecuteSQL() expected error for unknown driver")
+	}
+	if _, err := ExecuteSQL("sqlite", ":memory:", "SELECT * FROM missing"); err == nil {
+		t.Error("ExecuteSQL() expected error for missing table")
+	}
+}
+
+func TestExecuteSQLContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// A recursive query that would run for a long time
+	_, err := ExecuteSQLContext(ctx, "", ":memory:", "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT count(*) FROM n")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteSQLContext() error = %v, want context.Canceled", err)
+	}
+}
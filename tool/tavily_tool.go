@@ -16,17 +16,63 @@ func TavilySearch(query string) (string, error) {
 	return TavilySearchWithLimit(query, 20)
 }
 
-// TavilySearchWithLimit performs a web search using the Tavily API with a custom result limit.
+// TavilySearchContext is like TavilySearch but abandons the request when ctx is done.
+func TavilySearchContext(ctx context.Context, query string) (string, error) {
+	return tavilySearch(ctx, query, 20, tavilySearchURL)
+}
+
 // TavilySearchWithLimit performs a web search using the Tavily API with a custom result limit.
 func TavilySearchWithLimit(query string, maxResults int) (string, error) {
-	return TavilySearchWithLimitAndURL(query, maxResults, "https://api.tavily.com/search")
+	return TavilySearchWithLimitAndURL(query, maxResults, tavilySearchURL)
 }
 
 // TavilySearchWithLimitAndURL performs a web search using the Tavily API with a custom result limit and URL (for testing)
 func TavilySearchWithLimitAndURL(query string, maxResults int, apiURL string) (string, error) {
+	return tavilySearch(context.Background(), query, maxResults, apiURL)
+}
+
+// tavilySearch queries the Tavily API at apiURL and formats the results as text.
+func tavilySearch(ctx context.Context, query string, maxResults int, apiURL string) (string, error) {
+	result, err := tavilyQuery(ctx, query, maxResults, apiURL)
+	if err != nil {
+		return "", err
+	}
+
+	var sb bytes.Buffer
+	for _, item := range result.Results {
+		sb.WriteString(fmt.Sprintf("Title: %s\nURL: %s\nContent: %s\n\n", item.Title, item.URL, item.Content))
+	}
+
+	if len(result.Images) > 0 {
+		sb.WriteString("\nRelevant Images:\n")
+		for _, imgURL := range result.Images {
+			sb.WriteString(fmt.Sprintf("- Image URL: %s\n", imgURL))
+		}
+		sb.WriteString("\n")
+	}
+
+	if sb.Len() == 0 {
+		return "No results found.", nil
+	}
+
+	return sb.String(), nil
+}
+
+// tavilyResponse is the part of a Tavily search response goskills uses.
+type tavilyResponse struct {
+	Results []struct {
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Content string `json:"content"`
+	} `json:"results"`
+	Images []string `json:"images"`
+}
+
+// tavilyQuery sends a search request to the Tavily API at apiURL.
+func tavilyQuery(ctx context.Context, query string, maxResults int, apiURL string) (*tavilyResponse, error) {
 	apiKey := os.Getenv("TAVILY_API_KEY")
 	if apiKey == "" {
-		return "", fmt.Errorf("TAVILY_API_KEY environment variable is not set")
+		return nil, fmt.Errorf("TAVILY_API_KEY environment variable is not set")
 	}
 
 	if maxResults <= 0 {
@@ -43,12 +89,12 @@ func TavilySearchWithLimitAndURL(query string, maxResults int, apiURL string) (s
 		"include_images": true,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", apiURL, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -60,44 +106,18 @@ func TavilySearchWithLimitAndURL(query string, maxResults int, apiURL string) (s
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to perform Tavily search: %w", err)
+		return nil, fmt.Errorf("failed to perform Tavily search: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Tavily API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var result struct {
-		Results []struct {
-			Title   string `json:"title"`
-			URL     string `json:"url"`
-			Content string `json:"content"`
-		} `json:"results"`
-		Images []string `json:"images"`
+		return nil, fmt.Errorf("Tavily API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
+	var result tavilyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode Tavily response: %w", err)
+		return nil, fmt.Errorf("failed to decode Tavily response: %w", err)
 	}
-
-	var sb bytes.Buffer
-	for _, item := range result.Results {
-		sb.WriteString(fmt.Sprintf("Title: %s\nURL: %s\nContent: %s\n\n", item.Title, item.URL, item.Content))
-	}
-
-	if len(result.Images) > 0 {
-		sb.WriteString("\nRelevant Images:\n")
-		for _, imgURL := range result.Images {
-			sb.WriteString(fmt.Sprintf("- Image URL: %s\n", imgURL))
-		}
-		sb.WriteString("\n")
-	}
-
-	if sb.Len() == 0 {
-		return "No results found.", nil
-	}
-
-	return sb.String(), nil
+	return &result, nil
 }
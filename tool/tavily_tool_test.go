@@ -1,12 +1,14 @@
 package tool
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestTavilySearch(t *testing.T) {
@@ -229,3 +231,23 @@ func BenchmarkTavilySearch(b *testing.B) {
 		_ = err // Ignore error for benchmarking purposes
 	}
 }
+
+func TestTavilySearchContextCancelled(t *testing.T) {
+	t.Setenv("TAVILY_API_KEY", "test-key")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := tavilySearch(ctx, "test query", 10, server.URL); err == nil {
+		t.Fatal("tavilySearch() expected error after the context expired, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("tavilySearch() returned after %v, want it to stop with the context", elapsed)
+	}
+}
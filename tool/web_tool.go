@@ -1,21 +1,31 @@
 package tool
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os/exec"
+	"runtime"
 	"time"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
 )
 
 // WebFetch retrieves the main text content from a given URL.
 // It uses goquery to parse the HTML and extract text, removing script and style tags.
-func WebFetch(urlString string) (string, error) {
+// The request is abandoned when ctx is done.
+func WebFetch(ctx context.Context, urlString string) (string, error) {
 	client := http.Client{
 		Timeout: 20 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", urlString, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", urlString, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request for %s: %w", urlString, err)
 	}
@@ -54,3 +64,164 @@ func WebFetch(urlString string) (string, error) {
 	// return strings.Join(strings.Fields(bodyText), " ")
 	return bodyText, nil
 }
+
+// DefaultReadURLMaxBytes is the number of bytes ReadURL returns when maxBytes is not positive.
+const DefaultReadURLMaxBytes = 1 << 20
+
+// ReadURLResult is the JSON document returned by ReadURL.
+type ReadURLResult struct {
+	URL           string `json:"url"`
+	ContentType   string `json:"content_type,omitempty"`
+	Size          int    `json:"size"`
+	Truncated     bool   `json:"truncated"`
+	ContentBase64 string `json:"content_base64"`
+	ContentText   string `json:"content_text,omitempty"` // Set only when the content is valid UTF-8
+}
+
+// ReadURL fetches a URL and returns its raw body, base64-encoded, as a JSON ReadURLResult.
+// At most maxBytes bytes are read (DefaultReadURLMaxBytes if maxBytes <= 0); Truncated
+// reports whether the body was longer. Valid UTF-8 content is also returned as text.
+func ReadURL(urlString string, maxBytes int) (string, error) {
+	return ReadURLContext(context.Background(), urlString, maxBytes)
+}
+
+// ReadURLContext is like ReadURL but abandons the request when ctx is done.
+func ReadURLContext(ctx context.Context, urlString string, maxBytes int) (string, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultReadURLMaxBytes
+	}
+	client := http.Client{
+		Timeout: 20 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", urlString, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for %s: %w", urlString, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch URL %s: %w", urlString, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("request to %s failed with status code %d", urlString, resp.StatusCode)
+	}
+
+	// Read one byte more than allowed to detect truncation
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body from %s: %w", urlString, err)
+	}
+	result := ReadURLResult{
+		URL:         urlString,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if len(body) > maxBytes {
+		body = body[:maxBytes]
+		result.Truncated = true
+	}
+	result.Size = len(body)
+	result.ContentBase64 = base64.StdEncoding.EncodeToString(body)
+	if utf8.Valid(body) {
+		result.ContentText = string(body)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return string(data), nil
+}
+
+// chromeCandidates lists the executable names and paths probed when looking for a
+// Chrome/Chromium binary to drive with chromedp.
+var chromeCandidates = map[string][]string{
+	"darwin": {
+		"/Applications/Chromium.app/Contents/MacOS/Chromium",
+		"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	},
+	"windows": {
+		"chrome",
+		"chrome.exe",
+		`C:\Program Files (x86)\Google\Chrome\Application\chrome.exe`,
+		`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+	},
+	"default": {
+		"headless-shell",
+		"chromium",
+		"chromium-browser",
+		"google-chrome",
+		"google-chrome-stable",
+		"chrome",
+	},
+}
+
+// FindChrome returns the path of the first Chrome/Chromium binary found on this system.
+func FindChrome() (string, error) {
+	candidates, ok := chromeCandidates[runtime.GOOS]
+	if !ok {
+		candidates = chromeCandidates["default"]
+	}
+	for _, candidate := range candidates {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("no Chrome or Chromium executable found; install Chrome/Chromium to use browser tools")
+}
+
+// MaxScreenshotWait caps the settle delay of WebScreenshotWithWait.
+const MaxScreenshotWait = 10 * time.Second
+
+// WebScreenshot renders the page at the given URL in headless Chrome and returns a PNG
+// screenshot of the viewport. It waits 500ms after the page loads for JavaScript to settle
+// and gives up after 60 seconds.
+func WebScreenshot(urlString string, width, height int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	return WebScreenshotWithWait(ctx, urlString, width, height, 500*time.Millisecond)
+}
+
+// WebScreenshotWithWait renders the page at the given URL in headless Chrome with a custom
+// settle delay, capped at MaxScreenshotWait, and returns a PNG screenshot of the viewport.
+// The browser is shut down when ctx is done.
+func WebScreenshotWithWait(ctx context.Context, urlString string, width, height int, wait time.Duration) ([]byte, error) {
+	wait = min(max(wait, 0), MaxScreenshotWait)
+	if width <= 0 {
+		width = 1280
+	}
+	if height <= 0 {
+		height = 800
+	}
+
+	execPath, err := FindChrome()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take screenshot of %s: %w", urlString, err)
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(execPath),
+		chromedp.WindowSize(width, height),
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	defer cancelAlloc()
+
+	browserCtx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+
+	var png []byte
+	err = chromedp.Run(browserCtx,
+		chromedp.EmulateViewport(int64(width), int64(height)),
+		chromedp.Navigate(urlString),
+		chromedp.Sleep(wait),
+		chromedp.CaptureScreenshot(&png),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take screenshot of %s: %w", urlString, err)
+	}
+
+	return png, nil
+}
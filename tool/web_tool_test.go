@@ -1,11 +1,17 @@
 package tool
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestWebFetch(t *testing.T) {
@@ -28,7 +34,7 @@ func TestWebFetch(t *testing.T) {
 	}))
 	defer server.Close()
 
-	result, err := WebFetch(server.URL)
+	result, err := WebFetch(context.Background(), server.URL)
 	if err != nil {
 		t.Errorf("WebFetch() error = %v", err)
 		return
@@ -44,7 +50,7 @@ func TestWebFetch(t *testing.T) {
 	}
 
 	// Test case 2: Invalid URL
-	_, err = WebFetch("invalid-url")
+	_, err = WebFetch(context.Background(), "invalid-url")
 	if err == nil {
 		t.Error("WebFetch() with invalid URL expected error, got nil")
 	}
@@ -56,7 +62,7 @@ func TestWebFetch(t *testing.T) {
 	}))
 	defer errorServer.Close()
 
-	_, err = WebFetch(errorServer.URL)
+	_, err = WebFetch(context.Background(), errorServer.URL)
 	if err == nil {
 		t.Error("WebFetch() with non-200 status expected error, got nil")
 	}
@@ -108,7 +114,7 @@ func TestWebFetchWithDifferentContent(t *testing.T) {
 			}))
 			defer server.Close()
 
-			result, err := WebFetch(server.URL)
+			result, err := WebFetch(context.Background(), server.URL)
 
 			if (err != nil) != tt.expectedError {
 				t.Errorf("WebFetch() error = %v, wantErr %v", err, tt.expectedError)
@@ -142,12 +148,24 @@ func TestWebFetchWithNetworkErrors(t *testing.T) {
 	// Close the server immediately to simulate connection error
 	hangServer.Close()
 
-	_, err := WebFetch(hangServer.URL)
+	_, err := WebFetch(context.Background(), hangServer.URL)
 	if err == nil {
 		t.Error("WebFetch() with connection error expected error, got nil")
 	}
 
-	// Test case 2: Malformed URL
+	// Test case 2: Context deadline
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slowServer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = WebFetch(ctx, slowServer.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WebFetch() with timeout error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Test case 3: Malformed URL
 	malformedURLs := []string{
 		"not-a-url",
 		"http://",
@@ -156,7 +174,7 @@ func TestWebFetchWithNetworkErrors(t *testing.T) {
 	}
 
 	for _, url := range malformedURLs {
-		_, err := WebFetch(url)
+		_, err := WebFetch(context.Background(), url)
 		if err == nil {
 			t.Errorf("WebFetch() with malformed URL %q expected error, got nil", url)
 		}
@@ -180,7 +198,7 @@ func TestWebFetchUserAgent(t *testing.T) {
 	}))
 	defer server.Close()
 
-	_, err := WebFetch(server.URL)
+	_, err := WebFetch(context.Background(), server.URL)
 	if err != nil {
 		t.Errorf("WebFetch() error = %v", err)
 	}
@@ -204,7 +222,7 @@ func TestWebFetchWithLargeContent(t *testing.T) {
 	}))
 	defer server.Close()
 
-	result, err := WebFetch(server.URL)
+	result, err := WebFetch(context.Background(), server.URL)
 	if err != nil {
 		t.Errorf("WebFetch() with large content error = %v", err)
 		return
@@ -221,6 +239,139 @@ func TestWebFetchWithLargeContent(t *testing.T) {
 	}
 }
 
+func TestReadURL(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0xfe}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/binary":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(binary)
+		case "/text":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name": "café"}`)
+		case "/large":
+			w.Write(bytes.Repeat([]byte("a"), 5000))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	read := func(path string, maxBytes int) ReadURLResult {
+		t.Helper()
+		output, err := ReadURL(server.URL+path, maxBytes)
+		if err != nil {
+			t.Fatalf("ReadURL(%s) error = %v", path, err)
+		}
+		var result ReadURLResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("ReadURL(%s) returned invalid JSON: %v", path, err)
+		}
+		return result
+	}
+
+	// Binary content round-trips through base64 and has no text form
+	result := read("/binary", 0)
+	decoded, err := base64.StdEncoding.DecodeString(result.ContentBase64)
+	if err != nil {
+		t.Fatalf("content_base64 is not valid base64: %v", err)
+	}
+	if !bytes.Equal(decoded, binary) {
+		t.Errorf("decoded content = %v, want %v", decoded, binary)
+	}
+	if result.ContentText != "" {
+		t.Errorf("content_text = %q, want empty for binary content", result.ContentText)
+	}
+	if result.ContentType != "image/png" || result.Size != len(binary) || result.Truncated {
+		t.Errorf("unexpected result metadata: %+v", result)
+	}
+
+	// UTF-8 content is also returned as text
+	result = read("/text", 0)
+	if result.ContentText != `{"name": "café"}` {
+		t.Errorf("content_text = %q", result.ContentText)
+	}
+
+	// The body is limited to maxBytes
+	result = read("/large", 1000)
+	decoded, _ = base64.StdEncoding.DecodeString(result.ContentBase64)
+	if len(decoded) != 1000 || result.Size != 1000 || !result.Truncated {
+		t.Errorf("limited read returned %d bytes (size %d, truncated %v), want 1000 truncated", len(decoded), result.Size, result.Truncated)
+	}
+	result = read("/large", 5000)
+	if result.Size != 5000 || result.Truncated {
+		t.Errorf("exact-size read returned size %d, truncated %v", result.Size, result.Truncated)
+	}
+
+	if _, err := ReadURL(server.URL+"/missing", 0); err == nil {
+		t.Error("ReadURL() expected error for 404 response")
+	}
+}
+
+func TestWebScreenshot(t *testing.T) {
+	if _, err := FindChrome(); err != nil {
+		t.Skip("Skipping WebScreenshot tests: no Chrome or Chromium executable found")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body style='background:#0a0'><h1>Screenshot test</h1></body></html>")
+	}))
+	defer server.Close()
+
+	png, err := WebScreenshot(server.URL, 320, 240)
+	if err != nil {
+		t.Fatalf("WebScreenshot() error = %v", err)
+	}
+
+	// Every PNG file starts with the same 8-byte signature
+	if !strings.HasPrefix(string(png), "\x89PNG\r\n\x1a\n") {
+		t.Errorf("WebScreenshot() result is not a PNG image")
+	}
+}
+
+func TestWebScreenshotWithWaitCancelled(t *testing.T) {
+	if _, err := FindChrome(); err != nil {
+		t.Skip("Skipping WebScreenshot tests: no Chrome or Chromium executable found")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>slow</body></html>")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := WebScreenshotWithWait(ctx, server.URL, 320, 240, time.Hour); err == nil {
+		t.Fatal("WebScreenshotWithWait() expected error after the context expired, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > MaxScreenshotWait {
+		t.Errorf("WebScreenshotWithWait() returned after %v, want it to stop with the context", elapsed)
+	}
+}
+
+func TestWebScreenshotWithoutChrome(t *testing.T) {
+	// An empty PATH guarantees that no browser binary can be found
+	t.Setenv("PATH", t.TempDir())
+	if _, err := FindChrome(); err == nil {
+		t.Skip("Skipping: a browser is installed at an absolute path on this system")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>unreachable</body></html>")
+	}))
+	defer server.Close()
+
+	_, err := WebScreenshot(server.URL, 0, 0)
+	if err == nil {
+		t.Fatal("WebScreenshot() without a browser expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "Chrome") {
+		t.Errorf("WebScreenshot() error should mention the missing browser, got %v", err)
+	}
+}
+
 // Example of how to benchmark WebFetch
 func BenchmarkWebFetch(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -230,7 +381,7 @@ func BenchmarkWebFetch(b *testing.B) {
 	defer server.Close()
 
 	for b.Loop() {
-		_, err := WebFetch(server.URL)
+		_, err := WebFetch(context.Background(), server.URL)
 		if err != nil {
 			b.Fatalf("WebFetch() error = %v", err)
 		}
@@ -0,0 +1,108 @@
+package goskills
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/smallnest/goskills/log"
+)
+
+// ToolCache stores the output of idempotent tool calls so repeated calls with
+// the same arguments can be answered without re-running the tool.
+type ToolCache interface {
+	Get(key string) (string, bool)
+	Set(key string, value string, ttl time.Duration)
+}
+
+// toolCacheKey builds a cache key from the tool name and a canonical form of its
+// JSON arguments, so argument order and whitespace do not affect the key.
+func toolCacheKey(toolName, arguments string) string {
+	var args any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return toolName + ":" + arguments
+	}
+	canonical, err := json.Marshal(args)
+	if err != nil {
+		return toolName + ":" + arguments
+	}
+	return toolName + ":" + string(canonical)
+}
+
+type memoryCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryToolCache is an in-process ToolCache. It is safe for concurrent use.
+type MemoryToolCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+// NewMemoryToolCache creates an empty in-memory tool cache.
+func NewMemoryToolCache() *MemoryToolCache {
+	return &MemoryToolCache{
+		entries: make(map[string]memoryCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value for key if present and not expired.
+func (c *MemoryToolCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if c.now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.value, true
+}
+
+// Set stores value under key until ttl elapses.
+func (c *MemoryToolCache) Set(key string, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryCacheEntry{value: value, expiresAt: c.now().Add(ttl)}
+}
+
+// RedisToolCache is a ToolCache backed by Redis, allowing cached tool output to be
+// shared between processes.
+type RedisToolCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisToolCache creates a tool cache that stores entries in Redis under keys
+// starting with prefix.
+func NewRedisToolCache(client *redis.Client, prefix string) *RedisToolCache {
+	return &RedisToolCache{client: client, prefix: prefix}
+}
+
+// Get returns the cached value for key. Redis errors are treated as cache misses.
+func (c *RedisToolCache) Get(key string) (string, bool) {
+	value, err := c.client.Get(context.Background(), c.prefix+key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn("tool cache get failed: %v", err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+// Set stores value under key with the given ttl. Redis errors are logged and ignored.
+func (c *RedisToolCache) Set(key string, value string, ttl time.Duration) {
+	if err := c.client.Set(context.Background(), c.prefix+key, value, ttl).Err(); err != nil {
+		log.Warn("tool cache set failed: %v", err)
+	}
+}
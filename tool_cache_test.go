@@ -0,0 +1,63 @@
+package goskills
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCacheKey(t *testing.T) {
+	a := toolCacheKey("web_fetch", `{"url": "https://example.com", "depth": 1}`)
+	b := toolCacheKey("web_fetch", `{"depth":1,"url":"https://example.com"}`)
+	assert.Equal(t, a, b)
+
+	assert.NotEqual(t, a, toolCacheKey("wikipedia_search", `{"depth":1,"url":"https://example.com"}`))
+	assert.NotEqual(t, a, toolCacheKey("web_fetch", `{"depth":2,"url":"https://example.com"}`))
+	assert.Equal(t, "web_fetch:not json", toolCacheKey("web_fetch", "not json"))
+}
+
+func TestMemoryToolCache(t *testing.T) {
+	cache := NewMemoryToolCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("missing")
+	assert.False(t, ok)
+
+	cache.Set("key", "value", time.Minute)
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok, "expired entries should not be returned")
+}
+
+func TestRedisToolCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	cache := NewRedisToolCache(client, "goskills:")
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+
+	cache.Set("key", "value", time.Minute)
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	stored, err := server.Get("goskills:key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", stored)
+
+	server.FastForward(2 * time.Minute)
+	_, ok = cache.Get("key")
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package goskills
+
+import "context"
+
+// ToolEventType distinguishes tool calls from their results in a ToolEvent.
+type ToolEventType int
+
+const (
+	ToolEventCall   ToolEventType = iota // The LLM called a tool; Content holds the arguments
+	ToolEventResult                      // A tool call finished; Content holds the message sent back to the LLM
+)
+
+// ToolEvent reports a tool call, or its result, while an agent answers a prompt.
+type ToolEvent struct {
+	Type       ToolEventType
+	ToolName   string
+	ToolCallID string
+	Content    string
+}
+
+type toolEventsKey struct{}
+
+// WithToolEvents returns a copy of ctx that makes an agent run with it call fn for
+// every tool call and tool result as it happens. fn may be called concurrently
+// when RunnerConfig.ParallelSkills runs several skills at once.
+func WithToolEvents(ctx context.Context, fn func(ToolEvent)) context.Context {
+	return context.WithValue(ctx, toolEventsKey{}, fn)
+}
+
+// emitToolEvent passes ev to the function registered with WithToolEvents, if any.
+func emitToolEvent(ctx context.Context, ev ToolEvent) {
+	if fn, ok := ctx.Value(toolEventsKey{}).(func(ToolEvent)); ok {
+		fn(ev)
+	}
+}
@@ -0,0 +1,46 @@
+package goskills
+
+import (
+	"context"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinueSkillWithTools_ToolEvents(t *testing.T) {
+	mockClient := NewMockOpenAIClient([]openai.ChatCompletionResponse{
+		toolCallResponse("call-1", "run_shell_code", `{"code": "echo hi"}`),
+		toolCallResponse("call-2", "run_shell_code", `{"code": "rm -rf /tmp/x"}`),
+		textResponse("done"),
+	}, nil)
+	handler := &sequenceApprovalHandler{decisions: []bool{true, false}}
+	agent := &Agent{client: mockClient, cfg: RunnerConfig{Model: "test-model", ApprovalHandler: handler}}
+
+	var events []ToolEvent
+	ctx := WithToolEvents(context.Background(), func(ev ToolEvent) {
+		events = append(events, ev)
+	})
+	_, err := agent.continueSkillWithTools(ctx, "say hi", &SkillPackage{Meta: SkillMeta{Name: "test"}})
+	require.NoError(t, err)
+
+	// Denied calls are reported with the message the LLM receives
+	assert.Equal(t, []ToolEvent{
+		{Type: ToolEventCall, ToolName: "run_shell_code", ToolCallID: "call-1", Content: `{"code": "echo hi"}`},
+		{Type: ToolEventResult, ToolName: "run_shell_code", ToolCallID: "call-1", Content: "hi\n"},
+		{Type: ToolEventCall, ToolName: "run_shell_code", ToolCallID: "call-2", Content: `{"code": "rm -rf /tmp/x"}`},
+		{Type: ToolEventResult, ToolName: "run_shell_code", ToolCallID: "call-2", Content: "Error: User denied tool execution."},
+	}, events)
+}
+
+// sequenceApprovalHandler answers approval requests with decisions in order.
+type sequenceApprovalHandler struct {
+	decisions []bool
+}
+
+func (h *sequenceApprovalHandler) RequestApproval(toolName, args string) (bool, error) {
+	approved := h.decisions[0]
+	h.decisions = h.decisions[1:]
+	return approved, nil
+}
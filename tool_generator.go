@@ -1,9 +1,12 @@
 package goskills
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"text/template"
 
 	openai "github.com/sashabaranov/go-openai"
 	"github.com/smallnest/goskills/tool"
@@ -24,8 +27,9 @@ func GenerateToolDefinitions(skill *SkillPackage) ([]openai.Tool, map[string]str
 			allowedMap[t] = true
 		}
 
+		// list_tools and skill_info are always available so the LLM can discover what it may call
 		for _, t := range baseTools {
-			if allowedMap[t.Function.Name] {
+			if allowedMap[t.Function.Name] || t.Function.Name == tool.ListToolsName || t.Function.Name == tool.SkillInfoName {
 				tools = append(tools, t)
 			}
 		}
@@ -40,7 +44,150 @@ func GenerateToolDefinitions(skill *SkillPackage) ([]openai.Tool, map[string]str
 		scriptMap[toolName] = filepath.Join(skill.Path, scriptRelPath)
 	}
 
-	return tools, scriptMap
+	// 3. Inline Tools declared in the frontmatter
+	for _, inline := range skill.Meta.Tools {
+		tools = append(tools, generateInlineTool(inline))
+	}
+
+	return applyToolOverrides(tools, skill.Meta.ToolOverrides), scriptMap
+}
+
+// applyToolOverrides replaces tool descriptions with those given in the skill's tool-overrides.
+// Overridden tools are copied so the shared definitions are left untouched.
+func applyToolOverrides(tools []openai.Tool, overrides map[string]ToolOverride) []openai.Tool {
+	if len(overrides) == 0 {
+		return tools
+	}
+
+	for i, t := range tools {
+		override, ok := overrides[t.Function.Name]
+		if !ok || override.Description == "" {
+			continue
+		}
+		fn := *t.Function
+		fn.Description = override.Description
+		tools[i].Function = &fn
+	}
+	return tools
+}
+
+// validateToolOverrides ensures every tool-overrides entry targets a tool the skill can use.
+func validateToolOverrides(skill *SkillPackage) error {
+	if len(skill.Meta.ToolOverrides) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	for _, t := range append(append(tool.GetBaseTools(), tool.GetBrowserTools()...), tool.GetDockerTools()...) {
+		known[t.Function.Name] = true
+	}
+	for _, scriptRelPath := range skill.Resources.Scripts {
+		_, toolName := generateScriptTool(skill.Path, scriptRelPath)
+		known[toolName] = true
+	}
+	for _, inline := range skill.Meta.Tools {
+		known[inline.Name] = true
+	}
+
+	for name := range skill.Meta.ToolOverrides {
+		if !known[name] {
+			return fmt.Errorf("tool-overrides references unknown tool '%s'", name)
+		}
+	}
+	return nil
+}
+
+// validateInlineTools checks the tools declared in the frontmatter: each needs a name,
+// a command that is a valid template, and a name no other tool of the skill uses.
+func validateInlineTools(skill *SkillPackage) error {
+	if len(skill.Meta.Tools) == 0 {
+		return nil
+	}
+
+	taken := make(map[string]bool)
+	for _, t := range append(append(tool.GetBaseTools(), tool.GetBrowserTools()...), tool.GetDockerTools()...) {
+		taken[t.Function.Name] = true
+	}
+	for _, scriptRelPath := range skill.Resources.Scripts {
+		_, toolName := generateScriptTool(skill.Path, scriptRelPath)
+		taken[toolName] = true
+	}
+
+	seen := make(map[string]bool)
+	for i, inline := range skill.Meta.Tools {
+		if inline.Name == "" {
+			return fmt.Errorf("tools[%d] has no name", i)
+		}
+		if seen[inline.Name] {
+			return fmt.Errorf("duplicate tool name '%s' in tools", inline.Name)
+		}
+		if taken[inline.Name] {
+			return fmt.Errorf("tool '%s' in tools conflicts with an existing tool", inline.Name)
+		}
+		seen[inline.Name] = true
+		if strings.TrimSpace(inline.Command) == "" {
+			return fmt.Errorf("tool '%s' has no command", inline.Name)
+		}
+		if _, err := template.New(inline.Name).Parse(inline.Command); err != nil {
+			return fmt.Errorf("tool '%s' has an invalid command template: %w", inline.Name, err)
+		}
+	}
+	return nil
+}
+
+// generateInlineTool converts a frontmatter tool declaration into an OpenAI tool.
+// A missing parameters schema is treated as an object without properties.
+func generateInlineTool(inline InlineTool) openai.Tool {
+	params := inline.Parameters
+	if params == nil {
+		params = map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		}
+	}
+	return openai.Tool{
+		Type: openai.ToolTypeFunction,
+		Function: &openai.FunctionDefinition{
+			Name:        inline.Name,
+			Description: inline.Description,
+			Parameters:  params,
+		},
+	}
+}
+
+// runInlineTool fills the tool's command template with the call's JSON arguments and
+// runs it with bash until ctx is done. Every argument is expanded as a single-quoted
+// shell word, so the LLM cannot inject shell syntax; non-string arguments are JSON
+// encoded first. Declared parameters that were not passed expand to an empty word.
+func runInlineTool(ctx context.Context, inline InlineTool, arguments string, skillArgs map[string]string) (string, error) {
+	raw := make(map[string]any)
+	if arguments != "" {
+		if err := json.Unmarshal([]byte(arguments), &raw); err != nil {
+			return "", fmt.Errorf("failed to unmarshal %s arguments: %w", inline.Name, err)
+		}
+	}
+	args := make(map[string]any, len(raw))
+	if properties, ok := inline.Parameters["properties"].(map[string]any); ok {
+		for name := range properties {
+			args[name] = shellQuote("")
+		}
+	}
+	for name, value := range raw {
+		s, ok := value.(string)
+		if !ok {
+			encoded, _ := json.Marshal(value)
+			s = string(encoded)
+		}
+		args[name] = shellQuote(s)
+	}
+	shellTool := tool.ShellTool{SkillArgs: skillArgs}
+	return shellTool.Run(ctx, args, inline.Command)
+}
+
+// shellQuote returns s as one single-quoted bash word whose characters the shell
+// does not interpret.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func generateScriptTool(skillPath, scriptRelPath string) (openai.Tool, string) {
@@ -56,9 +203,14 @@ func generateScriptTool(skillPath, scriptRelPath string) (openai.Tool, string) {
 	// Determine type based on extension
 	ext := filepath.Ext(scriptRelPath)
 	var description string
-	if ext == ".py" {
+	switch ext {
+	case ".py":
 		description = fmt.Sprintf("Executes the python script '%s'.", scriptRelPath)
-	} else {
+	case ".js", ".mjs", ".cjs":
+		description = fmt.Sprintf("Executes the node script '%s'.", scriptRelPath)
+	case ".ts":
+		description = fmt.Sprintf("Executes the typescript script '%s'.", scriptRelPath)
+	default:
 		description = fmt.Sprintf("Executes the shell script '%s'.", scriptRelPath)
 	}
 
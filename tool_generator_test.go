@@ -1,10 +1,14 @@
 package goskills
 
 import (
+	"context"
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	openai "github.com/sashabaranov/go-openai"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestGenerateToolDefinitions_AllowedTools tests tool generation with allowed tools filter
@@ -21,8 +25,8 @@ func TestGenerateToolDefinitions_AllowedTools(t *testing.T) {
 
 	tools, scriptMap := GenerateToolDefinitions(&skill)
 
-	// Should have 2 allowed base tools + 2 script tools
-	assert.Len(t, tools, 4)
+	// Should have 2 allowed base tools + list_tools + skill_info + 2 script tools
+	assert.Len(t, tools, 6)
 	assert.Len(t, scriptMap, 2)
 
 	// Check that only allowed tools are included
@@ -32,6 +36,8 @@ func TestGenerateToolDefinitions_AllowedTools(t *testing.T) {
 	}
 	assert.True(t, toolNames["read_file"])
 	assert.True(t, toolNames["write_file"])
+	assert.True(t, toolNames["list_tools"]) // Always available
+	assert.True(t, toolNames["skill_info"]) // Always available
 	assert.True(t, toolNames["run_test_py"])
 	assert.True(t, toolNames["run_setup_sh"])
 }
@@ -73,12 +79,14 @@ func TestGenerateToolDefinitions_NoScripts(t *testing.T) {
 
 	tools, scriptMap := GenerateToolDefinitions(&skill)
 
-	// Should have only the allowed base tool
-	assert.Len(t, tools, 1)
+	// Should have only the allowed base tool, list_tools and skill_info
+	assert.Len(t, tools, 3)
 	assert.Len(t, scriptMap, 0) // No script tools
 
-	// Check it's the correct tool
+	// Check they are the correct tools
 	assert.Equal(t, "read_file", tools[0].Function.Name)
+	assert.Equal(t, "list_tools", tools[1].Function.Name)
+	assert.Equal(t, "skill_info", tools[2].Function.Name)
 }
 
 // TestGenerateScriptTool_PythonScript tests Python script tool generation
@@ -102,6 +110,17 @@ func TestGenerateScriptTool_PythonScript(t *testing.T) {
 	assert.NotNil(t, tool.Function.Parameters)
 }
 
+// TestGenerateScriptTool_NodeScripts tests JavaScript and TypeScript script tool generation
+func TestGenerateScriptTool_NodeScripts(t *testing.T) {
+	tool, toolName := generateScriptTool("/test/skill", "scripts/build.js")
+	assert.Equal(t, "run_scripts_build_js", toolName)
+	assert.Contains(t, tool.Function.Description, "node script")
+
+	tool, toolName = generateScriptTool("/test/skill", "scripts/build.ts")
+	assert.Equal(t, "run_scripts_build_ts", toolName)
+	assert.Contains(t, tool.Function.Description, "typescript script")
+}
+
 // TestGenerateScriptTool_ShellScript tests shell script tool generation
 func TestGenerateScriptTool_ShellScript(t *testing.T) {
 	skillPath := "/test/skill"
@@ -202,3 +221,160 @@ func TestGenerateScriptTool_ParametersStructure(t *testing.T) {
 	assert.Equal(t, "array", args["type"])
 	assert.Equal(t, "Arguments to pass to the script.", args["description"])
 }
+
+// TestGenerateToolDefinitions_ToolOverrides tests that skill tool-overrides replace descriptions
+func TestGenerateToolDefinitions_ToolOverrides(t *testing.T) {
+	override := "Use run_python_code to analyse the CSV using pandas."
+	skill := SkillPackage{
+		Path: "/test/skill",
+		Meta: SkillMeta{
+			AllowedTools: []string{"run_python_code", "read_file"},
+			ToolOverrides: map[string]ToolOverride{
+				"run_python_code": {Description: override},
+				"run_report_py":   {Description: "Generates the weekly report."},
+			},
+		},
+		Resources: SkillResources{
+			Scripts: []string{"report.py"},
+		},
+	}
+
+	tools, _ := GenerateToolDefinitions(&skill)
+
+	descriptions := make(map[string]string)
+	for _, tool := range tools {
+		descriptions[tool.Function.Name] = tool.Function.Description
+	}
+	assert.Equal(t, override, descriptions["run_python_code"])
+	assert.Equal(t, "Generates the weekly report.", descriptions["run_report_py"])
+	assert.Equal(t, "Reads the content of a file and returns it as a string.", descriptions["read_file"])
+}
+
+// TestValidateToolOverrides tests that overrides must target known tools
+func TestValidateToolOverrides(t *testing.T) {
+	skill := &SkillPackage{
+		Path: "/test/skill",
+		Meta: SkillMeta{
+			ToolOverrides: map[string]ToolOverride{
+				"run_python_code": {Description: "custom"},
+				"web_screenshot":  {Description: "custom"},
+				"run_build_sh":    {Description: "custom"},
+			},
+		},
+		Resources: SkillResources{
+			Scripts: []string{"build.sh"},
+		},
+	}
+	assert.NoError(t, validateToolOverrides(skill))
+
+	skill.Meta.ToolOverrides["no_such_tool"] = ToolOverride{Description: "custom"}
+	err := validateToolOverrides(skill)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no_such_tool")
+}
+
+// TestGenerateToolDefinitions_InlineTools tests that frontmatter tools become OpenAI tools
+func TestGenerateToolDefinitions_InlineTools(t *testing.T) {
+	params := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+		},
+	}
+	skill := SkillPackage{
+		Path: "/test/skill",
+		Meta: SkillMeta{
+			AllowedTools: []string{"read_file"},
+			Tools: []InlineTool{
+				{Name: "greet", Description: "Prints a greeting.", Parameters: params, Command: "echo {{.name}}"},
+				{Name: "list_files", Description: "Lists files.", Command: "ls"},
+			},
+			ToolOverrides: map[string]ToolOverride{
+				"greet": {Description: "Greets someone by name."},
+			},
+		},
+	}
+	assert.NoError(t, validateToolOverrides(&skill))
+
+	tools, scriptMap := GenerateToolDefinitions(&skill)
+	assert.Empty(t, scriptMap)
+
+	byName := make(map[string]openai.Tool)
+	for _, tool := range tools {
+		byName[tool.Function.Name] = tool
+	}
+	assert.Len(t, byName, 5) // read_file, list_tools, skill_info and the two inline tools
+	assert.Equal(t, "Greets someone by name.", byName["greet"].Function.Description)
+	assert.Equal(t, params, byName["greet"].Function.Parameters)
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, byName["list_files"].Function.Parameters)
+}
+
+// TestRunInlineTool tests that arguments are interpolated into the command
+func TestRunInlineTool(t *testing.T) {
+	inline := InlineTool{
+		Name: "greet",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":     map[string]any{"type": "string"},
+				"greeting": map[string]any{"type": "string"},
+			},
+		},
+		Command: `echo {{.greeting}}, {{.name}}!`,
+	}
+
+	output, err := runInlineTool(context.Background(), inline, `{"name": "Ada", "greeting": "Hello"}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, Ada!\n", output)
+
+	// Declared parameters that are not passed expand to empty strings
+	output, err = runInlineTool(context.Background(), inline, `{"name": "Ada"}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ", Ada!\n", output)
+
+	// Non-string arguments are JSON encoded
+	output, err = runInlineTool(context.Background(), inline, `{"name": 42, "greeting": ["a", "b"]}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `["a","b"], 42!`+"\n", output)
+
+	_, err = runInlineTool(context.Background(), inline, `not json`, nil)
+	assert.Error(t, err)
+}
+
+// TestRunInlineTool_ShellInjection tests that shell metacharacters in arguments are
+// passed through literally instead of being executed
+func TestRunInlineTool_ShellInjection(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "pwned")
+	inline := InlineTool{Name: "echo", Command: `printf '%s\n' {{.text}}`}
+
+	for _, payload := range []string{
+		"x; touch " + marker,
+		"$(touch " + marker + ")",
+		"`touch " + marker + "`",
+		"x' && touch " + marker + " && echo '",
+		"x\ntouch " + marker,
+		"a | touch " + marker,
+	} {
+		args, err := json.Marshal(map[string]string{"text": payload})
+		require.NoError(t, err)
+		output, err := runInlineTool(context.Background(), inline, string(args), nil)
+		require.NoError(t, err, payload)
+		assert.Equal(t, payload+"\n", output)
+		assert.NoFileExists(t, marker, payload)
+	}
+}
+
+// TestExecuteToolCall_InlineTool tests that executeToolCall dispatches frontmatter tools
+func TestExecuteToolCall_InlineTool(t *testing.T) {
+	agent := &Agent{
+		cfg: RunnerConfig{AutoApproveTools: true},
+		inlineTools: map[string]InlineTool{
+			"shout": {Name: "shout", Command: "echo {{.word}} | tr a-z A-Z"},
+		},
+	}
+	output, err := agent.executeToolCall(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "shout", Arguments: `{"word": "hello"}`},
+	}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "HELLO\n", output)
+}
@@ -0,0 +1,139 @@
+// Package trace records a detailed execution trace of an agent run: every LLM
+// request and response and every tool call, in the order they happened.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Event types.
+const (
+	EventLLMRequest  = "llm_request"
+	EventLLMResponse = "llm_response"
+	EventToolCall    = "tool_call"
+)
+
+// Stages of an agent run that talk to the LLM.
+const (
+	StageSelection     = "selection"
+	StageExecution     = "execution"
+	StageSummarization = "summarization"
+)
+
+// Event is a single entry in the trace. Only the fields relevant to Type are set.
+type Event struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Stage     string    `json:"stage,omitempty"`
+
+	// LLM requests
+	Model    string                         `json:"model,omitempty"`
+	Messages []openai.ChatCompletionMessage `json:"messages,omitempty"`
+	Tools    []string                       `json:"tools,omitempty"`
+
+	// LLM responses
+	Content   string            `json:"content,omitempty"`
+	ToolCalls []openai.ToolCall `json:"tool_calls,omitempty"`
+	Usage     *openai.Usage     `json:"usage,omitempty"`
+
+	// Tool calls
+	Tool       string `json:"tool,omitempty"`
+	Arguments  string `json:"arguments,omitempty"`
+	Output     string `json:"output,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Recorder collects trace events in memory. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+	now    func() time.Time
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+func (r *Recorder) add(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ev.Timestamp = r.now()
+	r.events = append(r.events, ev)
+}
+
+// RecordRequest records an LLM request made during stage.
+func (r *Recorder) RecordRequest(stage string, req openai.ChatCompletionRequest) {
+	messages := make([]openai.ChatCompletionMessage, len(req.Messages))
+	copy(messages, req.Messages)
+
+	var tools []string
+	for _, t := range req.Tools {
+		if t.Function != nil {
+			tools = append(tools, t.Function.Name)
+		}
+	}
+	r.add(Event{Type: EventLLMRequest, Stage: stage, Model: req.Model, Messages: messages, Tools: tools})
+}
+
+// RecordResponse records the LLM response received during stage.
+func (r *Recorder) RecordResponse(stage string, resp openai.ChatCompletionResponse) {
+	ev := Event{Type: EventLLMResponse, Stage: stage, Usage: &resp.Usage}
+	if len(resp.Choices) > 0 {
+		ev.Content = resp.Choices[0].Message.Content
+		ev.ToolCalls = resp.Choices[0].Message.ToolCalls
+	}
+	r.add(ev)
+}
+
+// RecordToolCall records a tool call with its output, duration and error, if any.
+func (r *Recorder) RecordToolCall(name, arguments, output string, duration time.Duration, err error) {
+	ev := Event{
+		Type:       EventToolCall,
+		Stage:      StageExecution,
+		Tool:       name,
+		Arguments:  arguments,
+		Output:     output,
+		DurationMs: duration.Milliseconds(),
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	r.add(ev)
+}
+
+// Events returns a copy of the recorded events.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// WriteFile writes all recorded events to path as an indented JSON array.
+func (r *Recorder) WriteFile(path string) error {
+	events := r.Events()
+	if events == nil {
+		events = []Event{}
+	}
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create trace directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_WriteFile(t *testing.T) {
+	r := NewRecorder()
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time { return fixed }
+
+	req := openai.ChatCompletionRequest{
+		Model:    "test-model",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+		Tools:    []openai.Tool{{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "read_file"}}},
+	}
+	r.RecordRequest(StageExecution, req)
+	req.Messages[0].Content = "mutated after recording"
+
+	r.RecordResponse(StageExecution, openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "hello"}}},
+		Usage:   openai.Usage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+	})
+	r.RecordToolCall("read_file", `{"filePath":"a.txt"}`, "", 1500*time.Millisecond, errors.New("not found"))
+
+	path := filepath.Join(t.TempDir(), "nested", "trace.json")
+	require.NoError(t, r.WriteFile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var events []Event
+	require.NoError(t, json.Unmarshal(data, &events))
+	require.Len(t, events, 3)
+
+	assert.Equal(t, EventLLMRequest, events[0].Type)
+	assert.Equal(t, fixed, events[0].Timestamp)
+	assert.Equal(t, "test-model", events[0].Model)
+	assert.Equal(t, "hi", events[0].Messages[0].Content)
+	assert.Equal(t, []string{"read_file"}, events[0].Tools)
+
+	assert.Equal(t, EventLLMResponse, events[1].Type)
+	assert.Equal(t, "hello", events[1].Content)
+	assert.Equal(t, 4, events[1].Usage.TotalTokens)
+
+	assert.Equal(t, EventToolCall, events[2].Type)
+	assert.Equal(t, "read_file", events[2].Tool)
+	assert.Equal(t, int64(1500), events[2].DurationMs)
+	assert.Equal(t, "not found", events[2].Error)
+}
+
+func TestRecorder_WriteFileEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, NewRecorder().WriteFile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
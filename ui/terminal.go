@@ -0,0 +1,117 @@
+// Package ui prints colored status lines for command-line tools.
+//
+// Colors are only emitted when the output is a terminal and the NO_COLOR environment
+// variable is not set, so redirected output stays plain text.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ANSI escape codes used by Terminal.
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorBold   = "\033[1m"
+)
+
+// Terminal writes status output, colored when Color is true.
+type Terminal struct {
+	Out   io.Writer
+	Color bool
+}
+
+// New returns a Terminal writing to out, with colors enabled if out is a terminal.
+func New(out io.Writer) *Terminal {
+	return &Terminal{Out: out, Color: IsTerminal(out)}
+}
+
+// IsTerminal reports whether w is a terminal that should receive colors.
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// StatusLine prints the progress of a multi-step task, e.g. "[2/5] search: find the docs".
+func (t *Terminal) StatusLine(step, total int, taskType, description string) {
+	t.printf(colorBold, "[%d/%d] %s: %s\n", step, total, taskType, description)
+}
+
+// ToolCall prints that a tool is being called with args.
+func (t *Terminal) ToolCall(name, args string) {
+	t.printf(colorCyan, "→ %s %s\n", name, args)
+}
+
+// ToolResult prints that a tool returned, noting whether its output was truncated.
+func (t *Terminal) ToolResult(name string, truncated bool) {
+	if truncated {
+		t.printf(colorYellow, "← %s (output truncated)\n", name)
+		return
+	}
+	t.printf(colorCyan, "← %s\n", name)
+}
+
+// Warning prints msg in yellow.
+func (t *Terminal) Warning(msg string) {
+	t.printf(colorYellow, "%s\n", msg)
+}
+
+// Error prints msg in red.
+func (t *Terminal) Error(msg string) {
+	t.printf(colorRed, "%s\n", msg)
+}
+
+// Success prints msg in green.
+func (t *Terminal) Success(msg string) {
+	t.printf(colorGreen, "%s\n", msg)
+}
+
+func (t *Terminal) printf(color, format string, args ...any) {
+	text := fmt.Sprintf(format, args...)
+	if t.Color {
+		// Keep the trailing newline outside the colored span
+		n := len(text)
+		if n > 0 && text[n-1] == '\n' {
+			text = color + text[:n-1] + colorReset + "\n"
+		} else {
+			text = color + text + colorReset
+		}
+	}
+	io.WriteString(t.Out, text)
+}
+
+// Default writes to standard output.
+var Default = New(os.Stdout)
+
+// StatusLine prints a progress line to standard output.
+func StatusLine(step, total int, taskType, description string) {
+	Default.StatusLine(step, total, taskType, description)
+}
+
+// ToolCall prints a tool call to standard output.
+func ToolCall(name, args string) { Default.ToolCall(name, args) }
+
+// ToolResult prints a tool result to standard output.
+func ToolResult(name string, truncated bool) { Default.ToolResult(name, truncated) }
+
+// Warning prints a warning to standard output.
+func Warning(msg string) { Default.Warning(msg) }
+
+// Error prints an error to standard output.
+func Error(msg string) { Default.Error(msg) }
+
+// Success prints a success message to standard output.
+func Success(msg string) { Default.Success(msg) }
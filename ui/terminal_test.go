@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func printAll(term *Terminal) {
+	term.StatusLine(2, 5, "search", "find the docs")
+	term.ToolCall("web_fetch", `{"url":"https://go.dev"}`)
+	term.ToolResult("web_fetch", false)
+	term.ToolResult("read_file", true)
+	term.Warning("slow response")
+	term.Error("tool failed")
+	term.Success("done")
+}
+
+func TestTerminal_PlainWhenNotTTY(t *testing.T) {
+	var buf bytes.Buffer
+	term := New(&buf)
+	assert.False(t, term.Color)
+
+	printAll(term)
+	assert.NotContains(t, buf.String(), "\033[")
+	assert.Equal(t, strings.Join([]string{
+		"[2/5] search: find the docs",
+		`→ web_fetch {"url":"https://go.dev"}`,
+		"← web_fetch",
+		"← read_file (output truncated)",
+		"slow response",
+		"tool failed",
+		"done",
+	}, "\n")+"\n", buf.String())
+}
+
+func TestTerminal_Color(t *testing.T) {
+	var buf bytes.Buffer
+	term := &Terminal{Out: &buf, Color: true}
+
+	term.Success("done")
+	term.Error("failed")
+	term.Warning("careful")
+	term.ToolCall("read_file", "{}")
+	assert.Equal(t, "\033[32mdone\033[0m\n\033[31mfailed\033[0m\n\033[33mcareful\033[0m\n\033[36m→ read_file {}\033[0m\n", buf.String())
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, IsTerminal(&bytes.Buffer{}))
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer f.Close()
+	assert.False(t, IsTerminal(f))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, IsTerminal(os.Stdout))
+}
@@ -0,0 +1,161 @@
+package goskills
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/smallnest/goskills/log"
+)
+
+// watchDebounce is how long Watch waits after the last file event before re-running,
+// so that an editor saving a file in several steps triggers a single run.
+var watchDebounce = 200 * time.Millisecond
+
+// clearScreen is the ANSI sequence that clears the terminal and moves the cursor home.
+const clearScreen = "\033[H\033[2J"
+
+// Watch runs userPrompt once and then re-runs it in a fresh conversation whenever the
+// selected skill's SKILL.md or one of its scripts changes. The skill is re-parsed
+// before every re-run so edits take effect. Results are written to out. Watch returns
+// when ctx is cancelled or after RunnerConfig.MaxWatchRuns re-runs, if set.
+func (a *Agent) Watch(ctx context.Context, userPrompt string, out io.Writer) error {
+	skill, err := a.selectAndPrepareSkill(ctx, userPrompt)
+	if err != nil {
+		return err
+	}
+
+	// Start watching before the first run so that edits made while it runs
+	// trigger a re-run instead of being missed.
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := addWatchDirs(watcher, skill.Path); err != nil {
+		return err
+	}
+	if a.cfg.Verbose >= 1 {
+		log.Info("watching %s for changes", skill.Path)
+	}
+
+	a.runWatched(ctx, userPrompt, skill, out)
+
+	var (
+		timer   *time.Timer
+		fire    <-chan time.Time
+		changed string
+		reruns  int
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("file watcher error: %v", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				// Watch directories created after startup, such as a new scripts/ folder.
+				if err := addWatchDirs(watcher, event.Name); err != nil && a.cfg.Verbose >= 2 {
+					log.Debug("failed to watch %s: %v", event.Name, err)
+				}
+			}
+			rel, ok := watchedSkillFile(skill.Path, event.Name)
+			if !ok || event.Op == fsnotify.Chmod {
+				continue
+			}
+			changed = rel
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				timer.Reset(watchDebounce)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			fmt.Fprint(out, clearScreen)
+			fmt.Fprintf(out, "[changed: %s]\n", changed)
+
+			updated, err := ParseSkillPackage(skill.Path)
+			if err != nil {
+				fmt.Fprintf(out, "error: failed to reload skill: %v\n", err)
+			} else {
+				skill = updated
+				a.runWatched(ctx, userPrompt, skill, out)
+			}
+
+			reruns++
+			if a.cfg.MaxWatchRuns > 0 && reruns >= a.cfg.MaxWatchRuns {
+				return nil
+			}
+		}
+	}
+}
+
+// runWatched executes skill for userPrompt in a fresh conversation and writes the
+// result, or the error, to out.
+func (a *Agent) runWatched(ctx context.Context, userPrompt string, skill *SkillPackage, out io.Writer) {
+	a.messages = []openai.ChatCompletionMessage{}
+	result, err := a.executeSkillWithTools(ctx, userPrompt, skill)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+		return
+	}
+	fmt.Fprintln(out, result)
+}
+
+// addWatchDirs adds root and every directory below it to watcher. Paths that are not
+// directories are ignored.
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// watchedSkillFile reports whether path is the skill's SKILL.md or a file under its
+// scripts directory, and returns the path relative to the skill root.
+func watchedSkillFile(skillPath, path string) (string, bool) {
+	rel, err := filepath.Rel(skillPath, path)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "SKILL.md" {
+		return rel, true
+	}
+	if strings.HasPrefix(rel, "scripts/") && !strings.HasPrefix(filepath.Base(rel), ".") {
+		return rel, true
+	}
+	return "", false
+}
@@ -0,0 +1,207 @@
+package goskills
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchClient answers every request with the first line of the skill body in the
+// system prompt and signals each call on runs.
+type watchClient struct {
+	mu       sync.Mutex
+	requests []openai.ChatCompletionRequest
+	runs     chan string
+}
+
+func (c *watchClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.mu.Unlock()
+
+	body := strings.SplitN(req.Messages[0].Content, "\n", 2)[0]
+	c.runs <- body
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "ran: " + body}}}}, nil
+}
+
+// syncBuffer is a bytes.Buffer that is safe to write from Watch while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitForRun(t *testing.T, runs <-chan string) string {
+	t.Helper()
+	select {
+	case body := <-runs:
+		return body
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a run")
+		return ""
+	}
+}
+
+func TestWatch_RerunsOnChange(t *testing.T) {
+	oldDebounce := watchDebounce
+	watchDebounce = 50 * time.Millisecond
+	defer func() { watchDebounce = oldDebounce }()
+
+	tmpDir := t.TempDir()
+	skillDir := writeTestSkill(t, tmpDir, "watched", "", "Version one.")
+	require.NoError(t, os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755))
+
+	client := &watchClient{runs: make(chan string, 10)}
+	agent := &Agent{
+		client: client,
+		cfg:    RunnerConfig{SkillsDir: tmpDir, SkillName: "watched", AutoApproveTools: true},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- agent.Watch(ctx, "go", out) }()
+
+	assert.Equal(t, "Version one.", waitForRun(t, client.runs))
+
+	// Give the watcher a moment to start, then change the skill with several writes.
+	time.Sleep(100 * time.Millisecond)
+	content := "---\nname: watched\ndescription: The watched skill\n---\nVersion two."
+	skillFile := filepath.Join(skillDir, "SKILL.md")
+	require.NoError(t, os.WriteFile(skillFile, []byte(content), 0644))
+	require.NoError(t, os.WriteFile(skillFile, []byte(content), 0644))
+
+	assert.Equal(t, "Version two.", waitForRun(t, client.runs))
+
+	// No further runs without further changes.
+	select {
+	case body := <-client.runs:
+		t.Fatalf("unexpected extra run: %q", body)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+
+	output := out.String()
+	assert.Contains(t, output, "ran: Version one.\n")
+	assert.Contains(t, output, clearScreen+"[changed: SKILL.md]\nran: Version two.\n")
+	assert.Len(t, client.requests, 2)
+}
+
+// editingClient edits the skill while the first run is in progress.
+type editingClient struct {
+	*watchClient
+	edit func()
+	once sync.Once
+}
+
+func (c *editingClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	c.once.Do(c.edit)
+	return c.watchClient.CreateChatCompletion(ctx, req)
+}
+
+func TestWatch_ChangeDuringFirstRun(t *testing.T) {
+	oldDebounce := watchDebounce
+	watchDebounce = 50 * time.Millisecond
+	defer func() { watchDebounce = oldDebounce }()
+
+	tmpDir := t.TempDir()
+	skillDir := writeTestSkill(t, tmpDir, "watched", "", "Version one.")
+	content := "---\nname: watched\ndescription: The watched skill\n---\nVersion two."
+
+	client := &editingClient{
+		watchClient: &watchClient{runs: make(chan string, 10)},
+		edit: func() {
+			require.NoError(t, os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644))
+		},
+	}
+	agent := &Agent{
+		client: client,
+		cfg:    RunnerConfig{SkillsDir: tmpDir, SkillName: "watched", AutoApproveTools: true, MaxWatchRuns: 1},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- agent.Watch(context.Background(), "go", &syncBuffer{}) }()
+
+	assert.Equal(t, "Version one.", waitForRun(t, client.runs))
+	assert.Equal(t, "Version two.", waitForRun(t, client.runs))
+	require.NoError(t, <-done)
+}
+
+func TestWatch_MaxWatchRuns(t *testing.T) {
+	oldDebounce := watchDebounce
+	watchDebounce = 50 * time.Millisecond
+	defer func() { watchDebounce = oldDebounce }()
+
+	tmpDir := t.TempDir()
+	skillDir := writeTestSkill(t, tmpDir, "watched", "", "Run the script.")
+	scriptsDir := filepath.Join(skillDir, "scripts")
+	require.NoError(t, os.MkdirAll(scriptsDir, 0755))
+
+	client := &watchClient{runs: make(chan string, 10)}
+	agent := &Agent{
+		client: client,
+		cfg:    RunnerConfig{SkillsDir: tmpDir, SkillName: "watched", AutoApproveTools: true, MaxWatchRuns: 1},
+	}
+
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- agent.Watch(context.Background(), "go", out) }()
+
+	waitForRun(t, client.runs)
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "run.sh"), []byte("echo hi\n"), 0755))
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not stop after MaxWatchRuns")
+	}
+	assert.Contains(t, out.String(), "[changed: scripts/run.sh]")
+	assert.Len(t, client.requests, 2)
+	assert.Contains(t, client.requests[1].Messages[0].Content, "Run the script.")
+}
+
+func TestWatchedSkillFile(t *testing.T) {
+	root := filepath.Join("skills", "demo")
+	testCases := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(root, "SKILL.md"), "SKILL.md", true},
+		{filepath.Join(root, "scripts", "run.py"), "scripts/run.py", true},
+		{filepath.Join(root, "scripts", "lib", "util.sh"), "scripts/lib/util.sh", true},
+		{filepath.Join(root, "scripts", ".run.py.swp"), "", false},
+		{filepath.Join(root, "references", "api.md"), "", false},
+		{filepath.Join(root, "notes.txt"), "", false},
+	}
+	for _, tc := range testCases {
+		got, ok := watchedSkillFile(root, tc.path)
+		assert.Equal(t, tc.ok, ok, tc.path)
+		assert.Equal(t, tc.want, got, tc.path)
+	}
+}